@@ -0,0 +1,85 @@
+package barbican
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/barbican"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var acl = &cobra.Command{Use: "acl", Short: "Secret ACLs"}
+
+type aclItem struct {
+	Operation string
+	barbican.ACL
+}
+
+var aclGet = &cobra.Command{
+	Use:   "get <secret>",
+	Short: "Get ACL of secret",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		secret, err := c.BarbicanV1().Secret().Find(args[0])
+		utility.LogError(err, "get secret failed", true)
+		acls, err := c.BarbicanV1().Secret().GetAcl(secret.Id)
+		utility.LogError(err, "get secret acl failed", true)
+
+		items := []aclItem{}
+		for operation, acl := range acls {
+			items = append(items, aclItem{Operation: operation, ACL: acl})
+		}
+		table := datatable.DataTable[aclItem]{
+			Items: items,
+			Columns: []datatable.Column[aclItem]{
+				{Name: "Operation"}, {Name: "ProjectAccess"},
+				{Name: "Users", RenderFunc: func(item aclItem) interface{} {
+					return strings.Join(item.Users, "\n")
+				}},
+				{Name: "Created"}, {Name: "Updated"},
+			},
+		}
+		common.PrintDataTable[aclItem](&table, false)
+	},
+}
+var aclSet = &cobra.Command{
+	Use:   "set <secret>",
+	Short: "Set read ACL of secret",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		users, _ := cmd.Flags().GetStringArray("user")
+		projectAccess, _ := cmd.Flags().GetBool("project-access")
+		secret, err := c.BarbicanV1().Secret().Find(args[0])
+		utility.LogError(err, "get secret failed", true)
+		err = c.BarbicanV1().Secret().SetAcl(secret.Id, projectAccess, users)
+		utility.LogError(err, "set secret acl failed", true)
+		console.Info("set ACL of secret %s, project access: %v, users: %v", args[0], projectAccess, users)
+	},
+}
+var aclDelete = &cobra.Command{
+	Use:   "delete <secret>",
+	Short: "Delete ACL of secret",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		secret, err := c.BarbicanV1().Secret().Find(args[0])
+		utility.LogError(err, "get secret failed", true)
+		err = c.BarbicanV1().Secret().DeleteAcl(secret.Id)
+		utility.LogError(err, "delete secret acl failed", true)
+		console.Info("deleted ACL of secret %s", args[0])
+	},
+}
+
+func init() {
+	aclSet.Flags().StringArray("user", []string{}, "User id allowed to read the secret, repeat option to add multiple users")
+	aclSet.Flags().Bool("project-access", true, "Allow all users of the project to read the secret")
+
+	acl.AddCommand(aclGet, aclSet, aclDelete)
+}
@@ -0,0 +1,122 @@
+package barbican
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/barbican"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var container = &cobra.Command{Use: "container", Short: "Secret containers"}
+
+func secretRefsString(c barbican.Container) interface{} {
+	refs := []string{}
+	for _, ref := range c.SecretRefs {
+		refs = append(refs, fmt.Sprintf("%s=%s", ref.Name, ref.SecretRef))
+	}
+	return strings.Join(refs, "\n")
+}
+
+func printContainer(c barbican.Container) {
+	table := datatable.DataIterator[barbican.Container]{
+		Items: []barbican.Container{c},
+		Fields: []datatable.Field[barbican.Container]{
+			{Name: "Id"}, {Name: "ContainerRef"}, {Name: "Name"}, {Name: "Type"},
+			{Name: "Status", AutoColor: true},
+			{Name: "SecretRefs", RenderFunc: secretRefsString},
+			{Name: "CreatorId"}, {Name: "Created"}, {Name: "Updated"},
+		},
+	}
+	common.PrintDataTable[barbican.Container](&table, false)
+}
+
+var containerListPageFlags flags.PageFlags
+
+var containerList = &cobra.Command{
+	Use:   "list",
+	Short: "List secret containers",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		c := openstack.DefaultClient()
+		containers, err := c.BarbicanV1().Container().List(nil)
+		utility.LogError(err, "list containers failed", true)
+		containers, err = flags.PageItems(containerListPageFlags, containers,
+			func(container barbican.Container) string { return container.Id })
+		utility.LogError(err, "list containers failed", true)
+
+		table := datatable.DataTable[barbican.Container]{
+			Items: containers,
+			Columns: []datatable.Column[barbican.Container]{
+				{Name: "Id"}, {Name: "Name"}, {Name: "Type"}, {Name: "Status", AutoColor: true},
+				{Name: "SecretRefs", RenderFunc: secretRefsString},
+			},
+		}
+		common.PrintDataTable[barbican.Container](&table, false)
+	},
+}
+var containerShow = &cobra.Command{
+	Use:   "show <container id>",
+	Short: "Show secret container",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		container, err := c.BarbicanV1().Container().Show(args[0])
+		utility.LogError(err, "get container failed", true)
+		printContainer(*container)
+	},
+}
+var containerCreate = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create secret container",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		containerType, _ := cmd.Flags().GetString("type")
+		secrets, _ := cmd.Flags().GetStringArray("secret")
+
+		refs := []barbican.SecretRef{}
+		for _, secret := range secrets {
+			kv, err := common.SplitKeyValue(secret)
+			utility.LogError(err, "invalid secret", true)
+			s, err := c.BarbicanV1().Secret().Find(kv[1])
+			utility.LogIfError(err, true, "get secret %s failed", kv[1])
+			refs = append(refs, barbican.SecretRef{Name: kv[0], SecretRef: s.SecretRef})
+		}
+		container, err := c.BarbicanV1().Container().Create(args[0], containerType, refs)
+		utility.LogError(err, "create container failed", true)
+		printContainer(*container)
+	},
+}
+var containerDelete = &cobra.Command{
+	Use:   "delete <container id> [<container id> ...]",
+	Short: "Delete secret container(s)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		for _, id := range args {
+			if err := c.BarbicanV1().Container().Delete(id); err != nil {
+				utility.LogIfError(err, false, "delete container %s failed", id)
+				continue
+			}
+			console.Info("requested to delete container %s", id)
+		}
+	},
+}
+
+func init() {
+	containerListPageFlags = flags.NewPageFlags(containerList)
+
+	containerCreate.Flags().String("type", "generic", "Container type, e.g. generic, rsa, certificate")
+	containerCreate.Flags().StringArray("secret", []string{},
+		"Secret in the container, format: <name>=<secret>, repeat option to add multiple secrets")
+
+	container.AddCommand(containerList, containerShow, containerCreate, containerDelete)
+}
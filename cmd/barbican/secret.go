@@ -0,0 +1,200 @@
+package barbican
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/barbican"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var Secret = &cobra.Command{Use: "secret", Short: "Key manager secrets (Barbican)"}
+
+func printSecret(secret barbican.Secret) {
+	table := datatable.DataIterator[barbican.Secret]{
+		Items: []barbican.Secret{secret},
+		Fields: []datatable.Field[barbican.Secret]{
+			{Name: "Id"}, {Name: "SecretRef"}, {Name: "Name"},
+			{Name: "Status", AutoColor: true}, {Name: "SecretType"},
+			{Name: "Algorithm"}, {Name: "BitLength"}, {Name: "Mode"},
+			{Name: "ContentTypes", Marshal: true}, {Name: "Expiration"},
+			{Name: "CreatorId"}, {Name: "Created"}, {Name: "Updated"},
+		},
+	}
+	common.PrintDataTable[barbican.Secret](&table, false)
+}
+
+var secretListPageFlags flags.PageFlags
+
+var secretList = &cobra.Command{
+	Use:   "list",
+	Short: "List secrets",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		c := openstack.DefaultClient()
+		long, _ := cmd.Flags().GetBool("long")
+		name, _ := cmd.Flags().GetString("name")
+		secretType, _ := cmd.Flags().GetString("secret-type")
+		query := url.Values{}
+		if name != "" {
+			query.Set("name", name)
+		}
+		if secretType != "" {
+			query.Set("secret_type", secretType)
+		}
+		secrets, err := c.BarbicanV1().Secret().List(query)
+		utility.LogError(err, "list secrets failed", true)
+		secrets, err = flags.PageItems(secretListPageFlags, secrets,
+			func(secret barbican.Secret) string { return secret.Id })
+		utility.LogError(err, "list secrets failed", true)
+
+		table := datatable.DataTable[barbican.Secret]{
+			Items: secrets,
+			Columns: []datatable.Column[barbican.Secret]{
+				{Name: "Id"}, {Name: "Name"}, {Name: "Status", AutoColor: true},
+				{Name: "SecretType"}, {Name: "Algorithm"}, {Name: "BitLength"},
+			},
+			MoreColumns: []datatable.Column[barbican.Secret]{
+				{Name: "Mode"}, {Name: "ContentTypes", Marshal: true},
+				{Name: "Expiration"}, {Name: "Created"},
+			},
+		}
+		common.PrintDataTable[barbican.Secret](&table, long)
+	},
+}
+var secretShow = &cobra.Command{
+	Use:   "show <secret>",
+	Short: "Show secret metadata",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		secret, err := c.BarbicanV1().Secret().Find(args[0])
+		utility.LogError(err, "get secret failed", true)
+		printSecret(*secret)
+	},
+}
+var secretCreate = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create secret",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+			return err
+		}
+		payload, _ := cmd.Flags().GetString("payload")
+		file, _ := cmd.Flags().GetString("file")
+		if payload != "" && file != "" {
+			return fmt.Errorf("argument --payload not allowed with argument --file")
+		}
+		secretType, _ := cmd.Flags().GetString("secret-type")
+		if !slices.Contains(barbican.SECRET_TYPES, secretType) {
+			return fmt.Errorf("invalid secret type %s, valid: %v", secretType, barbican.SECRET_TYPES)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		payload, _ := cmd.Flags().GetString("payload")
+		file, _ := cmd.Flags().GetString("file")
+		secretType, _ := cmd.Flags().GetString("secret-type")
+		algorithm, _ := cmd.Flags().GetString("algorithm")
+		bitLength, _ := cmd.Flags().GetInt("bit-length")
+		mode, _ := cmd.Flags().GetString("mode")
+		expiration, _ := cmd.Flags().GetString("expiration")
+
+		opt := barbican.SecretOpt{
+			Name: args[0], SecretType: secretType, Algorithm: algorithm,
+			BitLength: bitLength, Mode: mode, Expiration: expiration,
+		}
+		if payload != "" {
+			opt.Payload, opt.PayloadContentType = payload, "text/plain"
+		}
+		if file != "" {
+			content, err := os.ReadFile(file)
+			utility.LogIfError(err, true, "read file %s failed", file)
+			// 二进制内容使用 base64 编码上传
+			if utf8.Valid(content) {
+				opt.Payload, opt.PayloadContentType = string(content), "text/plain"
+			} else {
+				opt.Payload = base64.StdEncoding.EncodeToString(content)
+				opt.PayloadContentType, opt.PayloadContentEncoding = "application/octet-stream", "base64"
+			}
+		}
+		secret, err := c.BarbicanV1().Secret().Create(opt)
+		utility.LogError(err, "create secret failed", true)
+		printSecret(*secret)
+	},
+}
+var secretDelete = &cobra.Command{
+	Use:   "delete <secret> [<secret> ...]",
+	Short: "Delete secret(s)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		for _, idOrName := range args {
+			secret, err := c.BarbicanV1().Secret().Find(idOrName)
+			if err != nil {
+				utility.LogError(err, "get secret failed", false)
+				continue
+			}
+			if err := c.BarbicanV1().Secret().Delete(secret.Id); err != nil {
+				utility.LogIfError(err, false, "delete secret %s failed", idOrName)
+				continue
+			}
+			console.Info("requested to delete secret %s", idOrName)
+		}
+	},
+}
+var secretGetPayload = &cobra.Command{
+	Use:   "get-payload <secret>",
+	Short: "Get secret payload",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		file, _ := cmd.Flags().GetString("file")
+		secret, err := c.BarbicanV1().Secret().Find(args[0])
+		utility.LogError(err, "get secret failed", true)
+		payload, err := c.BarbicanV1().Secret().Payload(secret.Id, secret.DefaultContentType())
+		utility.LogError(err, "get secret payload failed", true)
+		if file != "" {
+			err := os.WriteFile(file, payload, 0600)
+			utility.LogIfError(err, true, "write file %s failed", file)
+			console.Info("saved payload of secret %s to %s", args[0], file)
+			return
+		}
+		if !utf8.Valid(payload) {
+			console.Fatal("payload of secret %s is binary, please use --file", args[0])
+		}
+		fmt.Println(string(payload))
+	},
+}
+
+func init() {
+	secretList.Flags().BoolP("long", "l", false, "List additional fields in output")
+	secretList.Flags().StringP("name", "n", "", "Search by secret name")
+	secretList.Flags().String("secret-type", "", "Search by secret type")
+	secretListPageFlags = flags.NewPageFlags(secretList)
+
+	secretCreate.Flags().String("payload", "", "The secret payload")
+	secretCreate.Flags().String("file", "", "Read the secret payload from the file")
+	secretCreate.Flags().String("secret-type", "opaque", "The secret type, e.g. opaque, symmetric, passphrase")
+	secretCreate.Flags().String("algorithm", "", "The algorithm of the secret, e.g. aes")
+	secretCreate.Flags().Int("bit-length", 0, "The bit length of the secret, e.g. 256")
+	secretCreate.Flags().String("mode", "", "The algorithm mode, e.g. cbc")
+	secretCreate.Flags().String("expiration", "", "The expiration time, e.g. 2030-01-01T00:00:00")
+
+	secretGetPayload.Flags().String("file", "", "Save the payload to the file")
+
+	Secret.AddCommand(secretList, secretShow, secretCreate, secretDelete, secretGetPayload,
+		container, acl)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/BytemanD/go-console/console"
 	"github.com/BytemanD/skyman/benchmark"
+	"github.com/BytemanD/skyman/cmd/views"
 	"github.com/BytemanD/skyman/openstack"
 	"github.com/BytemanD/skyman/utility"
 
@@ -16,6 +17,9 @@ import (
 var BenchmarkCmd = &cobra.Command{
 	Use:  "benchmark <name>",
 	Args: cobra.ExactArgs(1),
+	PostRun: func(cmd *cobra.Command, args []string) {
+		views.PrintRequestStats()
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		client := openstack.DefaultClient()
 
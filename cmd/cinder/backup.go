@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
 	"github.com/BytemanD/skyman/utility"
@@ -12,6 +13,8 @@ import (
 
 var Backup = &cobra.Command{Use: "backup"}
 
+var backupListPageFlags flags.PageFlags
+
 var backupList = &cobra.Command{
 	Use:   "list",
 	Short: "List backups",
@@ -34,7 +37,7 @@ var backupList = &cobra.Command{
 		if all {
 			query.Set("all_tenants", "true")
 		}
-		backups, err := client.CinderV2().Backup().Detail(query)
+		backups, err := flags.ListPages(backupListPageFlags, query, client.CinderV2().Backup().Iterator)
 		utility.LogError(err, "list backup falied", true)
 		table := common.PrettyTable{
 			ShortColumns: []common.Column{
@@ -117,6 +120,7 @@ func init() {
 	backupList.Flags().Bool("all", false, "List backups of all tenants")
 	backupList.Flags().StringP("name", "n", "", "Search by backup name")
 	backupList.Flags().String("status", "", "Search by backup status")
+	backupListPageFlags = flags.NewPageFlags(backupList)
 
 	backupCreate.Flags().Bool("force", false, "Ignores the current status of the volume ")
 	backupCreate.Flags().StringP("name", "n", "", "backup name")
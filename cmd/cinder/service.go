@@ -1,16 +1,21 @@
 package cinder
 
 import (
+	"fmt"
 	"net/url"
 
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/cinder"
 	"github.com/BytemanD/skyman/utility"
 	"github.com/spf13/cobra"
 )
 
 var service = &cobra.Command{Use: "service", Short: "Volume service command"}
 
+var serviceListPageFlags flags.PageFlags
+
 var list = &cobra.Command{
 	Use:   "list",
 	Short: "List volume services",
@@ -32,6 +37,10 @@ var list = &cobra.Command{
 
 		services, err := client.CinderV2().Service().List(query)
 		utility.LogIfError(err, true, "get services failed")
+		// cinder 服务没有 id, 使用 <host>:<binary> 作为 marker
+		services, err = flags.PageItems(serviceListPageFlags, services,
+			func(service cinder.Service) string { return fmt.Sprintf("%s:%s", service.Host, service.Binary) })
+		utility.LogIfError(err, true, "get services failed")
 		pt := common.PrettyTable{
 			ShortColumns: []common.Column{
 				{Name: "Binary"},
@@ -55,6 +64,7 @@ var list = &cobra.Command{
 
 func init() {
 	list.Flags().BoolP("long", "l", false, "List additional fields in output")
+	serviceListPageFlags = flags.NewPageFlags(list)
 
 	service.AddCommand(list)
 
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
 	"github.com/BytemanD/skyman/utility"
@@ -12,6 +13,8 @@ import (
 
 var Snapshot = &cobra.Command{Use: "snapshot"}
 
+var snapshotListPageFlags flags.PageFlags
+
 var snapshotList = &cobra.Command{
 	Use:   "list",
 	Short: "List snapshots",
@@ -34,7 +37,7 @@ var snapshotList = &cobra.Command{
 		if all {
 			query.Set("all_tenants", "true")
 		}
-		snapshots, err := client.CinderV2().Snapshot().Detail(query)
+		snapshots, err := flags.ListPages(snapshotListPageFlags, query, client.CinderV2().Snapshot().Iterator)
 		utility.LogError(err, "list snapshot falied", true)
 		table := common.PrettyTable{
 			ShortColumns: []common.Column{
@@ -134,6 +137,7 @@ func init() {
 	snapshotList.Flags().Bool("all", false, "List snapshots of all tenants")
 	snapshotList.Flags().StringP("name", "n", "", "Search by snapshot name")
 	snapshotList.Flags().String("status", "", "Search by snapshot status")
+	snapshotListPageFlags = flags.NewPageFlags(snapshotList)
 
 	snapshotCreate.Flags().Bool("force", false, "Ignores the current status of the volume ")
 	snapshotCreate.Flags().StringP("name", "n", "", "snapshot name")
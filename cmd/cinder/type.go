@@ -3,8 +3,11 @@ package cinder
 import (
 	"fmt"
 	"net/url"
+	"os"
 	"strings"
 
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
 	"github.com/BytemanD/skyman/openstack/model/cinder"
@@ -14,6 +17,8 @@ import (
 
 var VolumeType = &cobra.Command{Use: "type"}
 
+var typeListPageFlags flags.PageFlags
+
 var typeList = &cobra.Command{
 	Use:   "list",
 	Short: "List volume types",
@@ -60,7 +65,7 @@ var typeList = &cobra.Command{
 			if private {
 				query.Set("is_public", "false")
 			}
-			volumeTypes, err = client.CinderV2().VolumeType().List(query)
+			volumeTypes, err = flags.ListPages(typeListPageFlags, query, client.CinderV2().VolumeType().Iterator)
 			utility.RaiseIfError(err, "list volume type falied")
 		}
 
@@ -92,6 +97,11 @@ var typeShow = &cobra.Command{
 		volumeType, err := client.CinderV2().VolumeType().Find(args[0])
 		utility.LogError(err, "get volume type failed", true)
 		printVolumeType(*volumeType)
+		if volumeType.IsEncrypted {
+			encryption, err := client.CinderV2().VolumeType().ShowEncryption(volumeType.Id)
+			utility.LogError(err, "get volume type encryption failed", true)
+			printVolumeTypeEncryption(*encryption)
+		}
 	},
 }
 var typeDefault = &cobra.Command{
@@ -123,6 +133,16 @@ var typeCreate = &cobra.Command{
 				return err
 			}
 		}
+		provider, _ := cmd.Flags().GetString("encryption-provider")
+		for _, flag := range []string{"encryption-cipher", "encryption-key-size", "encryption-control-location"} {
+			if cmd.Flags().Changed(flag) && provider == "" {
+				return fmt.Errorf("argument --%s requires --encryption-provider", flag)
+			}
+		}
+		controlLocation, _ := cmd.Flags().GetString("encryption-control-location")
+		if controlLocation != "front-end" && controlLocation != "back-end" {
+			return fmt.Errorf("invalid control location %s, valid: front-end, back-end", controlLocation)
+		}
 		return nil
 	},
 	Run: func(cmd *cobra.Command, args []string) {
@@ -151,9 +171,33 @@ var typeCreate = &cobra.Command{
 		}
 
 		client := openstack.DefaultClient()
-		volume, err := client.CinderV2().VolumeType().Create(params)
+		volumeType, err := client.CinderV2().VolumeType().Create(params)
 		utility.LogError(err, "create volume type failed", true)
-		printVolumeType(*volume)
+
+		// 加密卷的密钥由 nova/cinder 在 barbican 中创建
+		if provider, _ := cmd.Flags().GetString("encryption-provider"); provider != "" {
+			cipher, _ := cmd.Flags().GetString("encryption-cipher")
+			keySize, _ := cmd.Flags().GetInt("encryption-key-size")
+			controlLocation, _ := cmd.Flags().GetString("encryption-control-location")
+			encryption, err := client.CinderV2().VolumeType().CreateEncryption(
+				volumeType.Id,
+				cinder.VolumeTypeEncryption{
+					Provider: provider, Cipher: cipher, KeySize: keySize, ControlLocation: controlLocation,
+				},
+			)
+			if err != nil {
+				// 删除创建的卷类型, 避免留下未加密的卷类型
+				utility.LogError(err, "create volume type encryption failed", false)
+				console.Info("delete volume type %s", volumeType.Name)
+				utility.LogError(client.CinderV2().VolumeType().Delete(volumeType.Id), "delete volume type failed", false)
+				os.Exit(1)
+			}
+			volumeType.IsEncrypted = true
+			printVolumeType(*volumeType)
+			printVolumeTypeEncryption(*encryption)
+			return
+		}
+		printVolumeType(*volumeType)
 	},
 }
 var typeDelete = &cobra.Command{
@@ -184,11 +228,18 @@ func init() {
 	typeList.Flags().Bool("public", false, "List only public types")
 	typeList.Flags().Bool("private", false, "List only private types(admin only)")
 	typeList.Flags().Bool("default", false, "List the default volume type")
+	typeListPageFlags = flags.NewPageFlags(typeList)
 
 	typeCreate.Flags().Bool("public", false, "Volume type is accessible to the public")
 	typeCreate.Flags().Bool("private", false, "Volume type is not accessible to the public")
 	typeCreate.Flags().StringArrayP("property", "p", []string{},
 		"Set a property on this volume type (repeat option to set multiple properties)")
+	typeCreate.Flags().String("encryption-provider", "",
+		"Set the encryption provider, e.g. luks, plain. The volume type will be encrypted")
+	typeCreate.Flags().String("encryption-cipher", "", "Set the encryption algorithm, e.g. aes-xts-plain64")
+	typeCreate.Flags().Int("encryption-key-size", 0, "Set the size of the encryption key, e.g. 256")
+	typeCreate.Flags().String("encryption-control-location", "front-end",
+		"Set the notional service where the encryption is performed, front-end or back-end")
 
 	VolumeType.AddCommand(typeList, typeShow, typeCreate, typeDelete, typeDefault)
 	Volume.AddCommand(VolumeType)
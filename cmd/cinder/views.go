@@ -29,6 +29,15 @@ func printVolumeType(volumeType cinder.VolumeType) {
 		},
 	)
 }
+func printVolumeTypeEncryption(encryption cinder.VolumeTypeEncryption) {
+	printResource(
+		encryption,
+		[]common.Column{
+			{Name: "EncryptionId"}, {Name: "VolumeTypeId"}, {Name: "Provider"},
+			{Name: "Cipher"}, {Name: "KeySize"}, {Name: "ControlLocation"},
+		},
+	)
+}
 
 func printVolume(volume cinder.Volume) {
 	printResource(
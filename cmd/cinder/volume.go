@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
 	"github.com/BytemanD/skyman/openstack/model/cinder"
@@ -17,6 +18,8 @@ import (
 
 var Volume = &cobra.Command{Use: "volume"}
 
+var volumeListPageFlags flags.PageFlags
+
 var volumeList = &cobra.Command{
 	Use:   "list",
 	Short: "List volumes",
@@ -29,7 +32,6 @@ var volumeList = &cobra.Command{
 		status, _ := cmd.Flags().GetString("status")
 		all, _ := cmd.Flags().GetBool("all")
 		sort, _ := cmd.Flags().GetString("sort")
-		limit, _ := cmd.Flags().GetInt("limit")
 
 		query := url.Values{}
 		if name != "" {
@@ -41,13 +43,10 @@ var volumeList = &cobra.Command{
 		if sort != "" {
 			query.Set("sort", sort)
 		}
-		if limit > 0 {
-			query.Set("limit", strconv.Itoa(limit))
-		}
 		if all {
 			query.Set("all_tenants", "true")
 		}
-		volumes, err := client.CinderV2().Volume().Detail(query)
+		volumes, err := flags.ListPages(volumeListPageFlags, query, client.CinderV2().Volume().Iterator)
 		utility.LogError(err, "list volume falied", true)
 		table := common.PrettyTable{
 			ShortColumns: []common.Column{
@@ -197,7 +196,7 @@ func init() {
 	volumeList.Flags().StringP("name", "n", "", "Search by volume name")
 	volumeList.Flags().String("status", "", "Search by volume status")
 	volumeList.Flags().String("sort", "", "Sort by specified field")
-	volumeList.Flags().Int("limit", 0, "limit")
+	volumeListPageFlags = flags.NewPageFlags(volumeList)
 
 	volumeCreate.Flags().Uint("size", 0, "Volume size (GB)")
 	volumeCreate.Flags().String("type", "", "Volume type")
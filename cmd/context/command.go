@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/utility"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -49,23 +50,27 @@ var viewCmd = &cobra.Command{
 }
 
 var setCmd = &cobra.Command{
-	Use:   "set <name> <conf file>",
+	Use:   "set <name> <conf file or cloud>",
 	Short: "Set context",
+	Long:  "Set context with a conf file, or with a cloud name defined in clouds.yaml",
 	Args:  cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
-		confPathAbs, err := filepath.Abs(args[1])
+		cConf, err := LoadContextConf()
 		if err != nil {
-			console.Fatal("get '%s' abs path failed: %s", args[1], err)
-		}
-		if !utility.IsFileExists(confPathAbs) {
-			console.Fatal("%s is not a file or not exits", confPathAbs)
+			console.Fatal("load context failed: %s", err)
 		}
 
-		cConf, err := LoadContextConf()
+		confPathAbs, err := filepath.Abs(args[1])
 		if err != nil {
-			console.Fatal("load context failed: %s", err)
+			console.Fatal("get '%s' abs path failed: %s", args[1], err)
+		}
+		if utility.IsFileExists(confPathAbs) {
+			cConf.SetContext(args[0], confPathAbs)
+		} else if _, err := common.LoadCloud(args[1]); err == nil {
+			cConf.SetCloudContext(args[0], args[1])
+		} else {
+			console.Fatal("%s is not a conf file or a cloud: %s", args[1], err)
 		}
-		cConf.SetContext(args[0], confPathAbs)
 		if err := cConf.Save(); err != nil {
 			console.Fatal("save context failed: %s", err)
 		}
@@ -22,8 +22,9 @@ func getContextFilePath() (f string) {
 }
 
 type Context struct {
-	Name string `yaml:"name"`
-	Conf string `yaml:"conf"`
+	Name  string `yaml:"name"`
+	Conf  string `yaml:"conf,omitempty"`
+	Cloud string `yaml:"cloud,omitempty"`
 }
 
 type ContextConf struct {
@@ -61,18 +62,27 @@ func (c *ContextConf) Save() (err error) {
 }
 
 func (c *ContextConf) SetContext(name string, conf string) {
+	c.setContext(Context{Name: name, Conf: conf})
+}
+
+// 设置使用 clouds.yaml 中 cloud 的上下文
+func (c *ContextConf) SetCloudContext(name string, cloud string) {
+	c.setContext(Context{Name: name, Cloud: cloud})
+}
+func (c *ContextConf) setContext(ctx Context) {
 	for _, context := range c.Contexts {
-		if context.Name != name {
+		if context.Name != ctx.Name {
 			continue
 		}
-		if context.Conf == conf {
+		if context.Conf == ctx.Conf && context.Cloud == ctx.Cloud {
 			return
 		}
-		context.Conf = conf
+		context.Conf = ctx.Conf
+		context.Cloud = ctx.Cloud
 		c.changed = true
 		return
 	}
-	c.Contexts = append(c.Contexts, &Context{Name: name, Conf: conf})
+	c.Contexts = append(c.Contexts, &ctx)
 	c.changed = true
 }
 func (c *ContextConf) RemoveContext(name string) {
@@ -0,0 +1,80 @@
+package designate
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/designate"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var ptr = &cobra.Command{Use: "ptr", Short: "Floating IP PTR records"}
+
+func ptrRegion(cmd *cobra.Command, client *openstack.Openstack) string {
+	region, _ := cmd.Flags().GetString("region")
+	if region == "" {
+		return client.Region()
+	}
+	return region
+}
+
+var ptrColumns = []datatable.Column[designate.FloatingIPPtr]{
+	{Name: "Id"}, {Name: "Address"}, {Name: "Ptrdname"}, {Name: "Ttl"},
+	{Name: "Status", AutoColor: true}, {Name: "Action"},
+}
+
+var ptrListPageFlags flags.PageFlags
+
+var ptrList = &cobra.Command{
+	Use:   "list",
+	Short: "List floating IP PTR records",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		c := openstack.DefaultClient()
+		ptrs, err := flags.ListPages(ptrListPageFlags, nil, c.DesignateV2().FloatingIPPtr().Iterator)
+		utility.LogError(err, "list PTR records failed", true)
+
+		table := datatable.DataTable[designate.FloatingIPPtr]{Items: ptrs, Columns: ptrColumns}
+		common.PrintDataTable[designate.FloatingIPPtr](&table, false)
+	},
+}
+var ptrSet = &cobra.Command{
+	Use:   "set <floating ip id> <ptrdname>",
+	Short: "Set PTR record of floating IP",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		ttl, _ := cmd.Flags().GetInt("ttl")
+		p, err := c.DesignateV2().FloatingIPPtr().Set(ptrRegion(cmd, c), args[0], args[1], ttl)
+		utility.LogError(err, "set PTR record failed", true)
+
+		table := datatable.DataTable[designate.FloatingIPPtr]{
+			Items: []designate.FloatingIPPtr{*p}, Columns: ptrColumns,
+		}
+		common.PrintDataTable[designate.FloatingIPPtr](&table, false)
+	},
+}
+var ptrUnset = &cobra.Command{
+	Use:   "unset <floating ip id>",
+	Short: "Unset PTR record of floating IP",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		err := c.DesignateV2().FloatingIPPtr().Unset(ptrRegion(cmd, c), args[0])
+		utility.LogError(err, "unset PTR record failed", true)
+		console.Info("requested to unset PTR record of floating IP %s", args[0])
+	},
+}
+
+func init() {
+	ptrListPageFlags = flags.NewPageFlags(ptrList)
+	ptrSet.Flags().String("region", "", "Region of the floating IP, default is the current region")
+	ptrSet.Flags().Int("ttl", 0, "TTL of the PTR record")
+	ptrUnset.Flags().String("region", "", "Region of the floating IP, default is the current region")
+
+	ptr.AddCommand(ptrList, ptrSet, ptrUnset)
+}
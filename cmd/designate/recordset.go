@@ -0,0 +1,150 @@
+package designate
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/designate"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var recordset = &cobra.Command{Use: "recordset", Short: "DNS recordsets"}
+
+func printRecordSet(r designate.RecordSet) {
+	table := datatable.DataIterator[designate.RecordSet]{
+		Items: []designate.RecordSet{r},
+		Fields: []datatable.Field[designate.RecordSet]{
+			{Name: "Id"}, {Name: "Name"}, {Name: "Type"},
+			{Name: "Records", RenderFunc: func(item designate.RecordSet) interface{} {
+				return strings.Join(item.Records, "\n")
+			}},
+			{Name: "Ttl"}, {Name: "Status", AutoColor: true}, {Name: "Action"},
+			{Name: "ZoneId"}, {Name: "ZoneName"}, {Name: "Description"},
+			{Name: "CreatedAt"}, {Name: "UpdatedAt"},
+		},
+	}
+	common.PrintDataTable[designate.RecordSet](&table, false)
+}
+
+var recordsetListPageFlags flags.PageFlags
+
+var recordsetList = &cobra.Command{
+	Use:   "list [<zone>]",
+	Short: "List recordsets",
+	Long:  "List recordsets of the zone, list recordsets of all zones if zone is not specified",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		recordType, _ := cmd.Flags().GetString("type")
+		data, _ := cmd.Flags().GetString("data")
+		zoneId := ""
+		if len(args) > 0 {
+			z, err := c.DesignateV2().Zone().Find(args[0])
+			utility.LogError(err, "get zone failed", true)
+			zoneId = z.Id
+		}
+		query := url.Values{}
+		if recordType != "" {
+			query.Set("type", strings.ToUpper(recordType))
+		}
+		if data != "" {
+			query.Set("data", data)
+		}
+		recordSets, err := flags.ListPages(recordsetListPageFlags, query, c.DesignateV2().RecordSet(zoneId).Iterator)
+		utility.LogError(err, "list recordsets failed", true)
+
+		table := datatable.DataTable[designate.RecordSet]{
+			Items: recordSets,
+			Columns: []datatable.Column[designate.RecordSet]{
+				{Name: "Id"}, {Name: "Name"}, {Name: "Type"},
+				{Name: "Records", RenderFunc: func(item designate.RecordSet) interface{} {
+					return strings.Join(item.Records, "\n")
+				}},
+				{Name: "Status", AutoColor: true}, {Name: "Action"},
+			},
+		}
+		common.PrintDataTable[designate.RecordSet](&table, false)
+	},
+}
+var recordsetShow = &cobra.Command{
+	Use:   "show <zone> <recordset>",
+	Short: "Show recordset",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		z, err := c.DesignateV2().Zone().Find(args[0])
+		utility.LogError(err, "get zone failed", true)
+		r, err := c.DesignateV2().RecordSet(z.Id).Find(args[1], z.Name)
+		utility.LogError(err, "get recordset failed", true)
+		printRecordSet(*r)
+	},
+}
+var recordsetCreate = &cobra.Command{
+	Use:   "create <zone> <name>",
+	Short: "Create recordset",
+	Long:  "Create recordset, name not ending with '.' is relative to the zone",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(2)(cmd, args); err != nil {
+			return err
+		}
+		if records, _ := cmd.Flags().GetStringArray("record"); len(records) == 0 {
+			return fmt.Errorf("at least one --record is required")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		recordType, _ := cmd.Flags().GetString("type")
+		records, _ := cmd.Flags().GetStringArray("record")
+		ttl, _ := cmd.Flags().GetInt("ttl")
+
+		z, err := c.DesignateV2().Zone().Find(args[0])
+		utility.LogError(err, "get zone failed", true)
+		r, err := c.DesignateV2().RecordSet(z.Id).Create(
+			designate.RecordName(args[1], z.Name), strings.ToUpper(recordType), records, ttl)
+		utility.LogError(err, "create recordset failed", true)
+		printRecordSet(*r)
+	},
+}
+var recordsetDelete = &cobra.Command{
+	Use:   "delete <zone> <recordset> [<recordset> ...]",
+	Short: "Delete recordset(s)",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		z, err := c.DesignateV2().Zone().Find(args[0])
+		utility.LogError(err, "get zone failed", true)
+		for _, idOrName := range args[1:] {
+			r, err := c.DesignateV2().RecordSet(z.Id).Find(idOrName, z.Name)
+			if err != nil {
+				utility.LogError(err, "get recordset failed", false)
+				continue
+			}
+			if err := c.DesignateV2().RecordSet(z.Id).Delete(r.Id); err != nil {
+				utility.LogIfError(err, false, "delete recordset %s failed", idOrName)
+				continue
+			}
+			console.Info("requested to delete recordset %s", idOrName)
+		}
+	},
+}
+
+func init() {
+	recordsetList.Flags().StringP("type", "t", "", "Search by record type, e.g. A, AAAA, CNAME")
+	recordsetList.Flags().String("data", "", "Search by record data, e.g. IP address")
+	recordsetListPageFlags = flags.NewPageFlags(recordsetList)
+
+	recordsetCreate.Flags().StringP("type", "t", designate.RECORD_A, "Record type, e.g. A, AAAA, CNAME, MX, TXT")
+	recordsetCreate.Flags().StringArray("record", []string{}, "Record data, can be specified multiple times")
+	recordsetCreate.Flags().Int("ttl", 0, "TTL of the recordset")
+
+	recordset.AddCommand(recordsetList, recordsetShow, recordsetCreate, recordsetDelete)
+}
@@ -0,0 +1,116 @@
+package designate
+
+import (
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/designate"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var Dns = &cobra.Command{Use: "dns", Short: "DNS zones and records (Designate)"}
+var zone = &cobra.Command{Use: "zone", Short: "DNS zones"}
+
+func printZone(z designate.Zone) {
+	table := datatable.DataIterator[designate.Zone]{
+		Items: []designate.Zone{z},
+		Fields: []datatable.Field[designate.Zone]{
+			{Name: "Id"}, {Name: "Name"}, {Name: "Description"}, {Name: "Type"},
+			{Name: "Email"}, {Name: "Ttl"}, {Name: "Serial"},
+			{Name: "Status", AutoColor: true}, {Name: "Action"},
+			{Name: "PoolId"}, {Name: "ProjectId"}, {Name: "CreatedAt"}, {Name: "UpdatedAt"},
+		},
+	}
+	common.PrintDataTable[designate.Zone](&table, false)
+}
+
+var zoneListPageFlags flags.PageFlags
+
+var zoneList = &cobra.Command{
+	Use:   "list",
+	Short: "List zones",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		c := openstack.DefaultClient()
+		name, _ := cmd.Flags().GetString("name")
+		query := url.Values{}
+		if name != "" {
+			query.Set("name", designate.Fqdn(name))
+		}
+		zones, err := flags.ListPages(zoneListPageFlags, query, c.DesignateV2().Zone().Iterator)
+		utility.LogError(err, "list zones failed", true)
+
+		table := datatable.DataTable[designate.Zone]{
+			Items: zones,
+			Columns: []datatable.Column[designate.Zone]{
+				{Name: "Id"}, {Name: "Name"}, {Name: "Type"}, {Name: "Serial"},
+				{Name: "Status", AutoColor: true}, {Name: "Action"},
+			},
+		}
+		common.PrintDataTable[designate.Zone](&table, false)
+	},
+}
+var zoneShow = &cobra.Command{
+	Use:   "show <zone>",
+	Short: "Show zone",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		z, err := c.DesignateV2().Zone().Find(args[0])
+		utility.LogError(err, "get zone failed", true)
+		printZone(*z)
+	},
+}
+var zoneCreate = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create zone",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		email, _ := cmd.Flags().GetString("email")
+		ttl, _ := cmd.Flags().GetInt("ttl")
+		description, _ := cmd.Flags().GetString("description")
+		z, err := c.DesignateV2().Zone().Create(args[0], email, ttl, description)
+		utility.LogError(err, "create zone failed", true)
+		printZone(*z)
+	},
+}
+var zoneDelete = &cobra.Command{
+	Use:   "delete <zone> [<zone> ...]",
+	Short: "Delete zone(s)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		for _, idOrName := range args {
+			z, err := c.DesignateV2().Zone().Find(idOrName)
+			if err != nil {
+				utility.LogError(err, "get zone failed", false)
+				continue
+			}
+			if err := c.DesignateV2().Zone().Delete(z.Id); err != nil {
+				utility.LogIfError(err, false, "delete zone %s failed", idOrName)
+				continue
+			}
+			console.Info("requested to delete zone %s", idOrName)
+		}
+	},
+}
+
+func init() {
+	zoneList.Flags().StringP("name", "n", "", "Search by zone name")
+	zoneListPageFlags = flags.NewPageFlags(zoneList)
+
+	zoneCreate.Flags().String("email", "", "Email of the zone owner")
+	zoneCreate.Flags().Int("ttl", 0, "Default TTL of the zone")
+	zoneCreate.Flags().String("description", "", "Zone description")
+	zoneCreate.MarkFlagRequired("email")
+
+	zone.AddCommand(zoneList, zoneShow, zoneCreate, zoneDelete)
+	Dns.AddCommand(zone, recordset, ptr)
+}
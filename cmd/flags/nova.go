@@ -12,11 +12,13 @@ type ServerListFlags struct {
 	Verbose       *bool
 	Dsc           *bool
 	Search        *string
+	Tags          *string
+	NotTags       *string
+	Metadata      *[]string
 	Watch         *bool
 	WatchInterval *uint
 	Long          *bool
-	// Marker *string
-	// Limit  *uint
+	PageFlags
 }
 
 type ServerCreateFlags struct {
@@ -33,6 +35,8 @@ type ServerCreateFlags struct {
 	KeyName    *string
 	AdminPass  *string
 	Wait       *bool
+	DnsZone    *string
+	Group      *string
 }
 type ServerSetFlags struct {
 	Name           *string
@@ -44,7 +48,8 @@ type ServerSetFlags struct {
 }
 
 type ServerDeleteFlags struct {
-	Wait *bool
+	Wait    *bool
+	DnsZone *string
 }
 
 type ServerRebootFlags struct {
@@ -80,6 +85,7 @@ type ServerRegionMigrateFlags struct {
 	DryRun *bool
 }
 type ServerMigrationListFlags struct {
+	PageFlags
 	Status        *string
 	Type          *string
 	Latest        *bool
@@ -88,7 +94,14 @@ type ServerMigrationListFlags struct {
 	WatchInterval *uint16
 }
 
+type ServerMigrationWatchFlags struct {
+	Interval    *uint16
+	MaxDuration *int
+	Policy      *string
+}
+
 type AggregateListFlags struct {
+	PageFlags
 	Long *bool
 	Name *string
 }
@@ -97,10 +110,12 @@ type AggregateCreateFlags struct {
 }
 
 type AZListFlags struct {
+	PageFlags
 	Tree *bool
 }
 
 type ComputeServiceListFlags struct {
+	PageFlags
 	Binary *string
 	Host   *string
 	Zone   *string
@@ -110,10 +125,19 @@ type ComputeServiceListFlags struct {
 type ComputeServiceDisableFlags struct {
 	Reason *string
 }
+type ComputeHostDrainFlags struct {
+	Reason   *string
+	Parallel *int
+	Timeout  *int
+}
 
 type ConsoleLogFlags struct {
 	Lines *uint
 }
+type ConsoleAttachFlags struct {
+	Log    *string
+	Escape *string
+}
 
 type ServerActionFlags struct {
 	Name  *string
@@ -130,6 +154,7 @@ type FlavorListFlags struct {
 	MinDisk *uint64
 	Long    *bool
 	Human   *bool
+	PageFlags
 }
 type FlavorCreateFlags struct {
 	Id   *string
@@ -154,6 +179,7 @@ type HypervisorListFlags struct {
 	Type        *string
 	WithServers *bool
 	Long        *bool
+	PageFlags
 }
 
 type HypervisorShowFlags struct {
@@ -165,11 +191,25 @@ type MigrationListFlags struct {
 	Instance *string
 	Type     *string
 	Long     *bool
+	PageFlags
 }
 
 type GroupListFlags struct {
+	PageFlags
 	Long *bool
 }
+type KeypairCreateFlags struct {
+	Type       *string
+	Bits       *int
+	PrivateKey *string
+}
+type KeypairImportFlags struct {
+	PublicKey *string
+}
+type GroupCreateFlags struct {
+	Policy *string
+	Rule   *[]string
+}
 type ServerCreateImageFlags struct {
 	Metadata *[]string
 }
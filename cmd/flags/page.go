@@ -0,0 +1,93 @@
+package flags
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/spf13/cobra"
+)
+
+// 列表命令的分页参数
+type PageFlags struct {
+	Limit    *uint
+	Marker   *string
+	AllPages *bool
+}
+
+func NewPageFlags(cmd *cobra.Command) PageFlags {
+	return PageFlags{
+		Limit:  cmd.Flags().Uint("limit", 0, "Maximum number of items to list in one page"),
+		Marker: cmd.Flags().String("marker", "", "List items after the marker (id of the last item of previous page)"),
+		AllPages: cmd.Flags().Bool("all-pages", false,
+			"List items of all pages, use --limit as the page size (not --all, which means all tenants in some lists)"),
+	}
+}
+
+// 分页迭代器, 例如 NovaV2().Server().Iterator 返回的迭代器
+type PageIterator[T any] interface {
+	Next() bool
+	Page() []T
+	Err() error
+	// 下一页的查询参数, 没有下一页时返回 nil
+	NextQuery() url.Values
+}
+
+// 分页查询
+//
+// 不指定 --limit 时查询所有页; 指定 --limit 时只查询一页, 同时指定 --all-pages 时,
+// 以 --limit 作为每页的数量, 按照服务端返回的下一页链接逐页查询。
+// 服务端每页的数量可能小于 --limit (例如超过了服务端的 max_limit), 所以不能根据数量判断是否还有下一页
+func ListPages[T any, I PageIterator[T]](f PageFlags, query url.Values, iterator func(query url.Values) I) ([]T, error) {
+	query = cloneQuery(query)
+	if *f.Marker != "" {
+		query.Set("marker", *f.Marker)
+	}
+	if *f.Limit != 0 {
+		query.Set("limit", strconv.Itoa(int(*f.Limit)))
+	}
+	items := []T{}
+	iter := iterator(query)
+	for iter.Next() {
+		items = append(items, iter.Page()...)
+		if *f.Limit == 0 || *f.AllPages {
+			continue
+		}
+		if next := iter.NextQuery(); next != nil {
+			console.Info("more items may exist, list the next page with --marker %s", next.Get("marker"))
+		}
+		break
+	}
+	return items, iter.Err()
+}
+
+// 在本地分页, 用于不支持分页的接口 (例如 keystone 以及 nova 的服务、聚合和可用域), marker 是 key 返回的值
+func PageItems[T any](f PageFlags, items []T, key func(item T) string) ([]T, error) {
+	if *f.Marker != "" {
+		found := false
+		for i, item := range items {
+			if key(item) == *f.Marker {
+				items, found = items[i+1:], true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("marker %s not found", *f.Marker)
+		}
+	}
+	if *f.Limit == 0 || *f.AllPages || len(items) <= int(*f.Limit) {
+		return items, nil
+	}
+	items = items[:*f.Limit]
+	console.Info("more items may exist, list the next page with --marker %s", key(items[len(items)-1]))
+	return items, nil
+}
+
+func cloneQuery(query url.Values) url.Values {
+	cloned := url.Values{}
+	for k, v := range query {
+		cloned[k] = append([]string{}, v...)
+	}
+	return cloned
+}
@@ -9,3 +9,14 @@ type PruneVolumeFlags struct {
 	Marker *string
 	Limit  *uint
 }
+
+type PruneShareFlags struct {
+	Name   *string
+	Status *string
+	Type   *string
+	All    *bool
+	Yes    *bool
+	Force  *bool
+	Marker *string
+	Limit  *uint
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/BytemanD/easygo/pkg/stringutils"
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/openstack/model/glance"
 )
 
@@ -12,9 +13,8 @@ type ImageListFlags struct {
 	Human      *bool
 	Visibility *string
 
+	flags.PageFlags
 	Total *uint
-	// page size
-	Limit *uint
 
 	Long *bool
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/BytemanD/easygo/pkg/stringutils"
 	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
 	"github.com/BytemanD/skyman/openstack/model/glance"
@@ -37,12 +38,24 @@ var ImageList = &cobra.Command{
 		if *imageListFlags.Visibility != "" {
 			query.Set("visibility", *imageListFlags.Visibility)
 		}
-		if *imageListFlags.Limit != 0 {
-			query.Set("limit", fmt.Sprintf("%d", *imageListFlags.Limit))
-		}
 
 		c := openstack.DefaultClient().GlanceV2()
-		images, err := c.Images().List(query, int(*imageListFlags.Total))
+		var (
+			images []glance.Image
+			err    error
+		)
+		if *imageListFlags.Total != 0 {
+			// 指定 --total 时, --limit 作为每页的数量, 查询到足够数量的镜像为止
+			if *imageListFlags.Limit != 0 {
+				query.Set("limit", fmt.Sprintf("%d", *imageListFlags.Limit))
+			}
+			if *imageListFlags.Marker != "" {
+				query.Set("marker", *imageListFlags.Marker)
+			}
+			images, err = c.Images().List(query, int(*imageListFlags.Total))
+		} else {
+			images, err = flags.ListPages(imageListFlags.PageFlags, query, c.Images().Iterator)
+		}
 		utility.LogError(err, "get imges failed", true)
 		pt := common.PrettyTable{
 			ShortColumns: []common.Column{
@@ -224,8 +237,8 @@ var (
 func init() {
 	imageListFlags = ImageListFlags{
 		Name:       ImageList.Flags().StringP("name", "n", "", "Search by image name"),
-		Limit:      ImageList.Flags().Uint("limit", 0, "Number of images to request in each paginated request"),
-		Total:      ImageList.Flags().Uint("total", 0, "Maximum number of images to get"),
+		PageFlags:  flags.NewPageFlags(ImageList),
+		Total:      ImageList.Flags().Uint("total", 0, "Maximum number of images to get, use --limit as the page size"),
 		Visibility: ImageList.Flags().String("visibility", "", "The visibility of the images to display."),
 		Human:      ImageList.Flags().Bool("human", false, "Human size"),
 		Long:       ImageList.Flags().BoolP("long", "l", false, "List additional fields in output"),
@@ -0,0 +1,66 @@
+package heat
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/heat"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var event = &cobra.Command{Use: "event", Short: "Stack events"}
+
+var eventListPageFlags flags.PageFlags
+
+var eventList = &cobra.Command{
+	Use:   "list <stack>",
+	Short: "List stack events",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		nested, _ := cmd.Flags().GetBool("nested")
+		resourceName, _ := cmd.Flags().GetString("resource")
+
+		stack, err := c.HeatV1().Stack().Find(args[0])
+		utility.LogError(err, "get stack failed", true)
+		query := url.Values{"sort_dir": []string{"asc"}}
+		if nested {
+			query.Set("nested_depth", fmt.Sprint(heat.MAX_NESTED_DEPTH))
+		}
+		if resourceName != "" {
+			query.Set("resource_name", resourceName)
+		}
+		events, err := c.HeatV1().Stack().Events(*stack, query)
+		utility.LogError(err, "list stack events failed", true)
+		events, err = flags.PageItems(eventListPageFlags, events,
+			func(event heat.Event) string { return event.Id })
+		utility.LogError(err, "list stack events failed", true)
+
+		table := datatable.DataTable[heat.Event]{
+			Items: events,
+			Columns: []datatable.Column[heat.Event]{
+				{Name: "EventTime"},
+				{Name: "StackName", RenderFunc: func(item heat.Event) interface{} {
+					return item.StackName()
+				}},
+				{Name: "ResourceName"},
+				{Name: "ResourceStatus", AutoColor: true},
+				{Name: "ResourceStatusReason"},
+			},
+		}
+		common.PrintDataTable[heat.Event](&table, false)
+	},
+}
+
+func init() {
+	eventList.Flags().Bool("nested", false, "Include events of nested stacks")
+	eventList.Flags().String("resource", "", "Search by resource name")
+	eventListPageFlags = flags.NewPageFlags(eventList)
+	event.AddCommand(eventList)
+}
@@ -0,0 +1,86 @@
+package heat
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/heat"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var resource = &cobra.Command{Use: "resource", Short: "Stack resources"}
+
+var resourceListPageFlags flags.PageFlags
+
+var resourceList = &cobra.Command{
+	Use:   "list <stack>",
+	Short: "List stack resources",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		long, _ := cmd.Flags().GetBool("long")
+		nested, _ := cmd.Flags().GetBool("nested")
+		nestedDepth, _ := cmd.Flags().GetInt("nested-depth")
+		failed, _ := cmd.Flags().GetBool("failed")
+		if nested && !cmd.Flags().Changed("nested-depth") {
+			nestedDepth = heat.MAX_NESTED_DEPTH
+		}
+
+		stack, err := c.HeatV1().Stack().Find(args[0])
+		utility.LogError(err, "get stack failed", true)
+		resources, err := c.HeatV1().Stack().Resources(*stack, nestedDepth)
+		utility.LogError(err, "list stack resources failed", true)
+		if failed {
+			failedResources := []heat.Resource{}
+			for _, resource := range resources {
+				if resource.IsFailed() {
+					failedResources = append(failedResources, resource)
+				}
+			}
+			resources = failedResources
+		}
+		// 嵌套 stack 中的资源名可能重复, 优先使用资源的 physical id 作为 marker
+		resources, err = flags.PageItems(resourceListPageFlags, resources, func(resource heat.Resource) string {
+			return utility.OneOfString(resource.PhysicalResourceId, resource.ResourceName)
+		})
+		utility.LogError(err, "list stack resources failed", true)
+
+		table := datatable.DataTable[heat.Resource]{
+			Items: resources,
+			Columns: []datatable.Column[heat.Resource]{
+				{Name: "ResourceName"}, {Name: "PhysicalResourceId"},
+				{Name: "ResourceType"},
+				{Name: "ResourceStatus", AutoColor: true},
+				{Name: "UpdatedTime"},
+			},
+			MoreColumns: []datatable.Column[heat.Resource]{
+				{Name: "ResourceStatusReason"},
+			},
+		}
+		// 包含嵌套 stack 的资源时, 显示资源所属的 stack 和失败原因
+		if nestedDepth > 0 || failed {
+			table.Columns = append(
+				[]datatable.Column[heat.Resource]{
+					{Name: "StackName", RenderFunc: func(item heat.Resource) interface{} {
+						return item.StackName()
+					}},
+				},
+				append(table.Columns, table.MoreColumns...)...,
+			)
+			table.MoreColumns = nil
+		}
+		common.PrintDataTable[heat.Resource](&table, long)
+	},
+}
+
+func init() {
+	resourceList.Flags().BoolP("long", "l", false, "List additional fields in output")
+	resourceList.Flags().Bool("nested", false, "Include resources of nested stacks")
+	resourceList.Flags().Int("nested-depth", 0, "Depth of nested stacks to list resources")
+	resourceList.Flags().Bool("failed", false, "Only list failed resources")
+	resourceListPageFlags = flags.NewPageFlags(resourceList)
+	resource.AddCommand(resourceList)
+}
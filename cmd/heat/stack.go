@@ -0,0 +1,260 @@
+package heat
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/heat"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var Stack = &cobra.Command{Use: "stack", Short: "Orchestration stacks (Heat)"}
+
+var stackListPageFlags flags.PageFlags
+
+// 等待 stack 操作结束的间隔, 单位秒
+const WAIT_INTERVAL = 2
+
+func printEvent(event heat.Event) {
+	line := fmt.Sprintf("%s [%s] %s: %s", event.EventTime, event.StackName(), event.ResourceName, event.ResourceStatus)
+	if event.ResourceStatusReason != "" {
+		line += "  " + event.ResourceStatusReason
+	}
+	fmt.Println(line)
+}
+
+// 等待 stack 操作结束, 同时输出 stack 的事件
+func waitStack(client *openstack.Openstack, stack heat.Stack, action string) *heat.Stack {
+	current, err := client.HeatV1().Stack().WaitComplete(client.Context(), stack, action, WAIT_INTERVAL, printEvent)
+	utility.LogIfError(err, true, "wait stack %s failed", stack.StackName)
+	console.Info("stack %s is %s", stack.StackName, current.StackStatus)
+	return current
+}
+
+func printStack(stack heat.Stack) {
+	table := datatable.DataIterator[heat.Stack]{
+		Items: []heat.Stack{stack},
+		Fields: []datatable.Field[heat.Stack]{
+			{Name: "Id"}, {Name: "StackName"}, {Name: "Description"},
+			{Name: "StackStatus", AutoColor: true}, {Name: "StackStatusReason"},
+			{Name: "Parent"}, {Name: "TimeoutMins"}, {Name: "DisableRollback"},
+			{Name: "Parameters", RenderFunc: func(item heat.Stack) interface{} {
+				params := []string{}
+				for k, v := range item.Parameters {
+					params = append(params, fmt.Sprintf("%s=%s", k, v))
+				}
+				sort.Strings(params)
+				return strings.Join(params, "\n")
+			}},
+			{Name: "Outputs", RenderFunc: func(item heat.Stack) interface{} {
+				outputs := []string{}
+				for _, output := range item.Outputs {
+					value := output.OutputValue
+					if output.OutputError != "" {
+						value = "ERROR: " + output.OutputError
+					}
+					outputs = append(outputs, fmt.Sprintf("%s=%v", output.OutputKey, value))
+				}
+				return strings.Join(outputs, "\n")
+			}},
+			{Name: "Tags", RenderFunc: func(item heat.Stack) interface{} {
+				return strings.Join(item.Tags, ",")
+			}},
+			{Name: "StackOwner"}, {Name: "CreationTime"}, {Name: "UpdatedTime"},
+		},
+	}
+	common.PrintDataTable[heat.Stack](&table, false)
+}
+
+// 解析 key=value 格式的参数, value 中可以包含 =
+func parseParameters(parameters []string) (map[string]string, error) {
+	params := map[string]string{}
+	for _, parameter := range parameters {
+		k, v, ok := strings.Cut(parameter, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid parameter '%s', it must be format: key=value", parameter)
+		}
+		params[k] = v
+	}
+	return params, nil
+}
+
+// 根据命令行参数生成创建/更新 stack 的参数
+func getStackOpt(cmd *cobra.Command, name string) heat.StackOpt {
+	template, _ := cmd.Flags().GetString("template")
+	environments, _ := cmd.Flags().GetStringArray("environment")
+	parameters, _ := cmd.Flags().GetStringArray("parameter")
+	timeout, _ := cmd.Flags().GetInt("timeout")
+	tags, _ := cmd.Flags().GetString("tags")
+
+	opt, err := openstack.NewStackOpt(name, template, environments)
+	utility.LogError(err, "load template failed", true)
+	opt.Parameters, err = parseParameters(parameters)
+	utility.LogError(err, "invalid parameters", true)
+	opt.TimeoutMins = timeout
+	opt.Tags = tags
+	if cmd.Flags().Changed("disable-rollback") {
+		disableRollback, _ := cmd.Flags().GetBool("disable-rollback")
+		opt.DisableRollback = &disableRollback
+	}
+	return *opt
+}
+
+var stackList = &cobra.Command{
+	Use:   "list",
+	Short: "List stacks",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		c := openstack.DefaultClient()
+		long, _ := cmd.Flags().GetBool("long")
+		name, _ := cmd.Flags().GetString("name")
+		status, _ := cmd.Flags().GetString("status")
+		nested, _ := cmd.Flags().GetBool("nested")
+		allProjects, _ := cmd.Flags().GetBool("all-projects")
+
+		query := url.Values{}
+		if name != "" {
+			query.Set("name", name)
+		}
+		if status != "" {
+			query.Set("status", strings.ToUpper(status))
+		}
+		if nested {
+			query.Set("show_nested", "true")
+		}
+		if allProjects {
+			query.Set("global_tenant", "true")
+		}
+		stacks, err := flags.ListPages(stackListPageFlags, query, c.HeatV1().Stack().Iterator)
+		utility.LogError(err, "list stacks failed", true)
+
+		table := datatable.DataTable[heat.Stack]{
+			Items: stacks,
+			Columns: []datatable.Column[heat.Stack]{
+				{Name: "Id"}, {Name: "StackName"},
+				{Name: "StackStatus", AutoColor: true},
+				{Name: "CreationTime"}, {Name: "UpdatedTime"},
+			},
+			MoreColumns: []datatable.Column[heat.Stack]{
+				{Name: "Parent"}, {Name: "Project"}, {Name: "StackOwner"},
+				{Name: "StackStatusReason"},
+			},
+		}
+		common.PrintDataTable[heat.Stack](&table, long)
+	},
+}
+var stackShow = &cobra.Command{
+	Use:   "show <stack>",
+	Short: "Show stack",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		stack, err := c.HeatV1().Stack().Find(args[0])
+		utility.LogError(err, "get stack failed", true)
+		printStack(*stack)
+	},
+}
+var stackCreate = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create stack",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		wait, _ := cmd.Flags().GetBool("wait")
+
+		stack, err := c.HeatV1().Stack().Create(getStackOpt(cmd, args[0]))
+		utility.LogError(err, "create stack failed", true)
+		if wait {
+			waitStack(c, *stack, heat.ACTION_CREATE)
+		}
+		stack, err = c.HeatV1().Stack().Show(stack.Id)
+		utility.LogError(err, "get stack failed", true)
+		printStack(*stack)
+	},
+}
+var stackUpdate = &cobra.Command{
+	Use:   "update <stack>",
+	Short: "Update stack",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		existing, _ := cmd.Flags().GetBool("existing")
+		wait, _ := cmd.Flags().GetBool("wait")
+		if !existing && !cmd.Flags().Changed("template") {
+			console.Fatal("--template is required without --existing")
+		}
+
+		stack, err := c.HeatV1().Stack().Find(args[0])
+		utility.LogError(err, "get stack failed", true)
+		err = c.HeatV1().Stack().Update(*stack, getStackOpt(cmd, ""), existing)
+		utility.LogError(err, "update stack failed", true)
+		if wait {
+			waitStack(c, *stack, heat.ACTION_UPDATE)
+		}
+		stack, err = c.HeatV1().Stack().Show(stack.Id)
+		utility.LogError(err, "get stack failed", true)
+		printStack(*stack)
+	},
+}
+var stackDelete = &cobra.Command{
+	Use:   "delete <stack> [stack ...]",
+	Short: "Delete stack(s)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		wait, _ := cmd.Flags().GetBool("wait")
+
+		for _, idOrName := range args {
+			stack, err := c.HeatV1().Stack().Find(idOrName)
+			if err != nil {
+				console.Error("get stack %s failed, %s", idOrName, err)
+				continue
+			}
+			console.Info("Reqeust to delete stack %s", idOrName)
+			if err := c.HeatV1().Stack().Delete(*stack); err != nil {
+				console.Error("Delete stack %s failed, %s", idOrName, err)
+				continue
+			}
+			if wait {
+				waitStack(c, *stack, heat.ACTION_DELETE)
+			}
+		}
+	},
+}
+
+func addStackOptFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("template", "t", "", "Path to the template")
+	cmd.Flags().StringArrayP("environment", "e", []string{}, "Path to the environment, it can be specified multiple times")
+	cmd.Flags().StringArray("parameter", []string{}, "Parameter values, format: key=value, it can be specified multiple times")
+	cmd.Flags().Int("timeout", 0, "Stack creating/updating timeout in minutes")
+	cmd.Flags().Bool("disable-rollback", false, "Disable rollback on failure")
+	cmd.Flags().String("tags", "", "A list of tags separated by comma")
+	cmd.Flags().Bool("wait", false, "Wait stack operation to complete and show events")
+}
+
+func init() {
+	stackList.Flags().BoolP("long", "l", false, "List additional fields in output")
+	stackList.Flags().StringP("name", "n", "", "Search by stack name")
+	stackList.Flags().String("status", "", "Search by stack status, e.g. CREATE_FAILED")
+	stackList.Flags().Bool("nested", false, "Show nested stacks")
+	stackList.Flags().BoolP("all-projects", "a", false, "List stacks of all projects")
+	stackListPageFlags = flags.NewPageFlags(stackList)
+
+	addStackOptFlags(stackCreate)
+	stackCreate.MarkFlagRequired("template")
+	addStackOptFlags(stackUpdate)
+	stackUpdate.Flags().Bool("existing", false, "Re-use the template, parameters and environment of the current stack")
+
+	stackDelete.Flags().Bool("wait", false, "Wait stack to be deleted and show events")
+
+	Stack.AddCommand(stackList, stackShow, stackCreate, stackUpdate, stackDelete, resource, event)
+}
@@ -0,0 +1,227 @@
+package ironic
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/ironic"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var Baremetal = &cobra.Command{Use: "baremetal", Short: "Bare metal nodes (Ironic)"}
+var node = &cobra.Command{Use: "node", Short: "Bare metal nodes"}
+
+var nodeListPageFlags flags.PageFlags
+
+// 等待节点状态变化的间隔, 单位秒
+const WAIT_INTERVAL = 5
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func waitProvisionState(client *openstack.Openstack, nodeId string, state string, timeout int) *ironic.Node {
+	node, err := client.IronicV1().Node().WaitProvisionState(
+		client.Context(), nodeId, state, time.Second*time.Duration(timeout), WAIT_INTERVAL)
+	utility.LogIfError(err, true, "wait node %s to be %s failed", nodeId, state)
+	console.Info("node %s is %s", nodeId, node.ProvisionState)
+	return node
+}
+
+func printNode(node ironic.Node) {
+	table := datatable.DataIterator[ironic.Node]{
+		Items: []ironic.Node{node},
+		Fields: []datatable.Field[ironic.Node]{
+			{Name: "Uuid"}, {Name: "Name"},
+			{Name: "PowerState", AutoColor: true}, {Name: "TargetPowerState"},
+			{Name: "ProvisionState", AutoColor: true}, {Name: "TargetProvisionState"},
+			{Name: "Maintenance"}, {Name: "MaintenanceReason"}, {Name: "Fault"},
+			{Name: "LastError"},
+			{Name: "InstanceUuid"}, {Name: "Driver"}, {Name: "ResourceClass"},
+			{Name: "ConductorGroup"}, {Name: "Conductor"},
+			{Name: "Properties", Marshal: true},
+			{Name: "CreatedAt"}, {Name: "UpdatedAt"}, {Name: "ProvisionUpdatedAt"},
+		},
+	}
+	common.PrintDataTable[ironic.Node](&table, false)
+}
+
+var nodeList = &cobra.Command{
+	Use:   "list",
+	Short: "List nodes",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		c := openstack.DefaultClient()
+		long, _ := cmd.Flags().GetBool("long")
+		provisionState, _ := cmd.Flags().GetString("provision-state")
+		driver, _ := cmd.Flags().GetString("driver")
+		resourceClass, _ := cmd.Flags().GetString("resource-class")
+		query := url.Values{}
+		if provisionState != "" {
+			query.Set("provision_state", provisionState)
+		}
+		if driver != "" {
+			query.Set("driver", driver)
+		}
+		if resourceClass != "" {
+			query.Set("resource_class", resourceClass)
+		}
+		if cmd.Flags().Changed("maintenance") {
+			maintenance, _ := cmd.Flags().GetBool("maintenance")
+			query.Set("maintenance", fmt.Sprint(maintenance))
+		}
+		nodes, err := flags.ListPages(nodeListPageFlags, query, c.IronicV1().Node().Iterator)
+		utility.LogError(err, "list nodes failed", true)
+
+		table := datatable.DataTable[ironic.Node]{
+			Items: nodes,
+			Columns: []datatable.Column[ironic.Node]{
+				{Name: "Uuid"}, {Name: "Name"}, {Name: "InstanceUuid"},
+				{Name: "PowerState", AutoColor: true},
+				{Name: "ProvisionState", AutoColor: true},
+				{Name: "Maintenance"},
+			},
+			MoreColumns: []datatable.Column[ironic.Node]{
+				{Name: "Driver"}, {Name: "ResourceClass"}, {Name: "ConductorGroup"},
+				{Name: "LastError"},
+			},
+		}
+		common.PrintDataTable[ironic.Node](&table, long)
+	},
+}
+var nodeShow = &cobra.Command{
+	Use:   "show <node>",
+	Short: "Show node",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		node, err := c.IronicV1().Node().Find(args[0])
+		utility.LogError(err, "get node failed", true)
+		printNode(*node)
+	},
+}
+var nodePower = &cobra.Command{
+	Use:   "power <node> <on|off|reboot|soft-off|soft-reboot>",
+	Short: "Set node power state",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(2)(cmd, args); err != nil {
+			return err
+		}
+		if _, ok := ironic.POWER_TARGETS[args[1]]; !ok {
+			return fmt.Errorf("invalid power state %s, valid: %v", args[1], sortedKeys(ironic.POWER_TARGETS))
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		node, err := c.IronicV1().Node().Find(args[0])
+		utility.LogError(err, "get node failed", true)
+		err = c.IronicV1().Node().SetPowerState(node.Uuid, ironic.POWER_TARGETS[args[1]])
+		utility.LogError(err, "set node power state failed", true)
+		console.Info("requested to set power state of node %s to %s", args[0], ironic.POWER_TARGETS[args[1]])
+	},
+}
+var nodeProvision = &cobra.Command{
+	Use:   "provision <node> <target>",
+	Short: "Set node provision state",
+	Long:  "Set node provision state, target: manage, provide, inspect, clean, deploy, rebuild, deleted, abort, rescue, unrescue, adopt",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(2)(cmd, args); err != nil {
+			return err
+		}
+		if _, ok := ironic.PROVISION_TARGETS[args[1]]; !ok && args[1] != "abort" {
+			return fmt.Errorf("invalid provision target %s, valid: %v", args[1],
+				append(sortedKeys(ironic.PROVISION_TARGETS), "abort"))
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		wait, _ := cmd.Flags().GetBool("wait")
+		timeout, _ := cmd.Flags().GetInt("timeout")
+
+		node, err := c.IronicV1().Node().Find(args[0])
+		utility.LogError(err, "get node failed", true)
+		err = c.IronicV1().Node().SetProvisionState(node.Uuid, args[1])
+		utility.LogError(err, "set node provision state failed", true)
+		console.Info("requested to %s node %s", args[1], args[0])
+		if state, ok := ironic.PROVISION_TARGETS[args[1]]; ok && wait {
+			printNode(*waitProvisionState(c, node.Uuid, state, timeout))
+		}
+	},
+}
+var nodeMaintenance = &cobra.Command{
+	Use:   "maintenance <node> <on|off>",
+	Short: "Set or unset node maintenance mode",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(2)(cmd, args); err != nil {
+			return err
+		}
+		if args[1] != "on" && args[1] != "off" {
+			return fmt.Errorf("invalid maintenance mode %s, valid: on, off", args[1])
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		reason, _ := cmd.Flags().GetString("reason")
+
+		node, err := c.IronicV1().Node().Find(args[0])
+		utility.LogError(err, "get node failed", true)
+		if args[1] == "on" {
+			err = c.IronicV1().Node().SetMaintenance(node.Uuid, reason)
+		} else {
+			err = c.IronicV1().Node().UnsetMaintenance(node.Uuid)
+		}
+		utility.LogError(err, "set node maintenance failed", true)
+		console.Info("turned maintenance %s for node %s", args[1], args[0])
+	},
+}
+var nodeWait = &cobra.Command{
+	Use:   "wait <node>",
+	Short: "Wait node to reach the provision state",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		state, _ := cmd.Flags().GetString("provision-state")
+		timeout, _ := cmd.Flags().GetInt("timeout")
+
+		node, err := c.IronicV1().Node().Find(args[0])
+		utility.LogError(err, "get node failed", true)
+		printNode(*waitProvisionState(c, node.Uuid, state, timeout))
+	},
+}
+
+func init() {
+	nodeList.Flags().BoolP("long", "l", false, "List additional fields in output")
+	nodeList.Flags().String("provision-state", "", "Search by provision state")
+	nodeList.Flags().String("driver", "", "Search by driver")
+	nodeList.Flags().String("resource-class", "", "Search by resource class")
+	nodeList.Flags().Bool("maintenance", false, "Search by maintenance mode")
+	nodeListPageFlags = flags.NewPageFlags(nodeList)
+
+	nodeProvision.Flags().Bool("wait", false, "Wait node to reach the target provision state")
+	nodeProvision.Flags().Int("timeout", 3600, "Timeout of waiting in seconds, 0 means no limit")
+
+	nodeMaintenance.Flags().String("reason", "", "Reason for setting maintenance mode")
+
+	nodeWait.Flags().String("provision-state", ironic.PROVISION_AVAILABLE, "Provision state to wait for")
+	nodeWait.Flags().Int("timeout", 3600, "Timeout of waiting in seconds, 0 means no limit")
+
+	node.AddCommand(nodeList, nodeShow, nodePower, nodeProvision, nodeMaintenance, nodeWait)
+	Baremetal.AddCommand(node, port, driver)
+}
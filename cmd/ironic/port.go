@@ -0,0 +1,89 @@
+package ironic
+
+import (
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/ironic"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var port = &cobra.Command{Use: "port", Short: "Bare metal ports"}
+var driver = &cobra.Command{Use: "driver", Short: "Bare metal drivers"}
+
+var portListPageFlags flags.PageFlags
+
+var portList = &cobra.Command{
+	Use:   "list",
+	Short: "List ports",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		c := openstack.DefaultClient()
+		long, _ := cmd.Flags().GetBool("long")
+		nodeIdOrName, _ := cmd.Flags().GetString("node")
+		address, _ := cmd.Flags().GetString("address")
+		query := url.Values{}
+		if nodeIdOrName != "" {
+			node, err := c.IronicV1().Node().Find(nodeIdOrName)
+			utility.LogError(err, "get node failed", true)
+			query.Set("node_uuid", node.Uuid)
+		}
+		if address != "" {
+			query.Set("address", address)
+		}
+		ports, err := flags.ListPages(portListPageFlags, query, c.IronicV1().Port().Iterator)
+		utility.LogError(err, "list ports failed", true)
+
+		table := datatable.DataTable[ironic.Port]{
+			Items: ports,
+			Columns: []datatable.Column[ironic.Port]{
+				{Name: "Uuid"}, {Name: "Address"}, {Name: "NodeUuid"}, {Name: "PxeEnabled"},
+			},
+			MoreColumns: []datatable.Column[ironic.Port]{
+				{Name: "PhysicalNetwork"}, {Name: "PortgroupUuid"},
+				{Name: "LocalLinkConnection", Marshal: true},
+			},
+		}
+		common.PrintDataTable[ironic.Port](&table, long)
+	},
+}
+
+var driverListPageFlags flags.PageFlags
+
+var driverList = &cobra.Command{
+	Use:   "list",
+	Short: "List drivers",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		c := openstack.DefaultClient()
+		drivers, err := c.IronicV1().Driver().List(nil)
+		utility.LogError(err, "list drivers failed", true)
+		drivers, err = flags.PageItems(driverListPageFlags, drivers,
+			func(driver ironic.Driver) string { return driver.Name })
+		utility.LogError(err, "list drivers failed", true)
+
+		table := datatable.DataTable[ironic.Driver]{
+			Items: drivers,
+			Columns: []datatable.Column[ironic.Driver]{
+				{Name: "Name"}, {Name: "Type"}, {Name: "Hosts"},
+			},
+		}
+		common.PrintDataTable[ironic.Driver](&table, false)
+	},
+}
+
+func init() {
+	portList.Flags().BoolP("long", "l", false, "List additional fields in output")
+	portList.Flags().String("node", "", "Only list ports of the node")
+	portList.Flags().String("address", "", "Search by MAC address")
+	portListPageFlags = flags.NewPageFlags(portList)
+	port.AddCommand(portList)
+
+	driverListPageFlags = flags.NewPageFlags(driverList)
+	driver.AddCommand(driverList)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/BytemanD/go-console/console"
 	"github.com/spf13/cobra"
 
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
 	"github.com/BytemanD/skyman/openstack/model/keystone"
@@ -15,6 +16,8 @@ import (
 
 var Endpoint = &cobra.Command{Use: "endpoint"}
 
+var endpointListPageFlags flags.PageFlags
+
 var endpointList = &cobra.Command{
 	Use:   "list",
 	Short: "List endpoints",
@@ -68,6 +71,9 @@ var endpointList = &cobra.Command{
 			console.Error("list endpoints failed, %s", err)
 
 		}
+		items, err = flags.PageItems(endpointListPageFlags, items,
+			func(endpoint keystone.Endpoint) string { return endpoint.Id })
+		utility.LogError(err, "list endpoints failed", true)
 
 		// TODO: 优化
 		serviceIds := []string{}
@@ -184,6 +190,7 @@ func init() {
 	endpointList.Flags().StringP("interface", "i", "", "Search by interface")
 	endpointList.Flags().StringP("service", "s", "", "Search by service name")
 	endpointList.Flags().Bool("current", false, "Search by current region")
+	endpointListPageFlags = flags.NewPageFlags(endpointList)
 
 	endpointCreate.Flags().Bool("diable", false, "Disable service")
 	endpointCreate.Flags().StringP("region", "r", "", "New endpoint region ID")
@@ -3,13 +3,17 @@ package keystone
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model"
 	"github.com/BytemanD/skyman/utility"
 )
 
 var Project = &cobra.Command{Use: "project"}
 
+var projectListPageFlags flags.PageFlags
+
 var projectList = &cobra.Command{
 	Use:   "list",
 	Short: "List endpoints",
@@ -20,6 +24,9 @@ var projectList = &cobra.Command{
 		c := openstack.DefaultClient().KeystoneV3()
 		projects, err := c.Project().List(nil)
 		utility.LogError(err, "list projects failed", true)
+		projects, err = flags.PageItems(projectListPageFlags, projects,
+			func(project model.Project) string { return project.Id })
+		utility.LogError(err, "list projects failed", true)
 
 		pt := common.PrettyTable{
 			ShortColumns: []common.Column{
@@ -70,6 +77,7 @@ var projectShow = &cobra.Command{
 
 func init() {
 	projectList.Flags().BoolP("long", "l", false, "List additional fields in output")
+	projectListPageFlags = flags.NewPageFlags(projectList)
 
 	Project.AddCommand(projectList, projectShow, projectDelete)
 }
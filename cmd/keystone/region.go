@@ -3,13 +3,17 @@ package keystone
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/keystone"
 	"github.com/BytemanD/skyman/utility"
 )
 
 var Region = &cobra.Command{Use: "region"}
 
+var regionListPageFlags flags.PageFlags
+
 var list = &cobra.Command{
 	Use:   "list",
 	Short: "List regions",
@@ -17,6 +21,9 @@ var list = &cobra.Command{
 		c := openstack.DefaultClient().KeystoneV3()
 		regions, err := c.Region().List(nil)
 		utility.LogError(err, "list region failed", true)
+		regions, err = flags.PageItems(regionListPageFlags, regions,
+			func(region keystone.Region) string { return region.Id })
+		utility.LogError(err, "list region failed", true)
 
 		pt := common.PrettyTable{
 			ShortColumns: []common.Column{
@@ -30,5 +37,7 @@ var list = &cobra.Command{
 }
 
 func init() {
+	regionListPageFlags = flags.NewPageFlags(list)
+
 	Region.AddCommand(list)
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
 	"github.com/BytemanD/skyman/openstack/model"
@@ -16,6 +17,8 @@ import (
 
 var Service = &cobra.Command{Use: "service"}
 
+var serviceListPageFlags flags.PageFlags
+
 var serviceList = &cobra.Command{
 	Use:   "list",
 	Short: "List services",
@@ -34,6 +37,9 @@ var serviceList = &cobra.Command{
 		c := openstack.DefaultClient().KeystoneV3()
 		services, err := c.Service().List(query)
 		utility.LogError(err, "list services failed", true)
+		services, err = flags.PageItems(serviceListPageFlags, services,
+			func(service keystone.Service) string { return service.Id })
+		utility.LogError(err, "list services failed", true)
 
 		pt := common.PrettyTable{
 			ShortColumns: []common.Column{
@@ -100,6 +106,7 @@ func init() {
 	serviceList.Flags().BoolP("long", "l", false, "List additional fields in output")
 	serviceList.Flags().StringP("name", "n", "", "Search by service name")
 	serviceList.Flags().StringP("type", "t", "", "Search by service type")
+	serviceListPageFlags = flags.NewPageFlags(serviceList)
 
 	serviceCreate.Flags().Bool("disable", false, "Disable service")
 	serviceCreate.Flags().StringP("name", "n", "", "New service name")
@@ -1,11 +1,16 @@
 package keystone
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/spf13/cobra"
 
 	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
 	"github.com/BytemanD/skyman/openstack"
 	"github.com/BytemanD/skyman/openstack/model"
+	"github.com/BytemanD/skyman/openstack/token_cache"
 	"github.com/BytemanD/skyman/utility"
 )
 
@@ -48,6 +53,55 @@ var tokenIssue = &cobra.Command{
 	},
 }
 
+var tokenCache = &cobra.Command{Use: "cache"}
+
+var tokenCacheList = &cobra.Command{
+	Use:   "list",
+	Short: "List cached tokens",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		long, _ := cmd.Flags().GetBool("long")
+
+		entries, err := token_cache.List()
+		utility.LogError(err, "list token caches failed", true)
+		table := datatable.DataTable[token_cache.Entry]{
+			Items: entries,
+			Columns: []datatable.Column[token_cache.Entry]{
+				{Name: "AuthUrl"}, {Name: "User"}, {Name: "Project"},
+				{Name: "Region"},
+				{Name: "ExpiredAt", RenderFunc: func(item token_cache.Entry) interface{} {
+					return item.ExpiredAt.Local().Format(time.DateTime)
+				}},
+				{Name: "Valid", RenderFunc: func(item token_cache.Entry) interface{} {
+					return item.IsValid()
+				}},
+			},
+			MoreColumns: []datatable.Column[token_cache.Entry]{
+				{Name: "Key", RenderFunc: func(item token_cache.Entry) interface{} {
+					return item.Digest()
+				}},
+			},
+		}
+		common.PrintDataTable[token_cache.Entry](&table, long)
+	},
+}
+var tokenCachePurge = &cobra.Command{
+	Use:   "purge",
+	Short: "Purge cached tokens",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		expired, _ := cmd.Flags().GetBool("expired")
+
+		purged, err := token_cache.Purge(expired)
+		utility.LogError(err, "purge token caches failed", true)
+		fmt.Printf("purged %d token cache(s)\n", purged)
+	},
+}
+
 func init() {
-	Token.AddCommand(tokenIssue)
+	tokenCacheList.Flags().BoolP("long", "l", false, "List additional fields in output")
+	tokenCachePurge.Flags().Bool("expired", false, "Only purge expired token caches")
+
+	tokenCache.AddCommand(tokenCacheList, tokenCachePurge)
+	Token.AddCommand(tokenIssue, tokenCache)
 }
@@ -4,13 +4,17 @@ import (
 	"github.com/BytemanD/go-console/console"
 	"github.com/spf13/cobra"
 
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model"
 	"github.com/BytemanD/skyman/utility"
 )
 
 var User = &cobra.Command{Use: "user"}
 
+var userListPageFlags flags.PageFlags
+
 var userList = &cobra.Command{
 	Use:   "list",
 	Short: "List users",
@@ -31,17 +35,22 @@ var userList = &cobra.Command{
 		}
 
 		c := openstack.DefaultClient().KeystoneV3()
+		var (
+			users []model.User
+			err   error
+		)
 		if project == "" {
-			users, err := c.User().List(nil)
+			users, err = c.User().List(nil)
 			utility.LogError(err, "list users failed", true)
-			pt.AddItems(users)
 		} else {
-			users, err := c.ListUsersByProjectId(project)
+			users, err = c.ListUsersByProjectId(project)
 			if err != nil {
 				console.Fatal("get users failed, %s", err)
 			}
-			pt.AddItems(users)
 		}
+		users, err = flags.PageItems(userListPageFlags, users, func(user model.User) string { return user.Id })
+		utility.LogError(err, "list users failed", true)
+		pt.AddItems(users)
 		common.PrintPrettyTable(pt, long)
 	},
 }
@@ -74,6 +83,7 @@ var userShow = &cobra.Command{
 func init() {
 	userList.Flags().BoolP("long", "l", false, "List additional fields in output")
 	userList.Flags().String("project", "", "Filter users by project ID")
+	userListPageFlags = flags.NewPageFlags(userList)
 
 	User.AddCommand(userList, userShow)
 }
@@ -0,0 +1,97 @@
+package manila
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/manila"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var access = &cobra.Command{Use: "access", Short: "Share access rules"}
+
+var accessListPageFlags flags.PageFlags
+
+var accessList = &cobra.Command{
+	Use:   "list <share>",
+	Short: "List access rules of share",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		share, err := c.ManilaV2().Share().Find(args[0])
+		utility.LogError(err, "get share failed", true)
+		rules, err := c.ManilaV2().Share().AccessRules(share.Id)
+		utility.LogError(err, "list access rules failed", true)
+		rules, err = flags.PageItems(accessListPageFlags, rules,
+			func(rule manila.AccessRule) string { return rule.Id })
+		utility.LogError(err, "list access rules failed", true)
+
+		table := datatable.DataTable[manila.AccessRule]{
+			Items: rules,
+			Columns: []datatable.Column[manila.AccessRule]{
+				{Name: "Id"}, {Name: "AccessType"}, {Name: "AccessTo"},
+				{Name: "AccessLevel"}, {Name: "State", AutoColor: true},
+				{Name: "AccessKey"},
+			},
+		}
+		common.PrintDataTable[manila.AccessRule](&table, false)
+	},
+}
+var accessCreate = &cobra.Command{
+	Use:   "create <share> <access type> <access to>",
+	Short: "Allow access to share",
+	Long:  "Allow access to share, access type: ip, user, cert, cephx",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(3)(cmd, args); err != nil {
+			return err
+		}
+		level, _ := cmd.Flags().GetString("access-level")
+		if level != "rw" && level != "ro" {
+			return fmt.Errorf("invalid access level %s, valid: rw, ro", level)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		level, _ := cmd.Flags().GetString("access-level")
+		share, err := c.ManilaV2().Share().Find(args[0])
+		utility.LogError(err, "get share failed", true)
+		rule, err := c.ManilaV2().Share().AllowAccess(share.Id, args[1], args[2], level)
+		utility.LogError(err, "allow access failed", true)
+
+		table := datatable.DataIterator[manila.AccessRule]{
+			Items: []manila.AccessRule{*rule},
+			Fields: []datatable.Field[manila.AccessRule]{
+				{Name: "Id"}, {Name: "ShareId"}, {Name: "AccessType"}, {Name: "AccessTo"},
+				{Name: "AccessLevel"}, {Name: "State"}, {Name: "CreatedAt"},
+			},
+		}
+		common.PrintDataTable[manila.AccessRule](&table, false)
+	},
+}
+var accessDelete = &cobra.Command{
+	Use:   "delete <share> <access id>",
+	Short: "Deny access to share",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		share, err := c.ManilaV2().Share().Find(args[0])
+		utility.LogError(err, "get share failed", true)
+		err = c.ManilaV2().Share().DenyAccess(share.Id, args[1])
+		utility.LogError(err, "deny access failed", true)
+		console.Info("requested to delete access rule %s", args[1])
+	},
+}
+
+func init() {
+	accessListPageFlags = flags.NewPageFlags(accessList)
+	accessCreate.Flags().String("access-level", "rw", "Access level, rw or ro")
+
+	access.AddCommand(accessList, accessCreate, accessDelete)
+}
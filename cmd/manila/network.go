@@ -0,0 +1,118 @@
+package manila
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/manila"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var network = &cobra.Command{Use: "network", Short: "Share networks"}
+
+func printShareNetwork(n manila.ShareNetwork) {
+	table := datatable.DataIterator[manila.ShareNetwork]{
+		Items: []manila.ShareNetwork{n},
+		Fields: []datatable.Field[manila.ShareNetwork]{
+			{Name: "Id"}, {Name: "Name"}, {Name: "Description"},
+			{Name: "NeutronNetId"}, {Name: "NeutronSubnetId"},
+			{Name: "NetworkType"}, {Name: "SegmentationId"}, {Name: "Cidr"},
+			{Name: "ProjectId"}, {Name: "CreatedAt"},
+		},
+	}
+	common.PrintDataTable[manila.ShareNetwork](&table, false)
+}
+
+var networkListPageFlags flags.PageFlags
+
+var networkList = &cobra.Command{
+	Use:   "list",
+	Short: "List share networks",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		c := openstack.DefaultClient()
+		networks, err := flags.ListPages(networkListPageFlags, nil, c.ManilaV2().ShareNetwork().Iterator)
+		utility.LogError(err, "list share networks failed", true)
+
+		table := datatable.DataTable[manila.ShareNetwork]{
+			Items: networks,
+			Columns: []datatable.Column[manila.ShareNetwork]{
+				{Name: "Id"}, {Name: "Name"}, {Name: "NeutronNetId"}, {Name: "NeutronSubnetId"},
+			},
+		}
+		common.PrintDataTable[manila.ShareNetwork](&table, false)
+	},
+}
+var networkShow = &cobra.Command{
+	Use:   "show <share network>",
+	Short: "Show share network",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		n, err := c.ManilaV2().ShareNetwork().Find(args[0])
+		utility.LogError(err, "get share network failed", true)
+		printShareNetwork(*n)
+	},
+}
+var networkCreate = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create share network",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		netIdOrName, _ := cmd.Flags().GetString("neutron-net")
+		subnetIdOrName, _ := cmd.Flags().GetString("neutron-subnet")
+		description, _ := cmd.Flags().GetString("description")
+
+		params := map[string]interface{}{"name": args[0]}
+		if description != "" {
+			params["description"] = description
+		}
+		if netIdOrName != "" {
+			net, err := c.NeutronV2().Network().Find(netIdOrName)
+			utility.LogError(err, "get network failed", true)
+			params["neutron_net_id"] = net.Id
+		}
+		if subnetIdOrName != "" {
+			subnet, err := c.NeutronV2().Subnet().Find(subnetIdOrName)
+			utility.LogError(err, "get subnet failed", true)
+			params["neutron_subnet_id"] = subnet.Id
+		}
+		n, err := c.ManilaV2().ShareNetwork().Create(params)
+		utility.LogError(err, "create share network failed", true)
+		printShareNetwork(*n)
+	},
+}
+var networkDelete = &cobra.Command{
+	Use:   "delete <share network> [<share network> ...]",
+	Short: "Delete share network(s)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		for _, idOrName := range args {
+			n, err := c.ManilaV2().ShareNetwork().Find(idOrName)
+			if err != nil {
+				utility.LogError(err, "get share network failed", false)
+				continue
+			}
+			if err := c.ManilaV2().ShareNetwork().Delete(n.Id); err != nil {
+				utility.LogIfError(err, false, "delete share network %s failed", idOrName)
+				continue
+			}
+			console.Info("requested to delete share network %s", idOrName)
+		}
+	},
+}
+
+func init() {
+	networkListPageFlags = flags.NewPageFlags(networkList)
+	networkCreate.Flags().String("neutron-net", "", "Neutron network id or name")
+	networkCreate.Flags().String("neutron-subnet", "", "Neutron subnet id or name")
+	networkCreate.Flags().String("description", "", "Share network description")
+
+	network.AddCommand(networkList, networkShow, networkCreate, networkDelete)
+}
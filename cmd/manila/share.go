@@ -0,0 +1,226 @@
+package manila
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/manila"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var Share = &cobra.Command{Use: "share", Short: "Shared file systems (Manila)"}
+
+var shareListPageFlags flags.PageFlags
+
+func printShare(client *openstack.Openstack, share manila.Share) {
+	locations, err := client.ManilaV2().Share().ExportLocations(share.Id)
+	utility.LogError(err, "get export locations failed", false)
+	paths := []string{}
+	for _, location := range locations {
+		paths = append(paths, location.Path)
+	}
+	table := datatable.DataIterator[manila.Share]{
+		Items: []manila.Share{share},
+		Fields: []datatable.Field[manila.Share]{
+			{Name: "Id"}, {Name: "Name"}, {Name: "Description"},
+			{Name: "Status", AutoColor: true},
+			{Name: "Size"}, {Name: "ShareProto"},
+			{Name: "ShareType"}, {Name: "ShareTypeName"},
+			{Name: "ShareNetworkId"}, {Name: "SnapshotId"},
+			{Name: "ExportLocations", RenderFunc: func(item manila.Share) any {
+				return strings.Join(paths, "\n")
+			}},
+			{Name: "AvailabilityZone"}, {Name: "Host"}, {Name: "IsPublic"},
+			{Name: "Metadata", Marshal: true},
+			{Name: "ProjectId"}, {Name: "CreatedAt"},
+		},
+	}
+	common.PrintDataTable[manila.Share](&table, false)
+}
+
+var shareList = &cobra.Command{
+	Use:   "list",
+	Short: "List shares",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		c := openstack.DefaultClient()
+		long, _ := cmd.Flags().GetBool("long")
+		name, _ := cmd.Flags().GetString("name")
+		status, _ := cmd.Flags().GetString("status")
+		all, _ := cmd.Flags().GetBool("all")
+		query := url.Values{}
+		if name != "" {
+			query.Set("name", name)
+		}
+		if status != "" {
+			query.Set("status", status)
+		}
+		if all {
+			query.Set("all_tenants", "1")
+		}
+		shares, err := flags.ListPages(shareListPageFlags, query, c.ManilaV2().Share().Iterator)
+		utility.LogError(err, "list shares failed", true)
+
+		table := datatable.DataTable[manila.Share]{
+			Items: shares,
+			Columns: []datatable.Column[manila.Share]{
+				{Name: "Id"}, {Name: "Name"}, {Name: "Status", AutoColor: true},
+				{Name: "Size"}, {Name: "ShareProto"}, {Name: "ShareTypeName"},
+			},
+			MoreColumns: []datatable.Column[manila.Share]{
+				{Name: "IsPublic"}, {Name: "AvailabilityZone"}, {Name: "Host"},
+				{Name: "ShareNetworkId"},
+			},
+		}
+		common.PrintDataTable[manila.Share](&table, long)
+	},
+}
+var shareShow = &cobra.Command{
+	Use:   "show <share>",
+	Short: "Show share",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		share, err := c.ManilaV2().Share().Find(args[0])
+		utility.LogError(err, "get share failed", true)
+		printShare(c, *share)
+	},
+}
+var shareCreate = &cobra.Command{
+	Use:   "create <protocol> <size>",
+	Short: "Create share",
+	Long:  fmt.Sprintf("Create share, protocol: %s", strings.Join(manila.SHARE_PROTOCOLS, ", ")),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(2)(cmd, args); err != nil {
+			return err
+		}
+		if !slices.Contains(manila.SHARE_PROTOCOLS, strings.ToUpper(args[0])) {
+			return fmt.Errorf("invalid protocol %s, valid: %v", args[0], manila.SHARE_PROTOCOLS)
+		}
+		if _, err := strconv.Atoi(args[1]); err != nil {
+			return fmt.Errorf("invalid size %s", args[1])
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		name, _ := cmd.Flags().GetString("name")
+		description, _ := cmd.Flags().GetString("description")
+		shareType, _ := cmd.Flags().GetString("share-type")
+		shareNetwork, _ := cmd.Flags().GetString("share-network")
+		snapshot, _ := cmd.Flags().GetString("snapshot")
+		az, _ := cmd.Flags().GetString("az")
+		public, _ := cmd.Flags().GetBool("public")
+
+		size, _ := strconv.Atoi(args[1])
+		params := map[string]interface{}{
+			"share_proto": strings.ToUpper(args[0]),
+			"size":        size,
+		}
+		if name != "" {
+			params["name"] = name
+		}
+		if description != "" {
+			params["description"] = description
+		}
+		if shareType != "" {
+			params["share_type"] = shareType
+		}
+		if shareNetwork != "" {
+			network, err := c.ManilaV2().ShareNetwork().Find(shareNetwork)
+			utility.LogError(err, "get share network failed", true)
+			params["share_network_id"] = network.Id
+		}
+		if snapshot != "" {
+			s, err := c.ManilaV2().Snapshot().Find(snapshot)
+			utility.LogError(err, "get snapshot failed", true)
+			params["snapshot_id"] = s.Id
+		}
+		if az != "" {
+			params["availability_zone"] = az
+		}
+		if public {
+			params["is_public"] = true
+		}
+		share, err := c.ManilaV2().Share().Create(params)
+		utility.LogError(err, "create share failed", true)
+		printShare(c, *share)
+	},
+}
+var shareDelete = &cobra.Command{
+	Use:   "delete <share> [<share> ...]",
+	Short: "Delete share(s)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		force, _ := cmd.Flags().GetBool("force")
+		for _, idOrName := range args {
+			share, err := c.ManilaV2().Share().Find(idOrName)
+			if err != nil {
+				utility.LogError(err, "get share failed", false)
+				continue
+			}
+			err = c.ManilaV2().Share().Delete(share.Id, force)
+			if err != nil {
+				utility.LogIfError(err, false, "delete share %s failed", idOrName)
+				continue
+			}
+			console.Info("requested to delete share %s", idOrName)
+		}
+	},
+}
+var shareExtend = &cobra.Command{
+	Use:   "extend <share> <new size>",
+	Short: "Extend share",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(2)(cmd, args); err != nil {
+			return err
+		}
+		if _, err := strconv.Atoi(args[1]); err != nil {
+			return fmt.Errorf("invalid size %s", args[1])
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		size, _ := strconv.Atoi(args[1])
+		share, err := c.ManilaV2().Share().Find(args[0])
+		utility.LogError(err, "get share failed", true)
+		err = c.ManilaV2().Share().Extend(share.Id, size)
+		utility.LogError(err, "extend share failed", true)
+		console.Info("requested to extend share %s to %dG", args[0], size)
+	},
+}
+
+func init() {
+	shareList.Flags().BoolP("long", "l", false, "List additional fields in output")
+	shareList.Flags().StringP("name", "n", "", "Search by share name")
+	shareList.Flags().String("status", "", "Search by share status")
+	shareList.Flags().BoolP("all", "a", false, "Display shares from all tenants")
+	shareListPageFlags = flags.NewPageFlags(shareList)
+
+	shareCreate.Flags().StringP("name", "n", "", "Share name")
+	shareCreate.Flags().String("description", "", "Share description")
+	shareCreate.Flags().String("share-type", "", "Share type id or name")
+	shareCreate.Flags().String("share-network", "", "Share network id or name")
+	shareCreate.Flags().String("snapshot", "", "Create share from the snapshot")
+	shareCreate.Flags().String("az", "", "Availability zone")
+	shareCreate.Flags().Bool("public", false, "Make the share visible to all projects")
+
+	shareDelete.Flags().Bool("force", false, "Force delete share in any state (admin only)")
+
+	Share.AddCommand(
+		shareList, shareShow, shareCreate, shareDelete, shareExtend,
+		access, snapshot, network, shareType,
+	)
+}
@@ -0,0 +1,128 @@
+package manila
+
+import (
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/manila"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var snapshot = &cobra.Command{Use: "snapshot", Short: "Share snapshots"}
+
+func printSnapshot(s manila.Snapshot) {
+	table := datatable.DataIterator[manila.Snapshot]{
+		Items: []manila.Snapshot{s},
+		Fields: []datatable.Field[manila.Snapshot]{
+			{Name: "Id"}, {Name: "Name"}, {Name: "Description"},
+			{Name: "Status", AutoColor: true},
+			{Name: "ShareId"}, {Name: "Size"}, {Name: "ShareSize"}, {Name: "ShareProto"},
+			{Name: "ProjectId"}, {Name: "CreatedAt"},
+		},
+	}
+	common.PrintDataTable[manila.Snapshot](&table, false)
+}
+
+var snapshotListPageFlags flags.PageFlags
+
+var snapshotList = &cobra.Command{
+	Use:   "list",
+	Short: "List share snapshots",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		c := openstack.DefaultClient()
+		shareIdOrName, _ := cmd.Flags().GetString("share")
+		query := url.Values{}
+		if shareIdOrName != "" {
+			share, err := c.ManilaV2().Share().Find(shareIdOrName)
+			utility.LogError(err, "get share failed", true)
+			query.Set("share_id", share.Id)
+		}
+		snapshots, err := flags.ListPages(snapshotListPageFlags, query, c.ManilaV2().Snapshot().Iterator)
+		utility.LogError(err, "list share snapshots failed", true)
+
+		table := datatable.DataTable[manila.Snapshot]{
+			Items: snapshots,
+			Columns: []datatable.Column[manila.Snapshot]{
+				{Name: "Id"}, {Name: "Name"}, {Name: "Status", AutoColor: true},
+				{Name: "ShareId"}, {Name: "Size"},
+			},
+		}
+		common.PrintDataTable[manila.Snapshot](&table, false)
+	},
+}
+var snapshotShow = &cobra.Command{
+	Use:   "show <snapshot>",
+	Short: "Show share snapshot",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		s, err := c.ManilaV2().Snapshot().Find(args[0])
+		utility.LogError(err, "get share snapshot failed", true)
+		printSnapshot(*s)
+	},
+}
+var snapshotCreate = &cobra.Command{
+	Use:   "create <share>",
+	Short: "Create share snapshot",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		name, _ := cmd.Flags().GetString("name")
+		description, _ := cmd.Flags().GetString("description")
+		force, _ := cmd.Flags().GetBool("force")
+
+		share, err := c.ManilaV2().Share().Find(args[0])
+		utility.LogError(err, "get share failed", true)
+		params := map[string]interface{}{"share_id": share.Id}
+		if name != "" {
+			params["name"] = name
+		}
+		if description != "" {
+			params["description"] = description
+		}
+		if force {
+			params["force"] = true
+		}
+		s, err := c.ManilaV2().Snapshot().Create(params)
+		utility.LogError(err, "create share snapshot failed", true)
+		printSnapshot(*s)
+	},
+}
+var snapshotDelete = &cobra.Command{
+	Use:   "delete <snapshot> [<snapshot> ...]",
+	Short: "Delete share snapshot(s)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		for _, idOrName := range args {
+			s, err := c.ManilaV2().Snapshot().Find(idOrName)
+			if err != nil {
+				utility.LogError(err, "get share snapshot failed", false)
+				continue
+			}
+			if err := c.ManilaV2().Snapshot().Delete(s.Id); err != nil {
+				utility.LogIfError(err, false, "delete share snapshot %s failed", idOrName)
+				continue
+			}
+			console.Info("requested to delete share snapshot %s", idOrName)
+		}
+	},
+}
+
+func init() {
+	snapshotListPageFlags = flags.NewPageFlags(snapshotList)
+	snapshotList.Flags().String("share", "", "Only list snapshots of the share")
+
+	snapshotCreate.Flags().StringP("name", "n", "", "Snapshot name")
+	snapshotCreate.Flags().String("description", "", "Snapshot description")
+	snapshotCreate.Flags().Bool("force", false, "Create snapshot even if the share is busy")
+
+	snapshot.AddCommand(snapshotList, snapshotShow, snapshotCreate, snapshotDelete)
+}
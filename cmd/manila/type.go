@@ -0,0 +1,109 @@
+package manila
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/manila"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var shareType = &cobra.Command{Use: "type", Short: "Share types"}
+
+var typeListPageFlags flags.PageFlags
+
+var typeList = &cobra.Command{
+	Use:   "list",
+	Short: "List share types",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		c := openstack.DefaultClient()
+		types, err := c.ManilaV2().ShareType().List(nil)
+		utility.LogError(err, "list share types failed", true)
+		types, err = flags.PageItems(typeListPageFlags, types,
+			func(shareType manila.ShareType) string { return shareType.Id })
+		utility.LogError(err, "list share types failed", true)
+
+		table := datatable.DataTable[manila.ShareType]{
+			Items: types,
+			Columns: []datatable.Column[manila.ShareType]{
+				{Name: "Id"}, {Name: "Name"}, {Name: "IsPublic"},
+				{Name: "ExtraSpecs", Marshal: true},
+			},
+		}
+		common.PrintDataTable[manila.ShareType](&table, false)
+	},
+}
+var typeCreate = &cobra.Command{
+	Use:   "create <name> <driver handles share servers>",
+	Short: "Create share type",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(2)(cmd, args); err != nil {
+			return err
+		}
+		if args[1] != "true" && args[1] != "false" {
+			return fmt.Errorf("invalid driver handles share servers %s, valid: true, false", args[1])
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		specs, _ := cmd.Flags().GetStringArray("extra-spec")
+		private, _ := cmd.Flags().GetBool("private")
+
+		extraSpecs := map[string]string{}
+		for _, spec := range specs {
+			kv := strings.SplitN(spec, "=", 2)
+			if len(kv) != 2 {
+				console.Fatal("invalid extra spec %s, expect key=value", spec)
+			}
+			extraSpecs[kv[0]] = kv[1]
+		}
+		t, err := c.ManilaV2().ShareType().Create(args[0], args[1] == "true", extraSpecs, !private)
+		utility.LogError(err, "create share type failed", true)
+
+		table := datatable.DataIterator[manila.ShareType]{
+			Items: []manila.ShareType{*t},
+			Fields: []datatable.Field[manila.ShareType]{
+				{Name: "Id"}, {Name: "Name"}, {Name: "IsPublic"},
+				{Name: "ExtraSpecs", Marshal: true},
+			},
+		}
+		common.PrintDataTable[manila.ShareType](&table, false)
+	},
+}
+var typeDelete = &cobra.Command{
+	Use:   "delete <share type> [<share type> ...]",
+	Short: "Delete share type(s)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		for _, idOrName := range args {
+			t, err := c.ManilaV2().ShareType().Find(idOrName)
+			if err != nil {
+				utility.LogError(err, "get share type failed", false)
+				continue
+			}
+			if err := c.ManilaV2().ShareType().Delete(t.Id); err != nil {
+				utility.LogIfError(err, false, "delete share type %s failed", idOrName)
+				continue
+			}
+			console.Info("requested to delete share type %s", idOrName)
+		}
+	},
+}
+
+func init() {
+	typeListPageFlags = flags.NewPageFlags(typeList)
+	typeCreate.Flags().StringArray("extra-spec", []string{}, "Extra spec, format: key=value")
+	typeCreate.Flags().Bool("private", false, "Make the share type private")
+
+	shareType.AddCommand(typeList, typeCreate, typeDelete)
+}
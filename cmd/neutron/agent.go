@@ -7,6 +7,7 @@ import (
 	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/spf13/cobra"
 
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
 	"github.com/BytemanD/skyman/openstack/model/neutron"
@@ -15,6 +16,8 @@ import (
 
 var agentCmd = &cobra.Command{Use: "agent", Short: "Network Agent comamnds"}
 
+var agentListPageFlags flags.PageFlags
+
 var agentList = &cobra.Command{
 	Use:   "list",
 	Short: "List agent",
@@ -32,7 +35,7 @@ var agentList = &cobra.Command{
 		if host != "" {
 			query.Set("host", host)
 		}
-		agents, err := c.Agent().List(query)
+		agents, err := flags.ListPages(agentListPageFlags, query, c.Agent().Iterator)
 		utility.LogError(err, "list ports failed", true)
 		pt := common.PrettyTable{
 			ShortColumns: []common.Column{
@@ -59,6 +62,7 @@ var agentList = &cobra.Command{
 func init() {
 	agentList.Flags().String("host", "", "filter by host")
 	agentList.Flags().String("binary", "", "filter by binary")
+	agentListPageFlags = flags.NewPageFlags(agentList)
 	agentCmd.AddCommand(agentList)
 	Network.AddCommand(agentCmd)
 }
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/cmd/views"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
@@ -18,6 +19,8 @@ import (
 
 var Network = &cobra.Command{Use: "network"}
 
+var networkListPageFlags flags.PageFlags
+
 var networkList = &cobra.Command{
 	Use:   "list",
 	Short: "List networks",
@@ -30,7 +33,7 @@ var networkList = &cobra.Command{
 		if name != "" {
 			query.Set("name", name)
 		}
-		networks, err := c.Network().List(query)
+		networks, err := flags.ListPages(networkListPageFlags, query, c.Network().Iterator)
 		if err != nil {
 			fmt.Println(err)
 		}
@@ -129,6 +132,7 @@ var networkCreate = &cobra.Command{
 func init() {
 	networkList.Flags().BoolP("long", "l", false, "List additional fields in output")
 	networkList.Flags().StringP("name", "n", "", "Search by router name")
+	networkListPageFlags = flags.NewPageFlags(networkList)
 
 	networkCreate.Flags().String("description", "", "Set network description")
 	networkCreate.Flags().Bool("disable", false, "Disable router")
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/spf13/cobra"
 
 	"github.com/BytemanD/easygo/pkg/syncutils"
 	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/views"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
 	"github.com/BytemanD/skyman/openstack/model/neutron"
@@ -19,6 +21,8 @@ import (
 
 var Port = &cobra.Command{Use: "port"}
 
+var portListPageFlags flags.PageFlags
+
 var portList = &cobra.Command{
 	Use:   "list",
 	Short: "List ports",
@@ -43,12 +47,12 @@ var portList = &cobra.Command{
 		host, _ := cmd.Flags().GetString("host")
 		noHost, _ := cmd.Flags().GetBool("no-host")
 
-		ports, err := c.Port().List(utility.UrlValues(map[string]string{
+		ports, err := flags.ListPages(portListPageFlags, utility.UrlValues(map[string]string{
 			"name":            name,
 			"network_id":      network,
 			"device_id":       device_id,
 			"binding:host_id": host,
-		}))
+		}), c.Port().Iterator)
 		utility.LogError(err, "list ports failed", true)
 		pt := common.PrettyTable{
 			ShortColumns: []common.Column{
@@ -145,6 +149,9 @@ var portDelete = &cobra.Command{
 	Use:   "delete <port> [port ...]",
 	Short: "Delete port(s)",
 	Args:  cobra.MinimumNArgs(1),
+	PostRun: func(cmd *cobra.Command, args []string) {
+		views.PrintRequestStats()
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		force, _ := cmd.Flags().GetBool("force")
 		c := openstack.DefaultClient().NeutronV2()
@@ -184,6 +191,7 @@ func init() {
 	portList.Flags().String("device-id", "", "Search by device id")
 	portList.Flags().String("host", "", "Search by binding host")
 	portList.Flags().Bool("no-host", false, "Search port with no host")
+	portListPageFlags = flags.NewPageFlags(portList)
 
 	portDelete.Flags().Bool("force", false, "Force delete")
 	Port.AddCommand(portList, portShow, portDelete)
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/url"
 
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
 	"github.com/BytemanD/skyman/openstack/model/neutron"
@@ -15,6 +16,8 @@ var Qos = &cobra.Command{Use: "qos"}
 
 var policy = &cobra.Command{Use: "policy"}
 
+var qosPolicyListPageFlags flags.PageFlags
+
 var qosPolicyList = &cobra.Command{
 	Use:   "list",
 	Short: "List qos policies",
@@ -32,7 +35,7 @@ var qosPolicyList = &cobra.Command{
 			query.Set("project_id", project.Id)
 		}
 
-		sgs, err := c.NeutronV2().QosPolicy().List(query)
+		sgs, err := flags.ListPages(qosPolicyListPageFlags, query, c.NeutronV2().QosPolicy().Iterator)
 		utility.LogError(err, "list qos policy failed", true)
 
 		pt := common.PrettyTable{
@@ -76,6 +79,7 @@ var qosPolicyShow = &cobra.Command{
 func init() {
 	// qosPolicyList.Flags().BoolP("long", "l", false, "List additional fields in output")
 	qosPolicyList.Flags().StringP("project", "", "", "List according to the project")
+	qosPolicyListPageFlags = flags.NewPageFlags(qosPolicyList)
 
 	policy.AddCommand(qosPolicyList, qosPolicyShow)
 	Qos.AddCommand(policy)
@@ -1,14 +1,18 @@
 package neutron
 
 import (
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/neutron"
 	"github.com/BytemanD/skyman/utility"
 	"github.com/spf13/cobra"
 )
 
 var qosRule = &cobra.Command{Use: "rule"}
 
+var qosRuleListPageFlags flags.PageFlags
+
 var qosRuleList = &cobra.Command{
 	Use:   "list <qos-policy>",
 	Short: "list qos rules",
@@ -18,6 +22,9 @@ var qosRuleList = &cobra.Command{
 
 		policy, err := c.NeutronV2().QosPolicy().Find(args[0])
 		utility.LogIfError(err, true, "get qos policy %s failed", args[0])
+		rules, err := flags.PageItems(qosRuleListPageFlags, policy.Rules,
+			func(rule neutron.QosRule) string { return rule.Id })
+		utility.LogIfError(err, true, "list rules of qos policy %s failed", args[0])
 
 		pt := common.PrettyTable{
 			ShortColumns: []common.Column{
@@ -29,12 +36,14 @@ var qosRuleList = &cobra.Command{
 				{Name: "MinKbps"},
 			},
 		}
-		pt.AddItems(policy.Rules)
+		pt.AddItems(rules)
 		common.PrintPrettyTable(pt, false)
 	},
 }
 
 func init() {
+	qosRuleListPageFlags = flags.NewPageFlags(qosRuleList)
+
 	qosRule.AddCommand(qosRuleList)
 	Qos.AddCommand(qosRule)
 }
@@ -4,6 +4,7 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/spf13/cobra"
@@ -18,6 +19,8 @@ import (
 
 var Router = &cobra.Command{Use: "router"}
 
+var routerListPageFlags flags.PageFlags
+
 var routerList = &cobra.Command{
 	Use:   "list",
 	Short: "List routers",
@@ -30,7 +33,7 @@ var routerList = &cobra.Command{
 		if name != "" {
 			query.Set("name", name)
 		}
-		routers, err := c.Router().List(query)
+		routers, err := flags.ListPages(routerListPageFlags, query, c.Router().Iterator)
 		utility.LogError(err, "list ports failed", true)
 		pt := common.PrettyTable{
 			ShortColumns: []common.Column{
@@ -181,6 +184,8 @@ var interfaceRemove = &cobra.Command{
 		}
 	},
 }
+var routerInterfaceListPageFlags flags.PageFlags
+
 var interfaceList = &cobra.Command{
 	Use:   "list <router>",
 	Short: "list router interfaces",
@@ -194,7 +199,7 @@ var interfaceList = &cobra.Command{
 
 		query := url.Values{}
 		query.Set("device_id", router.Id)
-		ports, err := c.Port().List(query)
+		ports, err := flags.ListPages(routerInterfaceListPageFlags, query, c.Port().Iterator)
 		utility.LogIfError(err, true, "list router ports failed")
 		pt := common.PrettyTable{
 			ShortColumns: []common.Column{
@@ -212,6 +217,8 @@ var interfaceList = &cobra.Command{
 func init() {
 	routerList.Flags().BoolP("long", "l", false, "List additional fields in output")
 	routerList.Flags().StringP("name", "n", "", "Search by router name")
+	routerListPageFlags = flags.NewPageFlags(routerList)
+	routerInterfaceListPageFlags = flags.NewPageFlags(interfaceList)
 
 	routerCreate.Flags().String("description", "", "Set router description")
 	routerCreate.Flags().Bool("disable", false, "Disable router")
@@ -4,6 +4,7 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/common/datatable"
 	"github.com/BytemanD/skyman/openstack"
@@ -17,6 +18,8 @@ var Security = &cobra.Command{Use: "security"}
 var group = &cobra.Command{Use: "group"}
 var SG = &cobra.Command{Use: "sg", Aliases: []string{"security group"}}
 
+var sgListPageFlags flags.PageFlags
+
 var sgList = &cobra.Command{
 	Use:   "list",
 	Short: "List security groups",
@@ -37,7 +40,7 @@ var sgList = &cobra.Command{
 		// result := c.NeutronV2().SecurityGroup().List2(query)
 		// sgs, err := result.Items()
 		// console.Debug("request id: %s", result.RequestId())
-		sgs, err := c.NeutronV2().SecurityGroup().List(query)
+		sgs, err := flags.ListPages(sgListPageFlags, query, c.NeutronV2().SecurityGroup().Iterator)
 		utility.LogError(err, "list security group failed", true)
 
 		table := datatable.DataTable[neutron.SecurityGroup]{
@@ -97,6 +100,7 @@ var sgShow = &cobra.Command{
 func init() {
 	sgList.Flags().BoolP("long", "l", false, "List additional fields in output")
 	sgList.Flags().StringP("project", "", "", "List according to the project")
+	sgListPageFlags = flags.NewPageFlags(sgList)
 
 	group.AddCommand(sgList, sgShow)
 	Security.AddCommand(group)
@@ -3,6 +3,7 @@ package neutron
 import (
 	"net/url"
 
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
 	"github.com/BytemanD/skyman/openstack/model/neutron"
@@ -12,6 +13,8 @@ import (
 
 var rule = &cobra.Command{Use: "rule"}
 
+var sgRuleListPageFlags flags.PageFlags
+
 var sgRuleList = &cobra.Command{
 	Use:   "list",
 	Short: "List security group rules",
@@ -28,7 +31,7 @@ var sgRuleList = &cobra.Command{
 			query.Set("security_group_id", sg.Id)
 		}
 
-		sgRules, err := c.NeutronV2().SecurityGroupRule().List(query)
+		sgRules, err := flags.ListPages(sgRuleListPageFlags, query, c.NeutronV2().SecurityGroupRule().Iterator)
 		utility.LogError(err, "list security group failed", true)
 
 		pt := common.PrettyTable{
@@ -88,6 +91,7 @@ var sgRuleShow = &cobra.Command{
 func init() {
 	sgRuleList.Flags().BoolP("long", "l", false, "List additional fields in output")
 	sgRuleList.Flags().StringP("security-group", "", "", "List according to the project")
+	sgRuleListPageFlags = flags.NewPageFlags(sgRuleList)
 
 	rule.AddCommand(sgRuleList, sgRuleShow)
 
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/cmd/views"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
@@ -16,6 +17,8 @@ import (
 
 var Subnet = &cobra.Command{Use: "subnet"}
 
+var subnetListPageFlags flags.PageFlags
+
 var subnetList = &cobra.Command{
 	Use:   "list",
 	Short: "List subnets",
@@ -28,7 +31,7 @@ var subnetList = &cobra.Command{
 		if name != "" {
 			query.Set("name", name)
 		}
-		subnets, err := c.Subnet().List(query)
+		subnets, err := flags.ListPages(subnetListPageFlags, query, c.Subnet().Iterator)
 		utility.LogError(err, "get subnets failed", true)
 
 		pt := common.PrettyTable{
@@ -128,6 +131,7 @@ var subnetDelete = &cobra.Command{
 func init() {
 	subnetList.Flags().BoolP("long", "l", false, "List additional fields in output")
 	subnetList.Flags().StringP("name", "n", "", "Search by router name")
+	subnetListPageFlags = flags.NewPageFlags(subnetList)
 
 	subnetCreate.Flags().String("description", "", "Set subnet description")
 	subnetCreate.Flags().String("network", "", "Set subnet description")
@@ -1,6 +1,7 @@
 package nova
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -56,6 +57,9 @@ var aggList = &cobra.Command{
 		} else {
 			filteredAggs = aggregates
 		}
+		filteredAggs, err = flags.PageItems(aggListFlags.PageFlags, filteredAggs,
+			func(agg nova.Aggregate) string { return strconv.Itoa(agg.Id) })
+		utility.LogError(err, "list aggregates failed", true)
 		pt.AddItems(filteredAggs)
 		common.PrintPrettyTable(pt, *aggListFlags.Long)
 	},
@@ -151,8 +155,9 @@ var removeHost = &cobra.Command{
 
 func init() {
 	aggListFlags = flags.AggregateListFlags{
-		Long: aggList.Flags().BoolP("long", "l", false, "List additional fields in output"),
-		Name: aggList.Flags().String("name", "", "List By aggregate name"),
+		PageFlags: flags.NewPageFlags(aggList),
+		Long:      aggList.Flags().BoolP("long", "l", false, "List additional fields in output"),
+		Name:      aggList.Flags().String("name", "", "List By aggregate name"),
 	}
 	aggCreateFlags = flags.AggregateCreateFlags{
 		AZ: aggCreate.Flags().String("az", "", "The availability zone of the aggregate"),
@@ -7,6 +7,7 @@ import (
 	"github.com/BytemanD/skyman/cmd/views"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/nova"
 	"github.com/BytemanD/skyman/utility"
 )
 
@@ -24,6 +25,9 @@ var azList = &cobra.Command{
 		client := openstack.DefaultClient()
 		azInfo, err := client.NovaV2().AZ().Detail(nil)
 		utility.LogError(err, "list availability zones failed", true)
+		azInfo, err = flags.PageItems(azListFlags.PageFlags, azInfo,
+			func(az nova.AvailabilityZone) string { return az.ZoneName })
+		utility.LogError(err, "list availability zones failed", true)
 
 		if *azListFlags.Tree {
 			views.PrintAZInfoTree(azInfo)
@@ -43,7 +47,8 @@ var azList = &cobra.Command{
 func init() {
 	// flavor list flags
 	azListFlags = flags.AZListFlags{
-		Tree: azList.Flags().Bool("tree", false, "Show tree view."),
+		PageFlags: flags.NewPageFlags(azList),
+		Tree:      azList.Flags().Bool("tree", false, "Show tree view."),
 	}
 
 	AZ.AddCommand(azList)
@@ -2,22 +2,30 @@ package nova
 
 import (
 	"net/url"
+	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/BytemanD/go-console/console"
 	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/cmd/views"
 	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
 	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/nova"
 	"github.com/BytemanD/skyman/utility"
 )
 
 var (
-	csListFlags    flags.ComputeServiceListFlags
-	csDisableFlags flags.ComputeServiceDisableFlags
+	csListFlags       flags.ComputeServiceListFlags
+	csDisableFlags    flags.ComputeServiceDisableFlags
+	hostDrainFlags    flags.ComputeHostDrainFlags
+	hostEvacuateFlags flags.ComputeHostDrainFlags
 )
 var Compute = &cobra.Command{Use: "compute"}
 var computeService = &cobra.Command{Use: "service"}
+var computeHost = &cobra.Command{Use: "host", Short: "Compute host maintenance"}
 
 var csList = &cobra.Command{
 	Use:   "list",
@@ -34,7 +42,11 @@ var csList = &cobra.Command{
 			query.Set("host", *csListFlags.Host)
 		}
 
-		services, _ := client.NovaV2().Service().List(query)
+		services, err := client.NovaV2().Service().List(query)
+		utility.LogError(err, "list compute services failed", true)
+		services, err = flags.PageItems(csListFlags.PageFlags, services,
+			func(service nova.Service) string { return service.Id })
+		utility.LogError(err, "list compute services failed", true)
 		pt := common.PrettyTable{
 			ShortColumns: []common.Column{
 				{Name: "Id"}, {Name: "Binary"},
@@ -118,14 +130,71 @@ var csDelete = &cobra.Command{
 	},
 }
 
+func printHostDrainReports(reports []openstack.HostDrainReport) {
+	table := datatable.DataTable[openstack.HostDrainReport]{
+		Items: reports,
+		Columns: []datatable.Column[openstack.HostDrainReport]{
+			{Name: "ServerId"}, {Name: "ServerName"}, {Name: "Status", AutoColor: true},
+			{Name: "Action"}, {Name: "Result", AutoColor: true},
+			{Name: "SourceHost"}, {Name: "DestHost"}, {Name: "Spend"}, {Name: "Error"},
+		},
+	}
+	common.PrintDataTable[openstack.HostDrainReport](&table, false)
+	for _, report := range reports {
+		if report.Failed() {
+			os.Exit(1)
+		}
+	}
+}
+func hostDrainOpt(drainFlags flags.ComputeHostDrainFlags) openstack.HostDrainOpt {
+	return openstack.HostDrainOpt{
+		Reason:   *drainFlags.Reason,
+		Parallel: *drainFlags.Parallel,
+		Timeout:  time.Second * time.Duration(*drainFlags.Timeout),
+		Interval: 5,
+	}
+}
+
+var hostDrain = &cobra.Command{
+	Use:   "drain <host>",
+	Short: "Disable compute service and migrate all servers out of the host",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		reports, err := client.DrainHost(client.Context(), args[0], hostDrainOpt(hostDrainFlags))
+		utility.LogIfError(err, true, "drain host %s failed", args[0])
+		if len(reports) == 0 {
+			console.Info("no server on host %s", args[0])
+			return
+		}
+		printHostDrainReports(reports)
+	},
+}
+var hostEvacuate = &cobra.Command{
+	Use:   "evacuate <host>",
+	Short: "Evacuate all servers of the host which is down",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		reports, err := client.EvacuateHost(client.Context(), args[0], hostDrainOpt(hostEvacuateFlags))
+		utility.LogIfError(err, true, "evacuate host %s failed", args[0])
+		if len(reports) == 0 {
+			console.Info("no server on host %s", args[0])
+			return
+		}
+		printHostDrainReports(reports)
+	},
+}
+
 func init() {
 	// compute service
 	csListFlags = flags.ComputeServiceListFlags{
-		Binary: csList.Flags().String("binary", "", "Search by binary"),
-		Host:   csList.Flags().String("host", "", "Search by hostname"),
-		Zone:   csList.Flags().String("zone", "", "Search by zone"),
-		State:  csList.Flags().StringArrayP("state", "s", nil, "Search by server status"),
-		Long:   csList.Flags().BoolP("long", "l", false, "List additional fields in output"),
+		PageFlags: flags.NewPageFlags(csList),
+		Binary:    csList.Flags().String("binary", "", "Search by binary"),
+		Host:      csList.Flags().String("host", "", "Search by hostname"),
+		Zone:      csList.Flags().String("zone", "", "Search by zone"),
+		State:     csList.Flags().StringArrayP("state", "s", nil, "Search by server status"),
+		Long:      csList.Flags().BoolP("long", "l", false, "List additional fields in output"),
 	}
 	csDisableFlags = flags.ComputeServiceDisableFlags{
 		Reason: csDisable.Flags().String("reason", "", "Reason"),
@@ -133,5 +202,18 @@ func init() {
 
 	computeService.AddCommand(csList, csEnable, csDisable, csUp, csDown, csDelete)
 
-	Compute.AddCommand(computeService)
+	// compute host
+	hostDrainFlags = flags.ComputeHostDrainFlags{
+		Reason:   hostDrain.Flags().String("reason", "host drain", "Reason for disabling the compute service"),
+		Parallel: hostDrain.Flags().Int("parallel", 2, "Number of servers to migrate at the same time"),
+		Timeout:  hostDrain.Flags().Int("timeout", 3600, "Timeout of each migration in seconds, 0 means no limit"),
+	}
+	hostEvacuateFlags = flags.ComputeHostDrainFlags{
+		Reason:   hostEvacuate.Flags().String("reason", "host evacuate", "Reason for disabling the compute service"),
+		Parallel: hostEvacuate.Flags().Int("parallel", 2, "Number of servers to evacuate at the same time"),
+		Timeout:  hostEvacuate.Flags().Int("timeout", 3600, "Timeout of each evacuation in seconds, 0 means no limit"),
+	}
+	computeHost.AddCommand(hostDrain, hostEvacuate)
+
+	Compute.AddCommand(computeService, computeHost)
 }
@@ -5,7 +5,9 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 
+	"github.com/BytemanD/go-console/console"
 	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
@@ -13,7 +15,8 @@ import (
 )
 
 var (
-	consoleLogFlags flags.ConsoleLogFlags
+	consoleLogFlags    flags.ConsoleLogFlags
+	consoleAttachFlags flags.ConsoleAttachFlags
 )
 var Console = &cobra.Command{Use: "console"}
 
@@ -57,23 +60,74 @@ var consoleUrl = &cobra.Command{
 
 		server, err := client.NovaV2().Server().Find(args[0])
 		utility.LogError(err, "get server failed", true)
-		console, err := client.NovaV2().Server().ConsoleUrl(server.Id, args[1])
+		serverConsole, err := client.NovaV2().Server().ConsoleUrl(server.Id, args[1])
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
 		pt := common.PrettyItemTable{
-			Item:        *console,
+			Item:        *serverConsole,
 			ShortFields: []common.Column{{Name: "Type"}, {Name: "Url"}},
 		}
 		common.PrintPrettyItemTable(pt)
 	},
 }
 
+var consoleAttach = &cobra.Command{
+	Use:   "attach <server>",
+	Short: "Attach to serial console of server",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+			return err
+		}
+		_, err := openstack.ParseConsoleEscape(*consoleAttachFlags.Escape)
+		return err
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		escape, _ := openstack.ParseConsoleEscape(*consoleAttachFlags.Escape)
+		opt := openstack.ConsoleAttachOpt{Escape: escape}
+
+		server, err := client.NovaV2().Server().Find(args[0])
+		utility.LogError(err, "get server failed", true)
+		if *consoleAttachFlags.Log != "" {
+			logFile, err := os.OpenFile(*consoleAttachFlags.Log, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+			utility.LogIfError(err, true, "open log file %s failed", *consoleAttachFlags.Log)
+			defer logFile.Close()
+			opt.Log = logFile
+		}
+		console.Info("attach to serial console of server %s, escape character is %s",
+			server.Name, *consoleAttachFlags.Escape)
+		// raw 模式下按键直接发送到虚拟机, 例如 Ctrl-C
+		stdin := int(os.Stdin.Fd())
+		var state *term.State
+		if term.IsTerminal(stdin) {
+			state, err = term.MakeRaw(stdin)
+			utility.LogError(err, "set terminal to raw mode failed", true)
+		}
+		err = client.AttachSerialConsole(server.Id, os.Stdin, os.Stdout, opt)
+		// 恢复终端后再输出日志
+		if state != nil {
+			term.Restore(stdin, state)
+			fmt.Println()
+		}
+		if err != nil {
+			console.Error("serial console of server %s disconnected: %s", server.Name, err)
+			return
+		}
+		console.Info("detached from serial console of server %s", server.Name)
+	},
+}
+
 func init() {
 	consoleLogFlags = flags.ConsoleLogFlags{
 		Lines: consoleLog.Flags().UintP("lines", "l", 0, "Number of lines to display from the end of the log"),
 	}
 
-	Console.AddCommand(consoleLog, consoleUrl)
+	consoleAttachFlags = flags.ConsoleAttachFlags{
+		Log:    consoleAttach.Flags().String("log", "", "Also write the console output to the file"),
+		Escape: consoleAttach.Flags().String("escape", "^]", "Escape character to detach, e.g. ^], ^O"),
+	}
+
+	Console.AddCommand(consoleLog, consoleUrl, consoleAttach)
 }
@@ -54,7 +54,7 @@ var flavorList = &cobra.Command{
 			query.Set("minDisk", strconv.FormatUint(*flavorListFlags.MinDisk, 10))
 		}
 		client := openstack.DefaultClient()
-		flavors, err := client.NovaV2().Flavor().Detail(query)
+		flavors, err := flags.ListPages(flavorListFlags.PageFlags, query, client.NovaV2().Flavor().Iterator)
 		utility.LogError(err, "get server failed %s", true)
 
 		filteredFlavors := []nova.Flavor{}
@@ -237,13 +237,14 @@ var flavorSet = &cobra.Command{
 
 func init() {
 	flavorListFlags = flags.FlavorListFlags{
-		Public:  flavorList.Flags().Bool("public", false, "List public flavors"),
-		Name:    flavorList.Flags().StringP("name", "n", "", "Show flavors matched by name (local)"),
-		MinVcpu: flavorList.Flags().Uint64("min-vcpu", 0, "Filters the flavors by a minimum vcpu (local)"),
-		MinRam:  flavorList.Flags().Uint64("min-ram", 0, "Filters the flavors by a minimum RAM, in MB."),
-		MinDisk: flavorList.Flags().Uint64("min-disk", 0, "Filters the flavors by a minimum disk space, in GiB."),
-		Long:    flavorList.Flags().BoolP("long", "l", false, "List additional fields in output"),
-		Human:   flavorList.Flags().Bool("human", false, " Print ram like 1M 2G etc"),
+		Public:    flavorList.Flags().Bool("public", false, "List public flavors"),
+		Name:      flavorList.Flags().StringP("name", "n", "", "Show flavors matched by name (local)"),
+		MinVcpu:   flavorList.Flags().Uint64("min-vcpu", 0, "Filters the flavors by a minimum vcpu (local)"),
+		MinRam:    flavorList.Flags().Uint64("min-ram", 0, "Filters the flavors by a minimum RAM, in MB."),
+		MinDisk:   flavorList.Flags().Uint64("min-disk", 0, "Filters the flavors by a minimum disk space, in GiB."),
+		Long:      flavorList.Flags().BoolP("long", "l", false, "List additional fields in output"),
+		Human:     flavorList.Flags().Bool("human", false, " Print ram like 1M 2G etc"),
+		PageFlags: flags.NewPageFlags(flavorList),
 	}
 	flavorCreateFlags = flags.FlavorCreateFlags{
 		Id:         flavorCreate.Flags().String("id", "", "Unique flavor ID, creates a UUID if empty"),
@@ -33,7 +33,7 @@ var hypervisorList = &cobra.Command{
 		if *hypervisorListFlags.Name != "" {
 			query.Set("hypervisor_hostname_pattern", *hypervisorListFlags.Name)
 		}
-		hypervisors, err := client.NovaV2().Hypervisor().Detail(query)
+		hypervisors, err := flags.ListPages(hypervisorListFlags.PageFlags, query, client.NovaV2().Hypervisor().Iterator)
 		utility.LogError(err, "list hypervisors failed", true)
 		pt := common.PrettyTable{
 			ShortColumns: []common.Column{
@@ -131,6 +131,24 @@ var hypervisorShow = &cobra.Command{
 			},
 			Item: *hypervisor,
 		}
+		if baremetal, _ := cmd.Flags().GetBool("baremetal"); baremetal {
+			node, err := client.FindHypervisorNode(*hypervisor)
+			utility.LogError(err, "get baremetal node failed", true)
+			pt.ShortFields = append(pt.ShortFields,
+				common.Column{Name: "BaremetalNode", Slot: func(item interface{}) interface{} {
+					return fmt.Sprintf("%s(%s)", node.Uuid, node.Name)
+				}},
+				common.Column{Name: "ProvisionState", Slot: func(item interface{}) interface{} {
+					return node.ProvisionState
+				}},
+				common.Column{Name: "PowerState", Slot: func(item interface{}) interface{} {
+					return node.PowerState
+				}},
+				common.Column{Name: "Maintenance", Slot: func(item interface{}) interface{} {
+					return node.Maintenance
+				}},
+			)
+		}
 		common.PrintPrettyItemTable(pt)
 	},
 }
@@ -164,6 +182,8 @@ func init() {
 		Type:        hypervisorList.Flags().StringP("type", "t", "", "Filte hypervisors by type"),
 		WithServers: hypervisorList.Flags().Bool("with-servers", false, "List hypervisors with servers"),
 		Long:        hypervisorList.Flags().BoolP("long", "l", false, "List additional fields in output"),
+		PageFlags:   flags.NewPageFlags(hypervisorList),
 	}
+	hypervisorShow.Flags().Bool("baremetal", false, "Show the Ironic node of the hypervisor")
 	Hypervisor.AddCommand(hypervisorList, hypervisorShow, hypervisorUptime)
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
 	"github.com/BytemanD/skyman/openstack/model/nova"
@@ -32,6 +33,8 @@ func printinterfaceAttachments(items []nova.InterfaceAttachment) {
 	common.PrintPrettyTable(dataListTable, false)
 }
 
+var interfaceListPageFlags flags.PageFlags
+
 var interfaceList = &cobra.Command{
 	Use:   "list <server>",
 	Short: "List server interfaces",
@@ -45,6 +48,9 @@ var interfaceList = &cobra.Command{
 			fmt.Println(err)
 			os.Exit(1)
 		}
+		attachments, err = flags.PageItems(interfaceListPageFlags, attachments,
+			func(attachment nova.InterfaceAttachment) string { return attachment.PortId })
+		utility.LogIfError(err, true, "list interfaces of server %s failed", args[0])
 		printinterfaceAttachments(attachments)
 	},
 }
@@ -102,6 +108,8 @@ var interfaceDetach = &cobra.Command{
 }
 
 func init() {
+	interfaceListPageFlags = flags.NewPageFlags(interfaceList)
+
 	serverInterface.AddCommand(
 		interfaceList, interfaceAttachNet, interfaceAttachPort,
 		interfaceDetach,
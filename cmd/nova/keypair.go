@@ -1,12 +1,25 @@
 package nova
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/spf13/cobra"
 
+	"github.com/BytemanD/easygo/pkg/stringutils"
 	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
 	"github.com/BytemanD/skyman/openstack/model/nova"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var (
+	keypairListPageFlags flags.PageFlags
+	keypairCreateFlags   flags.KeypairCreateFlags
+	keypairImportFlags   flags.KeypairImportFlags
 )
 
 var Keypair = &cobra.Command{Use: "keypair"}
@@ -16,7 +29,7 @@ var keypairList = &cobra.Command{
 	Short: "List keypairs",
 	Run: func(_ *cobra.Command, _ []string) {
 		client := openstack.DefaultClient()
-		keypairs, err := client.NovaV2().Keypair().List(nil)
+		keypairs, err := flags.ListPages(keypairListPageFlags, nil, client.NovaV2().Keypair().Iterator)
 		if err != nil {
 			console.Fatal("%s", err)
 		}
@@ -41,6 +54,128 @@ var keypairList = &cobra.Command{
 	},
 }
 
+func printKeypair(keypair nova.Keypair) {
+	pt := common.PrettyItemTable{
+		ShortFields: []common.Column{
+			{Name: "Name", Slot: func(item interface{}) interface{} {
+				p, _ := item.(nova.Keypair)
+				return p.Keypair.Name
+			}},
+			{Name: "Type", Slot: func(item interface{}) interface{} {
+				p, _ := item.(nova.Keypair)
+				return p.Keypair.Type
+			}},
+			{Name: "Fingerprint", Slot: func(item interface{}) interface{} {
+				p, _ := item.(nova.Keypair)
+				return p.Keypair.Fingerprint
+			}},
+			{Name: "UserId", Slot: func(item interface{}) interface{} {
+				p, _ := item.(nova.Keypair)
+				return p.Keypair.UserId
+			}},
+			{Name: "CreatedAt", Slot: func(item interface{}) interface{} {
+				p, _ := item.(nova.Keypair)
+				return p.Keypair.CreatedAt
+			}},
+			{Name: "PublicKey", Slot: func(item interface{}) interface{} {
+				p, _ := item.(nova.Keypair)
+				return p.Keypair.PublicKey
+			}},
+		},
+		Item: keypair,
+	}
+	common.PrintPrettyItemTable(pt)
+}
+
+var keypairCreate = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create keypair, the private key is generated locally",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+			return err
+		}
+		if !stringutils.ContainsString(openstack.KEY_ALGORITHMS, *keypairCreateFlags.Type) {
+			return fmt.Errorf("invalid type %s, valid: %v", *keypairCreateFlags.Type, openstack.KEY_ALGORITHMS)
+		}
+		return nil
+	},
+	Run: func(_ *cobra.Command, args []string) {
+		privateKeyFile := *keypairCreateFlags.PrivateKey
+		if privateKeyFile == "" {
+			privateKeyFile = args[0] + ".pem"
+		}
+		// 先检查私钥文件, 避免密钥对创建后私钥无法保存
+		if _, err := os.Stat(privateKeyFile); err == nil {
+			console.Fatal("private key file %s already exists", privateKeyFile)
+		}
+		client := openstack.DefaultClient()
+		keypair, err := client.CreateKeypair(args[0], *keypairCreateFlags.Type, *keypairCreateFlags.Bits)
+		utility.LogError(err, "Create keypair failed", true)
+
+		err = openstack.SavePrivateKey(privateKeyFile, keypair.Keypair.PrivateKey)
+		if err != nil {
+			fmt.Println(keypair.Keypair.PrivateKey)
+			utility.LogIfError(err, true, "save private key to %s failed", privateKeyFile)
+		}
+		printKeypair(*keypair)
+		console.Info("private key saved to %s", privateKeyFile)
+	},
+}
+var keypairImport = &cobra.Command{
+	Use:   "import <name>",
+	Short: "Import keypair from a public key file",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		content, err := os.ReadFile(*keypairImportFlags.PublicKey)
+		utility.LogIfError(err, true, "read public key %s failed", *keypairImportFlags.PublicKey)
+
+		client := openstack.DefaultClient()
+		keypair, err := client.NovaV2().Keypair().Create(
+			args[0], strings.TrimSpace(string(content)), nova.KEYPAIR_TYPE_SSH)
+		utility.LogError(err, "Import keypair failed", true)
+		printKeypair(*keypair)
+	},
+}
+var keypairShow = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show keypair",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		keypair, err := client.NovaV2().Keypair().Show(args[0])
+		utility.LogError(err, "Get keypair failed", true)
+		printKeypair(*keypair)
+	},
+}
+var keypairDelete = &cobra.Command{
+	Use:   "delete <name1> [name2 ...]",
+	Short: "Delete keypair(s)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		for _, name := range args {
+			if err := client.NovaV2().Keypair().Delete(name); err != nil {
+				utility.LogError(err, "Delete keypair failed", false)
+				continue
+			}
+			fmt.Printf("Keypair %s deleted\n", name)
+		}
+	},
+}
+
 func init() {
-	Keypair.AddCommand(keypairList)
+	keypairListPageFlags = flags.NewPageFlags(keypairList)
+	keypairCreateFlags = flags.KeypairCreateFlags{
+		Type: keypairCreate.Flags().String("type", openstack.KEY_ALGORITHM_ED25519,
+			fmt.Sprintf("Key algorithm, valid: %v", openstack.KEY_ALGORITHMS)),
+		Bits: keypairCreate.Flags().Int("bits", openstack.DEFAULT_RSA_BITS, "Number of bits of RSA key"),
+		PrivateKey: keypairCreate.Flags().String("private-key", "",
+			"File to save the private key (default <name>.pem)"),
+	}
+	keypairImportFlags = flags.KeypairImportFlags{
+		PublicKey: keypairImport.Flags().String("public-key", "", "Public key file, e.g. ~/.ssh/id_ed25519.pub"),
+	}
+	keypairImport.MarkFlagRequired("public-key")
+
+	Keypair.AddCommand(keypairList, keypairCreate, keypairImport, keypairShow, keypairDelete)
 }
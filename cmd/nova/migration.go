@@ -36,7 +36,7 @@ var migrationList = &cobra.Command{
 		if *migrationListFlags.Type != "" {
 			query.Set("migration_type", *migrationListFlags.Type)
 		}
-		migrations, err := client.NovaV2().Migration().List(query)
+		migrations, err := flags.ListPages(migrationListFlags.PageFlags, query, client.NovaV2().Migration().Iterator)
 		if err != nil {
 			console.Fatal("%s", err)
 		}
@@ -69,7 +69,8 @@ func init() {
 		Instance: migrationList.Flags().String("instance", "", "List migration matched by instance uuid"),
 		Type:     migrationList.Flags().String("type", "", "List migration matched by migration type"),
 
-		Long: migrationList.Flags().BoolP("long", "l", false, "List additional fields in output"),
+		Long:      migrationList.Flags().BoolP("long", "l", false, "List additional fields in output"),
+		PageFlags: flags.NewPageFlags(migrationList),
 	}
 
 	Migration.AddCommand(migrationList)
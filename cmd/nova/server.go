@@ -38,15 +38,19 @@ func refreshServers(c *openstack.Openstack, filterHosts []string, host, az strin
 				continue
 			}
 			query.Set("host", h)
-			tmpItems, err := c.NovaV2().Server().Detail(query)
+			tmpItems, err := flags.ListPages(listFlags.PageFlags, query, c.NovaV2().Server().Iterator)
 			utility.LogError(err, "list servers failed", true)
 			items = append(items, tmpItems...)
 		}
 	} else {
-		tmpItems, err := c.NovaV2().Server().Detail(query)
+		tmpItems, err := flags.ListPages(listFlags.PageFlags, query, c.NovaV2().Server().Iterator)
 		utility.LogError(err, "list servers failed", true)
 		items = append(items, tmpItems...)
 	}
+	// nova 不支持按 metadata 过滤
+	if metadata := parseKeyValues(*listFlags.Metadata); len(metadata) > 0 {
+		items = utility.Filter(items, func(s nova.Server) bool { return s.MatchMetadata(metadata) })
+	}
 	return items
 }
 
@@ -82,6 +86,12 @@ var serverList = &cobra.Command{
 		for _, status := range *listFlags.Status {
 			query.Add("status", status)
 		}
+		if *listFlags.Tags != "" {
+			query.Set("tags", *listFlags.Tags)
+		}
+		if *listFlags.NotTags != "" {
+			query.Set("not-tags", *listFlags.NotTags)
+		}
 		if *listFlags.Flavor != "" {
 			flavor, err := c.NovaV2().Flavor().Find(*listFlags.Flavor, false)
 			if err != nil {
@@ -259,6 +269,9 @@ var serverCreate = &cobra.Command{
 		if *createFlags.VolumeBoot && *createFlags.VolumeSize == 0 {
 			return fmt.Errorf("invalid flags: --volume-size is required when --volume-boot is true")
 		}
+		if *createFlags.DnsZone != "" && *createFlags.Max > 1 {
+			return fmt.Errorf("invalid flags: --dns-zone is not supported when --max > 1")
+		}
 
 		for _, nic := range *createFlags.Nic {
 			values := strings.Split(nic, "=")
@@ -289,6 +302,11 @@ var serverCreate = &cobra.Command{
 		if *createFlags.AdminPass != "" {
 			createOption.AdminPass = *createFlags.AdminPass
 		}
+		if *createFlags.Group != "" {
+			group, err := client.NovaV2().ServerGroup().Find(*createFlags.Group)
+			utility.LogError(err, "get server group failed", true)
+			createOption.SchedulerHints = map[string]interface{}{"group": group.Id}
+		}
 
 		if *createFlags.VolumeBoot {
 			createOption.BlockDeviceMappingV2 = []nova.BlockDeviceMappingV2{
@@ -323,12 +341,17 @@ var serverCreate = &cobra.Command{
 			os.Exit(1)
 		}
 		views.PrintServer(*server, nil)
-		if *createFlags.Wait {
-			_, err := client.NovaV2().Server().WaitStatus(server.Id, "ACTIVE", 5)
+		// 注册 DNS 记录需要等待虚拟机分配 IP 地址
+		if *createFlags.Wait || *createFlags.DnsZone != "" {
+			activeServer, err := client.NovaV2().Server().WaitStatus(server.Id, "ACTIVE", 5)
 			if err != nil {
 				console.Error("Server %s create failed, %v", server.Id, err)
-			} else {
-				console.Info("Server %s created", server.Id)
+				return
+			}
+			console.Info("Server %s created", server.Id)
+			if *createFlags.DnsZone != "" {
+				_, err := client.RegisterServerRecords(*createFlags.DnsZone, *activeServer, 0)
+				utility.LogIfError(err, false, "register DNS records of server %s failed", server.Id)
 			}
 		}
 	},
@@ -348,6 +371,10 @@ var serverDelete = &cobra.Command{
 				utility.LogError(err, fmt.Sprintf("found server %s failed", idOrName), false)
 				continue
 			}
+			if *deleteFlags.DnsZone != "" {
+				_, err := client.UnregisterServerRecords(*deleteFlags.DnsZone, *s)
+				utility.LogIfError(err, false, "unregister DNS records of server %s failed", idOrName)
+			}
 			err = client.NovaV2().Server().Delete(s.Id)
 			if err != nil {
 				utility.LogError(err, "delete server %s failed %s", false)
@@ -881,6 +908,9 @@ var serverMigrationList = &cobra.Command{
 			if err != nil {
 				console.Error("Reqeust to list server migration failed, %v", err)
 			}
+			migrations, err = flags.PageItems(serverMigrationListFlags.PageFlags, migrations,
+				func(migration nova.Migration) string { return fmt.Sprint(migration.Id) })
+			utility.LogError(err, "list server migrations failed", true)
 			table.CleanItems()
 			table.AddItems(migrations)
 			if *serverMigrationListFlags.Watch {
@@ -944,10 +974,14 @@ func init() {
 		Verbose:       serverList.Flags().BoolP("verbose", "v", false, "List verbose fields in output"),
 		Dsc:           serverList.Flags().Bool("dsc", false, "Sort name by dsc"),
 		Search:        serverList.Flags().String("search", "", i18n.T("localFuzzySearch")),
+		Tags:          serverList.Flags().String("tags", "", "Search by tags, split with ',', servers with all of the tags are listed"),
+		NotTags:       serverList.Flags().String("not-tags", "", "Exclude servers with all of the tags, split with ','"),
+		Metadata:      serverList.Flags().StringArray("metadata", nil, "Search by metadata, format: key=value (local)"),
 		Watch:         serverList.Flags().Bool("watch", false, "List loop"),
 		WatchInterval: serverList.Flags().UintP("watch-interval", "i", 2, "Loop interval"),
 		Fields:        serverList.Flags().String("fields", "", "Show specified fields"),
 		Long:          serverList.Flags().BoolP("long", "l", false, "List additional fields in output"),
+		PageFlags:     flags.NewPageFlags(serverList),
 	}
 	createFlags = flags.ServerCreateFlags{
 		Flavor:     serverCreate.Flags().String("flavor", "", "Create server with this flavor"),
@@ -963,13 +997,16 @@ func init() {
 		KeyName:    serverCreate.Flags().String("key-name", "", "Keypair to inject into this server."),
 		AdminPass:  serverCreate.Flags().String("admin-pass", "", "Admin password for the instance."),
 		Wait:       serverCreate.Flags().BoolP("wait", "w", false, "Wait server created"),
+		DnsZone:    serverCreate.Flags().String("dns-zone", "", "Register A/AAAA records of the server in this DNS zone"),
+		Group:      serverCreate.Flags().String("group", "", "Server group (name or ID) to launch the server in."),
 	}
 
 	serverCreate.MarkFlagRequired("flavor")
 	serverCreate.MarkFlagRequired("image")
 
 	deleteFlags = flags.ServerDeleteFlags{
-		Wait: serverDelete.Flags().BoolP("wait", "w", false, "Wait server rebooted"),
+		Wait:    serverDelete.Flags().BoolP("wait", "w", false, "Wait server rebooted"),
+		DnsZone: serverDelete.Flags().String("dns-zone", "", "Remove A/AAAA records of the server from this DNS zone"),
 	}
 	rebootFlags = flags.ServerRebootFlags{
 		Hard: serverReboot.Flags().Bool("hard", false, "Perform a hard reboot"),
@@ -1005,6 +1042,7 @@ func init() {
 	serverRegion.AddCommand(serverRegionLiveMigrate)
 
 	serverMigrationListFlags = flags.ServerMigrationListFlags{
+		PageFlags:     flags.NewPageFlags(serverMigrationList),
 		Status:        serverMigrationList.Flags().String("status", "", "List migration matched by status"),
 		Type:          serverMigrationList.Flags().String("type", "", "List migration matched by type"),
 		Latest:        serverMigrationList.Flags().Bool("latest", false, "List latest migrations"),
@@ -1,11 +1,17 @@
 package nova
 
 import (
+	"fmt"
 	"net/url"
+	"os"
+	"strconv"
 	"strings"
 
+	"github.com/BytemanD/easygo/pkg/stringutils"
+	"github.com/BytemanD/go-console/console"
 	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
 	"github.com/BytemanD/skyman/openstack"
 	"github.com/BytemanD/skyman/openstack/model/nova"
 	"github.com/BytemanD/skyman/utility"
@@ -13,7 +19,8 @@ import (
 )
 
 var (
-	groupListFlags flags.GroupListFlags
+	groupListFlags   flags.GroupListFlags
+	groupCreateFlags flags.GroupCreateFlags
 )
 
 var Group = &cobra.Command{Use: "group"}
@@ -29,17 +36,24 @@ var groupList = &cobra.Command{
 
 		serverGroups, err := client.NovaV2().ServerGroup().List(query)
 		utility.LogError(err, "Get server groups failed", true)
+		serverGroups, err = flags.PageItems(groupListFlags.PageFlags, serverGroups,
+			func(group nova.ServerGroup) string { return group.Id })
+		utility.LogError(err, "Get server groups failed", true)
 
 		pt := common.PrettyTable{
 			ShortColumns: []common.Column{
 				{Name: "Id"},
 				{Name: "Name", Sort: true},
-				{Name: "Policies", Slot: func(item interface{}) interface{} {
+				{Name: "Policy", Slot: func(item interface{}) interface{} {
 					p, _ := item.(nova.ServerGroup)
-					return strings.Join(p.Policies, "\n")
+					return p.GetPolicy()
 				}},
 			},
 			LongColumns: []common.Column{
+				{Name: "Rules", Slot: func(item interface{}) interface{} {
+					p, _ := item.(nova.ServerGroup)
+					return strings.Join(p.GetRulesList(), "\n")
+				}},
 				{Name: "Custom"},
 				{Name: "Members", Slot: func(item interface{}) interface{} {
 					p, _ := item.(nova.ServerGroup)
@@ -63,10 +77,137 @@ var groupList = &cobra.Command{
 	},
 }
 
+func printServerGroup(group nova.ServerGroup) {
+	pt := common.PrettyItemTable{
+		ShortFields: []common.Column{
+			{Name: "Id"}, {Name: "Name"},
+			{Name: "Policy", Slot: func(item interface{}) interface{} {
+				p, _ := item.(nova.ServerGroup)
+				return p.GetPolicy()
+			}},
+			{Name: "Rules", Slot: func(item interface{}) interface{} {
+				p, _ := item.(nova.ServerGroup)
+				return strings.Join(p.GetRulesList(), "\n")
+			}},
+			{Name: "Members", Slot: func(item interface{}) interface{} {
+				p, _ := item.(nova.ServerGroup)
+				return strings.Join(p.Members, "\n")
+			}},
+			{Name: "ProjectId"}, {Name: "UserId"},
+		},
+		Item: group,
+	}
+	common.PrintPrettyItemTable(pt)
+}
+
+// 解析 key=value 格式的规则, 数字类型的值 (例如 max_server_per_host) 转换为整数
+func parseGroupRules(items []string) map[string]interface{} {
+	rules := map[string]interface{}{}
+	for k, v := range parseKeyValues(items) {
+		if value, err := strconv.Atoi(v); err == nil {
+			rules[k] = value
+		} else {
+			rules[k] = v
+		}
+	}
+	return rules
+}
+
+var groupCreate = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create server group",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+			return err
+		}
+		if !stringutils.ContainsString(nova.GROUP_POLICIES, *groupCreateFlags.Policy) {
+			return fmt.Errorf("invalid policy %s, valid: %v", *groupCreateFlags.Policy, nova.GROUP_POLICIES)
+		}
+		return nil
+	},
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		group, err := client.NovaV2().ServerGroup().Create(
+			args[0], *groupCreateFlags.Policy, parseGroupRules(*groupCreateFlags.Rule))
+		utility.LogError(err, "Create server group failed", true)
+		printServerGroup(*group)
+	},
+}
+var groupShow = &cobra.Command{
+	Use:   "show <group>",
+	Short: "Show server group",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		group, err := client.NovaV2().ServerGroup().Find(args[0])
+		utility.LogError(err, "Get server group failed", true)
+		printServerGroup(*group)
+	},
+}
+var groupDelete = &cobra.Command{
+	Use:   "delete <group1> [group2 ...]",
+	Short: "Delete server group(s)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		groupApi := client.NovaV2().ServerGroup()
+		for _, idOrName := range args {
+			group, err := groupApi.Find(idOrName)
+			if err != nil {
+				utility.LogError(err, "Get server group failed", false)
+				continue
+			}
+			if err := groupApi.Delete(group.Id); err != nil {
+				utility.LogError(err, "Delete server group failed", false)
+				continue
+			}
+			fmt.Printf("Server group %s deleted\n", idOrName)
+		}
+	},
+}
+var groupCheck = &cobra.Command{
+	Use:   "check <group>",
+	Short: "Check whether placements of the members still satisfy the policy",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		report, err := client.CheckServerGroup(args[0])
+		utility.LogIfError(err, true, "check server group %s failed", args[0])
+
+		table := datatable.DataTable[openstack.ServerGroupMember]{
+			Items: report.Members,
+			Columns: []datatable.Column[openstack.ServerGroupMember]{
+				{Name: "ServerId"}, {Name: "ServerName"}, {Name: "Status", AutoColor: true}, {Name: "Host"}, {Name: "HostId"},
+			},
+		}
+		common.PrintDataTable[openstack.ServerGroupMember](&table, false)
+		if report.Satisfied() {
+			console.Info("server group %s (%s) satisfies the policy", report.Group.Name, report.Group.GetPolicy())
+			return
+		}
+		for _, violation := range report.Violations {
+			console.Error("server group %s (%s): %s", report.Group.Name, report.Group.GetPolicy(), violation)
+		}
+		if len(report.Unknown) > 0 {
+			console.Warn("server group %s (%s): placement of members %s is unknown", report.Group.Name,
+				report.Group.GetPolicy(), strings.Join(report.Unknown, ", "))
+		}
+		os.Exit(1)
+	},
+}
+
 func init() {
 	groupListFlags = flags.GroupListFlags{
-		Long: groupList.Flags().BoolP("long", "l", false, "List additional fields in output"),
+		PageFlags: flags.NewPageFlags(groupList),
+		Long:      groupList.Flags().BoolP("long", "l", false, "List additional fields in output"),
 	}
-	Group.AddCommand(groupList)
+	groupCreateFlags = flags.GroupCreateFlags{
+		Policy: groupCreate.Flags().String("policy", nova.GROUP_ANTI_AFFINITY,
+			fmt.Sprintf("Policy of the server group, valid: %v", nova.GROUP_POLICIES)),
+		Rule: groupCreate.Flags().StringArray("rule", []string{},
+			"Rule of the server group, e.g. max_server_per_host=2, require microversion 2.64 or later"),
+	}
+
+	Group.AddCommand(groupList, groupCreate, groupShow, groupDelete, groupCheck)
 	Server.AddCommand(Group)
 }
@@ -0,0 +1,151 @@
+package nova
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/utility"
+)
+
+type keyValue struct {
+	Key   string
+	Value string
+}
+
+// 解析 key=value 格式的参数
+func parseKeyValues(items []string) map[string]string {
+	values := map[string]string{}
+	for _, item := range items {
+		kv, err := common.SplitKeyValue(item)
+		if err != nil {
+			console.Fatal("invalid %s, must be: key=value", item)
+		}
+		values[kv[0]] = kv[1]
+	}
+	return values
+}
+
+func printMetadata(metadata map[string]string) {
+	items := []keyValue{}
+	for k, v := range metadata {
+		items = append(items, keyValue{Key: k, Value: v})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Key < items[j].Key })
+	table := datatable.DataTable[keyValue]{
+		Items:   items,
+		Columns: []datatable.Column[keyValue]{{Name: "Key"}, {Name: "Value"}},
+	}
+	common.PrintDataTable[keyValue](&table, false)
+}
+
+var serverMetadata = &cobra.Command{Use: "metadata", Short: "Server metadata"}
+var serverMetadataList = &cobra.Command{
+	Use:   "list <server>",
+	Short: "List server metadata",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		server, err := client.NovaV2().Server().Find(args[0])
+		utility.LogError(err, "get server failed", true)
+		metadata, err := client.NovaV2().Server().ListMetadata(server.Id)
+		utility.LogError(err, "list server metadata failed", true)
+		printMetadata(metadata)
+	},
+}
+var serverMetadataSet = &cobra.Command{
+	Use:   "set <server> <key=value> [<key=value> ...]",
+	Short: "Add or update server metadata",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(_ *cobra.Command, args []string) {
+		metadata := parseKeyValues(args[1:])
+		client := openstack.DefaultClient()
+		server, err := client.NovaV2().Server().Find(args[0])
+		utility.LogError(err, "get server failed", true)
+		metadata, err = client.NovaV2().Server().SetMetadata(server.Id, metadata)
+		utility.LogError(err, "set server metadata failed", true)
+		printMetadata(metadata)
+	},
+}
+var serverMetadataUnset = &cobra.Command{
+	Use:   "unset <server> <key> [<key> ...]",
+	Short: "Delete server metadata",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		server, err := client.NovaV2().Server().Find(args[0])
+		utility.LogError(err, "get server failed", true)
+		for _, key := range args[1:] {
+			err := client.NovaV2().Server().DeleteMetadata(server.Id, key)
+			utility.LogIfError(err, false, "delete metadata %s failed", key)
+		}
+	},
+}
+
+var serverTag = &cobra.Command{Use: "tag", Short: "Server tags (2.26+)"}
+var serverTagList = &cobra.Command{
+	Use:   "list <server>",
+	Short: "List server tags",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		server, err := client.NovaV2().Server().Find(args[0])
+		utility.LogError(err, "get server failed", true)
+		tags, err := client.NovaV2().Server().ListTags(server.Id)
+		utility.LogError(err, "list server tags failed", true)
+		sort.Strings(tags)
+		fmt.Println(strings.Join(tags, "\n"))
+	},
+}
+var serverTagAdd = &cobra.Command{
+	Use:   "add <server> <tag> [<tag> ...]",
+	Short: "Add server tags",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		server, err := client.NovaV2().Server().Find(args[0])
+		utility.LogError(err, "get server failed", true)
+		for _, tag := range args[1:] {
+			err := client.NovaV2().Server().AddTag(server.Id, tag)
+			utility.LogIfError(err, false, "add tag %s failed", tag)
+		}
+	},
+}
+var serverTagRemove = &cobra.Command{
+	Use:   "remove <server> <tag> [<tag> ...]",
+	Short: "Remove server tags",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		server, err := client.NovaV2().Server().Find(args[0])
+		utility.LogError(err, "get server failed", true)
+		for _, tag := range args[1:] {
+			err := client.NovaV2().Server().DeleteTag(server.Id, tag)
+			utility.LogIfError(err, false, "remove tag %s failed", tag)
+		}
+	},
+}
+var serverTagClear = &cobra.Command{
+	Use:   "clear <server>",
+	Short: "Remove all tags of server",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		server, err := client.NovaV2().Server().Find(args[0])
+		utility.LogError(err, "get server failed", true)
+		err = client.NovaV2().Server().ClearTags(server.Id)
+		utility.LogError(err, "clear server tags failed", true)
+	},
+}
+
+func init() {
+	serverMetadata.AddCommand(serverMetadataList, serverMetadataSet, serverMetadataUnset)
+	serverTag.AddCommand(serverTagList, serverTagAdd, serverTagRemove, serverTagClear)
+	Server.AddCommand(serverMetadata, serverTag)
+}
@@ -0,0 +1,104 @@
+package nova
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/easygo/pkg/stringutils"
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/nova"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var serverMigrationWatchFlags flags.ServerMigrationWatchFlags
+
+// 查询虚拟机和迁移 ID, 没有指定迁移 ID 时使用正在进行的热迁移
+func findServerMigration(client *openstack.Openstack, args []string) (*nova.Server, int) {
+	server, err := client.NovaV2().Server().Find(args[0])
+	utility.LogError(err, "get server failed", true)
+	if len(args) > 1 {
+		migrationId, err := strconv.Atoi(args[1])
+		utility.LogIfError(err, true, "invalid migration id %s", args[1])
+		return server, migrationId
+	}
+	migration, err := client.RunningLiveMigration(server.Id)
+	utility.LogError(err, "get running live migration failed", true)
+	return server, migration.Id
+}
+
+var serverMigrationWatch = &cobra.Command{
+	Use:   "watch <server>",
+	Short: "Watch the progress of server live migration",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+			return err
+		}
+		if policy := *serverMigrationWatchFlags.Policy; policy != "" &&
+			!stringutils.ContainsString(openstack.MIGRATION_POLICIES, policy) {
+			return fmt.Errorf("invalid policy %s, valid: %v", policy, openstack.MIGRATION_POLICIES)
+		}
+		if *serverMigrationWatchFlags.Interval == 0 {
+			return fmt.Errorf("invalid interval 0, it must be greater than 0")
+		}
+		return nil
+	},
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		server, err := client.NovaV2().Server().Find(args[0])
+		utility.LogError(err, "get server failed", true)
+
+		opt := openstack.MigrationWatchOpt{
+			Interval:    int(*serverMigrationWatchFlags.Interval),
+			MaxDuration: time.Second * time.Duration(*serverMigrationWatchFlags.MaxDuration),
+			Policy:      *serverMigrationWatchFlags.Policy,
+		}
+		migration, err := client.WatchLiveMigration(client.Context(), server.Id, opt, func(m nova.Migration) {
+			console.Info("[%s] migration %d %s, %s -> %s, memory: %s, disk: %s",
+				server.Id, m.Id, m.Status, m.SourceCompute, m.DestCompute, m.MemoryProgress(), m.DiskProgress())
+		})
+		utility.LogIfError(err, true, "live migration of server %s failed", server.Id)
+		console.Info("[%s] migration %d %s, %s -> %s",
+			server.Id, migration.Id, migration.Status, migration.SourceCompute, migration.DestCompute)
+	},
+}
+var serverMigrationAbort = &cobra.Command{
+	Use:   "abort <server> [<migration id>]",
+	Short: "Abort the running live migration of server",
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		server, migrationId := findServerMigration(client, args)
+		err := client.NovaV2().Server().AbortMigration(server.Id, migrationId)
+		utility.LogIfError(err, true, "abort migration %d failed", migrationId)
+		console.Info("requested to abort migration %d of server %s", migrationId, server.Id)
+	},
+}
+var serverMigrationForceComplete = &cobra.Command{
+	Use:   "force-complete <server> [<migration id>]",
+	Short: "Force the running live migration of server to complete",
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		server, migrationId := findServerMigration(client, args)
+		err := client.NovaV2().Server().ForceCompleteMigration(server.Id, migrationId)
+		utility.LogIfError(err, true, "force complete migration %d failed", migrationId)
+		console.Info("requested to force complete migration %d of server %s", migrationId, server.Id)
+	},
+}
+
+func init() {
+	serverMigrationWatchFlags = flags.ServerMigrationWatchFlags{
+		Interval: serverMigrationWatch.Flags().Uint16P("interval", "i", 2, "Interval of querying migration in seconds"),
+		MaxDuration: serverMigrationWatch.Flags().Int("max-duration", 0,
+			"Apply the policy when the migration lasts longer than this seconds, 0 means never"),
+		Policy: serverMigrationWatch.Flags().String("policy", openstack.MIGRATION_POLICY_FORCE_COMPLETE,
+			fmt.Sprintf("Policy when the migration lasts too long, %v", openstack.MIGRATION_POLICIES)),
+	}
+
+	serverMigration.AddCommand(serverMigrationWatch, serverMigrationAbort, serverMigrationForceComplete)
+}
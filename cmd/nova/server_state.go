@@ -0,0 +1,160 @@
+package nova
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var serverRescue = &cobra.Command{
+	Use:   "rescue <server>",
+	Short: "Put server in rescue mode",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		password, _ := cmd.Flags().GetString("password")
+		imageIdOrName, _ := cmd.Flags().GetString("image")
+
+		client := openstack.DefaultClient()
+		server, err := client.NovaV2().Server().Find(args[0])
+		utility.LogError(err, "get server failed", true)
+		imageRef := ""
+		if imageIdOrName != "" {
+			image, err := client.GlanceV2().Images().Find(imageIdOrName)
+			utility.LogError(err, "get image failed", true)
+			imageRef = image.Id
+		}
+		adminPass, err := client.NovaV2().Server().Rescue(server.Id, password, imageRef)
+		utility.LogError(err, "Reqeust to rescue server failed", true)
+		fmt.Printf("Requested to rescue server: %s\n", args[0])
+		if adminPass != "" {
+			fmt.Printf("Admin password: %s\n", adminPass)
+		}
+	},
+}
+var serverUnrescue = &cobra.Command{
+	Use:   "unrescue <server>",
+	Short: "Restore server from rescue mode",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		server, err := client.NovaV2().Server().Find(args[0])
+		utility.LogError(err, "get server failed", true)
+		err = client.NovaV2().Server().Unrescue(server.Id)
+		utility.LogError(err, "Reqeust to unrescue server failed", true)
+		fmt.Printf("Requested to unrescue server: %s\n", args[0])
+	},
+}
+var serverLock = &cobra.Command{
+	Use:   "lock <server> [<server> ...]",
+	Short: "Lock server(s)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		reason, _ := cmd.Flags().GetString("reason")
+		client := openstack.DefaultClient()
+
+		for _, idOrName := range args {
+			server, err := client.NovaV2().Server().Find(idOrName)
+			if err != nil {
+				console.Error("get server %s failed, %v", idOrName, err)
+				continue
+			}
+			err = client.NovaV2().Server().Lock(server.Id, reason)
+			if err != nil {
+				console.Error("Reqeust to lock server failed, %v", err)
+			} else {
+				fmt.Printf("Requested to lock server: %s\n", idOrName)
+			}
+		}
+	},
+}
+var serverUnlock = &cobra.Command{
+	Use:   "unlock <server> [<server> ...]",
+	Short: "Unlock server(s)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+
+		for _, idOrName := range args {
+			server, err := client.NovaV2().Server().Find(idOrName)
+			if err != nil {
+				console.Error("get server %s failed, %v", idOrName, err)
+				continue
+			}
+			err = client.NovaV2().Server().Unlock(server.Id)
+			if err != nil {
+				console.Error("Reqeust to unlock server failed, %v", err)
+			} else {
+				fmt.Printf("Requested to unlock server: %s\n", idOrName)
+			}
+		}
+	},
+}
+var serverCrashDump = &cobra.Command{
+	Use:   "crash-dump <server>",
+	Short: "Trigger crash dump of server",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		server, err := client.NovaV2().Server().Find(args[0])
+		utility.LogError(err, "get server failed", true)
+		err = client.NovaV2().Server().TriggerCrashDump(server.Id)
+		utility.LogError(err, "Reqeust to trigger crash dump failed", true)
+		fmt.Printf("Requested to trigger crash dump of server: %s\n", args[0])
+	},
+}
+
+var serverPassword = &cobra.Command{Use: "password", Short: "Server password generated by metadata service"}
+var serverPasswordShow = &cobra.Command{
+	Use:   "show <server>",
+	Short: "Show server password",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		privateKey, _ := cmd.Flags().GetString("private-key")
+		client := openstack.DefaultClient()
+		server, err := client.NovaV2().Server().Find(args[0])
+		utility.LogError(err, "get server failed", true)
+		encrypted, err := client.NovaV2().Server().GetPassword(server.Id)
+		utility.LogError(err, "get server password failed", true)
+		if encrypted == "" {
+			console.Warn("password of server %s is not set", args[0])
+			return
+		}
+		if privateKey == "" {
+			fmt.Println(encrypted)
+			return
+		}
+		key, err := os.ReadFile(privateKey)
+		utility.LogError(err, "read private key failed", true)
+		password, err := openstack.DecryptServerPassword(encrypted, key)
+		utility.LogError(err, "decrypt server password failed", true)
+		fmt.Println(password)
+	},
+}
+var serverPasswordClear = &cobra.Command{
+	Use:   "clear <server>",
+	Short: "Clear server password",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		server, err := client.NovaV2().Server().Find(args[0])
+		utility.LogError(err, "get server failed", true)
+		err = client.NovaV2().Server().ClearPassword(server.Id)
+		utility.LogError(err, "clear server password failed", true)
+		fmt.Printf("Cleared password of server: %s\n", args[0])
+	},
+}
+
+func init() {
+	serverRescue.Flags().String("password", "", "Admin password of the rescue system")
+	serverRescue.Flags().String("image", "", "Image used to rescue the server")
+	serverLock.Flags().String("reason", "", "Reason for locking the server (2.73+)")
+	serverPasswordShow.Flags().String("private-key", "", "Private key file to decrypt the password")
+
+	serverPassword.AddCommand(serverPasswordShow, serverPasswordClear)
+	Server.AddCommand(serverRescue, serverUnrescue, serverLock, serverUnlock, serverCrashDump, serverPassword)
+}
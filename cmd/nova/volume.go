@@ -5,6 +5,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/BytemanD/skyman/cmd/flags"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
 	"github.com/BytemanD/skyman/openstack/model/nova"
@@ -24,6 +25,8 @@ func printVolumeAttachments(items []nova.VolumeAttachment) {
 	common.PrintPrettyTable(pt, false)
 }
 
+var volumeListPageFlags flags.PageFlags
+
 var volumeList = &cobra.Command{
 	Use:   "list <server>",
 	Short: "List service volumes",
@@ -36,6 +39,9 @@ var volumeList = &cobra.Command{
 		if err != nil {
 			fmt.Println(err)
 		}
+		attachments, err = flags.PageItems(volumeListPageFlags, attachments,
+			func(attachment nova.VolumeAttachment) string { return attachment.VolumeId })
+		utility.LogIfError(err, true, "list volumes of server %s failed", args[0])
 		printVolumeAttachments(attachments)
 	},
 }
@@ -76,6 +82,7 @@ var volumeDetach = &cobra.Command{
 }
 
 func init() {
+	volumeListPageFlags = flags.NewPageFlags(volumeList)
 	// compute service
 	Volume.AddCommand(volumeList, volumeAttach, volumeDetach)
 
@@ -0,0 +1,107 @@
+package octavia
+
+import (
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/octavia"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var amphora = &cobra.Command{Use: "amphora", Short: "Amphorae (admin only)"}
+
+var amphoraListPageFlags flags.PageFlags
+
+var amphoraList = &cobra.Command{
+	Use:   "list",
+	Short: "List amphorae",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		c := openstack.DefaultClient()
+
+		long, _ := cmd.Flags().GetBool("long")
+		lbIdOrName, _ := cmd.Flags().GetString("loadbalancer")
+		status, _ := cmd.Flags().GetString("status")
+		query := url.Values{}
+		if lbIdOrName != "" {
+			lb, err := c.OctaviaV2().LoadBalancer().Find(lbIdOrName)
+			utility.LogError(err, "get load balancer failed", true)
+			query.Set("loadbalancer_id", lb.Id)
+		}
+		if status != "" {
+			query.Set("status", status)
+		}
+		amphorae, err := flags.ListPages(amphoraListPageFlags, query, c.OctaviaV2().Amphora().Iterator)
+		utility.LogError(err, "list amphorae failed", true)
+
+		table := datatable.DataTable[octavia.Amphora]{
+			Items: amphorae,
+			Columns: []datatable.Column[octavia.Amphora]{
+				{Name: "Id"}, {Name: "LoadbalancerId"},
+				{Name: "Status", AutoColor: true}, {Name: "Role"},
+				{Name: "LbNetworkIp"}, {Name: "HaIp"},
+			},
+			MoreColumns: []datatable.Column[octavia.Amphora]{
+				{Name: "ComputeId"}, {Name: "VrrpIp"},
+				{Name: "ImageId"}, {Name: "CertExpiration"},
+			},
+		}
+		common.PrintDataTable[octavia.Amphora](&table, long)
+	},
+}
+var amphoraShow = &cobra.Command{
+	Use:   "show <amphora id>",
+	Short: "Show amphora",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		amphora, err := c.OctaviaV2().Amphora().Show(args[0])
+		utility.LogError(err, "get amphora failed", true)
+		table := datatable.DataIterator[octavia.Amphora]{
+			Items: []octavia.Amphora{*amphora},
+			Fields: []datatable.Field[octavia.Amphora]{
+				{Name: "Id"}, {Name: "LoadbalancerId"}, {Name: "ComputeId"},
+				{Name: "Status", AutoColor: true}, {Name: "Role"},
+				{Name: "LbNetworkIp"}, {Name: "VrrpIp"}, {Name: "HaIp"},
+				{Name: "ImageId"}, {Name: "ComputeFlavor"},
+				{Name: "CertExpiration"},
+				{Name: "CreatedAt"}, {Name: "UpdatedAt"},
+			},
+		}
+		common.PrintDataTable[octavia.Amphora](&table, false)
+	},
+}
+var amphoraFailover = &cobra.Command{
+	Use:   "failover <amphora id>",
+	Short: "Trigger amphora failover",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		wait, _ := cmd.Flags().GetBool("wait")
+
+		amphora, err := c.OctaviaV2().Amphora().Show(args[0])
+		utility.LogError(err, "get amphora failed", true)
+		err = c.OctaviaV2().Amphora().Failover(amphora.Id)
+		utility.LogError(err, "failover amphora failed", true)
+		if wait && amphora.LoadbalancerId != "" {
+			waitLoadBalancer(c, amphora.LoadbalancerId, octavia.PROVISIONING_ACTIVE)
+		}
+	},
+}
+
+func init() {
+	amphoraList.Flags().BoolP("long", "l", false, "List additional fields in output")
+	amphoraList.Flags().String("loadbalancer", "", "List amphorae of the load balancer")
+	amphoraList.Flags().String("status", "", "List amphorae matched by status")
+	amphoraListPageFlags = flags.NewPageFlags(amphoraList)
+
+	amphoraFailover.Flags().Bool("wait", false, "Wait load balancer to be ACTIVE")
+
+	amphora.AddCommand(amphoraList, amphoraShow, amphoraFailover)
+	LB.AddCommand(amphora)
+}
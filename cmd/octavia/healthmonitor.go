@@ -0,0 +1,180 @@
+package octavia
+
+import (
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/octavia"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var healthMonitor = &cobra.Command{Use: "healthmonitor", Short: "Load balancer health monitors"}
+
+var healthMonitorListPageFlags flags.PageFlags
+
+func printHealthMonitor(hm octavia.HealthMonitor) {
+	table := datatable.DataIterator[octavia.HealthMonitor]{
+		Items: []octavia.HealthMonitor{hm},
+		Fields: []datatable.Field[octavia.HealthMonitor]{
+			{Name: "Id"}, {Name: "Name"},
+			{Name: "ProvisioningStatus", AutoColor: true},
+			{Name: "OperatingStatus", AutoColor: true},
+			{Name: "AdminStateUp"},
+			{Name: "Type"}, {Name: "Delay"}, {Name: "Timeout"},
+			{Name: "MaxRetries"}, {Name: "MaxRetriesDown"},
+			{Name: "HttpMethod"}, {Name: "UrlPath"}, {Name: "ExpectedCodes"},
+			{Name: "Pools", RenderFunc: func(item octavia.HealthMonitor) interface{} {
+				return item.PoolIds()
+			}},
+			{Name: "ProjectId"},
+			{Name: "CreatedAt"}, {Name: "UpdatedAt"},
+		},
+	}
+	common.PrintDataTable[octavia.HealthMonitor](&table, false)
+}
+
+var healthMonitorList = &cobra.Command{
+	Use:   "list",
+	Short: "List health monitors",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		c := openstack.DefaultClient()
+
+		long, _ := cmd.Flags().GetBool("long")
+		name, _ := cmd.Flags().GetString("name")
+		query := url.Values{}
+		if name != "" {
+			query.Set("name", name)
+		}
+		hms, err := flags.ListPages(healthMonitorListPageFlags, query, c.OctaviaV2().HealthMonitor().Iterator)
+		utility.LogError(err, "list health monitors failed", true)
+
+		table := datatable.DataTable[octavia.HealthMonitor]{
+			Items: hms,
+			Columns: []datatable.Column[octavia.HealthMonitor]{
+				{Name: "Id"}, {Name: "Name"}, {Name: "Type"},
+				{Name: "Delay"}, {Name: "Timeout"}, {Name: "MaxRetries"},
+				{Name: "ProvisioningStatus", AutoColor: true},
+				{Name: "OperatingStatus", AutoColor: true},
+			},
+			MoreColumns: []datatable.Column[octavia.HealthMonitor]{
+				{Name: "UrlPath"}, {Name: "ExpectedCodes"}, {Name: "ProjectId"},
+				{Name: "Pools", RenderFunc: func(item octavia.HealthMonitor) interface{} {
+					return item.PoolIds()
+				}},
+			},
+		}
+		common.PrintDataTable[octavia.HealthMonitor](&table, long)
+	},
+}
+var healthMonitorShow = &cobra.Command{
+	Use:   "show <healthmonitor>",
+	Short: "Show health monitor",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		hm, err := c.OctaviaV2().HealthMonitor().Find(args[0])
+		utility.LogError(err, "get health monitor failed", true)
+		printHealthMonitor(*hm)
+	},
+}
+var healthMonitorCreate = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create health monitor",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+
+		poolIdOrName, _ := cmd.Flags().GetString("pool")
+		hmType, _ := cmd.Flags().GetString("type")
+		delay, _ := cmd.Flags().GetInt("delay")
+		timeout, _ := cmd.Flags().GetInt("timeout")
+		maxRetries, _ := cmd.Flags().GetInt("max-retries")
+		maxRetriesDown, _ := cmd.Flags().GetInt("max-retries-down")
+		httpMethod, _ := cmd.Flags().GetString("http-method")
+		urlPath, _ := cmd.Flags().GetString("url-path")
+		expectedCodes, _ := cmd.Flags().GetString("expected-codes")
+		disable, _ := cmd.Flags().GetBool("disable")
+		wait, _ := cmd.Flags().GetBool("wait")
+
+		pool := findPool(c, poolIdOrName)
+		params := map[string]interface{}{
+			"name":        args[0],
+			"pool_id":     pool.Id,
+			"type":        hmType,
+			"delay":       delay,
+			"timeout":     timeout,
+			"max_retries": maxRetries,
+		}
+		if maxRetriesDown > 0 {
+			params["max_retries_down"] = maxRetriesDown
+		}
+		if httpMethod != "" {
+			params["http_method"] = httpMethod
+		}
+		if urlPath != "" {
+			params["url_path"] = urlPath
+		}
+		if expectedCodes != "" {
+			params["expected_codes"] = expectedCodes
+		}
+		if disable {
+			params["admin_state_up"] = false
+		}
+		hm, err := c.OctaviaV2().HealthMonitor().Create(params)
+		utility.LogError(err, "create health monitor failed", true)
+		if wait && len(pool.Loadbalancers) > 0 {
+			waitLoadBalancer(c, pool.Loadbalancers[0].Id, octavia.PROVISIONING_ACTIVE)
+			hm, err = c.OctaviaV2().HealthMonitor().Show(hm.Id)
+			utility.LogError(err, "get health monitor failed", true)
+		}
+		printHealthMonitor(*hm)
+	},
+}
+var healthMonitorDelete = &cobra.Command{
+	Use:   "delete <healthmonitor> [healthmonitor ...]",
+	Short: "Delete health monitor(s)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		for _, idOrName := range args {
+			hm, err := c.OctaviaV2().HealthMonitor().Find(idOrName)
+			if err != nil {
+				console.Error("get health monitor %s failed, %s", idOrName, err)
+				continue
+			}
+			console.Info("Reqeust to delete health monitor %s", idOrName)
+			if err := c.OctaviaV2().HealthMonitor().Delete(hm.Id); err != nil {
+				console.Error("Delete health monitor %s failed, %s", idOrName, err)
+			}
+		}
+	},
+}
+
+func init() {
+	healthMonitorList.Flags().BoolP("long", "l", false, "List additional fields in output")
+	healthMonitorList.Flags().StringP("name", "n", "", "Search by health monitor name")
+	healthMonitorListPageFlags = flags.NewPageFlags(healthMonitorList)
+
+	healthMonitorCreate.Flags().String("pool", "", "Pool id or name")
+	healthMonitorCreate.Flags().String("type", "TCP", "Type, e.g. HTTP, HTTPS, PING, TCP, TLS-HELLO, UDP-CONNECT")
+	healthMonitorCreate.Flags().Int("delay", 5, "Interval of probes, in seconds")
+	healthMonitorCreate.Flags().Int("timeout", 5, "Timeout of probe, in seconds")
+	healthMonitorCreate.Flags().Int("max-retries", 3, "Number of successful probes to change member to ONLINE")
+	healthMonitorCreate.Flags().Int("max-retries-down", 0, "Number of failed probes to change member to ERROR")
+	healthMonitorCreate.Flags().String("http-method", "", "HTTP method, e.g. GET, HEAD")
+	healthMonitorCreate.Flags().String("url-path", "", "HTTP url path, e.g. /healthcheck")
+	healthMonitorCreate.Flags().String("expected-codes", "", "Expected HTTP status codes, e.g. 200, 200-204, 200,202")
+	healthMonitorCreate.Flags().Bool("disable", false, "Disable health monitor")
+	healthMonitorCreate.Flags().Bool("wait", false, "Wait load balancer to be ACTIVE")
+	healthMonitorCreate.MarkFlagRequired("pool")
+
+	healthMonitor.AddCommand(healthMonitorList, healthMonitorShow, healthMonitorCreate, healthMonitorDelete)
+	LB.AddCommand(healthMonitor)
+}
@@ -0,0 +1,186 @@
+package octavia
+
+import (
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/octavia"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var listener = &cobra.Command{Use: "listener", Short: "Load balancer listeners"}
+
+var listenerListPageFlags flags.PageFlags
+
+func printListener(listener octavia.Listener) {
+	table := datatable.DataIterator[octavia.Listener]{
+		Items: []octavia.Listener{listener},
+		Fields: []datatable.Field[octavia.Listener]{
+			{Name: "Id"}, {Name: "Name"}, {Name: "Description"},
+			{Name: "ProvisioningStatus", AutoColor: true},
+			{Name: "OperatingStatus", AutoColor: true},
+			{Name: "AdminStateUp"},
+			{Name: "Protocol"}, {Name: "ProtocolPort"},
+			{Name: "ConnectionLimit"}, {Name: "DefaultPoolId"},
+			{Name: "Loadbalancers", RenderFunc: func(item octavia.Listener) interface{} {
+				return item.LoadbalancerIds()
+			}},
+			{Name: "AllowedCidrs"},
+			{Name: "ProjectId"},
+			{Name: "CreatedAt"}, {Name: "UpdatedAt"},
+		},
+	}
+	common.PrintDataTable[octavia.Listener](&table, false)
+}
+
+var listenerList = &cobra.Command{
+	Use:   "list",
+	Short: "List listeners",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		c := openstack.DefaultClient()
+
+		long, _ := cmd.Flags().GetBool("long")
+		name, _ := cmd.Flags().GetString("name")
+		lbIdOrName, _ := cmd.Flags().GetString("loadbalancer")
+		query := url.Values{}
+		if name != "" {
+			query.Set("name", name)
+		}
+		if lbIdOrName != "" {
+			lb, err := c.OctaviaV2().LoadBalancer().Find(lbIdOrName)
+			utility.LogError(err, "get load balancer failed", true)
+			query.Set("loadbalancer_id", lb.Id)
+		}
+		listeners, err := flags.ListPages(listenerListPageFlags, query, c.OctaviaV2().Listener().Iterator)
+		utility.LogError(err, "list listeners failed", true)
+
+		table := datatable.DataTable[octavia.Listener]{
+			Items: listeners,
+			Columns: []datatable.Column[octavia.Listener]{
+				{Name: "Id"}, {Name: "Name"},
+				{Name: "Protocol"}, {Name: "ProtocolPort"},
+				{Name: "ProvisioningStatus", AutoColor: true},
+				{Name: "OperatingStatus", AutoColor: true},
+				{Name: "DefaultPoolId"},
+			},
+			MoreColumns: []datatable.Column[octavia.Listener]{
+				{Name: "ConnectionLimit"}, {Name: "ProjectId"},
+				{Name: "Loadbalancers", RenderFunc: func(item octavia.Listener) interface{} {
+					return item.LoadbalancerIds()
+				}},
+			},
+		}
+		common.PrintDataTable[octavia.Listener](&table, long)
+	},
+}
+var listenerShow = &cobra.Command{
+	Use:   "show <listener>",
+	Short: "Show listener",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		listener, err := c.OctaviaV2().Listener().Find(args[0])
+		utility.LogError(err, "get listener failed", true)
+		printListener(*listener)
+	},
+}
+var listenerCreate = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create listener",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+
+		lbIdOrName, _ := cmd.Flags().GetString("loadbalancer")
+		protocol, _ := cmd.Flags().GetString("protocol")
+		port, _ := cmd.Flags().GetInt("protocol-port")
+		defaultPool, _ := cmd.Flags().GetString("default-pool")
+		connectionLimit, _ := cmd.Flags().GetInt("connection-limit")
+		allowedCidrs, _ := cmd.Flags().GetStringArray("allowed-cidr")
+		description, _ := cmd.Flags().GetString("description")
+		disable, _ := cmd.Flags().GetBool("disable")
+		wait, _ := cmd.Flags().GetBool("wait")
+
+		lb, err := c.OctaviaV2().LoadBalancer().Find(lbIdOrName)
+		utility.LogError(err, "get load balancer failed", true)
+		params := map[string]interface{}{
+			"name":            args[0],
+			"loadbalancer_id": lb.Id,
+			"protocol":        protocol,
+			"protocol_port":   port,
+		}
+		if defaultPool != "" {
+			pool, err := c.OctaviaV2().Pool().Find(defaultPool)
+			utility.LogError(err, "get pool failed", true)
+			params["default_pool_id"] = pool.Id
+		}
+		if connectionLimit != 0 {
+			params["connection_limit"] = connectionLimit
+		}
+		if len(allowedCidrs) > 0 {
+			params["allowed_cidrs"] = allowedCidrs
+		}
+		if description != "" {
+			params["description"] = description
+		}
+		if disable {
+			params["admin_state_up"] = false
+		}
+		listener, err := c.OctaviaV2().Listener().Create(params)
+		utility.LogError(err, "create listener failed", true)
+		if wait {
+			waitLoadBalancer(c, lb.Id, octavia.PROVISIONING_ACTIVE)
+			listener, err = c.OctaviaV2().Listener().Show(listener.Id)
+			utility.LogError(err, "get listener failed", true)
+		}
+		printListener(*listener)
+	},
+}
+var listenerDelete = &cobra.Command{
+	Use:   "delete <listener> [listener ...]",
+	Short: "Delete listener(s)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		for _, idOrName := range args {
+			listener, err := c.OctaviaV2().Listener().Find(idOrName)
+			if err != nil {
+				console.Error("get listener %s failed, %s", idOrName, err)
+				continue
+			}
+			console.Info("Reqeust to delete listener %s", idOrName)
+			if err := c.OctaviaV2().Listener().Delete(listener.Id); err != nil {
+				console.Error("Delete listener %s failed, %s", idOrName, err)
+			}
+		}
+	},
+}
+
+func init() {
+	listenerList.Flags().BoolP("long", "l", false, "List additional fields in output")
+	listenerList.Flags().StringP("name", "n", "", "Search by listener name")
+	listenerList.Flags().String("loadbalancer", "", "List listeners of the load balancer")
+	listenerListPageFlags = flags.NewPageFlags(listenerList)
+
+	listenerCreate.Flags().String("loadbalancer", "", "Load balancer id or name")
+	listenerCreate.Flags().String("protocol", "TCP", "Protocol, e.g. TCP, HTTP, HTTPS, UDP, TERMINATED_HTTPS")
+	listenerCreate.Flags().Int("protocol-port", 0, "Protocol port")
+	listenerCreate.Flags().String("default-pool", "", "Default pool id or name")
+	listenerCreate.Flags().Int("connection-limit", 0, "Maximum number of connections, 0 means unlimited")
+	listenerCreate.Flags().StringArray("allowed-cidr", []string{}, "CIDR allowed to access the listener")
+	listenerCreate.Flags().String("description", "", "Set listener description")
+	listenerCreate.Flags().Bool("disable", false, "Disable listener")
+	listenerCreate.Flags().Bool("wait", false, "Wait load balancer to be ACTIVE")
+	listenerCreate.MarkFlagRequired("loadbalancer")
+	listenerCreate.MarkFlagRequired("protocol-port")
+
+	listener.AddCommand(listenerList, listenerShow, listenerCreate, listenerDelete)
+	LB.AddCommand(listener)
+}
@@ -0,0 +1,240 @@
+package octavia
+
+import (
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/octavia"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var LB = &cobra.Command{Use: "lb", Short: "Load balancers (Octavia)"}
+
+var lbListPageFlags flags.PageFlags
+
+// 等待负载均衡器状态变化的间隔, 单位秒
+const WAIT_INTERVAL = 2
+
+func waitLoadBalancer(client *openstack.Openstack, lbId string, status string) *octavia.LoadBalancer {
+	lb, err := client.OctaviaV2().LoadBalancer().WaitProvisioningStatus(client.Context(), lbId, status, WAIT_INTERVAL)
+	utility.LogIfError(err, true, "wait loadbalancer %s %s failed", lbId, status)
+	return lb
+}
+
+func printLoadBalancer(lb octavia.LoadBalancer) {
+	table := datatable.DataIterator[octavia.LoadBalancer]{
+		Items: []octavia.LoadBalancer{lb},
+		Fields: []datatable.Field[octavia.LoadBalancer]{
+			{Name: "Id"}, {Name: "Name"}, {Name: "Description"},
+			{Name: "ProvisioningStatus", AutoColor: true},
+			{Name: "OperatingStatus", AutoColor: true},
+			{Name: "AdminStateUp"},
+			{Name: "VipAddress"}, {Name: "VipPortId"},
+			{Name: "VipSubnetId"}, {Name: "VipNetworkId"},
+			{Name: "Provider"}, {Name: "FlavorId"}, {Name: "AvailabilityZone"},
+			{Name: "Listeners", RenderFunc: func(item octavia.LoadBalancer) interface{} {
+				return item.ListenerIds()
+			}},
+			{Name: "Pools", RenderFunc: func(item octavia.LoadBalancer) interface{} {
+				return item.PoolIds()
+			}},
+			{Name: "ProjectId"},
+			{Name: "CreatedAt"}, {Name: "UpdatedAt"},
+		},
+	}
+	common.PrintDataTable[octavia.LoadBalancer](&table, false)
+}
+
+var lbList = &cobra.Command{
+	Use:   "list",
+	Short: "List load balancers",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		c := openstack.DefaultClient()
+
+		long, _ := cmd.Flags().GetBool("long")
+		name, _ := cmd.Flags().GetString("name")
+		projectIdOrName, _ := cmd.Flags().GetString("project")
+		query := url.Values{}
+		if name != "" {
+			query.Set("name", name)
+		}
+		if projectIdOrName != "" {
+			project, err := c.KeystoneV3().Project().Find(projectIdOrName)
+			utility.LogError(err, "get project failed", true)
+			query.Set("project_id", project.Id)
+		}
+		lbs, err := flags.ListPages(lbListPageFlags, query, c.OctaviaV2().LoadBalancer().Iterator)
+		utility.LogError(err, "list load balancers failed", true)
+
+		table := datatable.DataTable[octavia.LoadBalancer]{
+			Items: lbs,
+			Columns: []datatable.Column[octavia.LoadBalancer]{
+				{Name: "Id"}, {Name: "Name"},
+				{Name: "VipAddress"},
+				{Name: "ProvisioningStatus", AutoColor: true},
+				{Name: "OperatingStatus", AutoColor: true},
+				{Name: "Provider"},
+			},
+			MoreColumns: []datatable.Column[octavia.LoadBalancer]{
+				{Name: "VipSubnetId"}, {Name: "FlavorId"},
+				{Name: "AvailabilityZone"}, {Name: "ProjectId"},
+				{Name: "Listeners", RenderFunc: func(item octavia.LoadBalancer) interface{} {
+					return item.ListenerIds()
+				}},
+			},
+		}
+		common.PrintDataTable[octavia.LoadBalancer](&table, long)
+	},
+}
+var lbShow = &cobra.Command{
+	Use:   "show <loadbalancer>",
+	Short: "Show load balancer",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		lb, err := c.OctaviaV2().LoadBalancer().Find(args[0])
+		utility.LogError(err, "get load balancer failed", true)
+		printLoadBalancer(*lb)
+	},
+}
+var lbCreate = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create load balancer",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+
+		subnetIdOrName, _ := cmd.Flags().GetString("subnet")
+		description, _ := cmd.Flags().GetString("description")
+		vipAddress, _ := cmd.Flags().GetString("vip-address")
+		provider, _ := cmd.Flags().GetString("provider")
+		flavor, _ := cmd.Flags().GetString("flavor")
+		az, _ := cmd.Flags().GetString("availability-zone")
+		disable, _ := cmd.Flags().GetBool("disable")
+		wait, _ := cmd.Flags().GetBool("wait")
+
+		subnet, err := c.NeutronV2().Subnet().Find(subnetIdOrName)
+		utility.LogError(err, "get subnet failed", true)
+		params := map[string]interface{}{
+			"name":          args[0],
+			"vip_subnet_id": subnet.Id,
+		}
+		if description != "" {
+			params["description"] = description
+		}
+		if vipAddress != "" {
+			params["vip_address"] = vipAddress
+		}
+		if provider != "" {
+			params["provider"] = provider
+		}
+		if flavor != "" {
+			params["flavor_id"] = flavor
+		}
+		if az != "" {
+			params["availability_zone"] = az
+		}
+		if disable {
+			params["admin_state_up"] = false
+		}
+		lb, err := c.OctaviaV2().LoadBalancer().Create(params)
+		utility.LogError(err, "create load balancer failed", true)
+		if wait {
+			lb = waitLoadBalancer(c, lb.Id, octavia.PROVISIONING_ACTIVE)
+		}
+		printLoadBalancer(*lb)
+	},
+}
+var lbDelete = &cobra.Command{
+	Use:   "delete <loadbalancer> [loadbalancer ...]",
+	Short: "Delete load balancer(s)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		cascade, _ := cmd.Flags().GetBool("cascade")
+		wait, _ := cmd.Flags().GetBool("wait")
+
+		for _, idOrName := range args {
+			lb, err := c.OctaviaV2().LoadBalancer().Find(idOrName)
+			if err != nil {
+				console.Error("get load balancer %s failed, %s", idOrName, err)
+				continue
+			}
+			console.Info("Reqeust to delete load balancer %s", idOrName)
+			if err := c.OctaviaV2().LoadBalancer().Delete(lb.Id, cascade); err != nil {
+				console.Error("Delete load balancer %s failed, %s", idOrName, err)
+				continue
+			}
+			if wait {
+				waitLoadBalancer(c, lb.Id, octavia.PROVISIONING_DELETED)
+			}
+		}
+	},
+}
+var lbFailover = &cobra.Command{
+	Use:   "failover <loadbalancer>",
+	Short: "Trigger load balancer failover",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		wait, _ := cmd.Flags().GetBool("wait")
+
+		lb, err := c.OctaviaV2().LoadBalancer().Find(args[0])
+		utility.LogError(err, "get load balancer failed", true)
+		err = c.OctaviaV2().LoadBalancer().Failover(lb.Id)
+		utility.LogError(err, "failover load balancer failed", true)
+		if wait {
+			waitLoadBalancer(c, lb.Id, octavia.PROVISIONING_ACTIVE)
+		}
+	},
+}
+var lbStats = &cobra.Command{
+	Use:   "stats <loadbalancer>",
+	Short: "Show load balancer statistics",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		lb, err := c.OctaviaV2().LoadBalancer().Find(args[0])
+		utility.LogError(err, "get load balancer failed", true)
+		stats, err := c.OctaviaV2().LoadBalancer().Stats(lb.Id)
+		utility.LogError(err, "get load balancer stats failed", true)
+		table := datatable.DataIterator[octavia.LoadBalancerStats]{
+			Items: []octavia.LoadBalancerStats{*stats},
+			Fields: []datatable.Field[octavia.LoadBalancerStats]{
+				{Name: "ActiveConnections"}, {Name: "TotalConnections"},
+				{Name: "BytesIn"}, {Name: "BytesOut"}, {Name: "RequestErrors"},
+			},
+		}
+		common.PrintDataTable[octavia.LoadBalancerStats](&table, false)
+	},
+}
+
+func init() {
+	lbList.Flags().BoolP("long", "l", false, "List additional fields in output")
+	lbList.Flags().StringP("name", "n", "", "Search by load balancer name")
+	lbList.Flags().String("project", "", "List according to the project")
+	lbListPageFlags = flags.NewPageFlags(lbList)
+
+	lbCreate.Flags().String("subnet", "", "VIP subnet id or name")
+	lbCreate.Flags().String("description", "", "Set load balancer description")
+	lbCreate.Flags().String("vip-address", "", "VIP address")
+	lbCreate.Flags().String("provider", "", "Provider name, e.g. amphora, ovn")
+	lbCreate.Flags().String("flavor", "", "Octavia flavor id")
+	lbCreate.Flags().String("availability-zone", "", "Octavia availability zone")
+	lbCreate.Flags().Bool("disable", false, "Disable load balancer")
+	lbCreate.Flags().Bool("wait", false, "Wait load balancer to be ACTIVE")
+	lbCreate.MarkFlagRequired("subnet")
+
+	lbDelete.Flags().Bool("cascade", false, "Delete load balancer with its listeners, pools and members")
+	lbDelete.Flags().Bool("wait", false, "Wait load balancer to be deleted")
+	lbFailover.Flags().Bool("wait", false, "Wait load balancer to be ACTIVE")
+
+	LB.AddCommand(lbList, lbShow, lbCreate, lbDelete, lbFailover, lbStats)
+}
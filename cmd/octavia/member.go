@@ -0,0 +1,167 @@
+package octavia
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/octavia"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var member = &cobra.Command{Use: "member", Short: "Load balancer pool members"}
+
+var memberListPageFlags flags.PageFlags
+
+func printMember(member octavia.Member) {
+	table := datatable.DataIterator[octavia.Member]{
+		Items: []octavia.Member{member},
+		Fields: []datatable.Field[octavia.Member]{
+			{Name: "Id"}, {Name: "Name"},
+			{Name: "ProvisioningStatus", AutoColor: true},
+			{Name: "OperatingStatus", AutoColor: true},
+			{Name: "AdminStateUp"},
+			{Name: "Address"}, {Name: "ProtocolPort"},
+			{Name: "Weight"}, {Name: "Backup"}, {Name: "SubnetId"},
+			{Name: "MonitorAddress"}, {Name: "MonitorPort"},
+			{Name: "ProjectId"},
+			{Name: "CreatedAt"}, {Name: "UpdatedAt"},
+		},
+	}
+	common.PrintDataTable[octavia.Member](&table, false)
+}
+
+func findPool(c *openstack.Openstack, idOrName string) *octavia.Pool {
+	pool, err := c.OctaviaV2().Pool().Find(idOrName)
+	utility.LogError(err, "get pool failed", true)
+	return pool
+}
+
+var memberList = &cobra.Command{
+	Use:   "list <pool>",
+	Short: "List members of pool",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		long, _ := cmd.Flags().GetBool("long")
+
+		pool := findPool(c, args[0])
+		members, err := flags.ListPages(memberListPageFlags, nil, c.OctaviaV2().Member(pool.Id).Iterator)
+		utility.LogError(err, "list members failed", true)
+
+		table := datatable.DataTable[octavia.Member]{
+			Items: members,
+			Columns: []datatable.Column[octavia.Member]{
+				{Name: "Id"}, {Name: "Name"},
+				{Name: "Address"}, {Name: "ProtocolPort"}, {Name: "Weight"},
+				{Name: "ProvisioningStatus", AutoColor: true},
+				{Name: "OperatingStatus", AutoColor: true},
+			},
+			MoreColumns: []datatable.Column[octavia.Member]{
+				{Name: "SubnetId"}, {Name: "Backup"},
+				{Name: "MonitorAddress"}, {Name: "MonitorPort"},
+			},
+		}
+		common.PrintDataTable[octavia.Member](&table, long)
+	},
+}
+var memberShow = &cobra.Command{
+	Use:   "show <pool> <member>",
+	Short: "Show pool member",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		pool := findPool(c, args[0])
+		member, err := c.OctaviaV2().Member(pool.Id).Find(args[1])
+		utility.LogError(err, "get member failed", true)
+		printMember(*member)
+	},
+}
+var memberCreate = &cobra.Command{
+	Use:   "create <pool>",
+	Short: "Add member to pool",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+
+		name, _ := cmd.Flags().GetString("name")
+		address, _ := cmd.Flags().GetString("address")
+		port, _ := cmd.Flags().GetInt("protocol-port")
+		subnetIdOrName, _ := cmd.Flags().GetString("subnet")
+		weight, _ := cmd.Flags().GetInt("weight")
+		backup, _ := cmd.Flags().GetBool("backup")
+		disable, _ := cmd.Flags().GetBool("disable")
+		wait, _ := cmd.Flags().GetBool("wait")
+
+		pool := findPool(c, args[0])
+		params := map[string]interface{}{
+			"address":       address,
+			"protocol_port": port,
+			"weight":        weight,
+		}
+		if name != "" {
+			params["name"] = name
+		}
+		if subnetIdOrName != "" {
+			subnet, err := c.NeutronV2().Subnet().Find(subnetIdOrName)
+			utility.LogError(err, "get subnet failed", true)
+			params["subnet_id"] = subnet.Id
+		}
+		if backup {
+			params["backup"] = true
+		}
+		if disable {
+			params["admin_state_up"] = false
+		}
+		member, err := c.OctaviaV2().Member(pool.Id).Create(params)
+		utility.LogError(err, "create member failed", true)
+		if wait && len(pool.Loadbalancers) > 0 {
+			waitLoadBalancer(c, pool.Loadbalancers[0].Id, octavia.PROVISIONING_ACTIVE)
+			member, err = c.OctaviaV2().Member(pool.Id).Show(member.Id)
+			utility.LogError(err, "get member failed", true)
+		}
+		printMember(*member)
+	},
+}
+var memberDelete = &cobra.Command{
+	Use:   "delete <pool> <member> [member ...]",
+	Short: "Remove member(s) from pool",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		pool := findPool(c, args[0])
+		for _, idOrName := range args[1:] {
+			member, err := c.OctaviaV2().Member(pool.Id).Find(idOrName)
+			if err != nil {
+				console.Error("get member %s failed, %s", idOrName, err)
+				continue
+			}
+			console.Info("Reqeust to delete member %s", idOrName)
+			if err := c.OctaviaV2().Member(pool.Id).Delete(member.Id); err != nil {
+				console.Error("Delete member %s failed, %s", idOrName, err)
+			}
+		}
+	},
+}
+
+func init() {
+	memberList.Flags().BoolP("long", "l", false, "List additional fields in output")
+	memberListPageFlags = flags.NewPageFlags(memberList)
+
+	memberCreate.Flags().String("name", "", "Member name")
+	memberCreate.Flags().String("address", "", "Member IP address")
+	memberCreate.Flags().Int("protocol-port", 0, "Member protocol port")
+	memberCreate.Flags().String("subnet", "", "Subnet id or name of the member address")
+	memberCreate.Flags().Int("weight", 1, "Member weight, 0 ~ 256")
+	memberCreate.Flags().Bool("backup", false, "Set the member as backup")
+	memberCreate.Flags().Bool("disable", false, "Disable member")
+	memberCreate.Flags().Bool("wait", false, "Wait load balancer to be ACTIVE")
+	memberCreate.MarkFlagRequired("address")
+	memberCreate.MarkFlagRequired("protocol-port")
+
+	member.AddCommand(memberList, memberShow, memberCreate, memberDelete)
+	LB.AddCommand(member)
+}
@@ -0,0 +1,191 @@
+package octavia
+
+import (
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/octavia"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var pool = &cobra.Command{Use: "pool", Short: "Load balancer pools"}
+
+var poolListPageFlags flags.PageFlags
+
+func printPool(pool octavia.Pool) {
+	table := datatable.DataIterator[octavia.Pool]{
+		Items: []octavia.Pool{pool},
+		Fields: []datatable.Field[octavia.Pool]{
+			{Name: "Id"}, {Name: "Name"}, {Name: "Description"},
+			{Name: "ProvisioningStatus", AutoColor: true},
+			{Name: "OperatingStatus", AutoColor: true},
+			{Name: "AdminStateUp"},
+			{Name: "Protocol"}, {Name: "LbAlgorithm"},
+			{Name: "SessionPersistence", Marshal: true},
+			{Name: "HealthmonitorId"},
+			{Name: "Loadbalancers", RenderFunc: func(item octavia.Pool) interface{} {
+				return item.LoadbalancerIds()
+			}},
+			{Name: "Listeners", RenderFunc: func(item octavia.Pool) interface{} {
+				return item.ListenerIds()
+			}},
+			{Name: "ProjectId"},
+			{Name: "CreatedAt"}, {Name: "UpdatedAt"},
+		},
+	}
+	common.PrintDataTable[octavia.Pool](&table, false)
+}
+
+var poolList = &cobra.Command{
+	Use:   "list",
+	Short: "List pools",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		c := openstack.DefaultClient()
+
+		long, _ := cmd.Flags().GetBool("long")
+		name, _ := cmd.Flags().GetString("name")
+		lbIdOrName, _ := cmd.Flags().GetString("loadbalancer")
+		query := url.Values{}
+		if name != "" {
+			query.Set("name", name)
+		}
+		if lbIdOrName != "" {
+			lb, err := c.OctaviaV2().LoadBalancer().Find(lbIdOrName)
+			utility.LogError(err, "get load balancer failed", true)
+			query.Set("loadbalancer_id", lb.Id)
+		}
+		pools, err := flags.ListPages(poolListPageFlags, query, c.OctaviaV2().Pool().Iterator)
+		utility.LogError(err, "list pools failed", true)
+
+		table := datatable.DataTable[octavia.Pool]{
+			Items: pools,
+			Columns: []datatable.Column[octavia.Pool]{
+				{Name: "Id"}, {Name: "Name"},
+				{Name: "Protocol"}, {Name: "LbAlgorithm"},
+				{Name: "ProvisioningStatus", AutoColor: true},
+				{Name: "OperatingStatus", AutoColor: true},
+			},
+			MoreColumns: []datatable.Column[octavia.Pool]{
+				{Name: "HealthmonitorId"}, {Name: "ProjectId"},
+				{Name: "Loadbalancers", RenderFunc: func(item octavia.Pool) interface{} {
+					return item.LoadbalancerIds()
+				}},
+			},
+		}
+		common.PrintDataTable[octavia.Pool](&table, long)
+	},
+}
+var poolShow = &cobra.Command{
+	Use:   "show <pool>",
+	Short: "Show pool",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		pool, err := c.OctaviaV2().Pool().Find(args[0])
+		utility.LogError(err, "get pool failed", true)
+		printPool(*pool)
+	},
+}
+var poolCreate = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create pool",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+
+		lbIdOrName, _ := cmd.Flags().GetString("loadbalancer")
+		listenerIdOrName, _ := cmd.Flags().GetString("listener")
+		protocol, _ := cmd.Flags().GetString("protocol")
+		algorithm, _ := cmd.Flags().GetString("lb-algorithm")
+		sessionPersistence, _ := cmd.Flags().GetString("session-persistence")
+		description, _ := cmd.Flags().GetString("description")
+		disable, _ := cmd.Flags().GetBool("disable")
+		wait, _ := cmd.Flags().GetBool("wait")
+
+		if lbIdOrName == "" && listenerIdOrName == "" {
+			console.Fatal("--loadbalancer or --listener is required")
+		}
+		params := map[string]interface{}{
+			"name":         args[0],
+			"protocol":     protocol,
+			"lb_algorithm": algorithm,
+		}
+		// 等待负载均衡器状态时使用
+		lbId := ""
+		if lbIdOrName != "" {
+			lb, err := c.OctaviaV2().LoadBalancer().Find(lbIdOrName)
+			utility.LogError(err, "get load balancer failed", true)
+			params["loadbalancer_id"], lbId = lb.Id, lb.Id
+		}
+		if listenerIdOrName != "" {
+			listener, err := c.OctaviaV2().Listener().Find(listenerIdOrName)
+			utility.LogError(err, "get listener failed", true)
+			params["listener_id"] = listener.Id
+			if lbId == "" && len(listener.Loadbalancers) > 0 {
+				lbId = listener.Loadbalancers[0].Id
+			}
+		}
+		if sessionPersistence != "" {
+			params["session_persistence"] = map[string]string{"type": sessionPersistence}
+		}
+		if description != "" {
+			params["description"] = description
+		}
+		if disable {
+			params["admin_state_up"] = false
+		}
+		pool, err := c.OctaviaV2().Pool().Create(params)
+		utility.LogError(err, "create pool failed", true)
+		if wait && lbId != "" {
+			waitLoadBalancer(c, lbId, octavia.PROVISIONING_ACTIVE)
+			pool, err = c.OctaviaV2().Pool().Show(pool.Id)
+			utility.LogError(err, "get pool failed", true)
+		}
+		printPool(*pool)
+	},
+}
+var poolDelete = &cobra.Command{
+	Use:   "delete <pool> [pool ...]",
+	Short: "Delete pool(s)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		for _, idOrName := range args {
+			pool, err := c.OctaviaV2().Pool().Find(idOrName)
+			if err != nil {
+				console.Error("get pool %s failed, %s", idOrName, err)
+				continue
+			}
+			console.Info("Reqeust to delete pool %s", idOrName)
+			if err := c.OctaviaV2().Pool().Delete(pool.Id); err != nil {
+				console.Error("Delete pool %s failed, %s", idOrName, err)
+			}
+		}
+	},
+}
+
+func init() {
+	poolList.Flags().BoolP("long", "l", false, "List additional fields in output")
+	poolList.Flags().StringP("name", "n", "", "Search by pool name")
+	poolList.Flags().String("loadbalancer", "", "List pools of the load balancer")
+	poolListPageFlags = flags.NewPageFlags(poolList)
+
+	poolCreate.Flags().String("loadbalancer", "", "Load balancer id or name")
+	poolCreate.Flags().String("listener", "", "Listener id or name, the pool will be the default pool of it")
+	poolCreate.Flags().String("protocol", "TCP", "Protocol, e.g. TCP, HTTP, HTTPS, PROXY, UDP")
+	poolCreate.Flags().String("lb-algorithm", "ROUND_ROBIN", "Algorithm, e.g. ROUND_ROBIN, LEAST_CONNECTIONS, SOURCE_IP")
+	poolCreate.Flags().String("session-persistence", "", "Session persistence type, e.g. SOURCE_IP, HTTP_COOKIE")
+	poolCreate.Flags().String("description", "", "Set pool description")
+	poolCreate.Flags().Bool("disable", false, "Disable pool")
+	poolCreate.Flags().Bool("wait", false, "Wait load balancer to be ACTIVE")
+
+	pool.AddCommand(poolList, poolShow, poolCreate, poolDelete)
+	LB.AddCommand(pool)
+}
@@ -0,0 +1,106 @@
+package placement
+
+import (
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/placement"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var allocation = &cobra.Command{Use: "allocation", Short: "Resource allocations"}
+
+type providerAllocation struct {
+	ProviderUuid string
+	Provider     string
+	Generation   int
+	Resources    string
+}
+
+var allocationShow = &cobra.Command{
+	Use:   "show <consumer>",
+	Short: "Show allocations of consumer (server or migration uuid)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		allocations, err := client.PlacementV1().Allocation().Show(args[0])
+		utility.LogIfError(err, true, "get allocations of %s failed", args[0])
+		if len(allocations.Allocations) == 0 {
+			console.Warn("consumer %s has no allocations", args[0])
+			return
+		}
+		items := []providerAllocation{}
+		for uuid, a := range allocations.Allocations {
+			item := providerAllocation{
+				ProviderUuid: uuid, Generation: a.Generation, Resources: a.ResourcesString(),
+			}
+			if rp, err := client.PlacementV1().ResourceProvider().Show(uuid); err == nil {
+				item.Provider = rp.Name
+			} else {
+				console.Warn("get resource provider %s failed: %s", uuid, err)
+			}
+			items = append(items, item)
+		}
+		sort.Slice(items, func(i, j int) bool { return items[i].Provider < items[j].Provider })
+		pt := common.PrettyTable{
+			ShortColumns: []common.Column{
+				{Name: "ProviderUuid"}, {Name: "Provider"}, {Name: "Generation"},
+				{Name: "Resources"},
+			},
+		}
+		pt.AddItems(items)
+		common.PrintPrettyTable(pt, false)
+		if allocations.ProjectId != "" {
+			console.Info("project: %s, user: %s", allocations.ProjectId, allocations.UserId)
+		}
+	},
+}
+
+var allocationAudit = &cobra.Command{
+	Use:   "audit",
+	Short: "Find allocations of deleted servers, finished migrations or migrated servers",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		providerArgs, _ := cmd.Flags().GetStringArray("provider")
+
+		client := openstack.DefaultClient()
+		api := client.PlacementV1().ResourceProvider()
+		providers := []placement.ResourceProvider{}
+		if len(providerArgs) == 0 {
+			var err error
+			providers, err = api.List(nil)
+			utility.LogError(err, "list resource providers failed", true)
+		}
+		for _, idOrName := range providerArgs {
+			rp, err := api.Find(idOrName)
+			utility.LogIfError(err, true, "get resource provider %s failed", idOrName)
+			providers = append(providers, *rp)
+		}
+		console.Info("audit allocations of %d resource provider(s)", len(providers))
+		audits, err := client.AuditAllocations(providers)
+		utility.LogError(err, "audit allocations failed", true)
+		if len(audits) == 0 {
+			console.Info("no suspicious allocations found")
+			return
+		}
+		pt := common.PrettyTable{
+			ShortColumns: []common.Column{
+				{Name: "Consumer"}, {Name: "Provider"}, {Name: "Resources"},
+				{Name: "Reason"}, {Name: "Unknown"},
+			},
+		}
+		pt.AddItems(audits)
+		common.PrintPrettyTable(pt, false)
+	},
+}
+
+func init() {
+	allocationAudit.Flags().StringArray("provider", []string{},
+		"Resource provider uuid or name to audit, audit all providers if not specified")
+
+	allocation.AddCommand(allocationShow, allocationAudit)
+}
@@ -0,0 +1,11 @@
+package placement
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var Placement = &cobra.Command{Use: "placement", Short: "Placement resource providers and allocations"}
+
+func init() {
+	Placement.AddCommand(provider, allocation, trait)
+}
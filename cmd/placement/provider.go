@@ -0,0 +1,171 @@
+package placement
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/placement"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var provider = &cobra.Command{Use: "provider", Short: "Resource providers"}
+
+var providerListPageFlags flags.PageFlags
+
+var providerList = &cobra.Command{
+	Use:   "list",
+	Short: "List resource providers",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		long, _ := cmd.Flags().GetBool("long")
+		name, _ := cmd.Flags().GetString("name")
+		inTree, _ := cmd.Flags().GetString("in-tree")
+		required, _ := cmd.Flags().GetStringArray("required")
+		resources, _ := cmd.Flags().GetStringArray("resources")
+
+		query := url.Values{}
+		if name != "" {
+			query.Set("name", name)
+		}
+		if inTree != "" {
+			query.Set("in_tree", inTree)
+		}
+		if len(required) > 0 {
+			query.Set("required", strings.Join(required, ","))
+		}
+		if len(resources) > 0 {
+			query.Set("resources", strings.Join(resources, ","))
+		}
+		client := openstack.DefaultClient()
+		providers, err := client.PlacementV1().ResourceProvider().List(query)
+		utility.LogError(err, "list resource providers failed", true)
+		providers, err = flags.PageItems(providerListPageFlags, providers,
+			func(provider placement.ResourceProvider) string { return provider.Uuid })
+		utility.LogError(err, "list resource providers failed", true)
+
+		pt := common.PrettyTable{
+			ShortColumns: []common.Column{
+				{Name: "Uuid"}, {Name: "Name", Sort: true}, {Name: "Generation"},
+			},
+			LongColumns: []common.Column{
+				{Name: "RootProviderUuid"}, {Name: "ParentProviderUuid"},
+			},
+		}
+		pt.AddItems(providers)
+		common.PrintPrettyTable(pt, long)
+	},
+}
+var providerShow = &cobra.Command{
+	Use:   "show <provider>",
+	Short: "Show resource provider",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		api := client.PlacementV1().ResourceProvider()
+		rp, err := api.Find(args[0])
+		utility.LogIfError(err, true, "get resource provider %s failed", args[0])
+		traits, err := api.Traits(rp.Uuid)
+		utility.LogIfError(err, false, "get traits of %s failed", args[0])
+		aggregates, err := api.Aggregates(rp.Uuid)
+		utility.LogIfError(err, false, "get aggregates of %s failed", args[0])
+
+		pt := common.PrettyItemTable{
+			Item: *rp,
+			ShortFields: []common.Column{
+				{Name: "Uuid"}, {Name: "Name"}, {Name: "Generation"},
+				{Name: "RootProviderUuid"}, {Name: "ParentProviderUuid"},
+				{Name: "Traits", Slot: func(item interface{}) interface{} {
+					return strings.Join(traits, "\n")
+				}},
+				{Name: "Aggregates", Slot: func(item interface{}) interface{} {
+					return strings.Join(aggregates, "\n")
+				}},
+			},
+		}
+		common.PrintPrettyItemTable(pt)
+	},
+}
+var providerInventory = &cobra.Command{
+	Use:   "inventory <provider>",
+	Short: "Show inventories of resource provider",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		api := client.PlacementV1().ResourceProvider()
+		rp, err := api.Find(args[0])
+		utility.LogIfError(err, true, "get resource provider %s failed", args[0])
+		inventories, err := api.Inventories(rp.Uuid)
+		utility.LogIfError(err, true, "get inventories of %s failed", args[0])
+
+		pt := common.PrettyTable{
+			ShortColumns: []common.Column{
+				{Name: "ResourceClass"}, {Name: "Total"}, {Name: "Reserved"},
+				{Name: "AllocationRatio"},
+				{Name: "Capacity", Slot: func(item interface{}) interface{} {
+					p, _ := item.(placement.Inventory)
+					return p.Capacity()
+				}},
+				{Name: "MinUnit"}, {Name: "MaxUnit"}, {Name: "StepSize"},
+			},
+		}
+		pt.AddItems(inventories)
+		common.PrintPrettyTable(pt, false)
+	},
+}
+var providerUsage = &cobra.Command{
+	Use:   "usage <provider>",
+	Short: "Show usages of resource provider",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		client := openstack.DefaultClient()
+		api := client.PlacementV1().ResourceProvider()
+		rp, err := api.Find(args[0])
+		utility.LogIfError(err, true, "get resource provider %s failed", args[0])
+		usages, err := api.Usages(rp.Uuid)
+		utility.LogIfError(err, true, "get usages of %s failed", args[0])
+		inventories, err := api.Inventories(rp.Uuid)
+		utility.LogIfError(err, true, "get inventories of %s failed", args[0])
+
+		items := []placement.Usage{}
+		for _, inventory := range inventories {
+			items = append(items, placement.Usage{
+				ResourceClass: inventory.ResourceClass,
+				Used:          usages[inventory.ResourceClass],
+				Capacity:      inventory.Capacity(),
+			})
+		}
+		pt := common.PrettyTable{
+			ShortColumns: []common.Column{
+				{Name: "ResourceClass"}, {Name: "Used"}, {Name: "Capacity"},
+				{Name: "Percent", Slot: func(item interface{}) interface{} {
+					p, _ := item.(placement.Usage)
+					if p.Capacity == 0 {
+						return "-"
+					}
+					return fmt.Sprintf("%.1f%%", float64(p.Used)*100/float64(p.Capacity))
+				}},
+			},
+		}
+		pt.AddItems(items)
+		common.PrintPrettyTable(pt, false)
+	},
+}
+
+func init() {
+	providerList.Flags().BoolP("long", "l", false, "List additional fields in output")
+	providerList.Flags().String("name", "", "List resource providers matched by name")
+	providerList.Flags().String("in-tree", "", "List resource providers in the same tree with the provider uuid")
+	providerList.Flags().StringArray("required", []string{},
+		"List resource providers which have the trait, e.g. HW_CPU_X86_AVX2, or !HW_CPU_X86_AVX2 for forbidden trait")
+	providerList.Flags().StringArray("resources", []string{},
+		"List resource providers which have capacity for the resource, e.g. VCPU:2")
+	providerListPageFlags = flags.NewPageFlags(providerList)
+
+	provider.AddCommand(providerList, providerShow, providerInventory, providerUsage)
+}
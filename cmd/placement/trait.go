@@ -0,0 +1,73 @@
+package placement
+
+import (
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var trait = &cobra.Command{Use: "trait", Short: "Traits"}
+
+type traitItem struct {
+	Name string
+}
+
+var traitListPageFlags flags.PageFlags
+
+var traitList = &cobra.Command{
+	Use:   "list",
+	Short: "List traits",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		name, _ := cmd.Flags().GetString("name")
+		associated, _ := cmd.Flags().GetBool("associated")
+		providerArg, _ := cmd.Flags().GetString("provider")
+
+		client := openstack.DefaultClient()
+		var (
+			traits []string
+			err    error
+		)
+		if providerArg != "" {
+			rp, err := client.PlacementV1().ResourceProvider().Find(providerArg)
+			utility.LogIfError(err, true, "get resource provider %s failed", providerArg)
+			traits, err = client.PlacementV1().ResourceProvider().Traits(rp.Uuid)
+			utility.LogIfError(err, true, "get traits of %s failed", providerArg)
+		} else {
+			query := url.Values{}
+			if name != "" {
+				// 例如 startswith:HW_CPU, in:HW_CPU_X86_AVX,HW_CPU_X86_AVX2
+				query.Set("name", name)
+			}
+			if associated {
+				query.Set("associated", "true")
+			}
+			traits, err = client.PlacementV1().Trait().List(query)
+			utility.LogError(err, "list traits failed", true)
+		}
+		traits, err = flags.PageItems(traitListPageFlags, traits,
+			func(trait string) string { return trait })
+		utility.LogError(err, "list traits failed", true)
+		items := []traitItem{}
+		for _, name := range traits {
+			items = append(items, traitItem{Name: name})
+		}
+		pt := common.PrettyTable{ShortColumns: []common.Column{{Name: "Name"}}}
+		pt.AddItems(items)
+		common.PrintPrettyTable(pt, false)
+	},
+}
+
+func init() {
+	traitList.Flags().String("name", "", "Filter traits, e.g. startswith:HW_CPU or in:HW_CPU_X86_AVX,HW_CPU_X86_AVX2")
+	traitList.Flags().Bool("associated", false, "Only list traits associated with resource providers")
+	traitList.Flags().String("provider", "", "List traits of resource provider")
+	traitListPageFlags = flags.NewPageFlags(traitList)
+
+	trait.AddCommand(traitList)
+}
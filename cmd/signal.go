@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/BytemanD/go-console/console"
+)
+
+// 收到 SIGINT/SIGTERM 时取消 context, 正在进行的请求和等待会中止并返回被中断的操作;
+// 再次收到信号时直接退出
+func signalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-signals:
+			console.Warn("received signal %s, cancelling (send again to force exit)", sig)
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+		sig := <-signals
+		console.Error("received signal %s again, exit", sig)
+		os.Exit(130)
+	}()
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel()
+	}
+}
@@ -15,19 +15,28 @@ import (
 	"github.com/BytemanD/skyman/cmd/context"
 	"github.com/BytemanD/skyman/cmd/neutron"
 
+	"github.com/BytemanD/skyman/cmd/barbican"
 	"github.com/BytemanD/skyman/cmd/benchmark"
 	"github.com/BytemanD/skyman/cmd/cinder"
+	"github.com/BytemanD/skyman/cmd/designate"
 	"github.com/BytemanD/skyman/cmd/glance"
+	"github.com/BytemanD/skyman/cmd/heat"
+	"github.com/BytemanD/skyman/cmd/ironic"
 	"github.com/BytemanD/skyman/cmd/keystone"
+	"github.com/BytemanD/skyman/cmd/manila"
 
 	"github.com/BytemanD/skyman/cmd/nova"
+	"github.com/BytemanD/skyman/cmd/octavia"
+	"github.com/BytemanD/skyman/cmd/placement"
 	"github.com/BytemanD/skyman/cmd/quota"
+	"github.com/BytemanD/skyman/cmd/swift"
 	"github.com/BytemanD/skyman/cmd/templates"
 	"github.com/BytemanD/skyman/cmd/test"
 	"github.com/BytemanD/skyman/cmd/tool"
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/common/i18n"
 	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/session"
 	"github.com/BytemanD/skyman/utility"
 )
 
@@ -128,19 +137,20 @@ func main() {
 				os.Exit(1)
 			}
 			conf, _ := cmd.Flags().GetString("conf")
-			if conf == "" {
+			cloud, _ := cmd.Flags().GetString("os-cloud")
+			if conf == "" && cloud == "" {
 				ctxConf, err := context.LoadContextConf()
 				if err != nil {
 					console.Debug("load context failed: %s", err)
 				} else {
 					if ctx := ctxConf.GetCurrent(); ctx != nil {
 						console.Debug("use conf from context")
-						conf = ctx.Conf
+						conf, cloud = ctx.Conf, ctx.Cloud
 					}
 				}
 			}
 
-			if err := common.LoadConfig(conf); err != nil {
+			if err := common.LoadConfig(conf, cloud); err != nil {
 				fmt.Printf("load config failed: %v\n", err)
 				os.Exit(1)
 			}
@@ -151,16 +161,36 @@ func main() {
 				console.SetLogFile(common.CONF.LogFile)
 			}
 			console.Debug("load config file from %s", viper.ConfigFileUsed())
+			recordDir, _ := cmd.Flags().GetString("record")
+			replayDir, _ := cmd.Flags().GetString("replay")
+			if recordDir != "" && replayDir != "" {
+				console.Fatal("--record and --replay are mutually exclusive")
+			}
+			if recordDir != "" {
+				if err := session.SetRecordDir(recordDir); err != nil {
+					console.Fatal("enable record mode failed: %s", err)
+				}
+			} else if replayDir != "" {
+				if err := session.SetReplayDir(replayDir); err != nil {
+					console.Fatal("enable replay mode failed: %s", err)
+				}
+			}
 			computeApiVersion, _ := cmd.Flags().GetString("compute-api-version")
 			openstack.COMPUTE_API_VERSION = computeApiVersion
+			placementApiVersion, _ := cmd.Flags().GetString("placement-api-version")
+			openstack.PLACEMENT_API_VERSION = placementApiVersion
 		},
 	}
 
 	rootCmd.PersistentFlags().BoolP("debug", "d", false, i18n.T("showDebug"))
 	rootCmd.PersistentFlags().String("log-file", "", i18n.T("logFile"))
 	rootCmd.PersistentFlags().Bool("log-color", false, i18n.T("enableLogColor"))
+	rootCmd.PersistentFlags().String("record", "", i18n.T("recordDir"))
+	rootCmd.PersistentFlags().String("replay", "", i18n.T("replayDir"))
 	rootCmd.PersistentFlags().StringP("conf", "c", os.Getenv("SKYMAN_CONF_FILE"),
 		i18n.T("thePathOfConfigFile"))
+	rootCmd.PersistentFlags().String("os-cloud", os.Getenv("OS_CLOUD"),
+		i18n.T("cloudNameInCloudsYaml"))
 	rootCmd.PersistentFlags().StringP("format", "f", "table",
 		fmt.Sprintf(i18n.T("formatAndSupported"), common.GetOutputFormats()))
 
@@ -170,6 +200,11 @@ func main() {
 	viper.BindPFlag("enableLogColor", rootCmd.PersistentFlags().Lookup("log-color"))
 
 	rootCmd.PersistentFlags().String("compute-api-version", "", "Compute API version")
+	rootCmd.PersistentFlags().String("placement-api-version", "", "Placement API version")
+	rootCmd.PersistentFlags().Bool("insecure", false, i18n.T("insecure"))
+	rootCmd.PersistentFlags().String("os-interface", "", i18n.T("endpointInterface"))
+	viper.BindPFlag("interface", rootCmd.PersistentFlags().Lookup("os-interface"))
+	viper.BindPFlag("insecure", rootCmd.PersistentFlags().Lookup("insecure"))
 
 	TestCmd.AddCommand(
 		test.TestFio, test.ServerPing, test.TestNetQos, test.TestServerAction,
@@ -190,6 +225,14 @@ func main() {
 
 		neutron.Router, neutron.Network, neutron.Subnet, neutron.Port,
 		neutron.Security, neutron.SG,
+		placement.Placement,
+		octavia.LB,
+		heat.Stack,
+		swift.Object,
+		ironic.Baremetal,
+		manila.Share,
+		designate.Dns,
+		barbican.Secret,
 
 		quota.QuotaCmd,
 		templates.DefineCmd, templates.UndefineCmd,
@@ -197,5 +240,8 @@ func main() {
 		TestCmd,
 		benchmark.BenchmarkCmd,
 	)
-	rootCmd.Execute()
+	ctx, stop := signalContext()
+	defer stop()
+	openstack.SetDefaultContext(ctx)
+	rootCmd.ExecuteContext(ctx)
 }
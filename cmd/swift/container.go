@@ -0,0 +1,96 @@
+package swift
+
+import (
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/swift"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var container = &cobra.Command{Use: "container", Short: "Object storage containers"}
+
+var containerListPageFlags flags.PageFlags
+
+var containerList = &cobra.Command{
+	Use:   "list",
+	Short: "List containers",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		c := openstack.DefaultClient()
+		prefix, _ := cmd.Flags().GetString("prefix")
+		query := url.Values{}
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		containers, err := c.SwiftV1().Container().List(query)
+		utility.LogError(err, "list containers failed", true)
+		containers, err = flags.PageItems(containerListPageFlags, containers,
+			func(container swift.Container) string { return container.Name })
+		utility.LogError(err, "list containers failed", true)
+
+		table := datatable.DataTable[swift.Container]{
+			Items: containers,
+			Columns: []datatable.Column[swift.Container]{
+				{Name: "Name"}, {Name: "Count"}, {Name: "Bytes"}, {Name: "LastModified"},
+			},
+		}
+		common.PrintDataTable[swift.Container](&table, false)
+	},
+}
+var containerCreate = &cobra.Command{
+	Use:   "create <container> [container ...]",
+	Short: "Create container(s)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		for _, name := range args {
+			if err := c.SwiftV1().Container().Create(name); err != nil {
+				console.Error("create container %s failed, %s", name, err)
+				continue
+			}
+			console.Info("created container %s", name)
+		}
+	},
+}
+var containerDelete = &cobra.Command{
+	Use:   "delete <container> [container ...]",
+	Short: "Delete container(s)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		for _, name := range args {
+			if recursive {
+				objects, err := c.SwiftV1().Object(name).List(nil)
+				if err != nil {
+					console.Error("list objects of container %s failed, %s", name, err)
+					continue
+				}
+				for _, object := range objects {
+					if err := c.SwiftV1().Object(name).Delete(object.Name); err != nil {
+						console.Error("delete object %s failed, %s", object.Name, err)
+					}
+				}
+			}
+			console.Info("Reqeust to delete container %s", name)
+			if err := c.SwiftV1().Container().Delete(name); err != nil {
+				console.Error("Delete container %s failed, %s", name, err)
+			}
+		}
+	},
+}
+
+func init() {
+	containerList.Flags().String("prefix", "", "Only list containers beginning with the prefix")
+	containerListPageFlags = flags.NewPageFlags(containerList)
+	containerDelete.Flags().BoolP("recursive", "r", false, "Delete all objects in the container first")
+
+	container.AddCommand(containerList, containerCreate, containerDelete)
+}
@@ -0,0 +1,124 @@
+package swift
+
+import (
+	"net/url"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/common/datatable"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/swift"
+	"github.com/BytemanD/skyman/utility"
+)
+
+var Object = &cobra.Command{Use: "object", Short: "Object storage (Swift)"}
+
+const MB = 1024 * 1024
+
+var objectListPageFlags flags.PageFlags
+
+var objectList = &cobra.Command{
+	Use:   "list <container>",
+	Short: "List objects",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		long, _ := cmd.Flags().GetBool("long")
+		prefix, _ := cmd.Flags().GetString("prefix")
+		delimiter, _ := cmd.Flags().GetString("delimiter")
+		query := url.Values{}
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if delimiter != "" {
+			query.Set("delimiter", delimiter)
+		}
+		objects, err := c.SwiftV1().Object(args[0]).List(query)
+		utility.LogError(err, "list objects failed", true)
+		objects, err = flags.PageItems(objectListPageFlags, objects,
+			func(object swift.Object) string { return utility.OneOfString(object.Name, object.Subdir) })
+		utility.LogError(err, "list objects failed", true)
+
+		table := datatable.DataTable[swift.Object]{
+			Items: objects,
+			Columns: []datatable.Column[swift.Object]{
+				{Name: "Name", RenderFunc: func(item swift.Object) interface{} {
+					return utility.OneOfString(item.Name, item.Subdir)
+				}},
+				{Name: "Bytes"}, {Name: "LastModified"},
+			},
+			MoreColumns: []datatable.Column[swift.Object]{
+				{Name: "Hash"}, {Name: "ContentType"},
+			},
+		}
+		common.PrintDataTable[swift.Object](&table, long)
+	},
+}
+var objectUpload = &cobra.Command{
+	Use:   "upload <container> <file>",
+	Short: "Upload file",
+	Long:  "Upload file, files larger than the segment size are uploaded as static large objects",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		name, _ := cmd.Flags().GetString("name")
+		segmentSize, _ := cmd.Flags().GetInt64("segment-size")
+		parallel, _ := cmd.Flags().GetInt("parallel")
+
+		name = utility.OneOfString(name, filepath.Base(args[1]))
+		console.Info("uploading %s to %s/%s", args[1], args[0], name)
+		err := c.SwiftV1().Object(args[0]).Upload(c.Context(), name, args[1],
+			swift.UploadOpt{SegmentSize: segmentSize * MB, Parallel: parallel},
+		)
+		utility.LogError(err, "upload object failed", true)
+		console.Info("uploaded %s/%s", args[0], name)
+	},
+}
+var objectDownload = &cobra.Command{
+	Use:   "download <container> <object>",
+	Short: "Download object",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		file, _ := cmd.Flags().GetString("file")
+
+		file = utility.OneOfString(file, filepath.Base(args[1]))
+		console.Info("saving object to %s", file)
+		err := c.SwiftV1().Object(args[0]).Download(c.Context(), args[1], file)
+		utility.LogError(err, "download object failed", true)
+		console.Info("object saved")
+	},
+}
+var objectDelete = &cobra.Command{
+	Use:   "delete <container> <object> [object ...]",
+	Short: "Delete object(s)",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openstack.DefaultClient()
+		for _, name := range args[1:] {
+			console.Info("Reqeust to delete object %s", name)
+			if err := c.SwiftV1().Object(args[0]).Delete(name); err != nil {
+				console.Error("Delete object %s failed, %s", name, err)
+			}
+		}
+	},
+}
+
+func init() {
+	objectList.Flags().BoolP("long", "l", false, "List additional fields in output")
+	objectList.Flags().String("prefix", "", "Only list objects beginning with the prefix")
+	objectList.Flags().String("delimiter", "", "Roll up objects by the delimiter, e.g. /")
+	objectListPageFlags = flags.NewPageFlags(objectList)
+
+	objectUpload.Flags().String("name", "", "Object name, defaults to the file name")
+	objectUpload.Flags().Int64("segment-size", 1024, "Segment size in MiB")
+	objectUpload.Flags().Int("parallel", swift.DEFAULT_UPLOAD_PARALLEL, "Number of segments to upload in parallel")
+
+	objectDownload.Flags().String("file", "", "Save to the file, defaults to the base name of the object")
+
+	Object.AddCommand(container, objectList, objectUpload, objectDownload, objectDelete)
+}
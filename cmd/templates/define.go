@@ -91,6 +91,7 @@ func createServer(client *openstack.Openstack, server Server, watch bool) (*nova
 		AvailabilityZone: server.AvailabilityZone,
 		MinCount:         server.Min,
 		MaxCount:         server.Max,
+		KeyName:          server.KeyName,
 	}
 	for _, sg := range server.SecurityGroups {
 		serverOption.SecurityGroups = append(
@@ -156,6 +157,11 @@ func createServer(client *openstack.Openstack, server Server, watch bool) (*nova
 	if server.UserData != "" {
 		serverOption.UserData = utility.EncodedUserdata(server.UserData)
 	}
+	if server.Group != "" {
+		group, err := computeClient.ServerGroup().Find(server.Group)
+		utility.LogError(err, "get server group failed", true)
+		serverOption.SchedulerHints = map[string]interface{}{"group": group.Id}
+	}
 	s, err = computeClient.Server().Create(serverOption)
 	utility.LogError(err, "create server failed", true)
 	console.Info("creating server %s", serverOption.Name)
@@ -40,6 +40,9 @@ type Server struct {
 	BlockDeviceMappingV2 []BlockDeviceMappingV2 `yaml:"blockDeviceMappingV2,omitempty"`
 	UserData             string                 `yaml:"userData"`
 	SecurityGroups       []SecurityGroup        `yaml:"securityGroups,omitempty"`
+	// 服务器组的名字或 ID
+	Group   string `yaml:"group,omitempty"`
+	KeyName string `yaml:"keyName,omitempty"`
 }
 
 type Flavor struct {
@@ -9,6 +9,7 @@ import (
 
 	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/model/neutron"
 	"github.com/BytemanD/skyman/utility"
 	"github.com/spf13/cobra"
 )
@@ -42,23 +43,56 @@ var vpcCreate = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		vpc, cidr := args[0], args[1]
 
-		c := openstack.DefaultClient().NeutronV2()
+		client := openstack.DefaultClient()
+		c := client.NeutronV2()
 		routerName := fmt.Sprintf("%s-router", vpc)
 		networkName := fmt.Sprintf("%s-network", vpc)
 		subnetName := fmt.Sprintf("%s-subnet", vpc)
 		ipVersion, _ := cmd.Flags().GetString("ip-version")
 		ipVersions := strings.Split(ipVersion, ",")
+		dnsZone, _ := cmd.Flags().GetString("dns-zone")
 
+		// 设置网络的 dns_domain 后, 由 neutron 的 DNS 集成为端口和浮动 IP 注册 A/AAAA 记录,
+		// 创建资源之前检查 neutron 是否开启了 DNS 集成
+		networkParams := map[string]interface{}{"name": networkName}
+		if dnsZone != "" {
+			_, err := c.Extension().Show(neutron.EXTENSION_DNS_INTEGRATION)
+			utility.LogIfError(err, true, "neutron extension %s is not available", neutron.EXTENSION_DNS_INTEGRATION)
+			zone, err := client.DesignateV2().Zone().Find(dnsZone)
+			utility.LogIfError(err, true, "get DNS zone %s failed", dnsZone)
+			networkParams["dns_domain"] = zone.Name
+		}
+		// 创建失败时按相反的顺序删除已经创建的资源
+		rollbacks := []func() error{}
+		checkError := func(err error, format string, args ...interface{}) {
+			if err == nil {
+				return
+			}
+			utility.LogIfError(err, false, format, args...)
+			for i := len(rollbacks) - 1; i >= 0; i-- {
+				if err := rollbacks[i](); err != nil {
+					console.Error("rollback failed: %s", err)
+				}
+			}
+			console.Fatal("create VPC %s failed", vpc)
+		}
 		// create router
 		routerParams := map[string]interface{}{"name": routerName}
 		console.Info("create router %s", routerName)
 		router, err := c.Router().Create(routerParams)
 		utility.LogIfError(err, true, "create router %s failed", routerName)
+		rollbacks = append(rollbacks, func() error {
+			console.Info("delete router %s", routerName)
+			return c.Router().Delete(router.Id)
+		})
 		// create network
-		networkParams := map[string]interface{}{"name": networkName}
 		console.Info("create network %s", networkName)
 		network, err := c.Network().Create(networkParams)
-		utility.LogIfError(err, true, "create network %s failed", networkParams)
+		checkError(err, "create network %s failed", networkName)
+		rollbacks = append(rollbacks, func() error {
+			console.Info("delete network %s", networkName)
+			return c.Network().Delete(network.Id)
+		})
 		// create router
 		for _, v := range ipVersions {
 			subneVerionName := fmt.Sprintf("%s-v%s", subnetName, v)
@@ -70,13 +104,17 @@ var vpcCreate = &cobra.Command{
 			}
 			console.Info("create subnet %s", subneVerionName)
 			subnet, err := c.Subnet().Create(subnetParams)
-			utility.LogIfError(err, true, "create subnet %s failed", subnetName)
+			checkError(err, "create subnet %s failed", subneVerionName)
 			// add router interface
 			console.Info("add subnet %s to router %s", subneVerionName, routerName)
 			err = c.Router().AddSubnet(router.Id, subnet.Id)
-			utility.LogIfError(err, true, "add subnet %s to router %s failed", subnetName, routerName)
-			console.Info("create VPC %s success", vpc)
+			checkError(err, "add subnet %s to router %s failed", subneVerionName, routerName)
+			rollbacks = append(rollbacks, func() error {
+				console.Info("remove subnet %s from router %s", subneVerionName, routerName)
+				return c.Router().RemoveSubnet(router.Id, subnet.Id)
+			})
 		}
+		console.Info("create VPC %s success", vpc)
 	},
 }
 var vpcDelete = &cobra.Command{
@@ -128,6 +166,7 @@ var vpcDelete = &cobra.Command{
 
 func init() {
 	vpcCreate.Flags().StringP("ip-version", "v", "4", "IP version")
+	vpcCreate.Flags().String("dns-zone", "", "DNS zone used as the dns_domain of the VPC network")
 
 	vpcDelete.Flags().StringP("router", "r", "", "Router id or name")
 
@@ -44,6 +44,6 @@ func init() {
 		All:    volumePrune.Flags().Bool("all", false, "Search by all tenants"),
 		Yes:    volumePrune.Flags().BoolP("yes", "y", false, i18n.T("answerYes")),
 		Marker: volumePrune.Flags().String("marker", "", "Marker"),
-		Limit:  volumePrune.Flags().Uint("limit", 1000, "Limit"),
+		Limit:  volumePrune.Flags().Uint("limit", 1000, "Number of volumes to request in each paginated request"),
 	}
 }
@@ -1,15 +1,22 @@
 package prune
 
 import (
+	"github.com/BytemanD/skyman/cmd/views"
 	"github.com/spf13/cobra"
 )
 
-var PruneCmd = &cobra.Command{Use: "prune", Short: "prune resources"}
+var PruneCmd = &cobra.Command{
+	Use: "prune", Short: "prune resources",
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		views.PrintRequestStats()
+	},
+}
 
 func init() {
 	PruneCmd.AddCommand(
 		portPrune,
 		serverPrune,
+		sharePrune,
 		volumePrune,
 	)
 }
@@ -0,0 +1,50 @@
+package prune
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/BytemanD/skyman/cmd/flags"
+	"github.com/BytemanD/skyman/common/i18n"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/spf13/cobra"
+)
+
+var sharePruneFlags flags.PruneShareFlags
+
+var sharePrune = &cobra.Command{
+	Use:   "share",
+	Short: "Prune share(s)",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		query := url.Values{}
+		if *sharePruneFlags.Status != "" {
+			query.Add("status", *sharePruneFlags.Status)
+		}
+		if *sharePruneFlags.All {
+			query.Add("all_tenants", "1")
+		}
+		if *sharePruneFlags.Limit > 0 {
+			query.Add("limit", strconv.Itoa(int(*sharePruneFlags.Limit)))
+		}
+		if *sharePruneFlags.Marker != "" {
+			query.Add("marker", *sharePruneFlags.Marker)
+		}
+		c := openstack.DefaultClient()
+		c.PruneShares(query, *sharePruneFlags.Name, *sharePruneFlags.Type,
+			*sharePruneFlags.Yes, *sharePruneFlags.Force)
+	},
+}
+
+func init() {
+	sharePruneFlags = flags.PruneShareFlags{
+		Name:   sharePrune.Flags().StringP("name", "n", "", "Filter by share name"),
+		Status: sharePrune.Flags().StringP("status", "s", "error", "Search by share status, e.g. available, error"),
+		Type:   sharePrune.Flags().StringP("type", "t", "", "Search by share type id or name"),
+		All:    sharePrune.Flags().Bool("all", false, "Search by all tenants"),
+		Yes:    sharePrune.Flags().BoolP("yes", "y", false, i18n.T("answerYes")),
+		Force:  sharePrune.Flags().Bool("force", false, "Use force delete (admin only)"),
+		Marker: sharePrune.Flags().String("marker", "", "Marker"),
+		Limit:  sharePrune.Flags().Uint("limit", 1000, "Number of shares to request in each paginated request"),
+	}
+}
@@ -3,8 +3,10 @@ package prune
 import (
 	"net/url"
 
+	"github.com/BytemanD/skyman/common"
 	"github.com/BytemanD/skyman/common/i18n"
 	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/utility"
 	"github.com/spf13/cobra"
 )
 
@@ -17,6 +19,9 @@ var serverPrune = &cobra.Command{
 		name, _ := cmd.Flags().GetString("name")
 		host, _ := cmd.Flags().GetString("host")
 		statusList, _ := cmd.Flags().GetStringArray("status")
+		tags, _ := cmd.Flags().GetString("tags")
+		notTags, _ := cmd.Flags().GetString("not-tags")
+		metadataList, _ := cmd.Flags().GetStringArray("metadata")
 
 		query := url.Values{}
 		if name != "" {
@@ -28,8 +33,20 @@ var serverPrune = &cobra.Command{
 		for _, status := range statusList {
 			query.Add("status", status)
 		}
+		if tags != "" {
+			query.Set("tags", tags)
+		}
+		if notTags != "" {
+			query.Set("not-tags", notTags)
+		}
+		metadata := map[string]string{}
+		for _, item := range metadataList {
+			kv, err := common.SplitKeyValue(item)
+			utility.LogError(err, "invalid metadata", true)
+			metadata[kv[0]] = kv[1]
+		}
 		c := openstack.DefaultClient()
-		c.PruneServers(query, yes, true)
+		c.PruneServers(query, metadata, yes, true)
 	},
 }
 
@@ -37,5 +54,8 @@ func init() {
 	serverPrune.Flags().StringP("name", "n", "", "Search by server name")
 	serverPrune.Flags().String("host", "", "Search by hostname")
 	serverPrune.Flags().StringArrayP("status", "s", nil, "Search by server status")
+	serverPrune.Flags().String("tags", "", "Search by tags, split with ','")
+	serverPrune.Flags().String("not-tags", "", "Exclude servers with all of the tags, split with ','")
+	serverPrune.Flags().StringArray("metadata", nil, "Search by metadata, format: key=value")
 	serverPrune.Flags().BoolP("yes", "y", false, i18n.T("answerYes"))
 }
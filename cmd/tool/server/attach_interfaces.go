@@ -11,6 +11,7 @@ import (
 	"github.com/BytemanD/easygo/pkg/stringutils"
 	"github.com/BytemanD/easygo/pkg/syncutils"
 	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/views"
 	"github.com/BytemanD/skyman/openstack"
 	"github.com/BytemanD/skyman/openstack/model/neutron"
 	"github.com/BytemanD/skyman/utility"
@@ -21,6 +22,9 @@ var attachInterfaces = &cobra.Command{
 	Use:   "add-interfaces <server> <network1> [<network2>...]",
 	Short: "Add interfaces to server",
 	Args:  cobra.MinimumNArgs(2),
+	PostRun: func(cmd *cobra.Command, args []string) {
+		views.PrintRequestStats()
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		nums, _ := cmd.Flags().GetInt("nums")
 		parallel, _ := cmd.Flags().GetInt("parallel")
@@ -11,6 +11,7 @@ import (
 
 	"github.com/BytemanD/easygo/pkg/arrayutils"
 	"github.com/BytemanD/easygo/pkg/syncutils"
+	"github.com/BytemanD/skyman/cmd/views"
 	"github.com/BytemanD/skyman/openstack"
 	"github.com/BytemanD/skyman/utility"
 )
@@ -19,6 +20,9 @@ var attachVolume = &cobra.Command{
 	Use:   "add-volumes <server>",
 	Short: "Add volumes to server",
 	Args:  cobra.ExactArgs(1),
+	PostRun: func(cmd *cobra.Command, args []string) {
+		views.PrintRequestStats()
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		nums, _ := cmd.Flags().GetInt("nums")
 		parallel, _ := cmd.Flags().GetInt("parallel")
@@ -7,6 +7,7 @@ import (
 
 	"github.com/BytemanD/easygo/pkg/syncutils"
 	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/cmd/views"
 	"github.com/BytemanD/skyman/openstack"
 	"github.com/BytemanD/skyman/openstack/model/nova"
 	"github.com/BytemanD/skyman/utility"
@@ -17,6 +18,9 @@ var detachInterfaces = &cobra.Command{
 	Use:   "remove-interfaces <server>",
 	Short: "Remove interfaces from server",
 	Args:  cobra.MinimumNArgs(1),
+	PostRun: func(cmd *cobra.Command, args []string) {
+		views.PrintRequestStats()
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		nums, _ := cmd.Flags().GetInt("nums")
 		parallel, _ := cmd.Flags().GetInt("parallel")
@@ -71,6 +71,15 @@ func PrintServer(server nova.Server, client *openstack.Openstack) {
 				return string(bytes)
 			}},
 			{Name: "Progress"},
+			{Name: "Locked"}, {Name: "LockedReason"},
+			{Name: "Tags", Slot: func(item interface{}) interface{} {
+				p, _ := item.(nova.Server)
+				return strings.Join(p.Tags, ", ")
+			}},
+			{Name: "Metadata", Slot: func(item interface{}) interface{} {
+				p, _ := item.(nova.Server)
+				return strings.Join(nova.ExtraSpecs(p.Metadata).GetList(), "\n")
+			}},
 			{Name: "Created"}, {Name: "LaunchedAt"}, {Name: "Updated"}, {Name: "TerminatedAt"},
 
 			{Name: "Fault:code", Text: "Fault:code",
@@ -0,0 +1,21 @@
+package views
+
+import (
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/openstack/session"
+)
+
+// 打印各服务的请求统计, 用于批量操作结束后查看限流和重试情况
+//
+// 有限流或重试时总是输出, 否则只在调试模式下输出
+func PrintRequestStats() {
+	for _, stats := range session.GetRequestStats() {
+		if stats.Throttled+stats.Retried > 0 {
+			console.Info("%s: %d request(s), %d throttled, %d retried",
+				stats.Service, stats.Requests, stats.Throttled, stats.Retried)
+		} else {
+			console.Debug("%s: %d request(s), %d throttled, %d retried",
+				stats.Service, stats.Requests, stats.Throttled, stats.Retried)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/utility"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	CLOUDS_YAML = "clouds.yaml"
+	SECURE_YAML = "secure.yaml"
+)
+
+type CloudAuth struct {
+	AuthUrl                     string `yaml:"auth_url"`
+	Username                    string `yaml:"username"`
+	Password                    string `yaml:"password"`
+	UserDomainName              string `yaml:"user_domain_name"`
+	UserDomainId                string `yaml:"user_domain_id"`
+	ProjectName                 string `yaml:"project_name"`
+	ProjectId                   string `yaml:"project_id"`
+	ProjectDomainName           string `yaml:"project_domain_name"`
+	ProjectDomainId             string `yaml:"project_domain_id"`
+	DomainName                  string `yaml:"domain_name"`
+	ApplicationCredentialId     string `yaml:"application_credential_id"`
+	ApplicationCredentialName   string `yaml:"application_credential_name"`
+	ApplicationCredentialSecret string `yaml:"application_credential_secret"`
+	Token                       string `yaml:"token"`
+}
+
+type Cloud struct {
+	Auth               CloudAuth `yaml:"auth"`
+	AuthType           string    `yaml:"auth_type"`
+	RegionName         string    `yaml:"region_name"`
+	Interface          string    `yaml:"interface"`
+	CACert             string    `yaml:"cacert"`
+	Cert               string    `yaml:"cert"`
+	Key                string    `yaml:"key"`
+	Verify             *bool     `yaml:"verify"`
+	IdentityApiVersion string    `yaml:"identity_api_version"`
+}
+
+// clouds.yaml 和 secure.yaml 的查找路径, 优先级从高到低
+func CloudsConfigDirs() []string {
+	dirs := []string{"."}
+	if u, err := user.Current(); err == nil {
+		dirs = append(dirs, filepath.Join(u.HomeDir, ".config", "openstack"))
+	}
+	return append(dirs, "/etc/openstack")
+}
+
+func findCloudsFile(envName string, fileName string) string {
+	if file := os.Getenv(envName); file != "" {
+		return file
+	}
+	for _, dir := range CloudsConfigDirs() {
+		file := filepath.Join(dir, fileName)
+		if _, err := os.Stat(file); err == nil {
+			return file
+		}
+	}
+	return ""
+}
+
+func readCloudsFile(file string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	content := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("parse %s failed: %s", file, err)
+	}
+	clouds, _ := content["clouds"].(map[string]interface{})
+	if clouds == nil {
+		clouds = map[string]interface{}{}
+	}
+	return clouds, nil
+}
+
+// 把 src 合并到 dst 中, secure.yaml 中的配置覆盖 clouds.yaml
+func mergeMap(dst, src map[string]interface{}) {
+	for k, v := range src {
+		srcMap, srcIsMap := v.(map[string]interface{})
+		dstMap, dstIsMap := dst[k].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeMap(dstMap, srcMap)
+		} else {
+			dst[k] = v
+		}
+	}
+}
+
+// 读取所有的 cloud 配置
+func LoadClouds() (map[string]Cloud, error) {
+	cloudsFile := findCloudsFile("OS_CLIENT_CONFIG_FILE", CLOUDS_YAML)
+	if cloudsFile == "" {
+		return nil, fmt.Errorf("%s not found in %v", CLOUDS_YAML, CloudsConfigDirs())
+	}
+	console.Debug("load clouds from %s", cloudsFile)
+	clouds, err := readCloudsFile(cloudsFile)
+	if err != nil {
+		return nil, err
+	}
+	if secureFile := findCloudsFile("OS_CLIENT_SECURE_FILE", SECURE_YAML); secureFile != "" {
+		console.Debug("load secure clouds from %s", secureFile)
+		secureClouds, err := readCloudsFile(secureFile)
+		if err != nil {
+			return nil, err
+		}
+		mergeMap(clouds, secureClouds)
+	}
+	data, err := yaml.Marshal(clouds)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]Cloud{}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func LoadCloud(name string) (*Cloud, error) {
+	clouds, err := LoadClouds()
+	if err != nil {
+		return nil, err
+	}
+	cloud, ok := clouds[name]
+	if !ok {
+		return nil, fmt.Errorf("cloud %s not found", name)
+	}
+	return &cloud, nil
+}
+
+// 使用 cloud 中的配置覆盖当前配置
+func (c *ConfGroup) ApplyCloud(cloud Cloud) {
+	auth := cloud.Auth
+	c.Auth.Type = utility.OneOfString(cloud.AuthType, c.Auth.Type)
+	c.Auth.Url = utility.OneOfString(auth.AuthUrl, c.Auth.Url)
+	c.Auth.User.Name = utility.OneOfString(auth.Username, c.Auth.User.Name)
+	c.Auth.User.Password = utility.OneOfString(auth.Password, c.Auth.User.Password)
+	c.Auth.User.Domain.Name = utility.OneOfString(auth.UserDomainName, auth.DomainName, c.Auth.User.Domain.Name)
+	c.Auth.User.Domain.Id = utility.OneOfString(auth.UserDomainId, c.Auth.User.Domain.Id)
+	c.Auth.Project.Id = utility.OneOfString(auth.ProjectId, c.Auth.Project.Id)
+	c.Auth.Project.Name = utility.OneOfString(auth.ProjectName, c.Auth.Project.Name)
+	c.Auth.Project.Domain.Name = utility.OneOfString(auth.ProjectDomainName, auth.DomainName, c.Auth.Project.Domain.Name)
+	c.Auth.Project.Domain.Id = utility.OneOfString(auth.ProjectDomainId, c.Auth.Project.Domain.Id)
+	c.Auth.ApplicationCredential.Id = utility.OneOfString(auth.ApplicationCredentialId, c.Auth.ApplicationCredential.Id)
+	c.Auth.ApplicationCredential.Name = utility.OneOfString(auth.ApplicationCredentialName, c.Auth.ApplicationCredential.Name)
+	c.Auth.ApplicationCredential.Secret = utility.OneOfString(auth.ApplicationCredentialSecret, c.Auth.ApplicationCredential.Secret)
+	c.Auth.Token = utility.OneOfString(auth.Token, c.Auth.Token)
+	c.Auth.Region.Id = utility.OneOfString(cloud.RegionName, c.Auth.Region.Id)
+	c.Interface = utility.OneOfString(cloud.Interface, c.Interface)
+	c.CACert = utility.OneOfString(cloud.CACert, c.CACert)
+	c.Cert = utility.OneOfString(cloud.Cert, c.Cert)
+	c.Key = utility.OneOfString(cloud.Key, c.Key)
+	if cloud.Verify != nil && !*cloud.Verify {
+		c.Insecure = true
+	}
+	c.Identity.Api.Version = utility.OneOfString(cloud.IdentityApiVersion, c.Identity.Api.Version)
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/BytemanD/go-console/console"
 	"github.com/BytemanD/skyman/common/i18n"
 	"github.com/BytemanD/skyman/openstack/model"
 	"github.com/BytemanD/skyman/openstack/model/keystone"
@@ -25,6 +26,17 @@ var (
 	FORMAT_TABLE_LIGHT        = "table-light"
 	FORMAT_TABLE              = "table"
 	DEFAULT_TOKEN_EXPIRE_TIME = 60 * 30
+	// 配置中没有 retryCount 时的重试次数, retryCount 为 0 表示不重试
+	DEFAULT_RETRY_COUNT = 3
+)
+
+var (
+	AUTH_TYPE_PASSWORD                  = "password"
+	AUTH_TYPE_V3_APPLICATION_CREDENTIAL = "v3applicationcredential"
+	AUTH_TYPE_TOKEN                     = "token"
+	AUTH_TYPES                          = []string{
+		AUTH_TYPE_PASSWORD, AUTH_TYPE_V3_APPLICATION_CREDENTIAL, AUTH_TYPE_TOKEN,
+	}
 )
 
 type ConfGroup struct {
@@ -37,17 +49,65 @@ type ConfGroup struct {
 	LogFile             string `yaml:"logFile"`
 	EnableLogColor      bool   `yaml:"enableLogColor"`
 	BarChar             string `yaml:"barchar"`
+	// 访问服务使用的 endpoint 类型: public, internal, admin
+	Interface string `yaml:"interface"`
+	// TLS 配置
+	CACert   string `yaml:"cacert"`
+	Cert     string `yaml:"cert"`
+	Key      string `yaml:"key"`
+	Insecure bool   `yaml:"insecure"`
+
+	// 指定服务的 endpoint, 例如 compute: http://nova-api:8774/v2.1
+	Endpoints map[string]string `yaml:"endpoints"`
+	// 每秒最多发送的请求数, 按服务类型配置, 例如 compute: 10
+	RateLimit map[string]float64 `yaml:"rateLimit"`
 
 	Auth     Auth        `yaml:"auth"`
 	Identity Identity    `yaml:"identity"`
 	Neutron  NeutronConf `yaml:"neutron"`
 }
+type ApplicationCredential struct {
+	Id     string `yaml:"id"`
+	Name   string `yaml:"name"`
+	Secret string `yaml:"secret"`
+}
 type Auth struct {
-	Url             string          `yaml:"url"`
-	Region          keystone.Region `yaml:"region"`
-	User            model.User      `yaml:"user"`
-	Project         model.Project   `yaml:"project"`
-	TokenExpireTime int             `yaml:"tokenExpireTime"`
+	// 认证方式: password, v3applicationcredential, token
+	Type                  string                `yaml:"type"`
+	Url                   string                `yaml:"url"`
+	Region                keystone.Region       `yaml:"region"`
+	User                  model.User            `yaml:"user"`
+	Project               model.Project         `yaml:"project"`
+	ApplicationCredential ApplicationCredential `yaml:"applicationCredential"`
+	Token                 string                `yaml:"token"`
+	TokenExpireTime       int                   `yaml:"tokenExpireTime"`
+	// 是否在本地缓存 token, 多次执行命令时复用
+	TokenCache bool `yaml:"tokenCache"`
+}
+
+// 返回认证方式
+//
+// 未指定时, 如果设置了应用凭证则使用 v3applicationcredential,
+// 如果只设置了 token 则使用 token, 否则使用 password
+func (a Auth) GetType() string {
+	switch strings.ToLower(a.Type) {
+	case "":
+		if a.ApplicationCredential.Secret != "" {
+			return AUTH_TYPE_V3_APPLICATION_CREDENTIAL
+		}
+		if a.Token != "" && a.User.Password == "" {
+			return AUTH_TYPE_TOKEN
+		}
+		return AUTH_TYPE_PASSWORD
+	case AUTH_TYPE_PASSWORD, "v3password":
+		return AUTH_TYPE_PASSWORD
+	case AUTH_TYPE_V3_APPLICATION_CREDENTIAL:
+		return AUTH_TYPE_V3_APPLICATION_CREDENTIAL
+	case AUTH_TYPE_TOKEN, "v3token":
+		return AUTH_TYPE_TOKEN
+	default:
+		return a.Type
+	}
 }
 
 type Api struct {
@@ -68,10 +128,14 @@ func DefaultConfGroup() ConfGroup {
 		Auth: Auth{
 			TokenExpireTime: DEFAULT_TOKEN_EXPIRE_TIME,
 		},
+		RetryCount: DEFAULT_RETRY_COUNT,
 	}
 }
 
-func LoadConfig(configFile string) error {
+// 加载配置文件
+//
+// 如果指定了 cloudName, 认证信息从 clouds.yaml 中读取, 此时配置文件可以不存在
+func LoadConfig(configFile string, cloudName string) error {
 	viper.AutomaticEnv()
 	viper.SetEnvPrefix("OS")
 	viper.SetEnvKeyReplacer(strings.NewReplacer(
@@ -92,7 +156,10 @@ func LoadConfig(configFile string) error {
 		viper.AddConfigPath("/etc/skyman")
 	}
 	if err := viper.ReadInConfig(); err != nil {
-		return err
+		if cloudName == "" || configFile != "" {
+			return err
+		}
+		console.Debug("read config failed: %s", err)
 	}
 	CONF = DefaultConfGroup()
 	viper.Unmarshal(&CONF)
@@ -112,6 +179,43 @@ func LoadConfig(configFile string) error {
 	if os.Getenv("OS_IDENTITY_API_VERSION") != "" {
 		CONF.Identity.Api.Version = os.Getenv("OS_IDENTITY_API_VERSION")
 	}
+	if os.Getenv("OS_AUTH_TYPE") != "" {
+		CONF.Auth.Type = os.Getenv("OS_AUTH_TYPE")
+	}
+	if os.Getenv("OS_APPLICATION_CREDENTIAL_ID") != "" {
+		CONF.Auth.ApplicationCredential.Id = os.Getenv("OS_APPLICATION_CREDENTIAL_ID")
+	}
+	if os.Getenv("OS_APPLICATION_CREDENTIAL_NAME") != "" {
+		CONF.Auth.ApplicationCredential.Name = os.Getenv("OS_APPLICATION_CREDENTIAL_NAME")
+	}
+	if os.Getenv("OS_APPLICATION_CREDENTIAL_SECRET") != "" {
+		CONF.Auth.ApplicationCredential.Secret = os.Getenv("OS_APPLICATION_CREDENTIAL_SECRET")
+	}
+	if os.Getenv("OS_TOKEN") != "" {
+		CONF.Auth.Token = os.Getenv("OS_TOKEN")
+	}
+	if os.Getenv("OS_INTERFACE") != "" {
+		CONF.Interface = os.Getenv("OS_INTERFACE")
+	}
+	if os.Getenv("OS_CACERT") != "" {
+		CONF.CACert = os.Getenv("OS_CACERT")
+	}
+	if os.Getenv("OS_CERT") != "" {
+		CONF.Cert = os.Getenv("OS_CERT")
+	}
+	if os.Getenv("OS_KEY") != "" {
+		CONF.Key = os.Getenv("OS_KEY")
+	}
+	if strings.EqualFold(os.Getenv("OS_INSECURE"), "true") {
+		CONF.Insecure = true
+	}
+	if cloudName != "" {
+		cloud, err := LoadCloud(cloudName)
+		if err != nil {
+			return err
+		}
+		CONF.ApplyCloud(*cloud)
+	}
 
 	return nil
 }
@@ -22,6 +22,21 @@ func init() {
 		{ID: "thePathOfConfigFile",
 			Other: "the path of config file",
 		},
+		{ID: "cloudNameInCloudsYaml",
+			Other: "the name of cloud in clouds.yaml",
+		},
+		{ID: "insecure",
+			Other: "disable server certificate verification",
+		},
+		{ID: "endpointInterface",
+			Other: "endpoint interface, supported: public, internal, admin",
+		},
+		{ID: "recordDir",
+			Other: "record requests and responses as cassettes in the directory",
+		},
+		{ID: "replayDir",
+			Other: "replay responses from the cassettes in the directory instead of sending requests",
+		},
 		{ID: "showDebug",
 			Other: "show debug messages",
 		},
@@ -54,6 +54,7 @@ type CaseConfig struct {
 
 	BootWithSG string   `yaml:"bootWithSG"`
 	Networks   []string `yaml:"networks"`
+	KeyName    string   `yaml:"keyName"`
 
 	VolumeType string `yaml:"volumeType"`
 	VolumeSize int    `yaml:"volumeSize"`
@@ -109,6 +110,7 @@ func NewActionCaseConfig(config CaseConfig, def CaseConfig) CaseConfig {
 
 		BootWithSG: utility.OneOfString(config.BootWithSG, def.BootWithSG),
 		Networks:   utility.OneOfStringArrays(config.Networks, def.Networks),
+		KeyName:    utility.OneOfString(config.KeyName, def.KeyName),
 
 		VolumeType: utility.OneOfString(config.VolumeType, def.VolumeType),
 		VolumeSize: utility.OneOfNumber(config.VolumeSize, def.VolumeSize, 10),
@@ -26,9 +26,9 @@ require (
 	github.com/spf13/jwalterweatherman v1.1.0 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
 	github.com/subosito/gotenv v1.4.2 // indirect
-	golang.org/x/crypto v0.23.0 // indirect
+	golang.org/x/crypto v0.23.0
 	golang.org/x/sys v0.25.0 // indirect
-	golang.org/x/term v0.20.0 // indirect
+	golang.org/x/term v0.20.0
 	golang.org/x/text v0.15.0
 	gopkg.in/ini.v1 v1.67.0 // indirect
 )
@@ -60,7 +60,7 @@ require (
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/wxnacy/wgo v1.0.4 // indirect
 	golang.org/x/image v0.0.0-20191206065243-da761ea9ff43 // indirect
-	golang.org/x/net v0.25.0 // indirect
+	golang.org/x/net v0.25.0
 )
 
 replace github.com/BytemanD/skyman => ./
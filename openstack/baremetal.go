@@ -0,0 +1,37 @@
+package openstack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BytemanD/skyman/openstack/model/ironic"
+	"github.com/BytemanD/skyman/openstack/model/nova"
+	"github.com/BytemanD/skyman/openstack/session"
+)
+
+// 查询计算节点对应的 ironic 节点
+//
+// ironic 类型的计算节点, hypervisor_hostname 为节点的 uuid; 通过 ironic 部署的
+// 计算节点, 按主机名 (包括不带域名的短主机名) 查找同名的节点
+func (o *Openstack) FindHypervisorNode(hypervisor nova.Hypervisor) (*ironic.Node, error) {
+	candidates := []string{hypervisor.HypervisorHostname}
+	if !strings.EqualFold(hypervisor.Type, IRONIC) {
+		shortName, _, _ := strings.Cut(hypervisor.HypervisorHostname, ".")
+		candidates = append(candidates, hypervisor.Host, shortName)
+	}
+	checked := map[string]bool{}
+	for _, name := range candidates {
+		if name == "" || checked[name] {
+			continue
+		}
+		checked[name] = true
+		node, err := o.IronicV1().Node().Show(name)
+		if err == nil {
+			return node, nil
+		}
+		if !session.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("%w: baremetal node of hypervisor %s", session.ErrNotFound, hypervisor.HypervisorHostname)
+}
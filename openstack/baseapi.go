@@ -4,8 +4,11 @@ OpenStack Client with Golang
 package openstack
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/BytemanD/go-console/console"
 	"github.com/BytemanD/skyman/common"
@@ -13,23 +16,33 @@ import (
 	"github.com/BytemanD/skyman/openstack/internal"
 	"github.com/BytemanD/skyman/openstack/internal/auth_plugin"
 	"github.com/BytemanD/skyman/openstack/model"
+	"github.com/BytemanD/skyman/openstack/session"
 	"github.com/BytemanD/skyman/utility"
 )
 
 const (
+	V1   = "v1"
 	V2   = "v2"
 	V2_0 = "v2.0"
 	V2_1 = "v2.1"
 	V3   = "v3"
 
-	IDENTITY  = "identity"
-	NETWORK   = "network"
-	VOLUME    = "volume"
-	VOLUME_V2 = "volumev2"
-	VOLUME_V3 = "volumev3"
-	STORAGE   = "storage"
-	COMPUTE   = "compute"
-	IMAGE     = "image"
+	IDENTITY      = "identity"
+	NETWORK       = "network"
+	VOLUME        = "volume"
+	VOLUME_V2     = "volumev2"
+	VOLUME_V3     = "volumev3"
+	STORAGE       = "storage"
+	COMPUTE       = "compute"
+	IMAGE         = "image"
+	PLACEMENT     = "placement"
+	LB            = "load-balancer"
+	ORCHESTRATION = "orchestration"
+	OBJECT_STORE  = "object-store"
+	BAREMETAL     = "baremetal"
+	SHARE_V2      = "sharev2"
+	DNS           = "dns"
+	KEY_MANAGER   = "key-manager"
 
 	KEYSTONE  = "keystone"
 	NOVA      = "nova"
@@ -38,28 +51,75 @@ const (
 	CINDER_V3 = "cinderv3"
 	GLANCE    = "glance"
 	NEUTRON   = "neutron"
+	OCTAVIA   = "octavia"
+	HEAT      = "heat"
+	SWIFT     = "swift"
+	IRONIC    = "ironic"
+	MANILA    = "manila"
+	DESIGNATE = "designate"
+	BARBICAN  = "barbican"
 
 	PUBLIC   = "public"
 	INTERNAL = "internal"
 	ADMIN    = "admin"
 )
 
+// 服务类型对应的服务名
+var SERVICE_NAMES = map[string]string{
+	IDENTITY:      KEYSTONE,
+	COMPUTE:       NOVA,
+	NETWORK:       NEUTRON,
+	IMAGE:         GLANCE,
+	VOLUME:        CINDER,
+	VOLUME_V2:     CINDER,
+	VOLUME_V3:     CINDER,
+	STORAGE:       CINDER,
+	PLACEMENT:     PLACEMENT,
+	LB:            OCTAVIA,
+	ORCHESTRATION: HEAT,
+	OBJECT_STORE:  SWIFT,
+	BAREMETAL:     IRONIC,
+	SHARE_V2:      MANILA,
+	DNS:           DESIGNATE,
+	KEY_MANAGER:   BARBICAN,
+}
+
 var COMPUTE_API_VERSION string
+var PLACEMENT_API_VERSION string
+
+// 默认客户端使用的 context, 命令行中收到 SIGINT/SIGTERM 时取消
+var defaultContext = context.Background()
+
+func SetDefaultContext(ctx context.Context) {
+	defaultContext = ctx
+}
 
 type Openstack struct {
-	AuthPlugin        auth_plugin.AuthPlugin
-	ComputeApiVersion string
+	AuthPlugin          auth_plugin.AuthPlugin
+	ComputeApiVersion   string
+	PlacementApiVersion string
 
-	novaClient *internal.NovaV2
+	novaClient      *internal.NovaV2
+	placementClient *internal.PlacementV1
 
 	keystoneClient *internal.KeystoneV3
 	glanceClient   *internal.GlanceV2
 	cinderClient   *internal.CinderV2
 	neutronClient  *internal.NeutronV2
+	octaviaClient  *internal.OctaviaV2
+	heatClient     *internal.HeatV1
+	swiftClient    *internal.SwiftV1
+	ironicClient   *internal.IronicV1
+	manilaClient   *internal.ManilaV2
+	dnsClient      *internal.DesignateV2
+	barbicanClient *internal.BarbicanV1
 
 	servieLock *sync.Mutex
+	ctx        context.Context
 
-	neutronEndpoint string
+	// 服务类型 -> endpoint, 用于替换服务目录中的地址
+	endpoints         map[string]string
+	endpointInterface string
 }
 
 func (o *Openstack) WithRegion(region string) *Openstack {
@@ -69,56 +129,200 @@ func (o *Openstack) WithRegion(region string) *Openstack {
 	authPlugin := o.AuthPlugin
 	authPlugin.SetRegion(region)
 	return &Openstack{
-		AuthPlugin:        authPlugin,
-		ComputeApiVersion: o.ComputeApiVersion,
+		AuthPlugin:          authPlugin,
+		ComputeApiVersion:   o.ComputeApiVersion,
+		PlacementApiVersion: o.PlacementApiVersion,
 
-		neutronEndpoint: o.neutronEndpoint,
+		endpoints:         o.endpoints,
+		endpointInterface: o.endpointInterface,
 
 		servieLock: &sync.Mutex{},
+		ctx:        o.ctx,
 	}
 }
+
+// 设置 context, 取消后正在进行的请求和等待操作都会中止
+func (o *Openstack) SetContext(ctx context.Context) {
+	o.servieLock.Lock()
+	defer o.servieLock.Unlock()
+
+	o.ctx = ctx
+	o.AuthPlugin.SetContext(ctx)
+	if o.novaClient != nil {
+		o.novaClient.SetContext(ctx)
+	}
+	if o.keystoneClient != nil {
+		o.keystoneClient.SetContext(ctx)
+	}
+	if o.glanceClient != nil {
+		o.glanceClient.SetContext(ctx)
+	}
+	if o.cinderClient != nil {
+		o.cinderClient.SetContext(ctx)
+	}
+	if o.neutronClient != nil {
+		o.neutronClient.SetContext(ctx)
+	}
+	if o.placementClient != nil {
+		o.placementClient.SetContext(ctx)
+	}
+	if o.octaviaClient != nil {
+		o.octaviaClient.SetContext(ctx)
+	}
+	if o.heatClient != nil {
+		o.heatClient.SetContext(ctx)
+	}
+	if o.swiftClient != nil {
+		o.swiftClient.SetContext(ctx)
+	}
+	if o.ironicClient != nil {
+		o.ironicClient.SetContext(ctx)
+	}
+	if o.manilaClient != nil {
+		o.manilaClient.SetContext(ctx)
+	}
+	if o.dnsClient != nil {
+		o.dnsClient.SetContext(ctx)
+	}
+	if o.barbicanClient != nil {
+		o.barbicanClient.SetContext(ctx)
+	}
+}
+func (o *Openstack) Context() context.Context {
+	if o.ctx == nil {
+		return context.Background()
+	}
+	return o.ctx
+}
 func (o Openstack) Region() string {
 	return o.AuthPlugin.Region()
 }
 func (o Openstack) ProjectId() (string, error) {
 	return o.AuthPlugin.GetProjectId()
 }
+func (o Openstack) Interface() string {
+	// 兼容 publicURL, internalURL 这种格式
+	return strings.TrimSuffix(strings.ToLower(utility.OneOfString(o.endpointInterface, PUBLIC)), "url")
+}
 
-func NewClient(authUrl string, user model.User, project model.Project, regionName string) *Openstack {
-	authUrl = utility.VersionUrl(authUrl, fmt.Sprintf("v%s", common.CONF.Identity.Api.Version))
-	passwordAuth := internal.NewPasswordAuth(authUrl, user, project, regionName)
+func (o *Openstack) SetEndpoint(serviceType string, endpoint string) {
+	if o.endpoints == nil {
+		o.endpoints = map[string]string{}
+	}
+	o.endpoints[serviceType] = endpoint
+}
+
+// 获取服务的 endpoint
+//
+// 优先使用配置中指定的 endpoint (按 serviceTypes 的顺序查找), 否则从服务目录中查找
+func (o *Openstack) GetServiceEndpoint(serviceName string, serviceTypes ...string) (string, error) {
+	for _, serviceType := range serviceTypes {
+		if endpoint := o.endpoints[serviceType]; endpoint != "" {
+			console.Debug("use %s endpoint from conf: %s", serviceType, endpoint)
+			return endpoint, nil
+		}
+	}
+	return o.AuthPlugin.GetServiceEndpoint(serviceTypes[0], serviceName, o.Interface())
+}
+
+func newClient(authPlugin auth_plugin.AuthPlugin) *Openstack {
 	console.Debug("new openstack client, HttpTimeoutSecond=%d RetryWaitTimeSecond=%d RetryCount=%d",
 		common.CONF.HttpTimeoutSecond, common.CONF.RetryWaitTimeSecond, common.CONF.RetryCount,
 	)
-	passwordAuth.SetHttpTimeout(common.CONF.HttpTimeoutSecond)
-	passwordAuth.SetRetryWaitTime(common.CONF.RetryWaitTimeSecond)
-	passwordAuth.SetRetryCount(common.CONF.RetryCount)
+	if err := session.SetRetryOptions(common.CONF.RetryCount,
+		time.Second*time.Duration(common.CONF.RetryWaitTimeSecond)); err != nil {
+		console.Fatal("%s", err)
+	}
+	authPlugin.SetHttpTimeout(common.CONF.HttpTimeoutSecond)
+	authPlugin.SetRetryWaitTime(common.CONF.RetryWaitTimeSecond)
+	authPlugin.SetRetryCount(common.CONF.RetryCount)
+	session.SetSafeHeaderFunc(authPlugin.GetSafeHeader)
+	for serviceType, rate := range common.CONF.RateLimit {
+		if serviceName, ok := SERVICE_NAMES[serviceType]; ok {
+			session.SetRateLimit(serviceName, rate)
+		} else {
+			console.Warn("unknown service type '%s' in rateLimit", serviceType)
+		}
+	}
+	return &Openstack{AuthPlugin: authPlugin, servieLock: &sync.Mutex{}}
+}
 
-	return &Openstack{AuthPlugin: passwordAuth, servieLock: &sync.Mutex{}}
+func identityUrl(authUrl string) string {
+	return utility.VersionUrl(authUrl, fmt.Sprintf("v%s", common.CONF.Identity.Api.Version))
+}
+
+func NewClient(authUrl string, user model.User, project model.Project, regionName string) *Openstack {
+	return newClient(internal.NewPasswordAuth(identityUrl(authUrl), user, project, regionName))
+}
+func NewApplicationCredentialClient(authUrl string, credential model.ApplicationCredential, regionName string) *Openstack {
+	return newClient(internal.NewApplicationCredentialAuth(identityUrl(authUrl), credential, regionName))
+}
+func NewTokenClient(authUrl string, token string, project model.Project, regionName string) *Openstack {
+	return newClient(internal.NewTokenAuth(identityUrl(authUrl), token, project, regionName))
 }
 
 func ClientWithRegion(region string) *Openstack {
+	err := session.SetTLSOptions(session.TLSOptions{
+		CACert:   common.CONF.CACert,
+		Cert:     common.CONF.Cert,
+		Key:      common.CONF.Key,
+		Insecure: common.CONF.Insecure,
+	})
+	if err != nil {
+		console.Fatal("invalid tls options: %s", err)
+	}
 	user := model.User{
 		Name:     common.CONF.Auth.User.Name,
 		Password: common.CONF.Auth.User.Password,
-		Domain:   model.Domain{Name: common.CONF.Auth.User.Domain.Name},
+		Domain: model.Domain{
+			Id: common.CONF.Auth.User.Domain.Id, Name: common.CONF.Auth.User.Domain.Name,
+		},
 	}
 	project := model.Project{
+		Id:   common.CONF.Auth.Project.Id,
 		Name: common.CONF.Auth.Project.Name,
 		Domain: model.Domain{
-			Name: common.CONF.Auth.Project.Domain.Name,
+			Id: common.CONF.Auth.Project.Domain.Id, Name: common.CONF.Auth.Project.Domain.Name,
 		},
 	}
-	c := NewClient(common.CONF.Auth.Url, user, project, region)
+	var c *Openstack
+	switch common.CONF.Auth.GetType() {
+	case common.AUTH_TYPE_PASSWORD:
+		c = NewClient(common.CONF.Auth.Url, user, project, region)
+	case common.AUTH_TYPE_V3_APPLICATION_CREDENTIAL:
+		credential := model.ApplicationCredential{
+			Id:     common.CONF.Auth.ApplicationCredential.Id,
+			Name:   common.CONF.Auth.ApplicationCredential.Name,
+			Secret: common.CONF.Auth.ApplicationCredential.Secret,
+		}
+		if credential.Id == "" {
+			credential.User = &user
+		}
+		c = NewApplicationCredentialClient(common.CONF.Auth.Url, credential, region)
+	case common.AUTH_TYPE_TOKEN:
+		c = NewTokenClient(common.CONF.Auth.Url, common.CONF.Auth.Token, project, region)
+	default:
+		console.Fatal("unsupported auth type '%s', supported: %v",
+			common.CONF.Auth.Type, common.AUTH_TYPES)
+	}
 	c.AuthPlugin.SetLocalTokenExpire(common.CONF.Auth.TokenExpireTime)
+	c.AuthPlugin.EnableTokenCache(common.CONF.Auth.TokenCache)
+	c.SetContext(defaultContext)
 	return c
 }
 
 func DefaultClient() *Openstack {
 	c := ClientWithRegion(common.CONF.Auth.Region.Id)
 	c.ComputeApiVersion = "2.1"
-	c.neutronEndpoint = common.CONF.Neutron.Endpoint
+	c.endpointInterface = common.CONF.Interface
+	for serviceType, endpoint := range common.CONF.Endpoints {
+		c.SetEndpoint(serviceType, endpoint)
+	}
+	if common.CONF.Neutron.Endpoint != "" && c.endpoints[NETWORK] == "" {
+		c.SetEndpoint(NETWORK, common.CONF.Neutron.Endpoint)
+	}
 	c.ComputeApiVersion = COMPUTE_API_VERSION
+	c.PlacementApiVersion = PLACEMENT_API_VERSION
 	return c
 }
 
@@ -127,7 +331,7 @@ func (o *Openstack) GlanceV2() *internal.GlanceV2 {
 	defer o.servieLock.Unlock()
 
 	if o.glanceClient == nil {
-		endpoint, err := o.AuthPlugin.GetServiceEndpoint(IMAGE, GLANCE, PUBLIC)
+		endpoint, err := o.GetServiceEndpoint(GLANCE, IMAGE)
 		if err != nil {
 			console.Fatal("get glance endpoint falied: %v", err)
 
@@ -135,6 +339,8 @@ func (o *Openstack) GlanceV2() *internal.GlanceV2 {
 		o.glanceClient = &internal.GlanceV2{
 			ServiceClient: internal.NewServiceApi[internal.ServiceClient](endpoint, V2, o.AuthPlugin),
 		}
+		o.glanceClient.ServiceName = GLANCE
+		o.glanceClient.SetContext(o.Context())
 	}
 	return o.glanceClient
 }
@@ -148,7 +354,7 @@ func (o *Openstack) CinderV2() *internal.CinderV2 {
 			endpoint string
 			err      error
 		)
-		endpoint, err = o.AuthPlugin.GetServiceEndpoint(VOLUME_V2, CINDER_V2, PUBLIC)
+		endpoint, err = o.GetServiceEndpoint(CINDER_V2, VOLUME_V2, VOLUME_V3, VOLUME)
 		if err != nil {
 			console.Fatal("get cinder endpoint falied: %v", err)
 
@@ -156,6 +362,8 @@ func (o *Openstack) CinderV2() *internal.CinderV2 {
 		o.cinderClient = &internal.CinderV2{
 			ServiceClient: internal.NewServiceApi[internal.ServiceClient](endpoint, V2, o.AuthPlugin),
 		}
+		o.cinderClient.ServiceName = CINDER
+		o.cinderClient.SetContext(o.Context())
 	}
 	return o.cinderClient
 }
@@ -165,33 +373,175 @@ func (o *Openstack) NeutronV2() *internal.NeutronV2 {
 	defer o.servieLock.Unlock()
 
 	if o.neutronClient == nil {
-		endpoint := o.neutronEndpoint
-		if endpoint == "" {
-			var err error
-			endpoint, err = o.AuthPlugin.GetServiceEndpoint(NETWORK, NEUTRON, PUBLIC)
-			if err != nil {
-				console.Fatal("get neutron endpoint falied: %v", err)
-
-			}
+		endpoint, err := o.GetServiceEndpoint(NEUTRON, NETWORK)
+		if err != nil {
+			console.Fatal("get neutron endpoint falied: %v", err)
 		}
 		o.neutronClient = &internal.NeutronV2{
 			ServiceClient: internal.NewServiceApi(endpoint, V2_0, o.AuthPlugin),
 		}
+		o.neutronClient.ServiceName = NEUTRON
+		o.neutronClient.SetContext(o.Context())
 	}
 	return o.neutronClient
 }
+func (o *Openstack) OctaviaV2() *internal.OctaviaV2 {
+	o.servieLock.Lock()
+	defer o.servieLock.Unlock()
+
+	if o.octaviaClient == nil {
+		endpoint, err := o.GetServiceEndpoint(OCTAVIA, LB)
+		if err != nil {
+			console.Fatal("get octavia endpoint falied: %v", err)
+		}
+		o.octaviaClient = &internal.OctaviaV2{
+			ServiceClient: internal.NewServiceApi(endpoint, V2, o.AuthPlugin),
+		}
+		o.octaviaClient.ServiceName = OCTAVIA
+		o.octaviaClient.SetContext(o.Context())
+	}
+	return o.octaviaClient
+}
+
+func (o *Openstack) HeatV1() *internal.HeatV1 {
+	o.servieLock.Lock()
+	defer o.servieLock.Unlock()
+
+	if o.heatClient == nil {
+		endpoint, err := o.GetServiceEndpoint(HEAT, ORCHESTRATION)
+		if err != nil {
+			console.Fatal("get heat endpoint falied: %v", err)
+		}
+		o.heatClient = &internal.HeatV1{
+			ServiceClient: internal.NewServiceApi(endpoint, V1, o.AuthPlugin),
+		}
+		o.heatClient.ServiceName = HEAT
+		o.heatClient.SetContext(o.Context())
+	}
+	return o.heatClient
+}
+
+func (o *Openstack) SwiftV1() *internal.SwiftV1 {
+	o.servieLock.Lock()
+	defer o.servieLock.Unlock()
+
+	if o.swiftClient == nil {
+		endpoint, err := o.GetServiceEndpoint(SWIFT, OBJECT_STORE)
+		if err != nil {
+			console.Fatal("get swift endpoint falied: %v", err)
+		}
+		o.swiftClient = &internal.SwiftV1{
+			ServiceClient: internal.NewServiceApi(endpoint, V1, o.AuthPlugin),
+		}
+		o.swiftClient.ServiceName = SWIFT
+		o.swiftClient.SetContext(o.Context())
+	}
+	return o.swiftClient
+}
+
+func (o *Openstack) IronicV1() *internal.IronicV1 {
+	o.servieLock.Lock()
+	defer o.servieLock.Unlock()
+
+	if o.ironicClient == nil {
+		endpoint, err := o.GetServiceEndpoint(IRONIC, BAREMETAL)
+		if err != nil {
+			console.Fatal("get ironic endpoint falied: %v", err)
+		}
+		o.ironicClient = &internal.IronicV1{
+			ServiceClient: internal.NewServiceApi(endpoint, V1, o.AuthPlugin),
+		}
+		o.ironicClient.ServiceName = IRONIC
+		o.ironicClient.SetContext(o.Context())
+		// 不指定版本时, ironic 使用最小版本 1.1, 不支持按名字查询节点
+		apiVersion := "latest"
+		if currentVersion, err := o.ironicClient.GetCurrentVersion(); err != nil {
+			console.Warn("get current version failed: %v", err)
+		} else {
+			apiVersion = currentVersion.Version
+		}
+		console.Debug("current ironic version: %s", apiVersion)
+		o.ironicClient.AddBaseHeader("X-OpenStack-Ironic-API-Version", apiVersion)
+	}
+	return o.ironicClient
+}
+
+func (o *Openstack) ManilaV2() *internal.ManilaV2 {
+	o.servieLock.Lock()
+	defer o.servieLock.Unlock()
+
+	if o.manilaClient == nil {
+		// manila v2 服务名通常是 manilav2, 只按类型查找
+		endpoint, err := o.GetServiceEndpoint("", SHARE_V2)
+		if err != nil {
+			console.Fatal("get manila endpoint falied: %v", err)
+		}
+		o.manilaClient = &internal.ManilaV2{
+			ServiceClient: internal.NewServiceApi(endpoint, V2, o.AuthPlugin),
+		}
+		o.manilaClient.ServiceName = MANILA
+		o.manilaClient.SetContext(o.Context())
+		// 不指定版本时, manila 使用最小版本 2.0
+		if currentVersion, err := o.manilaClient.GetCurrentVersion(); err != nil {
+			console.Warn("get current version failed: %v", err)
+		} else {
+			o.manilaClient.MicroVersion = currentVersion
+			console.Debug("current manila version: %s", currentVersion.Version)
+			o.manilaClient.AddBaseHeader("X-OpenStack-Manila-API-Version", currentVersion.Version)
+		}
+	}
+	return o.manilaClient
+}
+
+func (o *Openstack) DesignateV2() *internal.DesignateV2 {
+	o.servieLock.Lock()
+	defer o.servieLock.Unlock()
+
+	if o.dnsClient == nil {
+		endpoint, err := o.GetServiceEndpoint(DESIGNATE, DNS)
+		if err != nil {
+			console.Fatal("get designate endpoint falied: %v", err)
+		}
+		o.dnsClient = &internal.DesignateV2{
+			ServiceClient: internal.NewServiceApi(endpoint, V2, o.AuthPlugin),
+		}
+		o.dnsClient.ServiceName = DESIGNATE
+		o.dnsClient.SetContext(o.Context())
+	}
+	return o.dnsClient
+}
+
+func (o *Openstack) BarbicanV1() *internal.BarbicanV1 {
+	o.servieLock.Lock()
+	defer o.servieLock.Unlock()
+
+	if o.barbicanClient == nil {
+		endpoint, err := o.GetServiceEndpoint(BARBICAN, KEY_MANAGER)
+		if err != nil {
+			console.Fatal("get barbican endpoint falied: %v", err)
+		}
+		o.barbicanClient = &internal.BarbicanV1{
+			ServiceClient: internal.NewServiceApi(endpoint, V1, o.AuthPlugin),
+		}
+		o.barbicanClient.ServiceName = BARBICAN
+		o.barbicanClient.SetContext(o.Context())
+	}
+	return o.barbicanClient
+}
 func (o *Openstack) KeystoneV3() *internal.KeystoneV3 {
 	o.servieLock.Lock()
 	defer o.servieLock.Unlock()
 
 	if o.keystoneClient == nil {
-		endpoint, err := o.AuthPlugin.GetServiceEndpoint(IDENTITY, KEYSTONE, PUBLIC)
+		endpoint, err := o.GetServiceEndpoint(KEYSTONE, IDENTITY)
 		if err != nil {
 			console.Fatal("get keystone endpoint falied: %v", err)
 		}
 		o.keystoneClient = &internal.KeystoneV3{
 			ServiceClient: internal.NewServiceApi[internal.ServiceClient](endpoint, V3, o.AuthPlugin),
 		}
+		o.keystoneClient.ServiceName = KEYSTONE
+		o.keystoneClient.SetContext(o.Context())
 	}
 	return o.keystoneClient
 }
@@ -200,7 +550,7 @@ func (o *Openstack) NovaV2(microVersion ...string) *internal.NovaV2 {
 	defer o.servieLock.Unlock()
 
 	if o.novaClient == nil {
-		endpoint, err := o.AuthPlugin.GetServiceEndpoint(COMPUTE, NOVA, PUBLIC)
+		endpoint, err := o.GetServiceEndpoint(NOVA, COMPUTE)
 		if err != nil {
 			console.Warn("get nova endpoint falied: %v", err)
 
@@ -209,6 +559,8 @@ func (o *Openstack) NovaV2(microVersion ...string) *internal.NovaV2 {
 		o.novaClient = &internal.NovaV2{
 			ServiceClient: internal.NewServiceApi(endpoint, V2_1, o.AuthPlugin),
 		}
+		o.novaClient.ServiceName = NOVA
+		o.novaClient.SetContext(o.Context())
 		if o.ComputeApiVersion != "" {
 			o.novaClient.MicroVersion = &model.ApiVersion{
 				Version: o.ComputeApiVersion,
@@ -233,3 +585,36 @@ func (o *Openstack) NovaV2(microVersion ...string) *internal.NovaV2 {
 	}
 	return o.novaClient
 }
+
+func (o *Openstack) PlacementV1() *internal.PlacementV1 {
+	o.servieLock.Lock()
+	defer o.servieLock.Unlock()
+
+	if o.placementClient == nil {
+		endpoint, err := o.GetServiceEndpoint(PLACEMENT, PLACEMENT)
+		if err != nil {
+			console.Fatal("get placement endpoint falied: %v", err)
+		}
+		// placement 的 endpoint 不包含版本号
+		o.placementClient = &internal.PlacementV1{
+			ServiceClient: internal.NewServiceApi(endpoint, "", o.AuthPlugin),
+		}
+		o.placementClient.ServiceName = PLACEMENT
+		o.placementClient.SetContext(o.Context())
+		if o.PlacementApiVersion != "" {
+			o.placementClient.MicroVersion = &model.ApiVersion{Version: o.PlacementApiVersion}
+		} else {
+			console.Debug("get current version of placement")
+			currentVersion, err := o.placementClient.GetCurrentVersion()
+			if err != nil {
+				console.Warn("get current version failed: %v", err)
+				o.placementClient.MicroVersion = &model.ApiVersion{Version: "1.0"}
+			} else {
+				o.placementClient.MicroVersion = currentVersion
+			}
+		}
+		console.Debug("current placement version: %s", o.placementClient.MicroVersion.Version)
+		o.placementClient.AddBaseHeader("OpenStack-API-Version", "placement "+o.placementClient.MicroVersion.Version)
+	}
+	return o.placementClient
+}
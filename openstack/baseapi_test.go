@@ -0,0 +1,30 @@
+package openstack
+
+import (
+	"testing"
+
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/openstack/fake"
+)
+
+func TestManilaEndpoint(t *testing.T) {
+	cloud := fake.NewCloud()
+	defer cloud.Close()
+
+	common.CONF.Identity.Api.Version = "3"
+	client := NewClient(cloud.AuthUrl(), cloud.User(), cloud.Project(), cloud.Region)
+	client.AuthPlugin.SetLocalTokenExpire(3600)
+
+	// 服务目录中 sharev2 的服务名是 manilav2
+	endpoint, err := client.GetServiceEndpoint("", SHARE_V2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manilaClient := client.ManilaV2()
+	if manilaClient.Url != endpoint {
+		t.Errorf("expect manila endpoint %s, but got %s", endpoint, manilaClient.Url)
+	}
+	if manilaClient.MicroVersion == nil || manilaClient.MicroVersion.Version != "2.65" {
+		t.Errorf("expect manila version 2.65, but got %v", manilaClient.MicroVersion)
+	}
+}
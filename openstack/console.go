@@ -0,0 +1,111 @@
+package openstack
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/openstack/session"
+)
+
+// 默认的退出字符 Ctrl-], 与 telnet/virsh console 一致
+const CONSOLE_ESCAPE = 0x1d
+
+type ConsoleAttachOpt struct {
+	// 输入中包含该字符时断开连接, 为 0 时不检查
+	Escape byte
+	// 控制台输出同时写入 Log
+	Log io.Writer
+}
+
+// 解析退出字符, 例如 ^] 表示 Ctrl-]
+func ParseConsoleEscape(escape string) (byte, error) {
+	if len(escape) == 2 && escape[0] == '^' {
+		c := strings.ToUpper(escape)[1]
+		if c >= '@' && c <= '_' {
+			return c & 0x1f, nil
+		}
+	}
+	if len(escape) == 1 {
+		return escape[0], nil
+	}
+	return 0, fmt.Errorf("invalid escape character %s, expect a single character or ^X", escape)
+}
+
+// 转发远程控制台和本地的输入输出, 直到输入退出字符、远程连接断开或者 ctx 结束
+//
+// 返回前关闭远程连接, 并等待远程的输出全部写入 out
+func ProxyConsole(ctx context.Context, remote io.ReadWriteCloser, in io.Reader, out io.Writer, opt ConsoleAttachOpt) error {
+	if opt.Log != nil {
+		out = io.MultiWriter(out, opt.Log)
+	}
+	outputDone := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(out, remote)
+		outputDone <- err
+	}()
+	inputDone := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := in.Read(buf)
+			if n > 0 {
+				data := buf[:n]
+				index := -1
+				if opt.Escape != 0 {
+					index = bytes.IndexByte(data, opt.Escape)
+				}
+				if index >= 0 {
+					data = data[:index]
+				}
+				if len(data) > 0 {
+					if _, err := remote.Write(data); err != nil {
+						inputDone <- err
+						return
+					}
+				}
+				if index >= 0 {
+					inputDone <- nil
+					return
+				}
+			}
+			if err != nil {
+				inputDone <- err
+				return
+			}
+		}
+	}()
+	var err error
+	select {
+	case <-ctx.Done():
+		err = session.Interrupted(ctx, "attach console")
+	case err = <-inputDone:
+	case err = <-outputDone:
+		remote.Close()
+		return err
+	}
+	remote.Close()
+	<-outputDone
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
+// 连接虚拟机的串口控制台
+func (o *Openstack) AttachSerialConsole(serverId string, in io.Reader, out io.Writer, opt ConsoleAttachOpt) error {
+	serialConsole, err := o.NovaV2().Server().SerialConsoleUrl(serverId)
+	if err != nil {
+		return fmt.Errorf("get serial console url failed: %w", err)
+	}
+	console.Debug("serial console url: %s", serialConsole.Url)
+	conn, err := session.DialWebsocket(serialConsole.Url, "binary")
+	if err != nil {
+		return err
+	}
+	return ProxyConsole(o.Context(), conn, in, out, opt)
+}
@@ -0,0 +1,50 @@
+package openstack
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestParseConsoleEscape(t *testing.T) {
+	cases := map[string]byte{"^]": 0x1d, "^o": 0x0f, "~": '~'}
+	for escape, expect := range cases {
+		if c, err := ParseConsoleEscape(escape); err != nil || c != expect {
+			t.Errorf("parse %s: expect %#x, but got %#x (%v)", escape, expect, c, err)
+		}
+	}
+	if _, err := ParseConsoleEscape("ab"); err == nil {
+		t.Errorf("expect error for invalid escape")
+	}
+}
+
+func TestProxyConsole(t *testing.T) {
+	local, remote := net.Pipe()
+	received := make(chan string, 1)
+	go func() {
+		local.Write([]byte("login: "))
+		buf := make([]byte, 64)
+		n, _ := local.Read(buf)
+		received <- string(buf[:n])
+		io.Copy(io.Discard, local)
+	}()
+	out, log := bytes.Buffer{}, bytes.Buffer{}
+	// 退出字符后的输入不会发送到远程
+	in := strings.NewReader("root\r\x1dexit\r")
+	opt := ConsoleAttachOpt{Escape: CONSOLE_ESCAPE, Log: &log}
+	if err := ProxyConsole(context.Background(), remote, in, &out, opt); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-received; got != "root\r" {
+		t.Errorf("expect remote received 'root\\r', but got %q", got)
+	}
+	if out.String() != "login: " {
+		t.Errorf("expect output 'login: ', but got %q", out.String())
+	}
+	if log.String() != out.String() {
+		t.Errorf("expect log %q equal to output %q", log.String(), out.String())
+	}
+}
@@ -0,0 +1,95 @@
+package openstack
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/openstack/model/designate"
+	"github.com/BytemanD/skyman/openstack/model/nova"
+)
+
+var invalidHostnameChars = regexp.MustCompile("[^a-z0-9-]+")
+
+// 虚拟机在 zone 中的记录名, 例如 vm_01 -> vm-01.example.com.
+func ServerRecordName(server nova.Server, zone string) string {
+	hostname := invalidHostnameChars.ReplaceAllString(strings.ToLower(server.GuestHostname()), "-")
+	return designate.RecordName(strings.Trim(hostname, "-"), zone)
+}
+
+// 虚拟机的 IPv4 和 IPv6 地址 (包括浮动 IP)
+func serverAddresses(server nova.Server) (ipv4 []string, ipv6 []string) {
+	for _, addresses := range server.Addresses {
+		for _, address := range addresses {
+			if address.Version == 6 {
+				ipv6 = append(ipv6, address.Addr)
+			} else {
+				ipv4 = append(ipv4, address.Addr)
+			}
+		}
+	}
+	return ipv4, ipv6
+}
+
+// 为虚拟机的固定 IP 和浮动 IP 注册 A/AAAA 记录, 注册失败时删除已经创建的记录
+func (o *Openstack) RegisterServerRecords(zoneIdOrName string, server nova.Server, ttl int) ([]designate.RecordSet, error) {
+	zone, err := o.DesignateV2().Zone().Find(zoneIdOrName)
+	if err != nil {
+		return nil, err
+	}
+	name := ServerRecordName(server, zone.Name)
+	ipv4, ipv6 := serverAddresses(server)
+	if len(ipv4) == 0 && len(ipv6) == 0 {
+		return nil, fmt.Errorf("server %s has no address", server.Id)
+	}
+	recordSets := []designate.RecordSet{}
+	for _, recordType := range []string{designate.RECORD_A, designate.RECORD_AAAA} {
+		records := ipv4
+		if recordType == designate.RECORD_AAAA {
+			records = ipv6
+		}
+		if len(records) == 0 {
+			continue
+		}
+		console.Info("register %s record %s -> %v", recordType, name, records)
+		recordSet, err := o.DesignateV2().RecordSet(zone.Id).Create(name, recordType, records, ttl)
+		if err != nil {
+			for _, created := range recordSets {
+				console.Warn("delete %s record %s", created.Type, created.Name)
+				if err := o.DesignateV2().RecordSet(zone.Id).Delete(created.Id); err != nil {
+					console.Error("delete %s record %s failed: %s", created.Type, created.Name, err)
+				}
+			}
+			return nil, fmt.Errorf("create %s record %s failed: %w", recordType, name, err)
+		}
+		recordSets = append(recordSets, *recordSet)
+	}
+	return recordSets, nil
+}
+
+// 删除虚拟机的 A/AAAA 记录, 返回删除的记录集数量
+func (o *Openstack) UnregisterServerRecords(zoneIdOrName string, server nova.Server) (int, error) {
+	zone, err := o.DesignateV2().Zone().Find(zoneIdOrName)
+	if err != nil {
+		return 0, err
+	}
+	name := ServerRecordName(server, zone.Name)
+	recordSets, err := o.DesignateV2().RecordSet(zone.Id).List(url.Values{"name": []string{name}})
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, recordSet := range recordSets {
+		if recordSet.Type != designate.RECORD_A && recordSet.Type != designate.RECORD_AAAA {
+			continue
+		}
+		console.Info("unregister %s record %s", recordSet.Type, recordSet.Name)
+		if err := o.DesignateV2().RecordSet(zone.Id).Delete(recordSet.Id); err != nil {
+			return deleted, fmt.Errorf("delete %s record %s failed: %w", recordSet.Type, recordSet.Name, err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
@@ -9,19 +9,24 @@ import (
 	"github.com/BytemanD/easygo/pkg/syncutils"
 	"github.com/BytemanD/go-console/console"
 	"github.com/BytemanD/skyman/openstack/model/cinder"
+	"github.com/BytemanD/skyman/openstack/model/manila"
 	"github.com/BytemanD/skyman/openstack/model/neutron"
 	"github.com/BytemanD/skyman/openstack/model/nova"
 	"github.com/BytemanD/skyman/utility"
 )
 
-func (o Openstack) PruneServers(query url.Values, yes bool, waitDeleted bool) {
+// metadata 不为空时, 只清理 metadata 匹配的虚拟机
+func (o Openstack) PruneServers(query url.Values, metadata map[string]string, yes bool, waitDeleted bool) {
 	c := o.NovaV2()
-	if len(query) == 0 {
+	if len(query) == 0 && len(metadata) == 0 {
 		query.Set("status", "error")
 	}
 	console.Info("查询虚拟机: %v", query.Encode())
-	servers, err := c.Server().List(query)
+	servers, err := c.Server().Detail(query)
 	utility.LogError(err, "query servers failed", true)
+	if len(metadata) > 0 {
+		servers = utility.Filter(servers, func(s nova.Server) bool { return s.MatchMetadata(metadata) })
+	}
 	console.Info("需要清理的虚拟机数量: %d\n", len(servers))
 	if len(servers) == 0 {
 		return
@@ -66,20 +71,21 @@ func (o Openstack) PruneVolumes(query url.Values, matchName string, volumeType s
 		query.Add("status", "error")
 	}
 	console.Info("查询卷: %s", query.Encode())
-	volumes, err := c.Volume().Detail(query)
-	filterdVolumes := []cinder.Volume{}
-	for _, vol := range volumes {
-		if volumeType != "" && vol.VolumeType != volumeType {
-			continue
-		}
-		if matchName != "" && !strings.Contains(vol.Name, matchName) {
-			continue
+	// limit 作为每页的数量, 查询所有页
+	volumes := []cinder.Volume{}
+	iter := c.Volume().Iterator(query)
+	for iter.Next() {
+		for _, vol := range iter.Page() {
+			if volumeType != "" && vol.VolumeType != volumeType {
+				continue
+			}
+			if matchName != "" && !strings.Contains(vol.Name, matchName) {
+				continue
+			}
+			volumes = append(volumes, vol)
 		}
-		filterdVolumes = append(filterdVolumes, vol)
 	}
-	volumes = filterdVolumes
-
-	if err != nil {
+	if err := iter.Err(); err != nil {
 		console.Error("get volumes failed, %s", err)
 		return
 	}
@@ -113,7 +119,72 @@ func (o Openstack) PruneVolumes(query url.Values, matchName string, volumeType s
 			return nil
 		},
 	}
-	err = tg.Start()
+	err := tg.Start()
+	if err != nil {
+		console.Error("清理失败: %v", err)
+	} else {
+		console.Info("清理完成")
+	}
+}
+func (o Openstack) PruneShares(query url.Values, matchName string, shareType string,
+	yes bool, force bool) {
+	c := o.ManilaV2()
+	if query == nil {
+		query = url.Values{}
+	}
+	if query.Get("status") == "" {
+		query.Add("status", "error")
+	}
+	console.Info("查询共享: %s", query.Encode())
+	// limit 作为每页的数量, 查询所有页
+	shares := []manila.Share{}
+	iter := c.Share().Iterator(query)
+	for iter.Next() {
+		for _, share := range iter.Page() {
+			if shareType != "" && share.ShareType != shareType && share.ShareTypeName != shareType {
+				continue
+			}
+			if matchName != "" && !strings.Contains(share.Name, matchName) {
+				continue
+			}
+			shares = append(shares, share)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		console.Error("get shares failed, %s", err)
+		return
+	}
+	console.Info("需要清理的共享数量: %d\n", len(shares))
+	if len(shares) == 0 {
+		return
+	}
+	console.Info("Last share id: %s", shares[len(shares)-1].Id)
+	if !yes {
+		for _, share := range shares {
+			fmt.Printf("%s 名称: %s\t创建时间: %s\n", share.Id, share.Name, share.CreatedAt)
+		}
+		fmt.Printf("即将清理 %d 个共享:\n", len(shares))
+		yes = stringutils.ScanfComfirm("是否删除?", []string{"yes", "y"}, []string{"no", "n"})
+		if !yes {
+			return
+		}
+	}
+	console.Info("开始清理")
+	tg := syncutils.TaskGroup{
+		Items:        shares,
+		Title:        fmt.Sprintf("delete %d share(s)", len(shares)),
+		ShowProgress: true,
+		Func: func(i interface{}) error {
+			share := i.(manila.Share)
+			console.Debug("delete share %s(%s)", share.Id, share.Name)
+			err := c.Share().Delete(share.Id, force)
+			if err != nil {
+				return fmt.Errorf("delete share %s failed: %v", share.Id, err)
+			}
+			return nil
+		},
+	}
+	err := tg.Start()
 	if err != nil {
 		console.Error("清理失败: %v", err)
 	} else {
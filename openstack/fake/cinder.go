@@ -0,0 +1,283 @@
+package fake
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/BytemanD/skyman/openstack/model"
+	"github.com/BytemanD/skyman/openstack/model/cinder"
+)
+
+type volume struct {
+	cinder.Volume
+	deleteOnTermination bool
+}
+type snapshot struct {
+	cinder.Snapshot
+}
+
+func (c *Cloud) createVolume(name string, size uint, volumeType string, imageId string) *volume {
+	vol := &volume{
+		Volume: cinder.Volume{
+			Resource: model.Resource{
+				Id: NewId(), Name: name, Status: "creating", CreatedAt: now(), UpdatedAt: now(),
+			},
+			Size: size, VolumeType: volumeType, Bootable: "false",
+			Attachments: []cinder.Attachment{}, Metadata: map[string]string{},
+			AvailabilityZone: DEFAULT_AZ, Host: "fake-volume-host@lvm#lvm", TenantId: c.ProjectId,
+		},
+	}
+	if vol.VolumeType == "" {
+		vol.VolumeType = "__DEFAULT__"
+	}
+	if imageId != "" {
+		vol.Bootable = "true"
+		vol.VolumeImageMetadata = map[string]string{"image_id": imageId}
+	}
+	c.volumes[vol.Id] = vol
+	c.order = append(c.order, vol.Id)
+	c.schedule(func() { vol.setStatus("available") })
+	return vol
+}
+
+func (v *volume) setStatus(status string) {
+	v.Status, v.UpdatedAt = status, now()
+}
+
+// 开始挂载卷: available -> attaching -> in-use
+func (c *Cloud) attachVolume(vol *volume, serverId string, device string, host string) {
+	vol.setStatus("attaching")
+	attachment := cinder.Attachment{
+		Id: vol.Id, AttachmentId: NewId(), VolumeId: vol.Id, ServerId: serverId,
+		Device: device, HostName: host, AttachmentAt: now(),
+	}
+	c.schedule(func() {
+		vol.Attachments = append(vol.Attachments, attachment)
+		vol.setStatus("in-use")
+	})
+}
+
+// 开始卸载卷: in-use -> detaching -> available
+func (c *Cloud) detachVolume(vol *volume, then func()) {
+	vol.setStatus("detaching")
+	c.schedule(func() {
+		vol.Attachments = []cinder.Attachment{}
+		vol.setStatus("available")
+		if then != nil {
+			then()
+		}
+	})
+}
+
+func (c *Cloud) serveCinder(w response, r request) {
+	paths := r.Paths
+	if len(paths) == 0 {
+		w.json(http.StatusOK, versions("v3.0", "3.70", "3.0"))
+		return
+	}
+	if (paths[0] != "v2" && paths[0] != "v3") || len(paths) < 2 {
+		w.notFound("resource not found")
+		return
+	}
+	// 兼容带 project id 的 endpoint
+	if paths[1] == c.ProjectId && len(paths) > 2 {
+		paths = paths[1:]
+	}
+	switch paths[1] {
+	case "volumes":
+		c.serveVolumes(w, r, paths[2:])
+	case "snapshots":
+		c.serveSnapshots(w, r, paths[2:])
+	case "types":
+		w.json(http.StatusOK, map[string]interface{}{
+			"volume_types": []cinder.VolumeType{{Resource: model.Resource{Id: "__DEFAULT__", Name: "__DEFAULT__"}}},
+		})
+	default:
+		w.notFound("resource %s not found", paths[1])
+	}
+}
+
+func (c *Cloud) serveVolumes(w response, r request, paths []string) {
+	if len(paths) == 0 || paths[0] == "detail" {
+		switch r.Method {
+		case http.MethodGet:
+			volumes := []cinder.Volume{}
+			for _, id := range c.order {
+				vol, ok := c.volumes[id]
+				if ok && matchQuery(r, map[string]string{"name": vol.Name, "status": vol.Status}) {
+					volumes = append(volumes, vol.Volume)
+				}
+			}
+			volumes, next, err := paginate(r, volumes, func(v cinder.Volume) string { return v.Id }, c.PageSize)
+			if err != nil {
+				w.badRequest("%s", err)
+				return
+			}
+			w.json(http.StatusOK, withLinks(map[string]interface{}{"volumes": volumes}, "volumes", next))
+		case http.MethodPost:
+			body := struct {
+				Volume struct {
+					Name       string `json:"name"`
+					Size       uint   `json:"size"`
+					VolumeType string `json:"volume_type"`
+					ImageRef   string `json:"imageRef"`
+				} `json:"volume"`
+			}{}
+			if err := r.decode(&body); err != nil {
+				w.badRequest("invalid request body: %s", err)
+				return
+			}
+			if body.Volume.Size == 0 {
+				w.badRequest("Invalid input received: size is required")
+				return
+			}
+			vol := c.createVolume(body.Volume.Name, body.Volume.Size, body.Volume.VolumeType, body.Volume.ImageRef)
+			w.json(http.StatusAccepted, map[string]interface{}{"volume": vol.Volume})
+		default:
+			w.notAllowed()
+		}
+		return
+	}
+	vol, ok := c.volumes[paths[0]]
+	if !ok {
+		w.notFound("Volume %s could not be found.", paths[0])
+		return
+	}
+	if len(paths) == 2 && paths[1] == "action" && r.Method == http.MethodPost {
+		c.volumeAction(w, r, vol)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		w.json(http.StatusOK, map[string]interface{}{"volume": vol.Volume})
+	case http.MethodDelete:
+		if vol.Status != "available" && vol.Status != "error" {
+			w.badRequest("Invalid volume: Volume status must be available or error, but current status is: %s.", vol.Status)
+			return
+		}
+		vol.setStatus("deleting")
+		c.schedule(func() { delete(c.volumes, vol.Id) })
+		w.json(http.StatusAccepted, nil)
+	default:
+		w.notAllowed()
+	}
+}
+
+func (c *Cloud) volumeAction(w response, r request, vol *volume) {
+	body := map[string]map[string]interface{}{}
+	if err := r.decode(&body); err != nil {
+		w.badRequest("invalid request body: %s", err)
+		return
+	}
+	for action, params := range body {
+		switch action {
+		case "os-extend":
+			newSize, _ := params["new_size"].(float64)
+			if uint(newSize) <= vol.Size {
+				w.badRequest("Invalid input received: New size for extend must be greater than current size. (current: %d, extended: %d).",
+					vol.Size, uint(newSize))
+				return
+			}
+			if vol.Status != "available" && vol.Status != "in-use" {
+				w.badRequest("Invalid volume: Volume status must be available or in-use.")
+				return
+			}
+			status := vol.Status
+			vol.setStatus("extending")
+			c.schedule(func() {
+				vol.Size = uint(newSize)
+				vol.setStatus(status)
+			})
+		case "revert":
+			snapshotId, _ := params["snapshot_id"].(string)
+			snap, ok := c.snapshots[snapshotId]
+			if !ok || snap.VolumeId != vol.Id {
+				w.badRequest("Invalid snapshot: snapshot %s is not the latest snapshot of volume %s", snapshotId, vol.Id)
+				return
+			}
+			status := vol.Status
+			vol.setStatus("reverting")
+			c.schedule(func() { vol.setStatus(status) })
+		case "os-reset_status":
+			if status, ok := params["status"].(string); ok {
+				vol.setStatus(status)
+			}
+		default:
+			w.badRequest("There is no such action: %s", action)
+			return
+		}
+	}
+	w.json(http.StatusAccepted, nil)
+}
+
+func (c *Cloud) serveSnapshots(w response, r request, paths []string) {
+	if len(paths) == 0 || paths[0] == "detail" {
+		switch r.Method {
+		case http.MethodGet:
+			snapshots := []cinder.Snapshot{}
+			for _, id := range c.order {
+				snap, ok := c.snapshots[id]
+				if ok && matchQuery(r, map[string]string{"name": snap.Name, "volume_id": snap.VolumeId}) {
+					snapshots = append(snapshots, snap.Snapshot)
+				}
+			}
+			w.json(http.StatusOK, map[string]interface{}{"snapshots": snapshots})
+		case http.MethodPost:
+			body := struct {
+				Snapshot struct {
+					Name     string `json:"name"`
+					VolumeId string `json:"volume_id"`
+					Force    bool   `json:"force"`
+				} `json:"snapshot"`
+			}{}
+			if err := r.decode(&body); err != nil {
+				w.badRequest("invalid request body: %s", err)
+				return
+			}
+			vol, ok := c.volumes[body.Snapshot.VolumeId]
+			if !ok {
+				w.notFound("Volume %s could not be found.", body.Snapshot.VolumeId)
+				return
+			}
+			if vol.Status == "in-use" && !body.Snapshot.Force {
+				w.badRequest("Invalid volume: Volume %s status must be available, but current status is: in-use.", vol.Id)
+				return
+			}
+			snap := &snapshot{Snapshot: cinder.Snapshot{
+				Resource: model.Resource{
+					Id: NewId(), Name: body.Snapshot.Name, Status: "creating", CreatedAt: now(), UpdatedAt: now(),
+				},
+				Size: vol.Size, VolumeId: vol.Id, ProjectId: c.ProjectId, Progress: "0%",
+			}}
+			c.snapshots[snap.Id] = snap
+			c.order = append(c.order, snap.Id)
+			c.schedule(func() { snap.Status, snap.Progress, snap.UpdatedAt = "available", "100%", now() })
+			w.json(http.StatusAccepted, map[string]interface{}{"snapshot": snap.Snapshot})
+		default:
+			w.notAllowed()
+		}
+		return
+	}
+	snap, ok := c.snapshots[paths[0]]
+	if !ok {
+		w.notFound("Snapshot %s could not be found.", paths[0])
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		w.json(http.StatusOK, map[string]interface{}{"snapshot": snap.Snapshot})
+	case http.MethodDelete:
+		snap.Status = "deleting"
+		c.schedule(func() { delete(c.snapshots, snap.Id) })
+		w.json(http.StatusAccepted, nil)
+	default:
+		w.notAllowed()
+	}
+}
+
+func (c *Cloud) findVolume(id string) (*volume, error) {
+	if vol, ok := c.volumes[id]; ok {
+		return vol, nil
+	}
+	return nil, fmt.Errorf("volume %s could not be found", id)
+}
@@ -0,0 +1,495 @@
+// 进程内的 OpenStack 模拟服务, 用于离线测试
+//
+// 提供 Keystone v3 认证和服务目录, Nova 实例状态机 (状态/任务状态变化、
+// 操作记录、迁移记录、网卡和卷的挂载)、计算服务、服务器组和密钥对, 以及 Cinder 卷、Glance 镜像、Neutron 网络/端口
+// 和 Placement 资源分配。
+//
+//	cloud := fake.NewCloud()
+//	defer cloud.Close()
+//	client := openstack.NewClient(cloud.AuthUrl(), cloud.User(), cloud.Project(), cloud.Region)
+package fake
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BytemanD/skyman/openstack/model"
+	"github.com/BytemanD/skyman/openstack/model/glance"
+	"github.com/BytemanD/skyman/openstack/model/neutron"
+	"github.com/BytemanD/skyman/openstack/model/nova"
+)
+
+const (
+	DEFAULT_USERNAME     = "admin"
+	DEFAULT_PASSWORD     = "admin"
+	DEFAULT_PROJECT_NAME = "admin"
+	DEFAULT_DOMAIN_NAME  = "Default"
+	DEFAULT_DOMAIN_ID    = "default"
+	DEFAULT_REGION       = "RegionOne"
+	DEFAULT_AZ           = "nova"
+
+	// 每个任务阶段 (例如 BUILD -> ACTIVE) 需要的时间
+	DEFAULT_TASK_DURATION = time.Millisecond * 500
+	DEFAULT_TOKEN_EXPIRE  = time.Hour
+
+	HEADER_AUTH_TOKEN    = "X-Auth-Token"
+	HEADER_SUBJECT_TOKEN = "X-Subject-Token"
+	HEADER_REQUEST_ID    = "X-Openstack-Request-Id"
+
+	TIME_FORMAT = "2006-01-02T15:04:05Z"
+)
+
+type task struct {
+	doneAt time.Time
+	finish func()
+}
+
+type Cloud struct {
+	Username    string
+	Password    string
+	ProjectId   string
+	ProjectName string
+	DomainName  string
+	Region      string
+	// 每个任务阶段需要的时间, 为 0 时任务在下一次请求时完成
+	TaskDuration time.Duration
+	TokenExpire  time.Duration
+	// 大于 0 时 resize/migrate 完成后自动确认, 对应 nova 的 resize_confirm_window
+	ResizeConfirmWindow time.Duration
+	// 计算节点
+	Hosts []string
+	// 列表接口每页默认的最大数量, 对应 nova 的 max_limit, 0 表示不分页
+	PageSize int
+	// 模拟非管理员用户, 虚拟机详情中不返回节点名, 只返回 hostId
+	HideServerHost bool
+
+	keystone  *httptest.Server
+	nova      *httptest.Server
+	cinder    *httptest.Server
+	glance    *httptest.Server
+	neutron   *httptest.Server
+	placement *httptest.Server
+	manila    *httptest.Server
+
+	mu        sync.Mutex
+	tasks     []*task
+	tokens    map[string]time.Time
+	flavors   []*nova.Flavor
+	servers   map[string]*server
+	volumes   map[string]*volume
+	snapshots map[string]*snapshot
+	images    map[string]*glance.Image
+	networks  map[string]*neutron.Network
+	subnets   map[string]*neutron.Subnet
+	ports     map[string]*neutron.Port
+	// 计算节点 -> resource provider uuid
+	providers   map[string]string
+	allocations map[string]*allocation
+	// 计算节点 -> nova-compute 服务
+	services     map[string]*nova.Service
+	migrations   []*nova.Migration
+	serverGroups map[string]*nova.ServerGroup
+	keypairs     []*nova.Keypair
+	// 记录每个对象的创建顺序, 保证列表结果稳定
+	order    []string
+	nextIp   int
+	nextMac  int
+	nextHost int
+}
+
+// 创建并启动模拟服务, 默认包含两个计算节点、两个规格、一个镜像和一个网络
+func NewCloud() *Cloud {
+	c := &Cloud{
+		Username:     DEFAULT_USERNAME,
+		Password:     DEFAULT_PASSWORD,
+		ProjectId:    NewId(),
+		ProjectName:  DEFAULT_PROJECT_NAME,
+		DomainName:   DEFAULT_DOMAIN_NAME,
+		Region:       DEFAULT_REGION,
+		TaskDuration: DEFAULT_TASK_DURATION,
+		TokenExpire:  DEFAULT_TOKEN_EXPIRE,
+		Hosts:        []string{"fake-host-1", "fake-host-2"},
+
+		tokens:      map[string]time.Time{},
+		servers:     map[string]*server{},
+		volumes:     map[string]*volume{},
+		snapshots:   map[string]*snapshot{},
+		images:      map[string]*glance.Image{},
+		networks:    map[string]*neutron.Network{},
+		subnets:     map[string]*neutron.Subnet{},
+		ports:       map[string]*neutron.Port{},
+		providers:   map[string]string{},
+		allocations: map[string]*allocation{},
+		services:    map[string]*nova.Service{},
+		nextIp:      10,
+
+		serverGroups: map[string]*nova.ServerGroup{},
+	}
+	c.keystone = httptest.NewServer(c.handler(c.serveKeystone, false))
+	c.nova = httptest.NewServer(c.handler(c.serveNova, true))
+	c.cinder = httptest.NewServer(c.handler(c.serveCinder, true))
+	c.glance = httptest.NewServer(c.handler(c.serveGlance, true))
+	c.neutron = httptest.NewServer(c.handler(c.serveNeutron, true))
+	c.placement = httptest.NewServer(c.handler(c.servePlacement, true))
+	c.manila = httptest.NewServer(c.handler(c.serveManila, true))
+
+	c.AddFlavor(nova.Flavor{Id: "1", Name: "fake.small", Vcpus: 1, Ram: 1024, Disk: 10})
+	c.AddFlavor(nova.Flavor{Id: "2", Name: "fake.medium", Vcpus: 2, Ram: 2048, Disk: 20})
+	c.AddImage("cirros")
+	network := c.AddNetwork("fake-net")
+	c.AddSubnet(network.Id, "fake-subnet", "10.0.0.0/24")
+	return c
+}
+
+func (c *Cloud) Close() {
+	for _, s := range []*httptest.Server{c.keystone, c.nova, c.cinder, c.glance, c.neutron, c.placement, c.manila} {
+		s.Close()
+	}
+}
+
+// Keystone v3 地址
+func (c *Cloud) AuthUrl() string {
+	return c.keystone.URL + "/v3"
+}
+func (c *Cloud) User() model.User {
+	return model.User{Name: c.Username, Password: c.Password, Domain: model.Domain{Name: c.DomainName}}
+}
+func (c *Cloud) Project() model.Project {
+	return model.Project{Name: c.ProjectName, Domain: model.Domain{Name: c.DomainName}}
+}
+
+// 生成 UUID 格式的 ID
+func NewId() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+func now() string {
+	return time.Now().UTC().Format(TIME_FORMAT)
+}
+
+// 添加一个任务阶段, 在 TaskDuration 之后执行 finish
+func (c *Cloud) schedule(finish func()) {
+	c.scheduleAfter(c.TaskDuration, finish)
+}
+func (c *Cloud) scheduleAfter(duration time.Duration, finish func()) {
+	c.tasks = append(c.tasks, &task{doneAt: time.Now().Add(duration), finish: finish})
+}
+
+// 完成所有已到期的任务
+func (c *Cloud) tick() {
+	for {
+		pending, done := []*task{}, []*task{}
+		for _, t := range c.tasks {
+			if t.doneAt.After(time.Now()) {
+				pending = append(pending, t)
+			} else {
+				done = append(done, t)
+			}
+		}
+		if len(done) == 0 {
+			return
+		}
+		c.tasks = pending
+		for _, t := range done {
+			t.finish()
+		}
+	}
+}
+
+// 等待所有任务完成
+func (c *Cloud) WaitTasks(timeout time.Duration) error {
+	startTime := time.Now()
+	for {
+		c.mu.Lock()
+		c.tick()
+		pending := len(c.tasks)
+		c.mu.Unlock()
+		if pending == 0 {
+			return nil
+		}
+		if time.Since(startTime) >= timeout {
+			return fmt.Errorf("%d task(s) not finished after %v", pending, timeout)
+		}
+		time.Sleep(c.TaskDuration / 5)
+	}
+}
+
+type request struct {
+	*http.Request
+	Id    string
+	Paths []string
+}
+
+func (r request) decode(body interface{}) error {
+	if r.Body == nil {
+		return fmt.Errorf("request body is required")
+	}
+	return json.NewDecoder(r.Body).Decode(body)
+}
+
+type response struct {
+	http.ResponseWriter
+}
+
+func (w response) json(status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if body != nil {
+		json.NewEncoder(w).Encode(body)
+	}
+}
+func (w response) fault(status int, kind string, format string, args ...interface{}) {
+	w.json(status, map[string]interface{}{
+		kind: map[string]interface{}{"code": status, "message": fmt.Sprintf(format, args...)},
+	})
+}
+func (w response) notFound(format string, args ...interface{}) {
+	w.fault(http.StatusNotFound, "itemNotFound", format, args...)
+}
+func (w response) badRequest(format string, args ...interface{}) {
+	w.fault(http.StatusBadRequest, "badRequest", format, args...)
+}
+func (w response) conflict(format string, args ...interface{}) {
+	w.fault(http.StatusConflict, "conflictingRequest", format, args...)
+}
+func (w response) notAllowed() {
+	w.fault(http.StatusMethodNotAllowed, "badRequest", "method not allowed")
+}
+
+// 按 limit/marker 分页, 返回当前页和下一页的链接, 没有下一页时链接为空
+func paginate[T any](r request, items []T, id func(T) string, pageSize int) ([]T, string, error) {
+	query := r.URL.Query()
+	if marker := query.Get("marker"); marker != "" {
+		found := false
+		for i, item := range items {
+			if id(item) == marker {
+				items, found = items[i+1:], true
+				break
+			}
+		}
+		if !found {
+			return nil, "", fmt.Errorf("marker [%s] not found", marker)
+		}
+	}
+	limit := pageSize
+	if value := query.Get("limit"); value != "" {
+		if _, err := fmt.Sscanf(value, "%d", &limit); err != nil || limit < 0 {
+			return nil, "", fmt.Errorf("invalid limit %s", value)
+		}
+		if pageSize > 0 && limit > pageSize {
+			limit = pageSize
+		}
+	}
+	if limit <= 0 || len(items) < limit {
+		return items, "", nil
+	}
+	items = items[:limit]
+	// 和 nova 一样, 结果数量等于 limit 时返回下一页的链接
+	query.Set("marker", id(items[len(items)-1]))
+	next := *r.URL
+	next.RawQuery = query.Encode()
+	return items, next.String(), nil
+}
+
+// 按 offset/limit 分页, limit 超过 pageSize 时使用 pageSize
+func paginateWithOffset[T any](r request, items []T, pageSize int) ([]T, error) {
+	query := r.URL.Query()
+	offset, limit := 0, pageSize
+	if value := query.Get("offset"); value != "" {
+		if _, err := fmt.Sscanf(value, "%d", &offset); err != nil || offset < 0 {
+			return nil, fmt.Errorf("invalid offset %s", value)
+		}
+	}
+	if value := query.Get("limit"); value != "" {
+		if _, err := fmt.Sscanf(value, "%d", &limit); err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid limit %s", value)
+		}
+		if pageSize > 0 && limit > pageSize {
+			limit = pageSize
+		}
+	}
+	if offset >= len(items) {
+		return []T{}, nil
+	}
+	items = items[offset:]
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// 在列表结果中添加 nova/cinder/neutron 格式的下一页链接
+func withLinks(body map[string]interface{}, key string, next string) map[string]interface{} {
+	if next != "" {
+		body[key+"_links"] = []map[string]string{{"rel": "next", "href": next}}
+	}
+	return body
+}
+
+func (c *Cloud) handler(serve func(w response, r request), requireAuth bool) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.tick()
+
+		req := request{Request: r, Id: "req-" + NewId()}
+		for _, p := range strings.Split(strings.Trim(r.URL.Path, "/"), "/") {
+			if p != "" {
+				req.Paths = append(req.Paths, p)
+			}
+		}
+		w := response{rw}
+		w.Header().Set(HEADER_REQUEST_ID, req.Id)
+		if requireAuth && !c.tokenValid(r.Header.Get(HEADER_AUTH_TOKEN)) {
+			w.json(http.StatusUnauthorized, map[string]interface{}{
+				"error": map[string]interface{}{
+					"code": 401, "title": "Unauthorized",
+					"message": "The request you have made requires authentication.",
+				},
+			})
+			return
+		}
+		serve(w, req)
+	})
+}
+
+func (c *Cloud) tokenValid(tokenId string) bool {
+	expiredAt, ok := c.tokens[tokenId]
+	return ok && expiredAt.After(time.Now())
+}
+
+// 吊销所有 token, 用于测试重新认证
+func (c *Cloud) RevokeTokens() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokens = map[string]time.Time{}
+}
+
+func (c *Cloud) catalog() []model.Catalog {
+	services := []struct {
+		Type, Name, Url string
+	}{
+		{"identity", "keystone", c.keystone.URL + "/v3"},
+		{"compute", "nova", c.nova.URL + "/v2.1"},
+		{"volumev2", "cinderv2", c.cinder.URL + "/v2"},
+		{"volumev3", "cinderv3", c.cinder.URL + "/v3"},
+		{"image", "glance", c.glance.URL},
+		{"network", "neutron", c.neutron.URL},
+		{"placement", "placement", c.placement.URL},
+		{"sharev2", "manilav2", c.manila.URL + "/v2"},
+	}
+	catalogs := []model.Catalog{}
+	for _, service := range services {
+		catalog := model.Catalog{Type: service.Type, Name: service.Name, Id: NewId()}
+		for _, endpointInterface := range []string{"public", "internal", "admin"} {
+			catalog.Endpoints = append(catalog.Endpoints, model.Endpoint{
+				Id: NewId(), Region: c.Region, RegionId: c.Region, Interface: endpointInterface,
+				Url: service.Url, ServiceId: catalog.Id,
+			})
+		}
+		catalogs = append(catalogs, catalog)
+	}
+	return catalogs
+}
+
+func (c *Cloud) serveKeystone(w response, r request) {
+	paths := r.Paths
+	if len(paths) == 0 || paths[0] != "v3" {
+		w.notFound("resource not found")
+		return
+	}
+	switch strings.Join(paths[1:], "/") {
+	case "":
+		w.json(http.StatusOK, map[string]interface{}{
+			"version": model.ApiVersion{Id: "v3.14", Status: "stable", Updated: "2020-04-07T00:00:00Z"},
+		})
+	case "auth/tokens":
+		if r.Method != http.MethodPost {
+			w.notAllowed()
+			return
+		}
+		c.issueToken(w, r)
+	default:
+		w.notFound("resource not found")
+	}
+}
+
+func (c *Cloud) issueToken(w response, r request) {
+	body := model.AuthBody{}
+	if err := r.decode(&body); err != nil {
+		w.badRequest("invalid request body: %s", err)
+		return
+	}
+	identity := body.Auth.Identity
+	methods := []string{}
+	switch {
+	case identity.Password != nil:
+		user := identity.Password.User
+		if user.Name != c.Username || user.Password != c.Password || !c.matchDomain(user.Domain) {
+			w.fault(http.StatusUnauthorized, "error", "The request you have made requires authentication.")
+			return
+		}
+		methods = append(methods, "password")
+	case identity.Token != nil:
+		if !c.tokenValid(identity.Token.Id) {
+			w.fault(http.StatusNotFound, "error", "Could not find token: %s.", identity.Token.Id)
+			return
+		}
+		methods = append(methods, "token")
+	default:
+		w.fault(http.StatusUnauthorized, "error", "Unsupported auth method")
+		return
+	}
+	if scope := body.Auth.Scope; scope != nil && !c.matchProject(scope.Project) {
+		w.fault(http.StatusUnauthorized, "error", "The request you have made requires authentication.")
+		return
+	}
+	tokenId := "gAAAAA" + strings.ReplaceAll(NewId(), "-", "")
+	expiredAt := time.Now().Add(c.TokenExpire)
+	c.tokens[tokenId] = expiredAt
+
+	w.Header().Set(HEADER_SUBJECT_TOKEN, tokenId)
+	w.json(http.StatusCreated, model.RespToken{
+		Token: model.Token{
+			Methods:   methods,
+			ExpiresAt: expiredAt.UTC().Format(time.RFC3339Nano),
+			Catalogs:  c.catalog(),
+			Roles:     []model.Role{{Id: NewId(), Name: "admin"}},
+			Project: model.Project{
+				Id: c.ProjectId, Name: c.ProjectName, Domain: model.Domain{Id: DEFAULT_DOMAIN_ID, Name: c.DomainName},
+			},
+			User: model.User{Id: NewId(), Name: c.Username, Domain: model.Domain{Id: DEFAULT_DOMAIN_ID, Name: c.DomainName}},
+		},
+	})
+}
+
+// 项目和域可以使用 ID 或者名字
+func (c *Cloud) matchProject(project model.Project) bool {
+	if project.Id != "" {
+		return project.Id == c.ProjectId
+	}
+	return project.Name == c.ProjectName && c.matchDomain(project.Domain)
+}
+func (c *Cloud) matchDomain(domain model.Domain) bool {
+	if domain.Id != "" {
+		return domain.Id == DEFAULT_DOMAIN_ID
+	}
+	return domain.Name == c.DomainName
+}
+
+func versions(id string, version string, minVersion string) map[string]interface{} {
+	return map[string]interface{}{
+		"versions": model.ApiVersions{
+			{Id: id, Status: "CURRENT", Version: version, MinVersion: minVersion, Updated: "2013-07-23T11:33:21Z"},
+		},
+	}
+}
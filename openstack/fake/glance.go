@@ -0,0 +1,98 @@
+package fake
+
+import (
+	"net/http"
+
+	"github.com/BytemanD/skyman/openstack/model"
+	"github.com/BytemanD/skyman/openstack/model/glance"
+)
+
+func (c *Cloud) AddImage(name string) *glance.Image {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.addImage(name, "active")
+}
+func (c *Cloud) addImage(name string, status string) *glance.Image {
+	image := &glance.Image{
+		Resource: model.Resource{
+			Id: NewId(), Name: name, Status: status, CreatedAt: now(), UpdatedAt: now(),
+		},
+		DiskFormat: "qcow2", ContainerFormat: "bare", Size: 16338944, VirtualSize: 117440512,
+		Owner: c.ProjectId, Visibility: "public", Tags: []string{},
+	}
+	c.images[image.Id] = image
+	c.order = append(c.order, image.Id)
+	return image
+}
+
+// 创建快照镜像: queued -> saving -> active
+func (c *Cloud) snapshotImage(name string) *glance.Image {
+	image := c.addImage(name, "queued")
+	c.schedule(func() {
+		image.Status, image.UpdatedAt = "saving", now()
+		c.schedule(func() { image.Status, image.UpdatedAt = "active", now() })
+	})
+	return image
+}
+
+func (c *Cloud) serveGlance(w response, r request) {
+	paths := r.Paths
+	if len(paths) == 0 {
+		w.json(http.StatusOK, versions("v2.9", "", ""))
+		return
+	}
+	if paths[0] != "v2" || len(paths) < 2 || paths[1] != "images" {
+		w.fault(http.StatusNotFound, "error", "The resource could not be found.")
+		return
+	}
+	if len(paths) == 2 {
+		switch r.Method {
+		case http.MethodGet:
+			images := []glance.Image{}
+			for _, id := range c.order {
+				image, ok := c.images[id]
+				if ok && matchQuery(r, map[string]string{"name": image.Name, "status": image.Status}) {
+					images = append(images, *image)
+				}
+			}
+			images, next, err := paginate(r, images, func(i glance.Image) string { return i.Id }, c.PageSize)
+			if err != nil {
+				w.fault(http.StatusBadRequest, "error", "%s", err)
+				return
+			}
+			w.json(http.StatusOK, glance.ImagesResp{Images: images, Next: next})
+		case http.MethodPost:
+			body := glance.Image{}
+			if err := r.decode(&body); err != nil {
+				w.badRequest("invalid request body: %s", err)
+				return
+			}
+			image := c.addImage(body.Name, "queued")
+			image.Size, image.VirtualSize = 0, 0
+			if body.DiskFormat != "" {
+				image.DiskFormat = body.DiskFormat
+			}
+			if body.ContainerFormat != "" {
+				image.ContainerFormat = body.ContainerFormat
+			}
+			w.json(http.StatusCreated, image)
+		default:
+			w.notAllowed()
+		}
+		return
+	}
+	image, ok := c.images[paths[2]]
+	if !ok {
+		w.fault(http.StatusNotFound, "error", "No image found with ID %s", paths[2])
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		w.json(http.StatusOK, image)
+	case http.MethodDelete:
+		delete(c.images, image.Id)
+		w.json(http.StatusNoContent, nil)
+	default:
+		w.notAllowed()
+	}
+}
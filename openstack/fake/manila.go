@@ -0,0 +1,12 @@
+package fake
+
+import "net/http"
+
+// manila 只模拟版本信息
+func (c *Cloud) serveManila(w response, r request) {
+	if len(r.Paths) == 0 {
+		w.json(http.StatusOK, versions("v2.0", "2.65", "2.0"))
+		return
+	}
+	w.notFound("resource not found")
+}
@@ -0,0 +1,341 @@
+package fake
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/BytemanD/skyman/openstack/model"
+	"github.com/BytemanD/skyman/openstack/model/neutron"
+)
+
+func neutronNotFound(w response, kind string, id string) {
+	w.json(http.StatusNotFound, map[string]interface{}{
+		"NeutronError": map[string]string{
+			"type":    kind + "NotFound",
+			"message": fmt.Sprintf("%s %s could not be found.", kind, id),
+			"detail":  "",
+		},
+	})
+}
+
+func neutronBadRequest(w response, format string, args ...interface{}) {
+	w.json(http.StatusBadRequest, map[string]interface{}{
+		"NeutronError": map[string]string{
+			"type":    "BadRequest",
+			"message": fmt.Sprintf(format, args...),
+			"detail":  "",
+		},
+	})
+}
+
+func (c *Cloud) AddNetwork(name string) *neutron.Network {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.addNetwork(name)
+}
+func (c *Cloud) addNetwork(name string) *neutron.Network {
+	network := &neutron.Network{
+		Resource: model.Resource{
+			Id: NewId(), Name: name, Status: "ACTIVE", ProjectId: c.ProjectId, TenantId: c.ProjectId,
+			CreatedAt: now(), UpdatedAt: now(),
+		},
+		AdminStateUp: true, Mtu: 1450, Subnets: []string{},
+		AvailabilityZones: []string{DEFAULT_AZ}, ProviderNetworkType: "vxlan",
+	}
+	c.networks[network.Id] = network
+	c.order = append(c.order, network.Id)
+	return network
+}
+
+func (c *Cloud) AddSubnet(networkId string, name string, cidr string) *neutron.Subnet {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.addSubnet(networkId, name, cidr)
+}
+func (c *Cloud) addSubnet(networkId string, name string, cidr string) *neutron.Subnet {
+	network, ok := c.networks[networkId]
+	if !ok {
+		return nil
+	}
+	gateway := ""
+	if ip, _, err := net.ParseCIDR(cidr); err == nil {
+		ip = ip.To4()
+		if ip != nil {
+			gateway = net.IPv4(ip[0], ip[1], ip[2], 1).String()
+		}
+	}
+	subnet := &neutron.Subnet{
+		Resource: model.Resource{
+			Id: NewId(), Name: name, ProjectId: c.ProjectId, TenantId: c.ProjectId,
+			CreatedAt: now(), UpdatedAt: now(),
+		},
+		NetworkId: networkId, Cidr: cidr, IpVersion: 4, EnableDhcp: true, GatewayIp: gateway,
+	}
+	c.subnets[subnet.Id] = subnet
+	c.order = append(c.order, subnet.Id)
+	network.Subnets = append(network.Subnets, subnet.Id)
+	return subnet
+}
+
+func (c *Cloud) allocateFixedIps(network *neutron.Network) []neutron.FixedIp {
+	fixedIps := []neutron.FixedIp{}
+	for _, subnetId := range network.Subnets {
+		subnet := c.subnets[subnetId]
+		ip, _, err := net.ParseCIDR(subnet.Cidr)
+		if err != nil || ip.To4() == nil {
+			continue
+		}
+		ip = ip.To4()
+		c.nextIp++
+		fixedIps = append(fixedIps, neutron.FixedIp{
+			SubnetId:  subnet.Id,
+			IpAddress: net.IPv4(ip[0], ip[1], byte(c.nextIp/256), byte(c.nextIp%256)).String(),
+		})
+	}
+	return fixedIps
+}
+
+func (c *Cloud) createPort(params map[string]interface{}) (*neutron.Port, error) {
+	networkId, _ := params["network_id"].(string)
+	network, ok := c.networks[networkId]
+	if !ok {
+		return nil, fmt.Errorf("network %s could not be found", networkId)
+	}
+	c.nextMac++
+	port := &neutron.Port{
+		Resource: model.Resource{
+			Id: NewId(), Status: "DOWN", ProjectId: c.ProjectId, TenantId: c.ProjectId,
+			CreatedAt: now(), UpdatedAt: now(),
+		},
+		AdminStateUp:    true,
+		MACAddress:      fmt.Sprintf("fa:16:3e:00:%02x:%02x", c.nextMac/256, c.nextMac%256),
+		FixedIps:        c.allocateFixedIps(network),
+		BindingVnicType: "normal",
+		SecurityGroups:  []string{},
+	}
+	port.NetworkId = networkId
+	if name, ok := params["name"].(string); ok {
+		port.Name = name
+	}
+	c.ports[port.Id] = port
+	c.order = append(c.order, port.Id)
+	return port, nil
+}
+
+func (c *Cloud) bindPort(port *neutron.Port, serverId string, host string) {
+	port.DeviceId = serverId
+	port.DeviceOwner = "compute:" + DEFAULT_AZ
+	port.BindingHostId = host
+	port.BindingVifType = "ovs"
+	port.Status = "ACTIVE"
+	port.UpdatedAt = now()
+}
+func (c *Cloud) unbindPort(port *neutron.Port) {
+	port.DeviceId, port.DeviceOwner, port.BindingHostId, port.BindingVifType = "", "", "", "unbound"
+	port.Status = "DOWN"
+	port.UpdatedAt = now()
+}
+
+func matchQuery(r request, fields map[string]string) bool {
+	for key, value := range fields {
+		if query := r.URL.Query(); query.Has(key) && query.Get(key) != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Cloud) serveNeutron(w response, r request) {
+	paths := r.Paths
+	if len(paths) == 0 {
+		w.json(http.StatusOK, versions("v2.0", "", ""))
+		return
+	}
+	if paths[0] != "v2.0" || len(paths) < 2 {
+		neutronNotFound(w, "Resource", strings.Join(paths, "/"))
+		return
+	}
+	switch paths[1] {
+	case "networks":
+		c.serveNetworks(w, r, paths[2:])
+	case "subnets":
+		c.serveSubnets(w, r, paths[2:])
+	case "ports":
+		c.servePorts(w, r, paths[2:])
+	default:
+		neutronNotFound(w, "Resource", paths[1])
+	}
+}
+
+func (c *Cloud) serveNetworks(w response, r request, paths []string) {
+	if len(paths) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			networks := []neutron.Network{}
+			for _, id := range c.order {
+				if network, ok := c.networks[id]; ok && matchQuery(r, map[string]string{"name": network.Name, "id": network.Id}) {
+					networks = append(networks, *network)
+				}
+			}
+			networks, next, err := paginate(r, networks, func(n neutron.Network) string { return n.Id }, c.PageSize)
+			if err != nil {
+				neutronBadRequest(w, "%s", err)
+				return
+			}
+			w.json(http.StatusOK, withLinks(map[string]interface{}{"networks": networks}, "networks", next))
+		case http.MethodPost:
+			body := struct{ Network neutron.Network }{}
+			if err := r.decode(&body); err != nil {
+				w.badRequest("invalid request body: %s", err)
+				return
+			}
+			network := c.addNetwork(body.Network.Name)
+			w.json(http.StatusCreated, map[string]interface{}{"network": network})
+		default:
+			w.notAllowed()
+		}
+		return
+	}
+	network, ok := c.networks[paths[0]]
+	if !ok {
+		neutronNotFound(w, "Network", paths[0])
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		w.json(http.StatusOK, map[string]interface{}{"network": network})
+	case http.MethodDelete:
+		for _, port := range c.ports {
+			if port.NetworkId == network.Id && port.DeviceId != "" {
+				w.json(http.StatusConflict, map[string]interface{}{
+					"NeutronError": map[string]string{
+						"type":    "NetworkInUse",
+						"message": fmt.Sprintf("Unable to complete operation on network %s. There are one or more ports still in use on the network.", network.Id),
+					},
+				})
+				return
+			}
+		}
+		for _, subnetId := range network.Subnets {
+			delete(c.subnets, subnetId)
+		}
+		delete(c.networks, network.Id)
+		w.json(http.StatusNoContent, nil)
+	default:
+		w.notAllowed()
+	}
+}
+
+func (c *Cloud) serveSubnets(w response, r request, paths []string) {
+	if len(paths) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			subnets := []neutron.Subnet{}
+			for _, id := range c.order {
+				subnet, ok := c.subnets[id]
+				if ok && matchQuery(r, map[string]string{"name": subnet.Name, "network_id": subnet.NetworkId}) {
+					subnets = append(subnets, *subnet)
+				}
+			}
+			w.json(http.StatusOK, map[string]interface{}{"subnets": subnets})
+		case http.MethodPost:
+			body := struct{ Subnet neutron.Subnet }{}
+			if err := r.decode(&body); err != nil {
+				w.badRequest("invalid request body: %s", err)
+				return
+			}
+			subnet := c.addSubnet(body.Subnet.NetworkId, body.Subnet.Name, body.Subnet.Cidr)
+			if subnet == nil {
+				neutronNotFound(w, "Network", body.Subnet.NetworkId)
+				return
+			}
+			w.json(http.StatusCreated, map[string]interface{}{"subnet": subnet})
+		default:
+			w.notAllowed()
+		}
+		return
+	}
+	subnet, ok := c.subnets[paths[0]]
+	if !ok {
+		neutronNotFound(w, "Subnet", paths[0])
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		w.json(http.StatusOK, map[string]interface{}{"subnet": subnet})
+	default:
+		w.notAllowed()
+	}
+}
+
+func (c *Cloud) servePorts(w response, r request, paths []string) {
+	if len(paths) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			ports := []neutron.Port{}
+			for _, id := range c.order {
+				port, ok := c.ports[id]
+				if ok && matchQuery(r, map[string]string{
+					"name": port.Name, "network_id": port.NetworkId, "device_id": port.DeviceId,
+					"device_owner": port.DeviceOwner, "binding:host_id": port.BindingHostId,
+				}) {
+					ports = append(ports, *port)
+				}
+			}
+			ports, next, err := paginate(r, ports, func(p neutron.Port) string { return p.Id }, c.PageSize)
+			if err != nil {
+				neutronBadRequest(w, "%s", err)
+				return
+			}
+			w.json(http.StatusOK, withLinks(map[string]interface{}{"ports": ports}, "ports", next))
+		case http.MethodPost:
+			body := struct{ Port map[string]interface{} }{}
+			if err := r.decode(&body); err != nil {
+				w.badRequest("invalid request body: %s", err)
+				return
+			}
+			port, err := c.createPort(body.Port)
+			if err != nil {
+				neutronNotFound(w, "Network", fmt.Sprintf("%v", body.Port["network_id"]))
+				return
+			}
+			w.json(http.StatusCreated, map[string]interface{}{"port": port})
+		default:
+			w.notAllowed()
+		}
+		return
+	}
+	port, ok := c.ports[paths[0]]
+	if !ok {
+		neutronNotFound(w, "Port", paths[0])
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		w.json(http.StatusOK, map[string]interface{}{"port": port})
+	case http.MethodPut:
+		body := struct{ Port map[string]interface{} }{}
+		if err := r.decode(&body); err != nil {
+			w.badRequest("invalid request body: %s", err)
+			return
+		}
+		if name, ok := body.Port["name"].(string); ok {
+			port.Name = name
+		}
+		if adminStateUp, ok := body.Port["admin_state_up"].(bool); ok {
+			port.AdminStateUp = adminStateUp
+		}
+		port.UpdatedAt = now()
+		w.json(http.StatusOK, map[string]interface{}{"port": port})
+	case http.MethodDelete:
+		if s, ok := c.servers[port.DeviceId]; ok {
+			s.removePort(port.Id)
+		}
+		delete(c.ports, port.Id)
+		w.json(http.StatusNoContent, nil)
+	default:
+		w.notAllowed()
+	}
+}
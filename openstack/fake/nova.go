@@ -0,0 +1,1578 @@
+package fake
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/BytemanD/skyman/openstack/model"
+	"github.com/BytemanD/skyman/openstack/model/neutron"
+	"github.com/BytemanD/skyman/openstack/model/nova"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	POWER_NOSTATE  = 0
+	POWER_RUNNING  = 1
+	POWER_PAUSED   = 3
+	POWER_SHUTDOWN = 4
+	POWER_SUSPEND  = 7
+)
+
+var VM_STATES = map[string]string{
+	"BUILD":             "building",
+	"ACTIVE":            "active",
+	"SHUTOFF":           "stopped",
+	"PAUSED":            "paused",
+	"SUSPENDED":         "suspended",
+	"SHELVED":           "shelved",
+	"SHELVED_OFFLOADED": "shelved_offloaded",
+	"VERIFY_RESIZE":     "resized",
+	"RESCUE":            "rescued",
+	"ERROR":             "error",
+}
+
+type server struct {
+	nova.Server
+	imageId string
+	ports   []string
+	// 创建实例或挂载网络时由 nova 创建的端口, 卸载时一并删除
+	novaPorts map[string]bool
+	volumes   []nova.VolumeAttachment
+	actions   []*nova.InstanceAction
+	deleted   bool
+
+	// 用于 confirm/revert resize
+	oldFlavor *nova.Flavor
+	oldHost   string
+	oldStatus string
+	migration *nova.Migration
+	// 正在进行的热迁移, 参数为迁移结束时的状态
+	finishLiveMigration func(result string)
+}
+
+func (s *server) setState(status string, powerState int) {
+	s.Status, s.VmState, s.PowerState = status, VM_STATES[status], powerState
+	s.TaskState, s.Updated = "", now()
+}
+func (s *server) removePort(portId string) {
+	ports := []string{}
+	for _, id := range s.ports {
+		if id != portId {
+			ports = append(ports, id)
+		}
+	}
+	s.ports = ports
+}
+
+// 实例是否包含所有的标签
+func (s *server) hasTags(tags []string) bool {
+	for _, tag := range tags {
+		if !slices.Contains(s.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+func (s *server) findAction(requestId string) *nova.InstanceAction {
+	for _, action := range s.actions {
+		if action.RequestId == requestId {
+			return action
+		}
+	}
+	return nil
+}
+
+func (c *Cloud) AddFlavor(flavor nova.Flavor) *nova.Flavor {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if flavor.Id == "" {
+		flavor.Id = NewId()
+	}
+	flavor.IsPublic = true
+	c.flavors = append(c.flavors, &flavor)
+	return &flavor
+}
+func (c *Cloud) findFlavor(idOrName string) *nova.Flavor {
+	idOrName = path.Base(idOrName)
+	for _, flavor := range c.flavors {
+		if flavor.Id == idOrName || flavor.Name == idOrName {
+			return flavor
+		}
+	}
+	return nil
+}
+func (c *Cloud) findImage(idOrName string) string {
+	idOrName = path.Base(idOrName)
+	if _, ok := c.images[idOrName]; ok {
+		return idOrName
+	}
+	for _, image := range c.images {
+		if image.Name == idOrName {
+			return image.Id
+		}
+	}
+	return ""
+}
+
+// 选择一个与 exclude 不同的计算节点
+func (c *Cloud) pickHost(exclude string) string {
+	for i := 0; i < len(c.Hosts); i++ {
+		c.nextHost = (c.nextHost + 1) % len(c.Hosts)
+		host := c.Hosts[c.nextHost]
+		if host != exclude && c.computeService(host).Status == "enabled" {
+			return host
+		}
+	}
+	return exclude
+}
+
+// 按照服务器组的策略选择计算节点, 节点不足时和 nova 的 soft 策略一样退化为普通调度
+func (c *Cloud) pickGroupHost(group *nova.ServerGroup) string {
+	if group == nil {
+		return c.pickHost("")
+	}
+	members := map[string]int{}
+	for _, id := range group.Members {
+		if s, ok := c.servers[id]; ok && s.Host != "" {
+			members[s.Host]++
+		}
+	}
+	switch group.GetPolicy() {
+	case nova.GROUP_AFFINITY, nova.GROUP_SOFT_AFFINITY:
+		for host := range members {
+			return host
+		}
+	case nova.GROUP_ANTI_AFFINITY, nova.GROUP_SOFT_ANTI_AFFINITY:
+		for i := 0; i < len(c.Hosts); i++ {
+			host := c.pickHost("")
+			if members[host] < group.MaxServerPerHost() {
+				return host
+			}
+		}
+	}
+	return c.pickHost("")
+}
+
+func (c *Cloud) serverView(s *server) nova.Server {
+	view := s.Server
+	flavor := view.Flavor
+	flavor.OriginalName = flavor.Name
+	view.Flavor = flavor
+	if s.imageId != "" {
+		view.Image = map[string]interface{}{"id": s.imageId}
+	} else {
+		view.Image = ""
+	}
+	view.HypervisorHostname = s.Host
+	if s.Host != "" {
+		// 和 nova 一样, hostId 为项目 id 和节点名的 sha224
+		view.HostId = fmt.Sprintf("%x", sha256.Sum224([]byte(c.ProjectId+s.Host)))
+	}
+	if c.HideServerHost {
+		view.Host, view.HypervisorHostname = "", ""
+	}
+	view.Addresses = map[string]nova.AddressList{}
+	for _, portId := range s.ports {
+		port, ok := c.ports[portId]
+		if !ok {
+			continue
+		}
+		networkName := port.NetworkId
+		if network, ok := c.networks[port.NetworkId]; ok {
+			networkName = network.Name
+		}
+		for _, fixedIp := range port.FixedIps {
+			view.Addresses[networkName] = append(view.Addresses[networkName], nova.Address{
+				MacAddr: port.MACAddress, Version: 4, Addr: fixedIp.IpAddress, Type: "fixed",
+			})
+		}
+	}
+	return view
+}
+
+func (c *Cloud) startAction(s *server, r request, action string) *nova.InstanceAction {
+	instanceAction := &nova.InstanceAction{
+		Action: action, InstanceUUID: s.Id, RequestId: r.Id, StartTime: now(),
+		Events: []nova.InstanceActionEvent{
+			{Event: "compute_" + strings.ReplaceAll(action, "-", "_"), StartTime: now(), Host: s.Host},
+		},
+	}
+	instanceAction.ProjectId, instanceAction.UserId = c.ProjectId, s.UserId
+	s.actions = append(s.actions, instanceAction)
+	return instanceAction
+}
+func finishAction(action *nova.InstanceAction, result string) {
+	if action == nil || len(action.Events) == 0 {
+		return
+	}
+	action.Events[0].FinishTime = now()
+	action.Events[0].Result = result
+}
+
+type stage struct {
+	status    string
+	taskState string
+}
+
+// 依次执行各个阶段, 每个阶段持续 TaskDuration, 最后执行 done 并清空任务状态
+func (c *Cloud) runTask(s *server, action *nova.InstanceAction, stages []stage, done func()) {
+	if len(stages) == 0 {
+		if done != nil {
+			done()
+		}
+		s.TaskState, s.Updated, s.Progress = "", now(), 0
+		finishAction(action, "Success")
+		return
+	}
+	if stages[0].status != "" {
+		s.Status = stages[0].status
+	}
+	s.TaskState, s.Updated = stages[0].taskState, now()
+	c.schedule(func() {
+		if !s.deleted {
+			c.runTask(s, action, stages[1:], done)
+		}
+	})
+}
+
+// 检查实例是否处于允许的状态
+func checkState(w response, s *server, action string, statuses ...string) bool {
+	if s.TaskState != "" {
+		w.conflict("Cannot '%s' instance %s while it is in task_state %s", action, s.Id, s.TaskState)
+		return false
+	}
+	for _, status := range statuses {
+		if s.Status == status {
+			return true
+		}
+	}
+	w.conflict("Cannot '%s' instance %s while it is in vm_state %s", action, s.Id, s.VmState)
+	return false
+}
+
+func (c *Cloud) serveNova(w response, r request) {
+	paths := r.Paths
+	if len(paths) == 0 {
+		w.json(http.StatusOK, versions("v2.1", "2.96", "2.1"))
+		return
+	}
+	if paths[0] != "v2.1" {
+		w.notFound("resource not found")
+		return
+	}
+	// 兼容带 project id 的 endpoint
+	if len(paths) > 2 && paths[1] == c.ProjectId {
+		paths = paths[1:]
+	}
+	if len(paths) == 1 {
+		w.json(http.StatusOK, map[string]interface{}{
+			"version": model.ApiVersion{Id: "v2.1", Status: "CURRENT", Version: "2.96", MinVersion: "2.1"},
+		})
+		return
+	}
+	switch paths[1] {
+	case "servers":
+		c.serveServers(w, r, paths[2:])
+	case "os-volumes_boot":
+		if r.Method != http.MethodPost {
+			w.notAllowed()
+			return
+		}
+		c.createServer(w, r)
+	case "flavors":
+		c.serveFlavors(w, r, paths[2:])
+	case "os-hypervisors":
+		c.serveHypervisors(w, r, paths[2:])
+	case "os-services":
+		c.serveServices(w, r, paths[2:])
+	case "os-migrations":
+		c.listMigrations(w, r, nil)
+	case "os-server-groups":
+		c.serveServerGroups(w, r, paths[2:])
+	case "os-keypairs":
+		c.serveKeypairs(w, r, paths[2:])
+	default:
+		w.notFound("resource %s not found", paths[1])
+	}
+}
+
+func (c *Cloud) serveServerMetadata(w response, r request, s *server, paths []string) {
+	switch {
+	case len(paths) == 0 && r.Method == http.MethodGet:
+		w.json(http.StatusOK, map[string]interface{}{"metadata": s.Metadata})
+	case len(paths) == 0 && (r.Method == http.MethodPost || r.Method == http.MethodPut):
+		body := struct{ Metadata map[string]string }{}
+		if err := r.decode(&body); err != nil {
+			w.badRequest("invalid request body: %s", err)
+			return
+		}
+		// PUT 替换所有的 metadata, POST 只更新指定的 key
+		if r.Method == http.MethodPut {
+			s.Metadata = map[string]string{}
+		}
+		for k, v := range body.Metadata {
+			s.Metadata[k] = v
+		}
+		w.json(http.StatusOK, map[string]interface{}{"metadata": s.Metadata})
+	case len(paths) == 1 && r.Method == http.MethodDelete:
+		if _, ok := s.Metadata[paths[0]]; !ok {
+			w.notFound("Metadata item was not found")
+			return
+		}
+		delete(s.Metadata, paths[0])
+		w.json(http.StatusNoContent, nil)
+	default:
+		w.notAllowed()
+	}
+}
+
+func (c *Cloud) serveServerTags(w response, r request, s *server, paths []string) {
+	switch {
+	case len(paths) == 0 && r.Method == http.MethodGet:
+		w.json(http.StatusOK, map[string]interface{}{"tags": append([]string{}, s.Tags...)})
+	case len(paths) == 0 && r.Method == http.MethodDelete:
+		s.Tags = nil
+		w.json(http.StatusNoContent, nil)
+	case len(paths) == 1 && r.Method == http.MethodPut:
+		if !slices.Contains(s.Tags, paths[0]) {
+			s.Tags = append(s.Tags, paths[0])
+		}
+		w.json(http.StatusCreated, nil)
+	case len(paths) == 1 && r.Method == http.MethodDelete:
+		if !slices.Contains(s.Tags, paths[0]) {
+			w.notFound("Tag %s could not be found.", paths[0])
+			return
+		}
+		s.Tags = slices.DeleteFunc(s.Tags, func(tag string) bool { return tag == paths[0] })
+		w.json(http.StatusNoContent, nil)
+	default:
+		w.notAllowed()
+	}
+}
+
+// 计算节点对应的 nova-compute 服务, 第一次使用时创建
+func (c *Cloud) computeService(host string) *nova.Service {
+	if service, ok := c.services[host]; ok {
+		return service
+	}
+	service := &nova.Service{
+		Resource: model.Resource{Id: NewId(), UpdatedAt: now()},
+		Binary:   "nova-compute", Host: host, Zone: DEFAULT_AZ, Status: "enabled", State: "up",
+	}
+	c.services[host] = service
+	return service
+}
+
+// 设置计算节点的服务状态为 down, 用于模拟节点故障
+func (c *Cloud) SetHostDown(host string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.computeService(host).State = "down"
+}
+
+func (c *Cloud) serveServices(w response, r request, paths []string) {
+	if len(paths) == 0 {
+		if r.Method != http.MethodGet {
+			w.notAllowed()
+			return
+		}
+		services := []nova.Service{}
+		for _, host := range c.Hosts {
+			service := c.computeService(host)
+			if matchQuery(r, map[string]string{"host": service.Host, "binary": service.Binary}) {
+				services = append(services, *service)
+			}
+		}
+		w.json(http.StatusOK, map[string]interface{}{"services": services})
+		return
+	}
+	if r.Method != http.MethodPut {
+		w.notAllowed()
+		return
+	}
+	var service *nova.Service
+	for _, host := range c.Hosts {
+		if c.computeService(host).Id == paths[0] {
+			service = c.computeService(host)
+		}
+	}
+	if service == nil {
+		w.notFound("Service %s could not be found.", paths[0])
+		return
+	}
+	body := struct {
+		Status         *string `json:"status"`
+		DisabledReason string  `json:"disabled_reason"`
+		ForcedDown     *bool   `json:"forced_down"`
+	}{}
+	if err := r.decode(&body); err != nil {
+		w.badRequest("invalid request body: %s", err)
+		return
+	}
+	if body.Status != nil {
+		service.Status, service.DisabledReason = *body.Status, body.DisabledReason
+	}
+	if body.ForcedDown != nil {
+		service.ForcedDown = *body.ForcedDown
+	}
+	service.UpdatedAt = now()
+	w.json(http.StatusOK, map[string]interface{}{"service": service})
+}
+
+func (c *Cloud) addMigration(s *server, migrationType string, dest string, status string) *nova.Migration {
+	migration := &nova.Migration{
+		Id: len(c.migrations) + 1, Uuid: NewId(), InstanceUUID: s.Id, MigrationType: migrationType,
+		Status: status, SourceCompute: s.Host, SourceNode: s.Host, DestCompute: dest, DestNode: dest,
+		OldInstanceTypeId: 1, NewInstanceTypeId: 1, CreatedAt: now(), UpdatedAt: now(),
+	}
+	c.migrations = append(c.migrations, migration)
+	return migration
+}
+func setMigrationStatus(migration *nova.Migration, status string) {
+	if migration != nil {
+		migration.Status, migration.UpdatedAt = status, now()
+	}
+}
+
+// 和 nova 一样, 迁移记录按创建时间倒序返回
+func (c *Cloud) listMigrations(w response, r request, filter func(m *nova.Migration) bool) {
+	query := r.URL.Query()
+	migrations := []nova.Migration{}
+	for i := len(c.migrations) - 1; i >= 0; i-- {
+		m := c.migrations[i]
+		if filter != nil && !filter(m) {
+			continue
+		}
+		if !matchQuery(r, map[string]string{
+			"instance_uuid": m.InstanceUUID, "migration_type": m.MigrationType, "status": m.Status,
+		}) {
+			continue
+		}
+		if host := query.Get("host"); host != "" && m.SourceCompute != host && m.DestCompute != host {
+			continue
+		}
+		migrations = append(migrations, *m)
+	}
+	w.json(http.StatusOK, map[string]interface{}{"migrations": migrations})
+}
+
+func (c *Cloud) findKeypair(name string) *nova.Keypair {
+	for _, keypair := range c.keypairs {
+		if keypair.Keypair.Name == name {
+			return keypair
+		}
+	}
+	return nil
+}
+
+// 和 2.92 及以上版本的 nova 一样, 只支持导入公钥
+func (c *Cloud) serveKeypairs(w response, r request, paths []string) {
+	if len(paths) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			keypairs := []nova.Keypair{}
+			for _, keypair := range c.keypairs {
+				keypairs = append(keypairs, *keypair)
+			}
+			w.json(http.StatusOK, map[string]interface{}{"keypairs": keypairs})
+		case http.MethodPost:
+			keypair := nova.Keypair{}
+			if err := r.decode(&keypair); err != nil {
+				w.badRequest("invalid request body: %s", err)
+				return
+			}
+			if keypair.Keypair.Name == "" {
+				w.badRequest("Invalid input for field/attribute name.")
+				return
+			}
+			publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(keypair.Keypair.PublicKey))
+			if err != nil {
+				w.badRequest("Keypair data is invalid: failed to generate fingerprint")
+				return
+			}
+			if c.findKeypair(keypair.Keypair.Name) != nil {
+				w.conflict("Key pair '%s' already exists.", keypair.Keypair.Name)
+				return
+			}
+			if keypair.Keypair.Type == "" {
+				keypair.Keypair.Type = nova.KEYPAIR_TYPE_SSH
+			}
+			keypair.Keypair.Fingerprint = ssh.FingerprintLegacyMD5(publicKey)
+			keypair.Keypair.UserId, keypair.Keypair.CreatedAt = c.Username, now()
+			c.keypairs = append(c.keypairs, &keypair)
+			w.json(http.StatusOK, keypair)
+		default:
+			w.notAllowed()
+		}
+		return
+	}
+	keypair := c.findKeypair(paths[0])
+	if keypair == nil {
+		w.notFound("Keypair %s not found for user %s.", paths[0], c.Username)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		w.json(http.StatusOK, keypair)
+	case http.MethodDelete:
+		c.keypairs = slices.DeleteFunc(c.keypairs, func(k *nova.Keypair) bool { return k == keypair })
+		w.json(http.StatusAccepted, nil)
+	default:
+		w.notAllowed()
+	}
+}
+
+func (c *Cloud) serveServerGroups(w response, r request, paths []string) {
+	if len(paths) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			groups := []nova.ServerGroup{}
+			for _, id := range c.order {
+				if group, ok := c.serverGroups[id]; ok {
+					groups = append(groups, *group)
+				}
+			}
+			// 和 nova 一样, 服务器组使用 offset/limit 分页, 不返回下一页链接
+			groups, err := paginateWithOffset(r, groups, c.PageSize)
+			if err != nil {
+				w.badRequest("%s", err)
+				return
+			}
+			w.json(http.StatusOK, map[string]interface{}{"server_groups": groups})
+		case http.MethodPost:
+			body := struct {
+				ServerGroup nova.ServerGroup `json:"server_group"`
+			}{}
+			if err := r.decode(&body); err != nil {
+				w.badRequest("invalid request body: %s", err)
+				return
+			}
+			group := body.ServerGroup
+			if group.Name == "" || !slices.Contains(nova.GROUP_POLICIES, group.GetPolicy()) {
+				w.badRequest("Invalid input for field/attribute server_group.")
+				return
+			}
+			if len(group.Rules) > 0 && group.GetPolicy() != nova.GROUP_ANTI_AFFINITY {
+				w.badRequest("Only anti-affinity policy supports rules.")
+				return
+			}
+			group.Id, group.ProjectId, group.Members = NewId(), c.ProjectId, []string{}
+			group.Policies, group.Policy = nil, group.GetPolicy()
+			if group.Rules == nil {
+				group.Rules = map[string]interface{}{}
+			}
+			c.serverGroups[group.Id] = &group
+			c.order = append(c.order, group.Id)
+			w.json(http.StatusOK, map[string]interface{}{"server_group": group})
+		default:
+			w.notAllowed()
+		}
+		return
+	}
+	group, ok := c.serverGroups[paths[0]]
+	if !ok {
+		w.notFound("Server group %s could not be found.", paths[0])
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		w.json(http.StatusOK, map[string]interface{}{"server_group": group})
+	case http.MethodDelete:
+		delete(c.serverGroups, group.Id)
+		w.json(http.StatusNoContent, nil)
+	default:
+		w.notAllowed()
+	}
+}
+
+func (c *Cloud) serveFlavors(w response, r request, paths []string) {
+	if len(paths) == 0 || paths[0] == "detail" {
+		switch r.Method {
+		case http.MethodGet:
+			flavors := []nova.Flavor{}
+			for _, flavor := range c.flavors {
+				flavors = append(flavors, *flavor)
+			}
+			w.json(http.StatusOK, map[string]interface{}{"flavors": flavors})
+		case http.MethodPost:
+			body := struct{ Flavor nova.Flavor }{}
+			if err := r.decode(&body); err != nil {
+				w.badRequest("invalid request body: %s", err)
+				return
+			}
+			if c.findFlavor(body.Flavor.Name) != nil {
+				w.conflict("Flavor with name %s already exists.", body.Flavor.Name)
+				return
+			}
+			flavor := body.Flavor
+			if flavor.Id == "" {
+				flavor.Id = NewId()
+			}
+			flavor.IsPublic = true
+			c.flavors = append(c.flavors, &flavor)
+			w.json(http.StatusOK, map[string]interface{}{"flavor": flavor})
+		default:
+			w.notAllowed()
+		}
+		return
+	}
+	var flavor *nova.Flavor
+	for _, f := range c.flavors {
+		if f.Id == paths[0] {
+			flavor = f
+		}
+	}
+	if flavor == nil {
+		w.notFound("Flavor %s could not be found.", paths[0])
+		return
+	}
+	if len(paths) > 1 && paths[1] == "os-extra_specs" {
+		if flavor.ExtraSpecs == nil {
+			flavor.ExtraSpecs = nova.ExtraSpecs{}
+		}
+		if r.Method == http.MethodPost {
+			body := struct {
+				ExtraSpecs nova.ExtraSpecs `json:"extra_specs"`
+			}{}
+			if err := r.decode(&body); err != nil {
+				w.badRequest("invalid request body: %s", err)
+				return
+			}
+			for k, v := range body.ExtraSpecs {
+				flavor.ExtraSpecs[k] = v
+			}
+		}
+		w.json(http.StatusOK, map[string]interface{}{"extra_specs": flavor.ExtraSpecs})
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		w.json(http.StatusOK, map[string]interface{}{"flavor": flavor})
+	case http.MethodDelete:
+		flavors := []*nova.Flavor{}
+		for _, f := range c.flavors {
+			if f != flavor {
+				flavors = append(flavors, f)
+			}
+		}
+		c.flavors = flavors
+		w.json(http.StatusAccepted, nil)
+	default:
+		w.notAllowed()
+	}
+}
+
+func (c *Cloud) hypervisors() []nova.Hypervisor {
+	hypervisors := []nova.Hypervisor{}
+	for i, host := range c.Hosts {
+		hypervisor := nova.Hypervisor{
+			Resource: model.Resource{Id: fmt.Sprintf("%d", i+1), Status: "enabled"},
+			Host:     host, HypervisorHostname: host, HostIp: fmt.Sprintf("192.168.0.%d", i+1),
+			State: "up", Type: "QEMU", Version: 6002000, Vcpus: 64, MemoryMB: 262144,
+		}
+		for _, s := range c.servers {
+			if s.Host == host {
+				hypervisor.VcpusUsed += s.Flavor.Vcpus
+				hypervisor.MemoryMBUsed += s.Flavor.Ram
+			}
+		}
+		hypervisors = append(hypervisors, hypervisor)
+	}
+	return hypervisors
+}
+
+func (c *Cloud) serveHypervisors(w response, r request, paths []string) {
+	if r.Method != http.MethodGet {
+		w.notAllowed()
+		return
+	}
+	if len(paths) == 0 || paths[0] == "detail" {
+		pattern := r.URL.Query().Get("hypervisor_hostname_pattern")
+		hypervisors := []nova.Hypervisor{}
+		for _, hypervisor := range c.hypervisors() {
+			if pattern == "" || strings.Contains(hypervisor.HypervisorHostname, pattern) {
+				hypervisors = append(hypervisors, hypervisor)
+			}
+		}
+		w.json(http.StatusOK, map[string]interface{}{"hypervisors": hypervisors})
+		return
+	}
+	for _, hypervisor := range c.hypervisors() {
+		if hypervisor.Id == paths[0] {
+			w.json(http.StatusOK, map[string]interface{}{"hypervisor": hypervisor})
+			return
+		}
+	}
+	w.notFound("Hypervisor with ID %s could not be found.", paths[0])
+}
+
+func (c *Cloud) serveServers(w response, r request, paths []string) {
+	if len(paths) == 0 || paths[0] == "detail" {
+		switch r.Method {
+		case http.MethodGet:
+			c.listServers(w, r, len(paths) > 0)
+		case http.MethodPost:
+			c.createServer(w, r)
+		default:
+			w.notAllowed()
+		}
+		return
+	}
+	s, ok := c.servers[paths[0]]
+	if !ok {
+		w.notFound("Instance %s could not be found.", paths[0])
+		return
+	}
+	if len(paths) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			w.json(http.StatusOK, map[string]interface{}{"server": c.serverView(s)})
+		case http.MethodPut:
+			body := struct{ Server map[string]interface{} }{}
+			if err := r.decode(&body); err != nil {
+				w.badRequest("invalid request body: %s", err)
+				return
+			}
+			if name, ok := body.Server["name"].(string); ok {
+				s.Name = name
+			}
+			if description, ok := body.Server["description"].(string); ok {
+				s.Description = description
+			}
+			s.Updated = now()
+			w.json(http.StatusOK, map[string]interface{}{"server": c.serverView(s)})
+		case http.MethodDelete:
+			c.deleteServer(w, r, s)
+		default:
+			w.notAllowed()
+		}
+		return
+	}
+	switch paths[1] {
+	case "action":
+		if r.Method != http.MethodPost {
+			w.notAllowed()
+			return
+		}
+		c.serverAction(w, r, s)
+	case "os-interface":
+		c.serveInterfaces(w, r, s, paths[2:])
+	case "os-volume_attachments":
+		c.serveVolumeAttachments(w, r, s, paths[2:])
+	case "metadata":
+		c.serveServerMetadata(w, r, s, paths[2:])
+	case "tags":
+		c.serveServerTags(w, r, s, paths[2:])
+	case "os-instance-actions":
+		if len(paths) == 2 {
+			actions := []nova.InstanceAction{}
+			for i := len(s.actions) - 1; i >= 0; i-- {
+				action := *s.actions[i]
+				action.Events = nil
+				actions = append(actions, action)
+			}
+			w.json(http.StatusOK, map[string]interface{}{"instanceActions": actions})
+			return
+		}
+		action := s.findAction(paths[2])
+		if action == nil {
+			w.notFound("Action %s on instance %s could not be found.", paths[2], s.Id)
+			return
+		}
+		w.json(http.StatusOK, map[string]interface{}{"instanceAction": action})
+	case "migrations":
+		if len(paths) > 2 {
+			c.serveServerMigration(w, r, s, paths[2:])
+			return
+		}
+		// 和 nova 一样, 只返回正在进行的热迁移
+		c.listMigrations(w, r, func(m *nova.Migration) bool {
+			return m.InstanceUUID == s.Id && m.MigrationType == "live-migration" && m.Status == "running"
+		})
+	default:
+		w.notFound("resource %s not found", paths[1])
+	}
+}
+
+func (c *Cloud) listServers(w response, r request, detail bool) {
+	query := r.URL.Query()
+	var nameRegex *regexp.Regexp
+	if name := query.Get("name"); name != "" {
+		var err error
+		if nameRegex, err = regexp.Compile(name); err != nil {
+			w.badRequest("Invalid filter name: %s", name)
+			return
+		}
+	}
+	matched := []*server{}
+	for _, id := range c.order {
+		s, ok := c.servers[id]
+		if !ok || (nameRegex != nil && !nameRegex.MatchString(s.Name)) {
+			continue
+		}
+		if !matchQuery(r, map[string]string{"status": s.Status, "host": s.Host}) {
+			continue
+		}
+		if tags := query.Get("tags"); tags != "" && !s.hasTags(strings.Split(tags, ",")) {
+			continue
+		}
+		if tags := query.Get("not-tags"); tags != "" && s.hasTags(strings.Split(tags, ",")) {
+			continue
+		}
+		matched = append(matched, s)
+	}
+	matched, next, err := paginate(r, matched, func(s *server) string { return s.Id }, c.PageSize)
+	if err != nil {
+		w.badRequest("%s", err)
+		return
+	}
+	servers := []interface{}{}
+	for _, s := range matched {
+		if detail {
+			servers = append(servers, c.serverView(s))
+		} else {
+			servers = append(servers, map[string]interface{}{"id": s.Id, "name": s.Name})
+		}
+	}
+	w.json(http.StatusOK, withLinks(map[string]interface{}{"servers": servers}, "servers", next))
+}
+
+func (c *Cloud) createServer(w response, r request) {
+	body := struct {
+		Server struct {
+			nova.ServerOpt
+			Networks interface{} `json:"networks"`
+		} `json:"server"`
+		SchedulerHints struct {
+			Group string `json:"group"`
+		} `json:"os:scheduler_hints"`
+	}{}
+	if err := r.decode(&body); err != nil {
+		w.badRequest("invalid request body: %s", err)
+		return
+	}
+	opt := body.Server
+	if opt.Name == "" {
+		w.badRequest("Invalid input for field/attribute name.")
+		return
+	}
+	flavor := c.findFlavor(opt.Flavor)
+	if flavor == nil {
+		w.badRequest("Flavor %s could not be found.", opt.Flavor)
+		return
+	}
+	imageId := ""
+	if len(opt.BlockDeviceMappingV2) == 0 {
+		if imageId = c.findImage(opt.Image); imageId == "" {
+			w.badRequest("Image %s could not be found.", opt.Image)
+			return
+		}
+	}
+	if opt.KeyName != "" && c.findKeypair(opt.KeyName) == nil {
+		w.badRequest("Invalid key_name provided.")
+		return
+	}
+	var group *nova.ServerGroup
+	if groupId := body.SchedulerHints.Group; groupId != "" {
+		if group = c.serverGroups[groupId]; group == nil {
+			w.badRequest("Invalid server group %s.", groupId)
+			return
+		}
+	}
+	// 解析网络参数
+	ports := []*neutron.Port{}
+	novaPorts := map[string]bool{}
+	switch networks := opt.Networks.(type) {
+	case string:
+		if networks == "auto" {
+			for _, id := range c.order {
+				if network, ok := c.networks[id]; ok {
+					port, _ := c.createPort(map[string]interface{}{"network_id": network.Id})
+					ports, novaPorts[port.Id] = append(ports, port), true
+					break
+				}
+			}
+		}
+	case []interface{}:
+		for _, item := range networks {
+			nic, _ := item.(map[string]interface{})
+			if portId, _ := nic["port"].(string); portId != "" {
+				port, ok := c.ports[portId]
+				if !ok {
+					w.badRequest("Port %s could not be found.", portId)
+					return
+				}
+				if port.DeviceId != "" {
+					w.conflict("Port %s is still in use.", portId)
+					return
+				}
+				ports = append(ports, port)
+			} else {
+				networkId, _ := nic["uuid"].(string)
+				port, err := c.createPort(map[string]interface{}{"network_id": networkId})
+				if err != nil {
+					w.badRequest("Network %s could not be found.", networkId)
+					return
+				}
+				ports, novaPorts[port.Id] = append(ports, port), true
+			}
+		}
+	}
+
+	s := &server{
+		Server: nova.Server{
+			Resource: model.Resource{
+				Id: NewId(), Name: opt.Name, ProjectId: c.ProjectId, TenantId: c.ProjectId,
+			},
+			Flavor:   *flavor,
+			AZ:       opt.AvailabilityZone,
+			KeyName:  opt.KeyName,
+			Metadata: map[string]string{}, Tags: opt.Tags,
+			Created: now(), Updated: now(),
+			SecurityGroups: []neutron.SecurityGroup{{Resource: model.Resource{Name: "default"}}},
+			RootBdmType:    "local", RootDeviceName: "/dev/vda",
+			InstanceName: fmt.Sprintf("instance-%08x", len(c.servers)+1),
+		},
+		imageId: imageId, novaPorts: novaPorts,
+	}
+	if s.AZ == "" {
+		s.AZ = DEFAULT_AZ
+	}
+	for k, v := range opt.Metadata {
+		s.Metadata[k] = v
+	}
+	for _, port := range ports {
+		s.ports = append(s.ports, port.Id)
+		port.DeviceId = s.Id
+	}
+	s.setState("BUILD", POWER_NOSTATE)
+	c.servers[s.Id] = s
+	c.order = append(c.order, s.Id)
+	if group != nil {
+		group.Members = append(group.Members, s.Id)
+	}
+
+	action := c.startAction(s, r, "create")
+	c.runTask(s, action, []stage{{"BUILD", "scheduling"}, {"BUILD", "networking"}, {"BUILD", "spawning"}},
+		func() {
+			s.Host = c.pickGroupHost(group)
+			for _, portId := range s.ports {
+				if port, ok := c.ports[portId]; ok {
+					c.bindPort(port, s.Id, s.Host)
+				}
+			}
+			for _, bdm := range opt.BlockDeviceMappingV2 {
+				if bdm.DestinationType != "volume" {
+					continue
+				}
+				var vol *volume
+				switch bdm.SourceType {
+				case "image":
+					vol = c.createVolume("", uint(bdm.VolumeSize), bdm.VolumeType, bdm.UUID)
+					vol.setStatus("available")
+				case "volume":
+					vol = c.volumes[bdm.UUID]
+				}
+				if vol == nil {
+					continue
+				}
+				vol.deleteOnTermination = bdm.DeleteOnTemination
+				device := "/dev/vda"
+				if bdm.BootIndex != 0 || len(s.volumes) > 0 {
+					device = c.nextDevice(s)
+				} else {
+					s.RootBdmType = "volume"
+				}
+				c.attachVolume(vol, s.Id, device, s.Host)
+				s.volumes = append(s.volumes, nova.VolumeAttachment{
+					Id: vol.Id, VolumeId: vol.Id, ServerId: s.Id, Device: device,
+					DeleteOnTermination: bdm.DeleteOnTemination,
+				})
+			}
+			s.LaunchedAt = now()
+			s.setState("ACTIVE", POWER_RUNNING)
+		},
+	)
+	w.json(http.StatusAccepted, map[string]interface{}{
+		"server": map[string]interface{}{
+			"id": s.Id, "adminPass": "fake-password", "OS-DCF:diskConfig": "MANUAL",
+			"security_groups": s.SecurityGroups, "links": []interface{}{},
+		},
+	})
+}
+
+func (c *Cloud) deleteServer(w response, r request, s *server) {
+	action := c.startAction(s, r, "delete")
+	c.runTask(s, action, []stage{{"", "deleting"}}, func() {
+		for _, portId := range s.ports {
+			port, ok := c.ports[portId]
+			if !ok {
+				continue
+			}
+			if s.novaPorts[portId] {
+				delete(c.ports, portId)
+			} else {
+				c.unbindPort(port)
+			}
+		}
+		for _, attachment := range s.volumes {
+			vol, ok := c.volumes[attachment.VolumeId]
+			if !ok {
+				continue
+			}
+			vol.Attachments = nil
+			if vol.deleteOnTermination {
+				delete(c.volumes, vol.Id)
+			} else {
+				vol.setStatus("available")
+			}
+		}
+		for _, group := range c.serverGroups {
+			group.Members = slices.DeleteFunc(group.Members, func(id string) bool { return id == s.Id })
+		}
+		s.deleted = true
+		delete(c.servers, s.Id)
+	})
+	w.json(http.StatusNoContent, nil)
+}
+
+func (c *Cloud) migrateServer(s *server, action *nova.InstanceAction, host string, flavor *nova.Flavor) {
+	if host == "" {
+		host = c.pickHost(s.Host)
+	}
+	oldFlavor := s.Flavor
+	s.oldFlavor, s.oldHost, s.oldStatus = &oldFlavor, s.Host, s.Status
+	migrationType := "migration"
+	if flavor != nil {
+		migrationType = "resize"
+	}
+	s.migration = c.addMigration(s, migrationType, host, "migrating")
+	c.runTask(s, action,
+		[]stage{{"RESIZE", "resize_prep"}, {"RESIZE", "resize_migrating"}, {"RESIZE", "resize_finish"}},
+		func() {
+			s.Host = host
+			if flavor != nil {
+				s.Flavor = *flavor
+			}
+			c.movePorts(s)
+			s.setState("VERIFY_RESIZE", s.PowerState)
+			setMigrationStatus(s.migration, "finished")
+			if c.ResizeConfirmWindow > 0 {
+				c.scheduleAfter(c.ResizeConfirmWindow, func() {
+					if !s.deleted && s.Status == "VERIFY_RESIZE" && s.TaskState == "" {
+						c.confirmResize(s, nil)
+					}
+				})
+			}
+		},
+	)
+}
+
+// 热迁移持续 TaskDuration, 期间可以取消或者强制完成
+func (c *Cloud) liveMigrate(s *server, action *nova.InstanceAction, host string) {
+	status := s.Status
+	migration := c.addMigration(s, "live-migration", host, "running")
+	s.migration = migration
+	s.Status, s.TaskState, s.Progress, s.Updated = "MIGRATING", "migrating", 50, now()
+	s.finishLiveMigration = func(result string) {
+		s.finishLiveMigration = nil
+		if result == "completed" {
+			s.Host = host
+			c.movePorts(s)
+			finishAction(action, "Success")
+		} else {
+			finishAction(action, "Error")
+		}
+		s.setState(status, s.PowerState)
+		s.Progress = 0
+		setMigrationStatus(migration, result)
+	}
+	c.schedule(func() {
+		if !s.deleted && migration.Status == "running" {
+			s.finishLiveMigration("completed")
+		}
+	})
+}
+
+// 正在进行的热迁移详情, 内存和磁盘按迁移进度模拟
+func (c *Cloud) serveServerMigration(w response, r request, s *server, paths []string) {
+	migration := s.migration
+	if migration == nil || fmt.Sprint(migration.Id) != paths[0] || migration.Status != "running" {
+		w.notFound("Migration %s for server %s could not be found.", paths[0], s.Id)
+		return
+	}
+	switch {
+	case len(paths) == 1 && r.Method == http.MethodGet:
+		view := *migration
+		view.ServerUuid = s.Id
+		view.MemoryTotalBytes, view.MemoryProcessedBytes = int64(s.Flavor.Ram)*1024*1024, int64(s.Flavor.Ram)*512*1024
+		view.MemoryRemainingBytes = view.MemoryTotalBytes - view.MemoryProcessedBytes
+		w.json(http.StatusOK, map[string]interface{}{"migration": view})
+	case len(paths) == 1 && r.Method == http.MethodDelete:
+		s.finishLiveMigration("cancelled")
+		w.json(http.StatusAccepted, nil)
+	case len(paths) == 2 && paths[1] == "action" && r.Method == http.MethodPost:
+		body := map[string]interface{}{}
+		if err := r.decode(&body); err != nil {
+			w.badRequest("invalid request body: %s", err)
+			return
+		}
+		if _, ok := body["force_complete"]; !ok {
+			w.badRequest("There is no such action: %v", body)
+			return
+		}
+		s.finishLiveMigration("completed")
+		w.json(http.StatusAccepted, nil)
+	default:
+		w.notAllowed()
+	}
+}
+
+func (c *Cloud) confirmResize(s *server, action *nova.InstanceAction) {
+	s.setState(s.oldStatus, s.PowerState)
+	s.oldFlavor, s.oldHost = nil, ""
+	setMigrationStatus(s.migration, "confirmed")
+	finishAction(action, "Success")
+}
+
+func (c *Cloud) movePorts(s *server) {
+	for _, portId := range s.ports {
+		if port, ok := c.ports[portId]; ok {
+			port.BindingHostId, port.UpdatedAt = s.Host, now()
+		}
+	}
+}
+
+func (c *Cloud) serverAction(w response, r request, s *server) {
+	body := map[string]interface{}{}
+	if err := r.decode(&body); err != nil {
+		w.badRequest("invalid request body: %s", err)
+		return
+	}
+	if len(body) != 1 {
+		w.badRequest("Invalid action request body")
+		return
+	}
+	for name, value := range body {
+		params, _ := value.(map[string]interface{})
+		switch name {
+		case "os-stop":
+			if !checkState(w, s, "stop", "ACTIVE", "ERROR") {
+				return
+			}
+			c.runTask(s, c.startAction(s, r, "stop"), []stage{{"", "powering-off"}},
+				func() { s.setState("SHUTOFF", POWER_SHUTDOWN) })
+		case "os-start":
+			if !checkState(w, s, "start", "SHUTOFF") {
+				return
+			}
+			c.runTask(s, c.startAction(s, r, "start"), []stage{{"", "powering-on"}},
+				func() { s.setState("ACTIVE", POWER_RUNNING) })
+		case "reboot":
+			if rebootType, _ := params["type"].(string); strings.ToLower(rebootType) == "hard" {
+				if !checkState(w, s, "reboot", "ACTIVE", "SHUTOFF", "PAUSED", "SUSPENDED", "ERROR") {
+					return
+				}
+				c.runTask(s, c.startAction(s, r, "reboot"), []stage{{"HARD_REBOOT", "reboot_pending_hard"}, {"HARD_REBOOT", "reboot_started_hard"}},
+					func() { s.setState("ACTIVE", POWER_RUNNING) })
+			} else {
+				if !checkState(w, s, "reboot", "ACTIVE") {
+					return
+				}
+				c.runTask(s, c.startAction(s, r, "reboot"), []stage{{"REBOOT", "reboot_pending"}, {"REBOOT", "reboot_started"}},
+					func() { s.setState("ACTIVE", POWER_RUNNING) })
+			}
+		case "pause":
+			if !checkState(w, s, "pause", "ACTIVE") {
+				return
+			}
+			c.runTask(s, c.startAction(s, r, "pause"), []stage{{"", "pausing"}},
+				func() { s.setState("PAUSED", POWER_PAUSED) })
+		case "unpause":
+			if !checkState(w, s, "unpause", "PAUSED") {
+				return
+			}
+			c.runTask(s, c.startAction(s, r, "unpause"), []stage{{"", "unpausing"}},
+				func() { s.setState("ACTIVE", POWER_RUNNING) })
+		case "suspend":
+			if !checkState(w, s, "suspend", "ACTIVE", "SHUTOFF") {
+				return
+			}
+			c.runTask(s, c.startAction(s, r, "suspend"), []stage{{"", "suspending"}},
+				func() { s.setState("SUSPENDED", POWER_SUSPEND) })
+		case "resume":
+			if !checkState(w, s, "resume", "SUSPENDED") {
+				return
+			}
+			c.runTask(s, c.startAction(s, r, "resume"), []stage{{"", "resuming"}},
+				func() { s.setState("ACTIVE", POWER_RUNNING) })
+		case "shelve":
+			if !checkState(w, s, "shelve", "ACTIVE", "SHUTOFF", "PAUSED", "SUSPENDED") {
+				return
+			}
+			c.runTask(s, c.startAction(s, r, "shelve"),
+				[]stage{{"", "shelving"}, {"", "shelving_image_uploading"}, {"SHELVED", "shelving_offloading"}},
+				func() {
+					s.Host = ""
+					c.movePorts(s)
+					s.setState("SHELVED_OFFLOADED", POWER_SHUTDOWN)
+				})
+		case "unshelve":
+			if !checkState(w, s, "unshelve", "SHELVED", "SHELVED_OFFLOADED") {
+				return
+			}
+			c.runTask(s, c.startAction(s, r, "unshelve"), []stage{{"", "unshelving"}, {"", "spawning"}},
+				func() {
+					s.Host = c.pickHost("")
+					c.movePorts(s)
+					s.setState("ACTIVE", POWER_RUNNING)
+				})
+		case "resize":
+			if !checkState(w, s, "resize", "ACTIVE", "SHUTOFF") {
+				return
+			}
+			flavorRef, _ := params["flavorRef"].(string)
+			flavor := c.findFlavor(flavorRef)
+			if flavor == nil {
+				w.badRequest("Flavor %s could not be found.", flavorRef)
+				return
+			}
+			if flavor.Id == s.Flavor.Id {
+				w.badRequest("When resizing, instances must change flavor!")
+				return
+			}
+			c.migrateServer(s, c.startAction(s, r, "resize"), "", flavor)
+		case "migrate":
+			if !checkState(w, s, "migrate", "ACTIVE", "SHUTOFF") {
+				return
+			}
+			host, _ := params["host"].(string)
+			if host != "" && host == s.Host {
+				w.badRequest("The target host can't be the same one.")
+				return
+			}
+			c.migrateServer(s, c.startAction(s, r, "migrate"), host, nil)
+		case "confirmResize":
+			if !checkState(w, s, "confirmResize", "VERIFY_RESIZE") {
+				return
+			}
+			c.confirmResize(s, c.startAction(s, r, "confirmResize"))
+		case "revertResize":
+			if !checkState(w, s, "revertResize", "VERIFY_RESIZE") {
+				return
+			}
+			c.runTask(s, c.startAction(s, r, "revertResize"), []stage{{"REVERT_RESIZE", "resize_reverting"}},
+				func() {
+					s.Host, s.Flavor = s.oldHost, *s.oldFlavor
+					c.movePorts(s)
+					s.setState(s.oldStatus, s.PowerState)
+					s.oldFlavor, s.oldHost = nil, ""
+					setMigrationStatus(s.migration, "reverted")
+				})
+		case "os-migrateLive":
+			if !checkState(w, s, "os-migrateLive", "ACTIVE", "PAUSED") {
+				return
+			}
+			host, _ := params["host"].(string)
+			if host != "" && host == s.Host {
+				w.badRequest("The target host can't be the same one.")
+				return
+			}
+			if host == "" {
+				host = c.pickHost(s.Host)
+			}
+			c.liveMigrate(s, c.startAction(s, r, "live-migration"), host)
+		case "evacuate":
+			if !checkState(w, s, "evacuate", "ACTIVE", "SHUTOFF", "ERROR") {
+				return
+			}
+			if service := c.computeService(s.Host); service.State != "down" && !service.ForcedDown {
+				w.badRequest("Compute service of %s is still in use.", s.Host)
+				return
+			}
+			host, _ := params["host"].(string)
+			if host == "" {
+				host = c.pickHost(s.Host)
+			}
+			status, powerState := "ACTIVE", POWER_RUNNING
+			if s.Status == "SHUTOFF" {
+				status, powerState = "SHUTOFF", POWER_SHUTDOWN
+			}
+			migration := c.addMigration(s, "evacuation", host, "accepted")
+			c.runTask(s, c.startAction(s, r, "evacuate"), []stage{{"REBUILD", "rebuilding"}, {"REBUILD", "rebuild_spawning"}},
+				func() {
+					s.Host = host
+					c.movePorts(s)
+					s.setState(status, powerState)
+					setMigrationStatus(migration, "done")
+				})
+		case "rebuild":
+			if !checkState(w, s, "rebuild", "ACTIVE", "SHUTOFF", "ERROR") {
+				return
+			}
+			if imageRef, _ := params["imageRef"].(string); imageRef != "" {
+				imageId := c.findImage(imageRef)
+				if imageId == "" {
+					w.badRequest("Image %s could not be found.", imageRef)
+					return
+				}
+				s.imageId = imageId
+			}
+			if name, _ := params["name"].(string); name != "" {
+				s.Name = name
+			}
+			c.runTask(s, c.startAction(s, r, "rebuild"),
+				[]stage{{"REBUILD", "rebuilding"}, {"REBUILD", "rebuild_block_device_mapping"}, {"REBUILD", "rebuild_spawning"}},
+				func() { s.setState("ACTIVE", POWER_RUNNING) })
+		case "createImage":
+			if !checkState(w, s, "createImage", "ACTIVE", "SHUTOFF", "PAUSED", "SUSPENDED") {
+				return
+			}
+			imageName, _ := params["name"].(string)
+			image := c.snapshotImage(imageName)
+			c.runTask(s, c.startAction(s, r, "createImage"),
+				[]stage{{"", "image_snapshot"}, {"", "image_pending_upload"}, {"", "image_uploading"}}, nil)
+			w.Header().Set("Location", c.glance.URL+"/v2/images/"+image.Id)
+			w.json(http.StatusAccepted, map[string]string{"image_id": image.Id})
+			return
+		case "os-resetState":
+			state, _ := params["state"].(string)
+			status := "ACTIVE"
+			if state == "error" {
+				status = "ERROR"
+			}
+			s.setState(status, s.PowerState)
+		case "os-getConsoleOutput":
+			w.json(http.StatusOK, map[string]string{
+				"output": fmt.Sprintf("[    0.000000] Linux version 5.15.0\n\n%s login: ", s.Name),
+			})
+			return
+		case "lock":
+			s.Locked = true
+			if reason, _ := params["locked_reason"].(string); reason != "" {
+				s.LockedReason = reason
+			}
+			finishAction(c.startAction(s, r, "lock"), "Success")
+		case "unlock":
+			s.Locked, s.LockedReason = false, ""
+			finishAction(c.startAction(s, r, "unlock"), "Success")
+		case "rescue":
+			if !checkState(w, s, "rescue", "ACTIVE", "SHUTOFF", "ERROR") {
+				return
+			}
+			password, _ := params["adminPass"].(string)
+			if password == "" {
+				password = "fake-" + NewId()[:8]
+			}
+			c.runTask(s, c.startAction(s, r, "rescue"), []stage{{"", "rescuing"}},
+				func() { s.setState("RESCUE", POWER_RUNNING) })
+			w.json(http.StatusOK, map[string]string{"adminPass": password})
+			return
+		case "unrescue":
+			if !checkState(w, s, "unrescue", "RESCUE") {
+				return
+			}
+			c.runTask(s, c.startAction(s, r, "unrescue"), []stage{{"", "unrescuing"}},
+				func() { s.setState("ACTIVE", POWER_RUNNING) })
+		case "trigger_crash_dump":
+			if !checkState(w, s, "trigger_crash_dump", "ACTIVE", "PAUSED", "RESCUE") {
+				return
+			}
+			finishAction(c.startAction(s, r, "trigger_crash_dump"), "Success")
+		case "changePassword":
+			if !checkState(w, s, "changePassword", "ACTIVE") {
+				return
+			}
+			finishAction(c.startAction(s, r, "changePassword"), "Success")
+		default:
+			w.badRequest("There is no such action: %s", name)
+			return
+		}
+	}
+	w.json(http.StatusAccepted, nil)
+}
+
+func (c *Cloud) interfaceAttachment(port *neutron.Port) nova.InterfaceAttachment {
+	fixedIps := []nova.FixedIp{}
+	for _, fixedIp := range port.FixedIps {
+		fixedIps = append(fixedIps, nova.FixedIp{IpAddress: fixedIp.IpAddress, SubnetId: fixedIp.SubnetId})
+	}
+	return nova.InterfaceAttachment{
+		MacAddr: port.MACAddress, NetId: port.NetworkId, PortId: port.Id,
+		PortState: port.Status, FixedIps: fixedIps,
+	}
+}
+
+func (c *Cloud) serveInterfaces(w response, r request, s *server, paths []string) {
+	if len(paths) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			attachments := []nova.InterfaceAttachment{}
+			for _, portId := range s.ports {
+				if port, ok := c.ports[portId]; ok {
+					attachments = append(attachments, c.interfaceAttachment(port))
+				}
+			}
+			w.json(http.StatusOK, map[string]interface{}{"interfaceAttachments": attachments})
+		case http.MethodPost:
+			if !checkState(w, s, "attach_interface", "ACTIVE", "SHUTOFF", "PAUSED") {
+				return
+			}
+			body := struct {
+				InterfaceAttachment struct {
+					NetId  string `json:"net_id"`
+					PortId string `json:"port_id"`
+				} `json:"interfaceAttachment"`
+			}{}
+			if err := r.decode(&body); err != nil {
+				w.badRequest("invalid request body: %s", err)
+				return
+			}
+			var port *neutron.Port
+			if portId := body.InterfaceAttachment.PortId; portId != "" {
+				p, ok := c.ports[portId]
+				if !ok {
+					w.notFound("Port %s could not be found.", portId)
+					return
+				}
+				if p.DeviceId != "" {
+					w.conflict("Port %s is still in use.", portId)
+					return
+				}
+				port = p
+			} else {
+				p, err := c.createPort(map[string]interface{}{"network_id": body.InterfaceAttachment.NetId})
+				if err != nil {
+					w.notFound("Network %s could not be found.", body.InterfaceAttachment.NetId)
+					return
+				}
+				port, s.novaPorts[p.Id] = p, true
+			}
+			c.bindPort(port, s.Id, s.Host)
+			s.ports = append(s.ports, port.Id)
+			action := c.startAction(s, r, "attach_interface")
+			c.schedule(func() { finishAction(action, "Success") })
+			w.json(http.StatusOK, map[string]interface{}{"interfaceAttachment": c.interfaceAttachment(port)})
+		default:
+			w.notAllowed()
+		}
+		return
+	}
+	if r.Method != http.MethodDelete {
+		w.notAllowed()
+		return
+	}
+	portId := paths[0]
+	attached := false
+	for _, id := range s.ports {
+		attached = attached || id == portId
+	}
+	if !attached {
+		w.notFound("Port %s is not attached", portId)
+		return
+	}
+	action := c.startAction(s, r, "detach_interface")
+	c.schedule(func() {
+		s.removePort(portId)
+		if port, ok := c.ports[portId]; ok {
+			if s.novaPorts[portId] {
+				delete(c.ports, portId)
+			} else {
+				c.unbindPort(port)
+			}
+		}
+		finishAction(action, "Success")
+	})
+	w.json(http.StatusAccepted, nil)
+}
+
+func (c *Cloud) nextDevice(s *server) string {
+	used := map[string]bool{}
+	for _, attachment := range s.volumes {
+		used[attachment.Device] = true
+	}
+	for letter := 'b'; letter <= 'z'; letter++ {
+		if device := fmt.Sprintf("/dev/vd%c", letter); !used[device] {
+			return device
+		}
+	}
+	return ""
+}
+
+func (c *Cloud) serveVolumeAttachments(w response, r request, s *server, paths []string) {
+	if len(paths) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			w.json(http.StatusOK, map[string]interface{}{"volumeAttachments": s.volumes})
+		case http.MethodPost:
+			if !checkState(w, s, "attach_volume", "ACTIVE", "SHUTOFF", "PAUSED") {
+				return
+			}
+			body := struct {
+				VolumeAttachment struct {
+					VolumeId string `json:"volumeId"`
+					Device   string `json:"device"`
+				} `json:"volumeAttachment"`
+			}{}
+			if err := r.decode(&body); err != nil {
+				w.badRequest("invalid request body: %s", err)
+				return
+			}
+			vol, err := c.findVolume(body.VolumeAttachment.VolumeId)
+			if err != nil {
+				w.notFound("Volume %s could not be found.", body.VolumeAttachment.VolumeId)
+				return
+			}
+			if vol.Status != "available" {
+				w.badRequest("Invalid volume: volume %s status must be 'available'. Currently in '%s'", vol.Id, vol.Status)
+				return
+			}
+			attachment := nova.VolumeAttachment{
+				Id: vol.Id, VolumeId: vol.Id, ServerId: s.Id, Device: c.nextDevice(s),
+			}
+			s.volumes = append(s.volumes, attachment)
+			action := c.startAction(s, r, "attach_volume")
+			c.attachVolume(vol, s.Id, attachment.Device, s.Host)
+			c.schedule(func() { finishAction(action, "Success") })
+			w.json(http.StatusOK, map[string]interface{}{"volumeAttachment": attachment})
+		default:
+			w.notAllowed()
+		}
+		return
+	}
+	if r.Method != http.MethodDelete {
+		w.notAllowed()
+		return
+	}
+	volumeId := paths[0]
+	attachments := []nova.VolumeAttachment{}
+	found := false
+	for _, attachment := range s.volumes {
+		if attachment.VolumeId != volumeId {
+			attachments = append(attachments, attachment)
+			continue
+		}
+		found = true
+		if s.RootBdmType == "volume" && attachment.Device == s.RootDeviceName {
+			w.badRequest("Cannot detach a root device volume")
+			return
+		}
+	}
+	if !found {
+		w.notFound("volume_id not found: %s", volumeId)
+		return
+	}
+	s.volumes = attachments
+	action := c.startAction(s, r, "detach_volume")
+	if vol, ok := c.volumes[volumeId]; ok {
+		c.detachVolume(vol, func() { finishAction(action, "Success") })
+	} else {
+		finishAction(action, "Success")
+	}
+	w.json(http.StatusAccepted, nil)
+}
@@ -0,0 +1,188 @@
+package fake
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/BytemanD/skyman/openstack/model/placement"
+)
+
+// 计算节点默认的 traits
+var DEFAULT_TRAITS = []string{"COMPUTE_NET_ATTACH_INTERFACE", "COMPUTE_VOLUME_ATTACH", "HW_CPU_X86_AVX2"}
+
+// 不属于任何实例的分配, 用于模拟实例删除或迁移后残留的分配
+type allocation struct {
+	host      string
+	resources map[string]int
+}
+
+// 计算节点对应的 resource provider uuid
+func (c *Cloud) providerUuid(host string) string {
+	if _, ok := c.providers[host]; !ok {
+		c.providers[host] = NewId()
+	}
+	return c.providers[host]
+}
+func (c *Cloud) resourceProviders() []placement.ResourceProvider {
+	providers := []placement.ResourceProvider{}
+	for _, host := range c.Hosts {
+		uuid := c.providerUuid(host)
+		providers = append(providers, placement.ResourceProvider{
+			Uuid: uuid, Name: host, Generation: 1, RootProviderUuid: uuid,
+		})
+	}
+	return providers
+}
+
+// 添加一个分配, consumer 可以是不存在的实例或者迁移记录
+func (c *Cloud) AddAllocation(consumer string, host string, resources map[string]int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.allocations[consumer] = &allocation{host: host, resources: resources}
+}
+
+// consumer -> resource provider uuid -> 分配
+func (c *Cloud) placementAllocations() map[string]map[string]placement.Allocation {
+	allocations := map[string]map[string]placement.Allocation{}
+	for _, s := range c.servers {
+		if s.Host == "" {
+			continue
+		}
+		allocations[s.Id] = map[string]placement.Allocation{
+			c.providerUuid(s.Host): {Generation: 1, Resources: map[string]int{
+				"VCPU": s.Flavor.Vcpus, "MEMORY_MB": s.Flavor.Ram, "DISK_GB": s.Flavor.Disk,
+			}},
+		}
+	}
+	for consumer, a := range c.allocations {
+		if _, ok := allocations[consumer]; !ok {
+			allocations[consumer] = map[string]placement.Allocation{}
+		}
+		allocations[consumer][c.providerUuid(a.host)] = placement.Allocation{Generation: 1, Resources: a.resources}
+	}
+	return allocations
+}
+
+func placementFault(w response, status int, format string, args ...interface{}) {
+	w.json(status, map[string]interface{}{
+		"errors": []map[string]interface{}{{
+			"status": status, "title": http.StatusText(status),
+			"detail": fmt.Sprintf(format, args...), "code": "placement.undefined_code",
+		}},
+	})
+}
+
+func (c *Cloud) servePlacement(w response, r request) {
+	paths := r.Paths
+	if len(paths) == 0 {
+		w.json(http.StatusOK, map[string]interface{}{
+			"versions": []placement.Version{
+				{Id: "v1.0", MinVersion: "1.0", MaxVersion: "1.39", Status: "CURRENT"},
+			},
+		})
+		return
+	}
+	if r.Method != http.MethodGet && !(paths[0] == "allocations" && r.Method == http.MethodDelete) {
+		placementFault(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	switch paths[0] {
+	case "resource_providers":
+		c.serveResourceProviders(w, r, paths[1:])
+	case "allocations":
+		if len(paths) != 2 {
+			placementFault(w, http.StatusNotFound, "resource not found")
+			return
+		}
+		allocations := c.placementAllocations()[paths[1]]
+		if r.Method == http.MethodDelete {
+			if _, ok := c.allocations[paths[1]]; !ok {
+				placementFault(w, http.StatusNotFound, "No allocations for consumer '%s'", paths[1])
+				return
+			}
+			delete(c.allocations, paths[1])
+			w.json(http.StatusNoContent, nil)
+			return
+		}
+		body := placement.ConsumerAllocations{Allocations: map[string]placement.Allocation{}}
+		if allocations != nil {
+			body.Allocations, body.ProjectId = allocations, c.ProjectId
+		}
+		w.json(http.StatusOK, body)
+	case "traits":
+		w.json(http.StatusOK, map[string]interface{}{"traits": DEFAULT_TRAITS})
+	default:
+		placementFault(w, http.StatusNotFound, "resource %s not found", paths[0])
+	}
+}
+
+func (c *Cloud) serveResourceProviders(w response, r request, paths []string) {
+	providers := c.resourceProviders()
+	if len(paths) == 0 {
+		name := r.URL.Query().Get("name")
+		filtered := []placement.ResourceProvider{}
+		for _, provider := range providers {
+			if name == "" || provider.Name == name {
+				filtered = append(filtered, provider)
+			}
+		}
+		w.json(http.StatusOK, map[string]interface{}{"resource_providers": filtered})
+		return
+	}
+	var provider *placement.ResourceProvider
+	for i := range providers {
+		if providers[i].Uuid == paths[0] {
+			provider = &providers[i]
+		}
+	}
+	if provider == nil {
+		placementFault(w, http.StatusNotFound, "No resource provider with uuid %s found", paths[0])
+		return
+	}
+	if len(paths) == 1 {
+		w.json(http.StatusOK, provider)
+		return
+	}
+	switch paths[1] {
+	case "inventories":
+		w.json(http.StatusOK, map[string]interface{}{
+			"resource_provider_generation": provider.Generation,
+			"inventories": map[string]placement.Inventory{
+				"VCPU":      {Total: 32, MinUnit: 1, MaxUnit: 32, StepSize: 1, AllocationRatio: 4},
+				"MEMORY_MB": {Total: 65536, Reserved: 512, MinUnit: 1, MaxUnit: 65536, StepSize: 1, AllocationRatio: 1.5},
+				"DISK_GB":   {Total: 1000, MinUnit: 1, MaxUnit: 1000, StepSize: 1, AllocationRatio: 1},
+			},
+		})
+	case "usages":
+		usages := map[string]int{"VCPU": 0, "MEMORY_MB": 0, "DISK_GB": 0}
+		for _, allocations := range c.placementAllocations() {
+			for class, amount := range allocations[provider.Uuid].Resources {
+				usages[class] += amount
+			}
+		}
+		w.json(http.StatusOK, map[string]interface{}{
+			"resource_provider_generation": provider.Generation, "usages": usages,
+		})
+	case "traits":
+		w.json(http.StatusOK, map[string]interface{}{
+			"resource_provider_generation": provider.Generation, "traits": DEFAULT_TRAITS,
+		})
+	case "aggregates":
+		w.json(http.StatusOK, map[string]interface{}{
+			"resource_provider_generation": provider.Generation, "aggregates": []string{},
+		})
+	case "allocations":
+		body := placement.ProviderAllocations{
+			ResourceProviderGeneration: provider.Generation,
+			Allocations:                map[string]placement.Allocation{},
+		}
+		for consumer, allocations := range c.placementAllocations() {
+			if a, ok := allocations[provider.Uuid]; ok {
+				body.Allocations[consumer] = placement.Allocation{Resources: a.Resources}
+			}
+		}
+		w.json(http.StatusOK, body)
+	default:
+		placementFault(w, http.StatusNotFound, "resource %s not found", paths[1])
+	}
+}
@@ -0,0 +1,243 @@
+package openstack
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/BytemanD/skyman/openstack/model/heat"
+)
+
+// 以这些后缀结尾的资源类型视为嵌套模板
+var NESTED_TEMPLATE_SUFFIXES = []string{".yaml", ".yml", ".template"}
+
+// 读取模板和环境文件中引用的本地文件
+//
+// 文件以 file:// 开头的绝对路径为 key, 模板和环境文件中引用的本地路径也替换为这个 key,
+// 避免不同目录下同名的文件互相覆盖。相对路径相对于引用它的文件
+type templateLoader struct {
+	files map[string]string
+}
+
+func isLocalRef(ref string) bool {
+	return !strings.HasPrefix(ref, "http://") && !strings.HasPrefix(ref, "https://")
+}
+func isNestedTemplate(resourceType string) bool {
+	for _, suffix := range NESTED_TEMPLATE_SUFFIXES {
+		if strings.HasSuffix(resourceType, suffix) {
+			return true
+		}
+	}
+	return false
+}
+func fileUrl(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// 返回 mapping 节点中 key 对应的值, 不存在时返回 nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func marshalYaml(node *yaml.Node) (string, error) {
+	buffer := bytes.Buffer{}
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+// 读取 baseFile 中引用的文件 ref, 返回文件的绝对路径和 key, 已经读取过时 loaded 为 false
+func (l *templateLoader) readRef(baseFile string, ref string) (path string, key string, loaded bool, err error) {
+	path = strings.TrimPrefix(ref, "file://")
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(baseFile), path)
+	}
+	if path, err = filepath.Abs(path); err != nil {
+		return "", "", false, err
+	}
+	key = fileUrl(path)
+	if _, ok := l.files[key]; ok {
+		return path, key, false, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", false, fmt.Errorf("read %s referenced by %s failed: %w", ref, baseFile, err)
+	}
+	l.files[key] = string(content)
+	return path, key, true, nil
+}
+
+// 遍历模板, 读取 get_file 引用的文件和嵌套模板, 并把引用替换为文件的 key
+func (l *templateLoader) walk(templateFile string, node *yaml.Node) (bool, error) {
+	changed := false
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			if value.Kind == yaml.ScalarNode && isLocalRef(value.Value) {
+				var (
+					ref string
+					err error
+				)
+				switch {
+				case key == "get_file":
+					_, ref, _, err = l.readRef(templateFile, value.Value)
+				case key == "type" && isNestedTemplate(value.Value):
+					ref, err = l.loadNestedTemplate(templateFile, value.Value)
+				}
+				if err != nil {
+					return false, err
+				}
+				if ref != "" {
+					value.Value, changed = ref, true
+					continue
+				}
+			}
+			valueChanged, err := l.walk(templateFile, value)
+			if err != nil {
+				return false, err
+			}
+			changed = changed || valueChanged
+		}
+	case yaml.SequenceNode:
+		for _, value := range node.Content {
+			valueChanged, err := l.walk(templateFile, value)
+			if err != nil {
+				return false, err
+			}
+			changed = changed || valueChanged
+		}
+	}
+	return changed, nil
+}
+
+// 读取模板引用的文件, 返回替换了引用之后的模板, 没有引用本地文件时返回原始内容
+func (l *templateLoader) loadTemplate(templateFile string, content []byte) (string, error) {
+	template := yaml.Node{}
+	if err := yaml.Unmarshal(content, &template); err != nil {
+		return "", fmt.Errorf("parse template %s failed: %w", templateFile, err)
+	}
+	resources := mappingValue(&template, "resources")
+	if resources == nil {
+		return string(content), nil
+	}
+	changed, err := l.walk(templateFile, resources)
+	if err != nil || !changed {
+		return string(content), err
+	}
+	return marshalYaml(&template)
+}
+
+// 读取嵌套模板, 返回模板的 key
+func (l *templateLoader) loadNestedTemplate(baseFile string, ref string) (string, error) {
+	path, key, loaded, err := l.readRef(baseFile, ref)
+	if err != nil || !loaded {
+		return key, err
+	}
+	template, err := l.loadTemplate(path, []byte(l.files[key]))
+	if err != nil {
+		return "", err
+	}
+	l.files[key] = template
+	return key, nil
+}
+
+// 读取环境文件中 resource_registry 引用的模板, 返回替换了引用之后的环境文件
+func (l *templateLoader) loadEnvironment(envFile string, content []byte) (string, error) {
+	env := yaml.Node{}
+	if err := yaml.Unmarshal(content, &env); err != nil {
+		return "", fmt.Errorf("parse environment %s failed: %w", envFile, err)
+	}
+	registry := mappingValue(&env, "resource_registry")
+	if registry == nil || registry.Kind != yaml.MappingNode {
+		return string(content), nil
+	}
+	mappings := []*yaml.Node{}
+	for i := 0; i+1 < len(registry.Content); i += 2 {
+		key, value := registry.Content[i].Value, registry.Content[i+1]
+		if key != "resources" || value.Kind != yaml.MappingNode {
+			mappings = append(mappings, value)
+			continue
+		}
+		// 为指定资源设置类型: resources: {<name>: {<type>: <template>}}
+		for j := 1; j < len(value.Content); j += 2 {
+			if resource := value.Content[j]; resource.Kind == yaml.MappingNode {
+				for k := 1; k < len(resource.Content); k += 2 {
+					mappings = append(mappings, resource.Content[k])
+				}
+			}
+		}
+	}
+	changed := false
+	for _, mapping := range mappings {
+		if mapping.Kind != yaml.ScalarNode || !isLocalRef(mapping.Value) || !isNestedTemplate(mapping.Value) {
+			continue
+		}
+		key, err := l.loadNestedTemplate(envFile, mapping.Value)
+		if err != nil {
+			return "", err
+		}
+		mapping.Value, changed = key, true
+	}
+	if !changed {
+		return string(content), nil
+	}
+	return marshalYaml(&env)
+}
+
+// 根据模板文件和环境文件生成创建/更新 stack 的参数
+func NewStackOpt(name string, templateFile string, envFiles []string) (*heat.StackOpt, error) {
+	loader := templateLoader{files: map[string]string{}}
+	opt := heat.StackOpt{StackName: name}
+	if templateFile != "" {
+		content, err := os.ReadFile(templateFile)
+		if err != nil {
+			return nil, fmt.Errorf("read template failed: %w", err)
+		}
+		if opt.Template, err = loader.loadTemplate(templateFile, content); err != nil {
+			return nil, err
+		}
+	}
+	for _, envFile := range envFiles {
+		content, err := os.ReadFile(envFile)
+		if err != nil {
+			return nil, fmt.Errorf("read environment failed: %w", err)
+		}
+		env, err := loader.loadEnvironment(envFile, content)
+		if err != nil {
+			return nil, err
+		}
+		path, err := filepath.Abs(envFile)
+		if err != nil {
+			return nil, err
+		}
+		loader.files[fileUrl(path)] = env
+		opt.EnvironmentFiles = append(opt.EnvironmentFiles, fileUrl(path))
+	}
+	if len(loader.files) > 0 {
+		opt.Files = loader.files
+	}
+	return &opt, nil
+}
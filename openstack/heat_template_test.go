@@ -0,0 +1,90 @@
+package openstack
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewStackOpt(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.yaml": `heat_template_version: 2018-08-31
+resources:
+  config:
+    type: OS::Heat::SoftwareConfig
+    properties:
+      config: {get_file: scripts/init.sh}
+  data:
+    type: OS::Heat::Value
+    properties:
+      value: {get_file: user_data.txt}
+  group:
+    type: nested/server.yaml
+  remote:
+    type: https://example.com/remote.yaml
+`,
+		"scripts/init.sh": "#!/bin/sh\n",
+		"nested/server.yaml": `heat_template_version: 2018-08-31
+resources:
+  server:
+    type: OS::Nova::Server
+    properties:
+      user_data: {get_file: user_data.txt}
+`,
+		"nested/user_data.txt": "hello\n",
+		"user_data.txt":        "world\n",
+		"env.yaml": `resource_registry:
+  My::Port: port.yaml
+  resources:
+    group:
+      OS::Nova::Server: nested/server.yaml
+`,
+		"port.yaml": "heat_template_version: 2018-08-31\nresources: {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	envFile := filepath.Join(dir, "env.yaml")
+	opt, err := NewStackOpt("stack1", filepath.Join(dir, "main.yaml"), []string{envFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := func(name string) string { return "file://" + filepath.ToSlash(filepath.Join(dir, name)) }
+	// 不同目录下同名的文件使用不同的 key, 模板中的引用替换为 key
+	for _, ref := range []string{key("scripts/init.sh"), key("nested/server.yaml"), "https://example.com/remote.yaml"} {
+		if !strings.Contains(opt.Template, ref) {
+			t.Errorf("expect %s in template, got %s", ref, opt.Template)
+		}
+	}
+	if !strings.Contains(opt.Files[key("nested/server.yaml")], key("nested/user_data.txt")) {
+		t.Errorf("expect reference of user_data.txt rewritten, got %s", opt.Files[key("nested/server.yaml")])
+	}
+	if !strings.Contains(opt.Files[key("env.yaml")], key("port.yaml")) {
+		t.Errorf("expect reference of port.yaml rewritten, got %s", opt.Files[key("env.yaml")])
+	}
+	expected := map[string]string{
+		key("scripts/init.sh"):      files["scripts/init.sh"],
+		key("nested/user_data.txt"): files["nested/user_data.txt"],
+		key("user_data.txt"):        files["user_data.txt"],
+		key("port.yaml"):            files["port.yaml"],
+	}
+	if len(opt.Files) != len(expected)+2 {
+		t.Errorf("expect %d files, got %v", len(expected)+2, opt.Files)
+	}
+	for name, content := range expected {
+		if opt.Files[name] != content {
+			t.Errorf("file %s: expect %q, got %q", name, content, opt.Files[name])
+		}
+	}
+	if len(opt.EnvironmentFiles) != 1 || opt.EnvironmentFiles[0] != key("env.yaml") {
+		t.Errorf("unexpected environment files %v", opt.EnvironmentFiles)
+	}
+}
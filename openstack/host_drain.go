@@ -0,0 +1,230 @@
+package openstack
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BytemanD/easygo/pkg/syncutils"
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/openstack/model/nova"
+)
+
+const BINARY_NOVA_COMPUTE = "nova-compute"
+
+// 包含这些 extra specs 的规格不支持热迁移, 只能冷迁移
+var COLD_MIGRATE_ONLY_SPECS = []string{"pci_passthrough:alias", "hw:pmem"}
+
+// 各迁移类型成功结束时的状态
+var migrationDoneStatus = map[string][]string{
+	nova.MIGRATION_TYPE_LIVE:       {"completed"},
+	nova.MIGRATION_TYPE_COLD:       {"finished", "confirmed"},
+	nova.MIGRATION_TYPE_EVACUATION: {"done"},
+}
+
+type HostDrainOpt struct {
+	// 禁用计算服务的原因
+	Reason   string
+	Parallel int
+	// 每个虚拟机迁移的超时时间, 0 表示不限制
+	Timeout  time.Duration
+	Interval int
+}
+
+// 每个虚拟机的迁移结果
+type HostDrainReport struct {
+	ServerId   string
+	ServerName string
+	Status     string
+	Action     string
+	Result     string
+	SourceHost string
+	DestHost   string
+	Spend      time.Duration
+	Error      string
+}
+
+// 跳过的虚拟机也算失败, 因为它仍然留在节点上
+func (r HostDrainReport) Failed() bool {
+	return r.Error != ""
+}
+
+// 获取虚拟机规格的 extra specs, nova 2.47 之前虚拟机详情中只有规格 id
+func (o *Openstack) serverExtraSpecs(server nova.Server) (nova.ExtraSpecs, error) {
+	if server.Flavor.ExtraSpecs != nil {
+		return server.Flavor.ExtraSpecs, nil
+	}
+	flavorId := server.Flavor.Id
+	if flavorId == "" {
+		flavor, err := o.NovaV2().Flavor().Find(server.Flavor.OriginalName, false)
+		if err != nil {
+			return nil, err
+		}
+		flavorId = flavor.Id
+	}
+	return o.NovaV2().Flavor().ListExtraSpecs(flavorId)
+}
+
+// 根据虚拟机的状态和规格选择迁移方式, 返回空字符串表示不迁移
+func (o *Openstack) drainAction(server nova.Server) (string, error) {
+	extraSpecs, err := o.serverExtraSpecs(server)
+	if err != nil {
+		return "", fmt.Errorf("get extra specs of flavor failed: %w", err)
+	}
+	coldOnly := ""
+	for _, key := range COLD_MIGRATE_ONLY_SPECS {
+		if extraSpecs.Get(key) != "" {
+			coldOnly = key
+			break
+		}
+	}
+	switch strings.ToUpper(server.Status) {
+	case "ACTIVE":
+		if coldOnly != "" {
+			return nova.MIGRATION_TYPE_COLD, nil
+		}
+		return nova.MIGRATION_TYPE_LIVE, nil
+	case "PAUSED":
+		if coldOnly != "" {
+			return "", fmt.Errorf("flavor with %s can't be live migrated", coldOnly)
+		}
+		return nova.MIGRATION_TYPE_LIVE, nil
+	case "SHUTOFF":
+		return nova.MIGRATION_TYPE_COLD, nil
+	default:
+		return "", fmt.Errorf("server is %s", server.Status)
+	}
+}
+
+func (o *Openstack) hostServers(host string) ([]nova.Server, error) {
+	return o.NovaV2().Server().Detail(url.Values{"host": []string{host}, "all_tenants": []string{"1"}})
+}
+
+// 迁移虚拟机并等待迁移结束, 冷迁移完成后自动确认
+func (o *Openstack) moveServer(ctx context.Context, server nova.Server, action string, opt HostDrainOpt) (*nova.Migration, error) {
+	serverApi, migrationApi := o.NovaV2().Server(), o.NovaV2().Migration()
+	after := 0
+	if latest, err := migrationApi.Latest(server.Id, action); err != nil {
+		return nil, err
+	} else if latest != nil {
+		after = latest.Id
+	}
+	var err error
+	switch action {
+	case nova.MIGRATION_TYPE_LIVE:
+		var blockMigrate interface{} = false
+		if serverApi.MicroVersionLargeEqual("2.25") {
+			blockMigrate = "auto"
+		}
+		err = serverApi.LiveMigrate(server.Id, blockMigrate, "")
+	case nova.MIGRATION_TYPE_COLD:
+		err = serverApi.Migrate(server.Id, "")
+	case nova.MIGRATION_TYPE_EVACUATION:
+		err = serverApi.Evacuate(server.Id, "", "", false)
+	}
+	if err != nil {
+		return nil, err
+	}
+	migration, err := migrationApi.Wait(ctx, server.Id, action, after, migrationDoneStatus[action], opt.Timeout, opt.Interval)
+	if err != nil || migration.Status != "finished" {
+		return migration, err
+	}
+	console.Info("[%s] confirm resize", server.Id)
+	if err := serverApi.ResizeConfirm(server.Id); err != nil {
+		return migration, err
+	}
+	return migrationApi.Wait(ctx, server.Id, action, migration.Id-1, []string{"confirmed"}, opt.Timeout, opt.Interval)
+}
+
+// 并发迁移节点上的虚拟机, 返回每个虚拟机的迁移结果
+func (o *Openstack) moveServers(ctx context.Context, servers []nova.Server, opt HostDrainOpt, chooseAction func(nova.Server) (string, error)) []HostDrainReport {
+	reports := make([]HostDrainReport, len(servers))
+	mu := sync.Mutex{}
+	indexes := []int{}
+	for i := range servers {
+		indexes = append(indexes, i)
+	}
+	syncutils.StartTasks(
+		syncutils.TaskOption{TaskName: "move servers", MaxWorker: opt.Parallel},
+		indexes,
+		func(i int) error {
+			server := servers[i]
+			report := HostDrainReport{
+				ServerId: server.Id, ServerName: server.Name, Status: server.Status, SourceHost: server.Host,
+			}
+			startTime := time.Now()
+			action, err := chooseAction(server)
+			if err != nil {
+				report.Result, report.Error = "skipped", err.Error()
+				console.Warn("[%s] skip: %s", server.Id, err)
+			} else {
+				report.Action = action
+				console.Info("[%s] start %s", server.Id, action)
+				migration, err := o.moveServer(ctx, server, action, opt)
+				if migration != nil {
+					report.Result, report.DestHost = migration.Status, migration.DestCompute
+				}
+				if err != nil {
+					report.Error = err.Error()
+					console.Error("[%s] %s failed: %s", server.Id, action, err)
+				} else {
+					console.Info("[%s] %s to %s %s", server.Id, action, report.DestHost, report.Result)
+				}
+			}
+			report.Spend = time.Since(startTime).Round(time.Second)
+			mu.Lock()
+			reports[i] = report
+			mu.Unlock()
+			return nil
+		},
+	)
+	return reports
+}
+
+// 禁用节点的计算服务, 并把节点上所有的虚拟机迁移到其他节点
+func (o *Openstack) DrainHost(ctx context.Context, host string, opt HostDrainOpt) ([]HostDrainReport, error) {
+	console.Info("disable compute service of %s", host)
+	if _, err := o.NovaV2().Service().Disable(host, BINARY_NOVA_COMPUTE, opt.Reason); err != nil {
+		return nil, fmt.Errorf("disable compute service failed: %w", err)
+	}
+	servers, err := o.hostServers(host)
+	if err != nil {
+		return nil, fmt.Errorf("list servers of %s failed: %w", host, err)
+	}
+	console.Info("found %d server(s) on %s", len(servers), host)
+	return o.moveServers(ctx, servers, opt, o.drainAction), nil
+}
+
+// 在其他节点上重建故障节点上的虚拟机, 节点的计算服务必须是 down 状态
+func (o *Openstack) EvacuateHost(ctx context.Context, host string, opt HostDrainOpt) ([]HostDrainReport, error) {
+	service, err := o.NovaV2().Service().GetByHostBinary(host, BINARY_NOVA_COMPUTE)
+	if err != nil {
+		return nil, err
+	}
+	if service.State != "down" && !service.ForcedDown {
+		return nil, fmt.Errorf("compute service of %s is %s, evacuate is only allowed when it is down",
+			host, service.State)
+	}
+	if service.Status != "disabled" {
+		console.Info("disable compute service of %s", host)
+		if _, err := o.NovaV2().Service().Disable(host, BINARY_NOVA_COMPUTE, opt.Reason); err != nil {
+			return nil, fmt.Errorf("disable compute service failed: %w", err)
+		}
+	}
+	servers, err := o.hostServers(host)
+	if err != nil {
+		return nil, fmt.Errorf("list servers of %s failed: %w", host, err)
+	}
+	console.Info("found %d server(s) on %s", len(servers), host)
+	return o.moveServers(ctx, servers, opt, func(server nova.Server) (string, error) {
+		switch strings.ToUpper(server.Status) {
+		case "ACTIVE", "SHUTOFF", "ERROR":
+			return nova.MIGRATION_TYPE_EVACUATION, nil
+		default:
+			return "", fmt.Errorf("server is %s", server.Status)
+		}
+	}), nil
+}
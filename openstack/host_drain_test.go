@@ -0,0 +1,168 @@
+package openstack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/openstack/fake"
+	"github.com/BytemanD/skyman/openstack/model"
+	"github.com/BytemanD/skyman/openstack/model/nova"
+)
+
+func TestDrainHost(t *testing.T) {
+	cloud := fake.NewCloud()
+	cloud.TaskDuration = 0
+	cloud.Hosts = []string{"fake-host-1", "fake-host-2", "fake-host-3"}
+	defer cloud.Close()
+	cloud.AddFlavor(nova.Flavor{Id: "3", Name: "fake.pci", Vcpus: 1, Ram: 1024, Disk: 10,
+		ExtraSpecs: nova.ExtraSpecs{"pci_passthrough:alias": "gpu:1"}})
+
+	common.CONF.Identity.Api.Version = "3"
+	client := NewClient(cloud.AuthUrl(), cloud.User(), cloud.Project(), cloud.Region)
+	client.AuthPlugin.SetLocalTokenExpire(3600)
+
+	servers := map[string]*nova.Server{}
+	for _, opt := range []nova.ServerOpt{
+		{Name: "vm-live", Flavor: "1", Image: "cirros"},
+		{Name: "vm-pci", Flavor: "3", Image: "cirros"},
+		{Name: "vm-stopped", Flavor: "1", Image: "cirros"},
+		{Name: "vm-paused", Flavor: "1", Image: "cirros"},
+	} {
+		server, err := client.NovaV2().Server().Create(opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		servers[opt.Name] = server
+	}
+	cloud.WaitTasks(0)
+	if err := client.NovaV2().Server().Stop(servers["vm-stopped"].Id); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.NovaV2().Server().Pause(servers["vm-paused"].Id); err != nil {
+		t.Fatal(err)
+	}
+	cloud.WaitTasks(0)
+
+	// 把所有虚拟机迁移到同一个节点
+	host := cloud.Hosts[0]
+	for _, server := range servers {
+		server, err := client.NovaV2().Server().Show(server.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if server.Host != host {
+			if err := client.NovaV2().Server().LiveMigrate(server.Id, "auto", host); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	cloud.WaitTasks(0)
+
+	reports, err := client.DrainHost(context.Background(), host, HostDrainOpt{Reason: "maintenance", Parallel: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != len(servers) {
+		t.Fatalf("expect %d reports, but got %v", len(servers), reports)
+	}
+	expected := map[string]struct{ action, result string }{
+		"vm-live":    {nova.MIGRATION_TYPE_LIVE, "completed"},
+		"vm-pci":     {nova.MIGRATION_TYPE_COLD, "confirmed"},
+		"vm-stopped": {nova.MIGRATION_TYPE_COLD, "confirmed"},
+		"vm-paused":  {nova.MIGRATION_TYPE_LIVE, "completed"},
+	}
+	for _, report := range reports {
+		want := expected[report.ServerName]
+		if report.Action != want.action || report.Result != want.result || report.Error != "" {
+			t.Errorf("unexpected report of %s: %v", report.ServerName, report)
+		}
+		if report.DestHost == "" || report.DestHost == host {
+			t.Errorf("server %s is not moved out of %s: %v", report.ServerName, host, report)
+		}
+	}
+	service, err := client.NovaV2().Service().GetByHostBinary(host, BINARY_NOVA_COMPUTE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service.Status != "disabled" || service.DisabledReason != "maintenance" {
+		t.Errorf("expect service disabled with reason, but got %v", service)
+	}
+	left, err := client.NovaV2().Server().Detail(map[string][]string{"host": {host}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) != 0 {
+		t.Errorf("expect no server left on %s, but got %d", host, len(left))
+	}
+}
+
+func TestEvacuateHost(t *testing.T) {
+	cloud := fake.NewCloud()
+	cloud.TaskDuration = 0
+	defer cloud.Close()
+
+	common.CONF.Identity.Api.Version = "3"
+	client := NewClient(cloud.AuthUrl(), cloud.User(), cloud.Project(), cloud.Region)
+	client.AuthPlugin.SetLocalTokenExpire(3600)
+
+	server, err := client.NovaV2().Server().Create(nova.ServerOpt{Name: "vm1", Flavor: "1", Image: "cirros"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cloud.WaitTasks(0)
+	if server, err = client.NovaV2().Server().Show(server.Id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.EvacuateHost(context.Background(), server.Host, HostDrainOpt{}); err == nil {
+		t.Fatalf("expect error when compute service is up")
+	}
+
+	cloud.SetHostDown(server.Host)
+	reports, err := client.EvacuateHost(context.Background(), server.Host, HostDrainOpt{Reason: "host down"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 {
+		t.Fatalf("expect 1 report, but got %v", reports)
+	}
+	report := reports[0]
+	if report.Action != nova.MIGRATION_TYPE_EVACUATION || report.Result != "done" || report.Error != "" {
+		t.Errorf("unexpected report: %v", report)
+	}
+	if report.DestHost == "" || report.DestHost == server.Host {
+		t.Errorf("expect server evacuated to other host, but got %v", report)
+	}
+}
+
+func TestDrainAction(t *testing.T) {
+	cloud := fake.NewCloud()
+	defer cloud.Close()
+	cloud.AddFlavor(nova.Flavor{Id: "3", Name: "fake.pci", Vcpus: 1, Ram: 1024, Disk: 10,
+		ExtraSpecs: nova.ExtraSpecs{"pci_passthrough:alias": "gpu:1"}})
+
+	common.CONF.Identity.Api.Version = "3"
+	client := NewClient(cloud.AuthUrl(), cloud.User(), cloud.Project(), cloud.Region)
+	client.AuthPlugin.SetLocalTokenExpire(3600)
+
+	// nova 2.47 之前虚拟机详情中的规格只有 id
+	for _, c := range []struct {
+		server nova.Server
+		action string
+	}{
+		{nova.Server{Resource: model.Resource{Status: "ACTIVE"}, Flavor: nova.Flavor{Id: "3"}}, nova.MIGRATION_TYPE_COLD},
+		{nova.Server{Resource: model.Resource{Status: "ACTIVE"}, Flavor: nova.Flavor{Id: "1"}}, nova.MIGRATION_TYPE_LIVE},
+		{nova.Server{Resource: model.Resource{Status: "ACTIVE"}, Flavor: nova.Flavor{OriginalName: "fake.pci"}}, nova.MIGRATION_TYPE_COLD},
+	} {
+		action, err := client.drainAction(c.server)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if action != c.action {
+			t.Errorf("expect %s for flavor %v, but got %s", c.action, c.server.Flavor, action)
+		}
+	}
+	if (HostDrainReport{Result: "skipped", Error: "server is ERROR"}).Failed() != true {
+		t.Errorf("expect skipped server is failed")
+	}
+}
@@ -8,3 +8,9 @@ import (
 func NewPasswordAuth(authUrl string, user model.User, project model.Project, regionName string) *auth_plugin.PasswordAuthPlugin {
 	return auth_plugin.NewPasswordAuthPlugin(authUrl, user, project, regionName)
 }
+func NewApplicationCredentialAuth(authUrl string, credential model.ApplicationCredential, regionName string) *auth_plugin.ApplicationCredentialAuthPlugin {
+	return auth_plugin.NewApplicationCredentialAuthPlugin(authUrl, credential, regionName)
+}
+func NewTokenAuth(authUrl string, token string, project model.Project, regionName string) *auth_plugin.TokenAuthPlugin {
+	return auth_plugin.NewTokenAuthPlugin(authUrl, token, project, regionName)
+}
@@ -0,0 +1,62 @@
+package auth_plugin
+
+import (
+	"fmt"
+
+	"github.com/BytemanD/skyman/openstack/model"
+)
+
+// 使用 Keystone 应用凭证 (application credential) 认证
+//
+// 应用凭证已经绑定了项目, 因此请求体中不能指定 scope
+type ApplicationCredentialAuthPlugin struct {
+	baseAuthPlugin
+	CredentialId   string
+	CredentialName string
+	Secret         string
+	// 使用名称认证时需要指定用户
+	Username       string
+	UserDomainName string
+}
+
+func (plugin *ApplicationCredentialAuthPlugin) applicationCredentialAuthReqBody() AuthBody {
+	credential := model.ApplicationCredential{
+		Id:     plugin.CredentialId,
+		Secret: plugin.Secret,
+	}
+	if credential.Id == "" {
+		credential.Name = plugin.CredentialName
+		credential.User = &model.User{
+			Name:   plugin.Username,
+			Domain: model.Domain{Name: plugin.UserDomainName},
+		}
+	}
+	return AuthBody{
+		Auth: model.Auth{
+			Identity: model.Identity{
+				Methods:               []string{"application_credential"},
+				ApplicationCredential: &credential,
+			},
+		},
+	}
+}
+
+func NewApplicationCredentialAuthPlugin(authUrl string, credential model.ApplicationCredential, regionName string) *ApplicationCredentialAuthPlugin {
+	plugin := &ApplicationCredentialAuthPlugin{
+		baseAuthPlugin: newBaseAuthPlugin(authUrl, regionName),
+		CredentialId:   credential.Id,
+		CredentialName: credential.Name,
+		Secret:         credential.Secret,
+	}
+	plugin.cacheUser = "application_credential:" + credential.Id
+	if credential.User != nil {
+		plugin.Username = credential.User.Name
+		plugin.UserDomainName = credential.User.Domain.Name
+	}
+	if credential.Id == "" {
+		plugin.cacheUser = fmt.Sprintf("application_credential:%s/%s/%s",
+			plugin.UserDomainName, plugin.Username, credential.Name)
+	}
+	plugin.newAuthReqBody = plugin.applicationCredentialAuthReqBody
+	return plugin
+}
@@ -0,0 +1,311 @@
+package auth_plugin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/openstack/model"
+	"github.com/BytemanD/skyman/openstack/session"
+	"github.com/BytemanD/skyman/openstack/token_cache"
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	TYPE_COMPUTE   string = "compute"
+	TYPE_VOLUME    string = "volume"
+	TYPE_VOLUME_V2 string = "volumev2"
+	TYPE_VOLUME_V3 string = "volumev3"
+	TYPE_IDENTITY  string = "identity"
+	TYPE_IMAGE     string = "image"
+	TYPE_NETWORK   string = "network"
+
+	INTERFACE_PUBLIC   string = "public"
+	INTERFACE_ADMIN    string = "admin"
+	INTERFACE_INTERVAL string = "internal"
+
+	URL_AUTH_TOKEN  string = "/auth/tokens"
+	X_AUTH_TOKEN    string = "X-Auth-Token"
+	X_SUBJECT_TOKEN string = "X-Subject-Token"
+)
+
+type AuthBody struct {
+	Auth model.Auth `json:"auth"`
+}
+
+// 各认证方式公共的 token 管理逻辑
+//
+// 认证方式只需要提供 newAuthReqBody, 用于生成 POST /auth/tokens 的请求体
+type baseAuthPlugin struct {
+	AuthUrl    string
+	RegionName string
+
+	LocalTokenExpireSecond int
+	token                  *model.Token
+	tokenId                string
+	expiredAt              time.Time
+
+	tokenLock *sync.Mutex
+
+	mu      *sync.Mutex
+	session *resty.Client
+	ctx     context.Context
+
+	newAuthReqBody func() AuthBody
+
+	tokenCache   bool
+	cacheUser    string
+	cacheProject string
+	// 从本地缓存加载的 token
+	cachedTokenId string
+}
+
+func newBaseAuthPlugin(authUrl string, regionName string) baseAuthPlugin {
+	return baseAuthPlugin{
+		session:    session.DefaultRestyClient(),
+		AuthUrl:    authUrl,
+		RegionName: regionName,
+		tokenLock:  &sync.Mutex{},
+		mu:         &sync.Mutex{},
+	}
+}
+
+func (plugin *baseAuthPlugin) SetContext(ctx context.Context) {
+	plugin.ctx = ctx
+}
+func (plugin baseAuthPlugin) context() context.Context {
+	if plugin.ctx == nil {
+		return context.Background()
+	}
+	return plugin.ctx
+}
+
+func (plugin baseAuthPlugin) Region() string {
+	return plugin.RegionName
+}
+
+func (plugin *baseAuthPlugin) SetRegion(region string) {
+	plugin.RegionName = region
+}
+
+func (plugin *baseAuthPlugin) SetLocalTokenExpire(expireSeconds int) {
+	plugin.LocalTokenExpireSecond = expireSeconds
+}
+
+func (plugin *baseAuthPlugin) IsTokenExpired() bool {
+	if plugin.tokenId == "" {
+		return true
+	}
+	if plugin.expiredAt.Before(time.Now()) {
+		console.Warn("token exipred, expired at: %s , now: %s", plugin.expiredAt, time.Now())
+		return true
+	}
+	return false
+}
+
+func (plugin *baseAuthPlugin) EnableTokenCache(enable bool) {
+	plugin.tokenCache = enable
+}
+func (plugin baseAuthPlugin) cacheKey() token_cache.Key {
+	return token_cache.Key{
+		AuthUrl: plugin.AuthUrl,
+		User:    plugin.cacheUser,
+		Project: plugin.cacheProject,
+		Region:  plugin.RegionName,
+	}
+}
+func (plugin *baseAuthPlugin) loadTokenCache() bool {
+	if !plugin.tokenCache {
+		return false
+	}
+	entry := token_cache.Load(plugin.cacheKey())
+	if entry == nil {
+		return false
+	}
+	plugin.tokenId = entry.TokenId
+	plugin.token = &entry.Token
+	// Load 只返回距离过期还有 SAFETY_MARGIN 以上的 token, 这里不需要再提前
+	plugin.expiredAt = entry.ExpiredAt
+	plugin.cachedTokenId = entry.TokenId
+	return true
+}
+func (plugin *baseAuthPlugin) saveTokenCache() {
+	if !plugin.tokenCache {
+		return
+	}
+	err := token_cache.Save(token_cache.Entry{
+		Key:       plugin.cacheKey(),
+		TokenId:   plugin.tokenId,
+		Token:     *plugin.token,
+		ExpiredAt: plugin.expiredAt,
+	})
+	if err != nil {
+		console.Warn("save token cache failed: %s", err)
+	}
+}
+
+// 服务端拒绝了 tokenId (返回 401) 时调用
+//
+// 只有 tokenId 是从本地缓存加载的 token 时 (例如缓存的 token 已被吊销) 才删除缓存, 下次请求时重新申请,
+// 并返回 true 表示请求可以重试; 其他情况重新申请 token 也无法解决, 返回 false
+func (plugin *baseAuthPlugin) InvalidateCachedToken(tokenId string) bool {
+	plugin.tokenLock.Lock()
+	defer plugin.tokenLock.Unlock()
+
+	if tokenId == "" || tokenId != plugin.cachedTokenId {
+		return false
+	}
+	if plugin.tokenId == tokenId {
+		console.Debug("invalidate cached token")
+		plugin.tokenId = ""
+		if err := token_cache.Remove(plugin.cacheKey()); err != nil {
+			console.Warn("remove token cache failed: %s", err)
+		}
+	}
+	return true
+}
+
+func (plugin *baseAuthPlugin) makesureTokenValid() error {
+	plugin.tokenLock.Lock()
+	defer plugin.tokenLock.Unlock()
+
+	if plugin.IsTokenExpired() {
+		if plugin.loadTokenCache() {
+			return nil
+		}
+		return plugin.TokenIssue()
+	}
+	return nil
+}
+
+func (plugin *baseAuthPlugin) GetToken() (*model.Token, error) {
+	plugin.makesureTokenValid()
+	return plugin.token, nil
+}
+func (plugin *baseAuthPlugin) GetTokenId() (string, error) {
+	if err := plugin.makesureTokenValid(); err != nil {
+		return "", err
+	}
+	return plugin.tokenId, nil
+}
+
+func (plugin *baseAuthPlugin) TokenIssue() error {
+	if plugin.newAuthReqBody == nil {
+		return fmt.Errorf("token issue failed, auth request body is not defined")
+	}
+	respBody := struct {
+		Token model.Token `json:"token"`
+	}{}
+	tokenUrl := fmt.Sprintf("%s%s", plugin.AuthUrl, URL_AUTH_TOKEN)
+	resp, err := plugin.session.R().SetContext(session.WithService(plugin.context(), "keystone")).
+		SetBody(plugin.newAuthReqBody()).
+		SetResult(&respBody).
+		Post(tokenUrl)
+	if err != nil {
+		if interrupted := session.Interrupted(plugin.context(), "POST %s", tokenUrl); interrupted != nil {
+			return interrupted
+		}
+		return fmt.Errorf("token issue failed, %s", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("token issue failed, %w", session.NewHttpError(resp))
+	}
+	plugin.tokenId = resp.Header().Get(X_SUBJECT_TOKEN)
+	plugin.token = &respBody.Token
+	plugin.expiredAt = time.Now().Add(time.Second * time.Duration(plugin.LocalTokenExpireSecond))
+	// 忽略已过期的时间 (例如时钟偏差或回放的响应)
+	if expiresAt, err := time.Parse(time.RFC3339Nano, respBody.Token.ExpiresAt); err == nil &&
+		expiresAt.After(time.Now()) && expiresAt.Before(plugin.expiredAt) {
+		plugin.expiredAt = expiresAt
+	}
+	plugin.saveTokenCache()
+	return nil
+}
+func (plugin *baseAuthPlugin) GetServiceEndpoints(sType string, sName string) ([]model.Endpoint, error) {
+	token, err := plugin.GetToken()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, catalog := range token.Catalogs {
+		if catalog.Type != sType || (sName != "" && catalog.Name != sName) {
+			continue
+		}
+		return catalog.Endpoints, nil
+	}
+	return []model.Endpoint{}, nil
+}
+func (plugin *baseAuthPlugin) GetServiceEndpoint(sType string, sName string, sInterface string) (string, error) {
+	if err := plugin.makesureTokenValid(); err != nil {
+		return "", fmt.Errorf("get catalogs failed: %s", err)
+	}
+
+	for _, catalog := range plugin.token.Catalogs {
+		if catalog.Type != sType || (sName != "" && catalog.Name != sName) {
+			continue
+		}
+		for _, endpoint := range catalog.Endpoints {
+			if endpoint.Interface == sInterface && endpoint.Region == plugin.RegionName {
+				return endpoint.Url, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("endpoint %s:%s:%s for region '%s' not found",
+		sType, sName, sInterface, plugin.RegionName)
+}
+func (plugin *baseAuthPlugin) SetHttpTimeout(timeout int) {
+	plugin.session.SetTimeout(time.Second * time.Duration(timeout))
+}
+func (plugin *baseAuthPlugin) SetRetryWaitTime(waitTime int) {
+	plugin.session.SetRetryWaitTime(time.Second * time.Duration(waitTime))
+}
+func (plugin *baseAuthPlugin) SetRetryCount(count int) {
+	plugin.session.SetRetryCount(count)
+}
+
+func (plugin *baseAuthPlugin) AuthRequest(req *resty.Request) error {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	tokenId, err := plugin.GetTokenId()
+	if err != nil {
+		return err
+	}
+	if req.Header.Get(X_AUTH_TOKEN) == tokenId {
+		return nil
+	}
+	console.Debug("set auth token %s", tokenId)
+	req.Header.Set(X_AUTH_TOKEN, tokenId)
+	return nil
+}
+func (plugin baseAuthPlugin) GetSafeHeader(header http.Header) http.Header {
+	safeHeaders := http.Header{}
+	for k, v := range header {
+		if k == X_AUTH_TOKEN || k == X_SUBJECT_TOKEN {
+			safeHeaders[k] = []string{"<TOKEN>"}
+		} else {
+			safeHeaders[k] = v
+		}
+	}
+	return safeHeaders
+}
+func (plugin *baseAuthPlugin) GetProjectId() (string, error) {
+	if err := plugin.makesureTokenValid(); err != nil {
+		return "", err
+	}
+	return plugin.token.Project.Id, nil
+}
+func (plugin *baseAuthPlugin) IsAdmin() bool {
+	if err := plugin.makesureTokenValid(); err != nil {
+		return false
+	}
+	for _, role := range plugin.token.Roles {
+		if role.Name == "admin" {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package auth_plugin
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/BytemanD/skyman/openstack/model"
@@ -19,4 +20,10 @@ type AuthPlugin interface {
 	GetSafeHeader(header http.Header) http.Header
 	GetProjectId() (string, error)
 	IsAdmin() bool
+	SetHttpTimeout(timeout int)
+	SetRetryWaitTime(waitTime int)
+	SetRetryCount(count int)
+	EnableTokenCache(enable bool)
+	InvalidateCachedToken(tokenId string) bool
+	SetContext(ctx context.Context)
 }
@@ -0,0 +1,57 @@
+package auth_plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/BytemanD/skyman/openstack/model"
+)
+
+// 使用已有的 token (例如 OS_TOKEN) 认证
+//
+// 通过 token 方式重新申请一个指定项目的 token, 以获取服务目录
+type TokenAuthPlugin struct {
+	baseAuthPlugin
+	Token             string
+	ProjectId         string
+	ProjectName       string
+	ProjectDomainName string
+}
+
+func (plugin *TokenAuthPlugin) tokenAuthReqBody() AuthBody {
+	authData := model.Auth{
+		Identity: model.Identity{
+			Methods: []string{"token"},
+			Token:   &model.TokenId{Id: plugin.Token},
+		},
+	}
+	if plugin.ProjectId != "" {
+		authData.Scope = &model.Scope{Project: model.Project{Id: plugin.ProjectId}}
+	} else if plugin.ProjectName != "" {
+		authData.Scope = &model.Scope{Project: model.Project{
+			Name:   plugin.ProjectName,
+			Domain: model.Domain{Name: plugin.ProjectDomainName}},
+		}
+	}
+	return AuthBody{Auth: authData}
+}
+
+func NewTokenAuthPlugin(authUrl string, token string, project model.Project, regionName string) *TokenAuthPlugin {
+	plugin := &TokenAuthPlugin{
+		baseAuthPlugin:    newBaseAuthPlugin(authUrl, regionName),
+		Token:             token,
+		ProjectId:         project.Id,
+		ProjectName:       project.Name,
+		ProjectDomainName: project.Domain.Name,
+	}
+	plugin.newAuthReqBody = plugin.tokenAuthReqBody
+	// 缓存中不保存原始的 token
+	sum := sha256.Sum256([]byte(token))
+	plugin.cacheUser = "token:" + hex.EncodeToString(sum[:8])
+	if project.Id != "" {
+		plugin.cacheProject = project.Id
+	} else {
+		plugin.cacheProject = project.Domain.Name + "/" + project.Name
+	}
+	return plugin
+}
@@ -0,0 +1,29 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/BytemanD/skyman/openstack/fake"
+	"github.com/BytemanD/skyman/openstack/model"
+)
+
+func TestPasswordAuthWithIds(t *testing.T) {
+	cloud := fake.NewCloud()
+	defer cloud.Close()
+
+	// Horizon 生成的 clouds.yaml 只有 project_id 和 domain id
+	user := model.User{Name: cloud.Username, Password: cloud.Password, Domain: model.Domain{Id: fake.DEFAULT_DOMAIN_ID}}
+	authPlugin := NewPasswordAuth(cloud.AuthUrl(), user, model.Project{Id: cloud.ProjectId}, cloud.Region)
+	if err := authPlugin.TokenIssue(); err != nil {
+		t.Fatal(err)
+	}
+	projectId, err := authPlugin.GetProjectId()
+	if err != nil || projectId != cloud.ProjectId {
+		t.Errorf("expect project %s, but got %s (%v)", cloud.ProjectId, projectId, err)
+	}
+
+	authPlugin = NewPasswordAuth(cloud.AuthUrl(), user, model.Project{Id: fake.NewId()}, cloud.Region)
+	if err := authPlugin.TokenIssue(); err == nil {
+		t.Errorf("expect error when project id is wrong")
+	}
+}
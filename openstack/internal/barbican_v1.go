@@ -0,0 +1,169 @@
+package internal
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/BytemanD/skyman/openstack/model/barbican"
+	"github.com/BytemanD/skyman/openstack/session"
+)
+
+// barbican 每页的最大数量
+const BARBICAN_PAGE_LIMIT = 100
+
+type BarbicanV1 struct{ *ServiceClient }
+
+func (c *BarbicanV1) String() string {
+	return fmt.Sprintf("<KeyManager: %s>", c.Url)
+}
+
+type SecretApi struct{ ResourceApi }
+type SecretContainerApi struct{ ResourceApi }
+
+func (c BarbicanV1) Secret() SecretApi {
+	return SecretApi{
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl: "secrets",
+			PluralKey:   "secrets",
+		},
+	}
+}
+func (c BarbicanV1) Container() SecretContainerApi {
+	return SecretContainerApi{
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl: "containers",
+			PluralKey:   "containers",
+		},
+	}
+}
+
+// acl 接口, 资源可以是 secrets 或者 containers
+func getAcl(r ResourceApi, id string) (map[string]barbican.ACL, error) {
+	acls := map[string]barbican.ACL{}
+	if _, err := r.R().SetResult(&acls).Get(id, "acl"); err != nil {
+		return nil, err
+	}
+	return acls, nil
+}
+
+// 设置读权限, projectAccess 为 false 时, 只有 users 中的用户和创建者可以访问
+func setAcl(r ResourceApi, id string, projectAccess bool, users []string) error {
+	body := map[string]barbican.ACL{
+		barbican.ACL_READ: {ProjectAccess: projectAccess, Users: users},
+	}
+	_, err := r.R().SetBody(body).Put(id, "acl")
+	return err
+}
+func deleteAcl(r ResourceApi, id string) error {
+	_, err := r.R().Delete(id, "acl")
+	return err
+}
+
+// secret api
+func (c SecretApi) List(query url.Values) ([]barbican.Secret, error) {
+	secrets, err := listWithOffset[barbican.Secret](c.ResourceApi, query, BARBICAN_PAGE_LIMIT)
+	for i := range secrets {
+		secrets[i].SetIdFromRef()
+	}
+	return secrets, err
+}
+func (c SecretApi) Show(id string) (*barbican.Secret, error) {
+	secret := barbican.Secret{}
+	if _, err := c.R().SetResult(&secret).Get(id); err != nil {
+		return nil, err
+	}
+	secret.SetIdFromRef()
+	return &secret, nil
+}
+func (c SecretApi) Find(idOrName string) (*barbican.Secret, error) {
+	secret, err := c.Show(idOrName)
+	if err == nil || !session.IsNotFound(err) {
+		return secret, err
+	}
+	secrets, err := c.List(url.Values{"name": []string{idOrName}})
+	if err != nil {
+		return nil, err
+	}
+	switch len(secrets) {
+	case 0:
+		return nil, fmt.Errorf("secret %s %w", idOrName, session.ErrNotFound)
+	case 1:
+		return &secrets[0], nil
+	default:
+		return nil, fmt.Errorf("found multi secrets named %s", idOrName)
+	}
+}
+func (c SecretApi) Create(opt barbican.SecretOpt) (*barbican.Secret, error) {
+	result := barbican.Secret{}
+	if _, err := c.R().SetBody(opt).SetResult(&result).Post(); err != nil {
+		return nil, err
+	}
+	result.SetIdFromRef()
+	return c.Show(result.Id)
+}
+func (c SecretApi) Delete(id string) error {
+	_, err := DeleteResource(c.ResourceApi, id)
+	return err
+}
+
+// 获取 secret 的内容, contentType 为空时使用 text/plain
+func (c SecretApi) Payload(id string, contentType string) ([]byte, error) {
+	if contentType == "" {
+		contentType = "text/plain"
+	}
+	resp, err := c.R().SetHeader("Accept", contentType).Get(id, "payload")
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body(), nil
+}
+func (c SecretApi) GetAcl(id string) (map[string]barbican.ACL, error) {
+	return getAcl(c.ResourceApi, id)
+}
+func (c SecretApi) SetAcl(id string, projectAccess bool, users []string) error {
+	return setAcl(c.ResourceApi, id, projectAccess, users)
+}
+func (c SecretApi) DeleteAcl(id string) error {
+	return deleteAcl(c.ResourceApi, id)
+}
+
+// container api
+func (c SecretContainerApi) List(query url.Values) ([]barbican.Container, error) {
+	containers, err := listWithOffset[barbican.Container](c.ResourceApi, query, BARBICAN_PAGE_LIMIT)
+	for i := range containers {
+		containers[i].SetIdFromRef()
+	}
+	return containers, err
+}
+func (c SecretContainerApi) Show(id string) (*barbican.Container, error) {
+	container := barbican.Container{}
+	if _, err := c.R().SetResult(&container).Get(id); err != nil {
+		return nil, err
+	}
+	container.SetIdFromRef()
+	return &container, nil
+}
+
+// 创建容器, secrets 为 名字 -> secret 链接
+func (c SecretContainerApi) Create(name string, containerType string, secrets []barbican.SecretRef) (*barbican.Container, error) {
+	body := map[string]interface{}{"name": name, "type": containerType, "secret_refs": secrets}
+	result := barbican.Container{}
+	if _, err := c.R().SetBody(body).SetResult(&result).Post(); err != nil {
+		return nil, err
+	}
+	result.SetIdFromRef()
+	return c.Show(result.Id)
+}
+func (c SecretContainerApi) Delete(id string) error {
+	_, err := DeleteResource(c.ResourceApi, id)
+	return err
+}
+func (c SecretContainerApi) GetAcl(id string) (map[string]barbican.ACL, error) {
+	return getAcl(c.ResourceApi, id)
+}
+func (c SecretContainerApi) SetAcl(id string, projectAccess bool, users []string) error {
+	return setAcl(c.ResourceApi, id, projectAccess, users)
+}
+func (c SecretContainerApi) DeleteAcl(id string) error {
+	return deleteAcl(c.ResourceApi, id)
+}
@@ -0,0 +1,34 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestListSecretsWithOffset(t *testing.T) {
+	total := BARBICAN_PAGE_LIMIT + 5
+	routes := testRoutes{"GET /v1/secrets": func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		secrets := []string{}
+		for i := offset; i < total && i < offset+limit; i++ {
+			secrets = append(secrets, fmt.Sprintf(`{"secret_ref": "http://%s/v1/secrets/secret%d"}`, r.Host, i))
+		}
+		fmt.Fprintf(w, `{"secrets": [%s], "total": %d}`, strings.Join(secrets, ","), total)
+	}}
+	client := BarbicanV1{ServiceClient: newTestServiceClient(t, "barbican", "/v1", routes)}
+
+	secrets, err := client.Secret().List(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secrets) != total {
+		t.Fatalf("expect %d secrets, but got %d", total, len(secrets))
+	}
+	if last := secrets[total-1].Id; last != fmt.Sprintf("secret%d", total-1) {
+		t.Errorf("expect id parsed from secret ref, but got %s", last)
+	}
+}
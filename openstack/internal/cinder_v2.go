@@ -25,7 +25,7 @@ type BackupApi struct{ ResourceApi }
 func (c CinderV2) Volume() VolumeApi {
 	return VolumeApi{
 		ResourceApi: ResourceApi{
-			Client: c.rawClient, BaseUrl: c.Url,
+			Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
 			ResourceUrl: "volumes",
 			SingularKey: "volume",
 			PluralKey:   VOLUMES,
@@ -35,7 +35,7 @@ func (c CinderV2) Volume() VolumeApi {
 func (c CinderV2) Service() VolumeServiceApi {
 	return VolumeServiceApi{
 		ResourceApi: ResourceApi{
-			Client: c.rawClient, BaseUrl: c.Url,
+			Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
 			ResourceUrl: "os-services",
 			SingularKey: "service",
 			PluralKey:   "services",
@@ -46,7 +46,7 @@ func (c CinderV2) Service() VolumeServiceApi {
 func (c CinderV2) Snapshot() SnapshotApi {
 	return SnapshotApi{
 		ResourceApi: ResourceApi{
-			Client: c.rawClient, BaseUrl: c.Url,
+			Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
 			ResourceUrl: "snapshots",
 			SingularKey: "snapshot",
 			PluralKey:   SNAPSHOTS,
@@ -55,7 +55,7 @@ func (c CinderV2) Snapshot() SnapshotApi {
 }
 func (c CinderV2) Backup() BackupApi {
 	return BackupApi{
-		ResourceApi: ResourceApi{Client: c.rawClient, BaseUrl: c.Url,
+		ResourceApi: ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
 			ResourceUrl: "backups",
 			SingularKey: "backup",
 			PluralKey:   BACKUPS,
@@ -67,6 +67,7 @@ func (c CinderV2) VolumeType() VolumeTypeApi {
 	return VolumeTypeApi{
 		ResourceApi{
 			Client:      c.rawClient,
+			Ctx:         c.Context(),
 			BaseUrl:     c.Url,
 			ResourceUrl: "types",
 			SingularKey: "volume_type",
@@ -91,6 +92,11 @@ func (c VolumeApi) ListByName(name string) ([]cinder.Volume, error) {
 func (c VolumeApi) DetailByName(name string) ([]cinder.Volume, error) {
 	return c.Detail(utility.UrlValues(map[string]string{"name": name}))
 }
+
+// 逐页获取卷详情
+func (c VolumeApi) Iterator(query url.Values) *ResourceIterator[cinder.Volume] {
+	return NewResourceIterator[cinder.Volume](c.ResourceApi, "detail", query)
+}
 func (c VolumeApi) Show(id string) (*cinder.Volume, error) {
 	return ShowResource[cinder.Volume](c.ResourceApi, id)
 }
@@ -129,7 +135,9 @@ func (c VolumeApi) CreateAndWait(options map[string]interface{}, timeoutSeconds
 		if time.Since(startTime) >= time.Second*time.Duration(timeoutSeconds) {
 			return volume, fmt.Errorf("create timeout")
 		}
-		time.Sleep(time.Second * 2)
+		if err := c.sleep(time.Second*2, "wait volume %s available", volume.Id); err != nil {
+			return volume, err
+		}
 	}
 }
 func (c VolumeApi) Delete(id string, force bool, cascade bool) error {
@@ -176,6 +184,9 @@ func (c VolumeApi) Revert(id string, snapshotId string) error {
 func (c VolumeTypeApi) List(query url.Values) ([]cinder.VolumeType, error) {
 	return ListResource[cinder.VolumeType](c.ResourceApi, query)
 }
+func (c VolumeTypeApi) Iterator(query url.Values) *ResourceIterator[cinder.VolumeType] {
+	return NewResourceIterator[cinder.VolumeType](c.ResourceApi, "", query)
+}
 func (c VolumeTypeApi) Show(id string) (*cinder.VolumeType, error) {
 	return ShowResource[cinder.VolumeType](c.ResourceApi, id)
 }
@@ -201,6 +212,26 @@ func (c VolumeTypeApi) Delete(id string) error {
 	return err
 }
 
+// 查询卷类型的加密参数, 没有加密时 Provider 为空
+func (c VolumeTypeApi) ShowEncryption(id string) (*cinder.VolumeTypeEncryption, error) {
+	encryption := cinder.VolumeTypeEncryption{}
+	if _, err := c.R().SetResult(&encryption).Get(id, "encryption"); err != nil {
+		return nil, err
+	}
+	return &encryption, nil
+}
+func (c VolumeTypeApi) CreateEncryption(id string, encryption cinder.VolumeTypeEncryption) (*cinder.VolumeTypeEncryption, error) {
+	result := struct {
+		Encryption cinder.VolumeTypeEncryption `json:"encryption"`
+	}{}
+	_, err := c.R().SetBody(map[string]cinder.VolumeTypeEncryption{"encryption": encryption}).
+		SetResult(&result).Post(id, "encryption")
+	if err != nil {
+		return nil, err
+	}
+	return &result.Encryption, nil
+}
+
 // volume service api
 
 func (c VolumeServiceApi) List(query url.Values) ([]cinder.Service, error) {
@@ -215,6 +246,11 @@ func (c SnapshotApi) List(query url.Values) ([]cinder.Snapshot, error) {
 func (c SnapshotApi) Detail(query url.Values) ([]cinder.Snapshot, error) {
 	return ListResource[cinder.Snapshot](c.ResourceApi, query, true)
 }
+
+// 逐页获取快照详情
+func (c SnapshotApi) Iterator(query url.Values) *ResourceIterator[cinder.Snapshot] {
+	return NewResourceIterator[cinder.Snapshot](c.ResourceApi, "detail", query)
+}
 func (c SnapshotApi) Show(id string) (*cinder.Snapshot, error) {
 	return ShowResource[cinder.Snapshot](c.ResourceApi, id)
 }
@@ -251,6 +287,11 @@ func (c BackupApi) List(query url.Values) ([]cinder.Backup, error) {
 func (c BackupApi) Detail(query url.Values) ([]cinder.Backup, error) {
 	return ListResource[cinder.Backup](c.ResourceApi, query, true)
 }
+
+// 逐页获取备份详情
+func (c BackupApi) Iterator(query url.Values) *ResourceIterator[cinder.Backup] {
+	return NewResourceIterator[cinder.Backup](c.ResourceApi, "detail", query)
+}
 func (c BackupApi) Show(id string) (*cinder.Backup, error) {
 	return ShowResource[cinder.Backup](c.ResourceApi, id)
 }
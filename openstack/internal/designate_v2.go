@@ -0,0 +1,196 @@
+package internal
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/BytemanD/skyman/openstack/model/designate"
+	"github.com/BytemanD/skyman/openstack/session"
+)
+
+type DesignateV2 struct{ *ServiceClient }
+
+func (c *DesignateV2) String() string {
+	return fmt.Sprintf("<DNS: %s>", c.Url)
+}
+
+type ZoneApi struct{ ResourceApi }
+type RecordSetApi struct {
+	ResourceApi
+	zoneId string
+}
+type FloatingIPPtrApi struct{ ResourceApi }
+
+func (c DesignateV2) Zone() ZoneApi {
+	return ZoneApi{
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl: "zones",
+			PluralKey:   "zones",
+		},
+	}
+}
+
+// zone 的记录集, zoneId 为空时查询所有 zone 的记录集
+func (c DesignateV2) RecordSet(zoneId string) RecordSetApi {
+	resourceUrl := "recordsets"
+	if zoneId != "" {
+		resourceUrl = fmt.Sprintf("zones/%s/recordsets", zoneId)
+	}
+	return RecordSetApi{
+		ResourceApi: ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl: resourceUrl,
+			PluralKey:   "recordsets",
+		},
+		zoneId: zoneId,
+	}
+}
+func (c DesignateV2) FloatingIPPtr() FloatingIPPtrApi {
+	return FloatingIPPtrApi{
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl: "reverse/floatingips",
+			PluralKey:   "floatingips",
+		},
+	}
+}
+
+// zone api
+func (c ZoneApi) List(query url.Values) ([]designate.Zone, error) {
+	return ListResource[designate.Zone](c.ResourceApi, query)
+}
+func (c ZoneApi) Iterator(query url.Values) *ResourceIterator[designate.Zone] {
+	return NewResourceIterator[designate.Zone](c.ResourceApi, "", query)
+}
+func (c ZoneApi) Show(id string) (*designate.Zone, error) {
+	zone := designate.Zone{}
+	if _, err := c.R().SetResult(&zone).Get(id); err != nil {
+		return nil, err
+	}
+	return &zone, nil
+}
+
+// 根据 id 或者域名查找 zone, 域名可以不以 . 结尾
+func (c ZoneApi) Find(idOrName string) (*designate.Zone, error) {
+	// 域名中一定包含 ".", 直接按名字查询, 避免非 uuid 的 id 返回 400
+	if !strings.Contains(idOrName, ".") {
+		return c.Show(idOrName)
+	}
+	name := designate.Fqdn(idOrName)
+	zones, err := c.List(url.Values{"name": []string{name}})
+	if err != nil {
+		return nil, err
+	}
+	for _, zone := range zones {
+		if zone.Name == name {
+			return &zone, nil
+		}
+	}
+	return nil, fmt.Errorf("zone %s %w", idOrName, session.ErrNotFound)
+}
+func (c ZoneApi) Create(name string, email string, ttl int, description string) (*designate.Zone, error) {
+	body := map[string]interface{}{"name": designate.Fqdn(name), "email": email}
+	if ttl > 0 {
+		body["ttl"] = ttl
+	}
+	if description != "" {
+		body["description"] = description
+	}
+	zone := designate.Zone{}
+	if _, err := c.R().SetBody(body).SetResult(&zone).Post(); err != nil {
+		return nil, err
+	}
+	return &zone, nil
+}
+func (c ZoneApi) Delete(id string) error {
+	_, err := DeleteResource(c.ResourceApi, id)
+	return err
+}
+
+// recordset api
+func (c RecordSetApi) List(query url.Values) ([]designate.RecordSet, error) {
+	return ListResource[designate.RecordSet](c.ResourceApi, query)
+}
+func (c RecordSetApi) Iterator(query url.Values) *ResourceIterator[designate.RecordSet] {
+	return NewResourceIterator[designate.RecordSet](c.ResourceApi, "", query)
+}
+func (c RecordSetApi) Show(id string) (*designate.RecordSet, error) {
+	recordSet := designate.RecordSet{}
+	if _, err := c.R().SetResult(&recordSet).Get(id); err != nil {
+		return nil, err
+	}
+	return &recordSet, nil
+}
+
+// 根据 id 或者名字查找记录集, 名字可以是相对于 zone 的名字
+func (c RecordSetApi) Find(idOrName string, zoneName string) (*designate.RecordSet, error) {
+	recordSet, err := c.Show(idOrName)
+	if err == nil || (!session.IsNotFound(err) && !session.IsBadRequest(err)) {
+		return recordSet, err
+	}
+	name := designate.RecordName(idOrName, zoneName)
+	recordSets, err := c.List(url.Values{"name": []string{name}})
+	if err != nil {
+		return nil, err
+	}
+	switch len(recordSets) {
+	case 0:
+		return nil, fmt.Errorf("recordset %s %w", idOrName, session.ErrNotFound)
+	case 1:
+		return &recordSets[0], nil
+	default:
+		return nil, fmt.Errorf("found multi recordsets named %s", name)
+	}
+}
+func (c RecordSetApi) Create(name string, recordType string, records []string, ttl int) (*designate.RecordSet, error) {
+	if c.zoneId == "" {
+		return nil, fmt.Errorf("zone is required")
+	}
+	body := map[string]interface{}{"name": name, "type": recordType, "records": records}
+	if ttl > 0 {
+		body["ttl"] = ttl
+	}
+	recordSet := designate.RecordSet{}
+	if _, err := c.R().SetBody(body).SetResult(&recordSet).Post(); err != nil {
+		return nil, err
+	}
+	return &recordSet, nil
+}
+func (c RecordSetApi) Delete(id string) error {
+	if c.zoneId == "" {
+		return fmt.Errorf("zone is required")
+	}
+	_, err := DeleteResource(c.ResourceApi, id)
+	return err
+}
+
+// floating ip ptr api
+//
+// ptr 记录的 id 格式为 <region>:<floating ip id>
+func (c FloatingIPPtrApi) List(query url.Values) ([]designate.FloatingIPPtr, error) {
+	return ListResource[designate.FloatingIPPtr](c.ResourceApi, query)
+}
+func (c FloatingIPPtrApi) Iterator(query url.Values) *ResourceIterator[designate.FloatingIPPtr] {
+	return NewResourceIterator[designate.FloatingIPPtr](c.ResourceApi, "", query)
+}
+func (c FloatingIPPtrApi) Show(region string, floatingipId string) (*designate.FloatingIPPtr, error) {
+	ptr := designate.FloatingIPPtr{}
+	if _, err := c.R().SetResult(&ptr).Get(region + ":" + floatingipId); err != nil {
+		return nil, err
+	}
+	return &ptr, nil
+}
+func (c FloatingIPPtrApi) Set(region string, floatingipId string, ptrdname string, ttl int) (*designate.FloatingIPPtr, error) {
+	body := map[string]interface{}{"ptrdname": designate.Fqdn(ptrdname)}
+	if ttl > 0 {
+		body["ttl"] = ttl
+	}
+	ptr := designate.FloatingIPPtr{}
+	if _, err := c.R().SetBody(body).SetResult(&ptr).Patch(region + ":" + floatingipId); err != nil {
+		return nil, err
+	}
+	return &ptr, nil
+}
+func (c FloatingIPPtrApi) Unset(region string, floatingipId string) error {
+	_, err := c.R().SetBody(map[string]interface{}{"ptrdname": nil}).Patch(region + ":" + floatingipId)
+	return err
+}
@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/BytemanD/skyman/openstack/model/designate"
+)
+
+func TestZoneFindAndCreateRecordSet(t *testing.T) {
+	created := ""
+	routes := testRoutes{
+		"GET /v2/zones": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("name") != "example.com." {
+				fmt.Fprint(w, `{"zones": []}`)
+				return
+			}
+			fmt.Fprint(w, `{"zones": [{"id": "zone1", "name": "example.com."}], "links": {}}`)
+		},
+		"POST /v2/zones/zone1/recordsets": func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			created = string(body)
+			fmt.Fprint(w, `{"id": "rs1", "name": "vm-01.example.com.", "type": "A", "records": ["10.0.0.3"]}`)
+		},
+	}
+	client := DesignateV2{ServiceClient: newTestServiceClient(t, "designate", "/v2", routes)}
+
+	zone, err := client.Zone().Find("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if zone.Id != "zone1" {
+		t.Errorf("expect zone1, but got %s", zone.Id)
+	}
+	name := designate.RecordName("vm-01", zone.Name)
+	recordSet, err := client.RecordSet(zone.Id).Create(name, designate.RECORD_A, []string{"10.0.0.3"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `{"name":"vm-01.example.com.","records":["10.0.0.3"],"type":"A"}`
+	if created != expect || recordSet.Id != "rs1" {
+		t.Errorf("expect request body %s, but got %s", expect, created)
+	}
+	if _, err := client.RecordSet("").Create(name, designate.RECORD_A, nil, 0); err == nil {
+		t.Errorf("expect error when zone is empty")
+	}
+}
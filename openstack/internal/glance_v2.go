@@ -1,13 +1,13 @@
 package internal
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
 	"os"
 	"strconv"
 
-	"github.com/BytemanD/go-console/console"
 	"github.com/BytemanD/skyman/openstack/model"
 	"github.com/BytemanD/skyman/openstack/model/glance"
 	"github.com/BytemanD/skyman/openstack/session"
@@ -16,44 +16,31 @@ import (
 
 type ImageApi struct{ ResourceApi }
 
+// 获取镜像列表, total 大于 0 时最多返回 total 个镜像
 func (c ImageApi) List(query url.Values, total int) ([]glance.Image, error) {
 	images := []glance.Image{}
-	fixQuery := query
-	if !fixQuery.Has("limit") {
-		if total > 0 {
+	fixQuery := url.Values{}
+	for k, v := range query {
+		fixQuery[k] = v
+	}
+	if total > 0 {
+		if limit, _ := strconv.Atoi(fixQuery.Get("limit")); limit <= 0 || limit > total {
 			fixQuery.Set("limit", strconv.Itoa(total))
 		}
-	} else if total > 0 {
-		limit, _ := strconv.Atoi(query.Get("limit"))
-		fixQuery.Set("limit", strconv.Itoa(min(limit, total)))
-	}
-	for {
-		respBbody := glance.ImagesResp{}
-		req := c.NewGetRequest("images", fixQuery, nil)
-		console.Debug("query params: %s", req.QueryParam.Encode())
-		_, err := req.SetResult(&respBbody).Send()
-		if err != nil {
-			return nil, err
-		}
-		if len(respBbody.Images) == 0 {
-			break
-		}
-		images = append(images, respBbody.Images...)
-		if len(images) >= total {
-			break
-		}
-		if respBbody.Next == "" {
-			break
-		}
-		parsedUrl, _ := url.Parse(respBbody.Next)
-		fixQuery = parsedUrl.Query()
-		if parsedUrl.Query().Has("limit") && total > 0 {
-			limit, _ := strconv.Atoi(parsedUrl.Query().Get("limit"))
-			limit = min(limit, total-len(images))
-			fixQuery.Set("limit", strconv.Itoa(limit))
+	}
+	iter := c.Iterator(fixQuery)
+	for iter.Next() {
+		images = append(images, iter.Page()...)
+		if total > 0 && len(images) >= total {
+			return images[:total], nil
 		}
 	}
-	return images, nil
+	return images, iter.Err()
+}
+
+// 逐页获取镜像
+func (c ImageApi) Iterator(query url.Values) *ResourceIterator[glance.Image] {
+	return NewResourceIterator[glance.Image](c.ResourceApi, "", query)
 }
 func (c ImageApi) ListAll(query url.Values) ([]glance.Image, error) {
 	return c.List(query, 0)
@@ -72,7 +59,7 @@ func (c ImageApi) FoundByName(name string) (*glance.Image, error) {
 		return nil, err
 	}
 	if len(images) == 0 {
-		return nil, fmt.Errorf("image %s not found", name)
+		return nil, fmt.Errorf("image %s %w", name, session.ErrNotFound)
 	}
 	if len(images) > 1 {
 		return nil, fmt.Errorf("found multi images named %s ", name)
@@ -134,9 +121,9 @@ func (c ImageApi) Upload(id string, file string) error {
 
 	// TODO:
 	reader := utility.NewProcessReader(fileReader, int(fileStat.Size()))
-	_, err = c.NewPutRequest(utility.UrlJoin("images", id, "file"), reader, nil).
+	_, err = checkError(c.NewPutRequest(utility.UrlJoin("images", id, "file"), reader, nil).
 		SetHeader(session.CONTENT_TYPE, session.CONTENT_TYPE_STREAM).
-		Send()
+		Send())
 
 	// _, err := c.NewPutRequest(utility.UrlJoin("images", id, "file"), nil, nil).
 	// 	SetHeader(httpclient.CONTENT_TYPE, httpclient.CONTENT_TYPE_STREAM).
@@ -159,9 +146,21 @@ func (c ImageApi) Download(id string, fileName string, process bool) error {
 			return err
 		}
 	}
-	// TODO: 异常
-	go req.Send()
-	utility.WatchFileSize(fileName, int(image.Size))
+	// 下载结束后停止显示进度
+	ctx, cancel := context.WithCancel(c.Context())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := checkError(req.SetContext(ctx).Send())
+		cancel()
+		done <- err
+	}()
+	utility.WatchFileSize(ctx, fileName, int(image.Size))
+	err = <-done
+	if interrupted := session.Interrupted(c.Context(), "download image %s", id); interrupted != nil {
+		os.Remove(fileName)
+		return interrupted
+	}
 	return err
 }
 
@@ -169,7 +168,10 @@ type GlanceV2 struct{ *ServiceClient }
 
 func (c GlanceV2) Images() ImageApi {
 	return ImageApi{
-		ResourceApi: ResourceApi{Client: c.rawClient, BaseUrl: c.Url},
+		ResourceApi: ResourceApi{
+			Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl: "images", PluralKey: "images",
+		},
 	}
 }
 
@@ -0,0 +1,201 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/openstack/model"
+	"github.com/BytemanD/skyman/openstack/model/heat"
+	"github.com/BytemanD/skyman/openstack/session"
+	"github.com/BytemanD/skyman/utility"
+)
+
+type HeatV1 struct {
+	*ServiceClient
+	currentVersion *model.ApiVersion
+}
+
+func (c *HeatV1) GetCurrentVersion() (*model.ApiVersion, error) {
+	if c.currentVersion == nil {
+		result := struct{ Versions model.ApiVersions }{}
+		if resp, err := c.Index(nil); err != nil {
+			return nil, err
+		} else if err := resp.UnmarshalBody(&result); err != nil {
+			return nil, err
+		}
+		c.currentVersion = result.Versions.Current()
+	}
+	if c.currentVersion != nil {
+		return c.currentVersion, nil
+	}
+	return nil, fmt.Errorf("current version not found")
+}
+func (c *HeatV1) String() string {
+	return fmt.Sprintf("<Orchestration: %s>", c.Url)
+}
+
+type StackApi struct{ ResourceApi }
+
+func (c HeatV1) Stack() StackApi {
+	return StackApi{
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl: "stacks",
+			SingularKey: "stack",
+			PluralKey:   "stacks",
+		},
+	}
+}
+
+// stack 的接口路径: <stack name>/<stack id>
+func stackPath(stack heat.Stack) string {
+	return stack.StackName + "/" + stack.Id
+}
+
+func (c StackApi) List(query url.Values) ([]heat.Stack, error) {
+	return ListResource[heat.Stack](c.ResourceApi, query)
+}
+func (c StackApi) Iterator(query url.Values) *ResourceIterator[heat.Stack] {
+	return NewResourceIterator[heat.Stack](c.ResourceApi, "", query)
+}
+
+// 查询 stack, 支持 id 和名字, heat 会重定向到 stacks/<name>/<id>
+func (c StackApi) Show(idOrName string) (*heat.Stack, error) {
+	return ShowResource[heat.Stack](c.ResourceApi, idOrName)
+}
+func (c StackApi) Find(idOrName string) (*heat.Stack, error) {
+	return c.Show(idOrName)
+}
+func (c StackApi) Create(opt heat.StackOpt) (*heat.Stack, error) {
+	result := struct {
+		Stack heat.Stack `json:"stack"`
+	}{}
+	if _, err := c.R().SetBody(opt).SetResult(&result).Post(); err != nil {
+		return nil, err
+	}
+	result.Stack.StackName = opt.StackName
+	return &result.Stack, nil
+}
+
+// 更新 stack, existing 为 true 时使用 PATCH, 未指定的模板和参数保持不变
+func (c StackApi) Update(stack heat.Stack, opt heat.StackOpt, existing bool) error {
+	opt.StackName = ""
+	req := c.R().SetBody(opt)
+	var err error
+	if existing {
+		_, err = req.Patch(stackPath(stack))
+	} else {
+		_, err = req.Put(stackPath(stack))
+	}
+	return err
+}
+func (c StackApi) Delete(stack heat.Stack) error {
+	_, err := c.R().Delete(stackPath(stack))
+	return err
+}
+
+// 查询 stack 的资源, nestedDepth 大于 0 时同时返回嵌套 stack 中的资源
+func (c StackApi) Resources(stack heat.Stack, nestedDepth int) ([]heat.Resource, error) {
+	query := url.Values{}
+	if nestedDepth > 0 {
+		query.Set("nested_depth", fmt.Sprint(nestedDepth))
+	}
+	result := struct {
+		Resources []heat.Resource `json:"resources"`
+	}{}
+	if _, err := c.R().SetQuery(query).SetResult(&result).Get(stackPath(stack), "resources"); err != nil {
+		return nil, err
+	}
+	return result.Resources, nil
+}
+func (c StackApi) Events(stack heat.Stack, query url.Values) ([]heat.Event, error) {
+	result := struct {
+		Events []heat.Event `json:"events"`
+	}{}
+	if _, err := c.R().SetQuery(query).SetResult(&result).Get(stackPath(stack), "events"); err != nil {
+		return nil, err
+	}
+	return result.Events, nil
+}
+
+// 等待 stack 的操作 action (例如 CREATE, UPDATE, DELETE) 结束
+//
+// 状态变为 <action>_COMPLETE 时返回, 变为 *_FAILED 或者回滚完成时返回错误; stack
+// 不存在时视为删除完成。onEvent 不为空时, 按时间顺序传入新产生的事件 (包括嵌套 stack 的事件)
+//
+// 操作开始前查询到的可能是上一次操作的结束状态, 只有出现过 *_IN_PROGRESS、
+// updated_time 或者状态与传入的 stack 不同时, 才认为操作已经结束
+func (c StackApi) WaitComplete(ctx context.Context, stack heat.Stack, action string, interval int, onEvent func(event heat.Event)) (*heat.Stack, error) {
+	c.ResourceApi = c.WithContext(ctx)
+	var (
+		current = &stack
+		err     error
+		marker  string
+		seen    = map[string]bool{}
+		started bool
+	)
+	showEvents := func() {
+		query := url.Values{"sort_dir": []string{"asc"}, "nested_depth": []string{fmt.Sprint(heat.MAX_NESTED_DEPTH)}}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		events, err := c.Events(stack, query)
+		if err != nil {
+			console.Debug("list events of stack %s failed: %s", stack.StackName, err)
+			return
+		}
+		for _, event := range events {
+			if seen[event.Id] {
+				continue
+			}
+			seen[event.Id], marker = true, event.Id
+			onEvent(event)
+		}
+	}
+	retryErr := utility.Retry(
+		utility.RetryCondition{
+			Ctx:     c.Context(),
+			Timeout: time.Hour, IntervalMin: time.Second * time.Duration(interval),
+		},
+		func() bool {
+			if onEvent != nil {
+				showEvents()
+			}
+			var latest *heat.Stack
+			latest, err = c.Show(stackPath(stack))
+			if err != nil {
+				if session.IsNotFound(err) {
+					current.StackStatus, err = "DELETE_COMPLETE", nil
+				}
+				return false
+			}
+			current = latest
+			console.Debug("[stack: %s] status: %s", stack.StackName, current.StackStatus)
+			if !started {
+				started = current.IsInProgress() ||
+					current.UpdatedTime != stack.UpdatedTime || current.StackStatus != stack.StackStatus
+				if !started {
+					return true
+				}
+			}
+			if current.IsFailed() || (current.IsComplete() && current.Action() == heat.ACTION_ROLLBACK) {
+				err = fmt.Errorf("stack %s is %s: %s", stack.StackName, current.StackStatus, current.StackStatusReason)
+				return false
+			}
+			// 请求返回后状态可能还没有更新, 需要等待状态变为当前的操作
+			return !(current.IsComplete() && current.Action() == action)
+		},
+	)
+	if onEvent != nil {
+		showEvents()
+	}
+	if interrupted := session.Interrupted(c.Context(), "wait stack %s", stack.StackName); interrupted != nil {
+		return current, interrupted
+	}
+	if err == nil && retryErr != nil {
+		err = fmt.Errorf("wait stack %s failed: %w", stack.StackName, retryErr)
+	}
+	return current, err
+}
@@ -0,0 +1,45 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/BytemanD/skyman/openstack/model/heat"
+)
+
+func TestStackWaitCompleteIgnoreStaleStatus(t *testing.T) {
+	testCases := []struct {
+		name     string
+		statuses []string
+		expect   string
+		polls    int
+	}{
+		{"stale", []string{"UPDATE_COMPLETE", "UPDATE_IN_PROGRESS", "UPDATE_COMPLETE"}, "UPDATE_COMPLETE", 3},
+		{"stale failed", []string{"UPDATE_FAILED", "UPDATE_IN_PROGRESS", "UPDATE_COMPLETE"}, "UPDATE_COMPLETE", 3},
+		{"status changed", []string{"CREATE_COMPLETE", "UPDATE_COMPLETE"}, "UPDATE_COMPLETE", 2},
+	}
+	for _, testCase := range testCases {
+		polls := 0
+		routes := testRoutes{"GET /v1/stacks/stack1/1111": func(w http.ResponseWriter, r *http.Request) {
+			status := testCase.statuses[min(polls, len(testCase.statuses)-1)]
+			polls++
+			fmt.Fprintf(w, `{"stack": {"id": "1111", "stack_name": "stack1", "stack_status": "%s", "updated_time": "2024-01-01T00:00:00Z"}}`, status)
+		}}
+		client := HeatV1{ServiceClient: newTestServiceClient(t, "heat", "/v1", routes)}
+		stack := heat.Stack{
+			Id: "1111", StackName: "stack1",
+			StackStatus: testCase.statuses[0], UpdatedTime: "2024-01-01T00:00:00Z",
+		}
+		current, err := client.Stack().WaitComplete(context.Background(), stack, heat.ACTION_UPDATE, 0, nil)
+		if err != nil {
+			t.Errorf("%s: %s", testCase.name, err)
+			continue
+		}
+		if current.StackStatus != testCase.expect || polls != testCase.polls {
+			t.Errorf("%s: expect %s after %d polls, but got %s after %d polls",
+				testCase.name, testCase.expect, testCase.polls, current.StackStatus, polls)
+		}
+	}
+}
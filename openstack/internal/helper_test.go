@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/BytemanD/skyman/openstack/fake"
+	"github.com/BytemanD/skyman/openstack/internal/auth_plugin"
+	"github.com/BytemanD/skyman/openstack/model"
+	"github.com/BytemanD/skyman/openstack/session"
+)
+
+// 测试服务的路由, key 为 "<method> <path>", 未匹配的请求返回 404
+type testRoutes map[string]http.HandlerFunc
+
+func (routes testRoutes) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	handler, ok := routes[r.Method+" "+r.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	handler(w, r)
+}
+
+// 启动测试服务, 返回不需要认证的 ServiceClient, 地址为 <server url><path>
+func newTestServiceClient(t *testing.T, serviceName string, path string, handler http.Handler) *ServiceClient {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &ServiceClient{
+		Url: server.URL + path, rawClient: session.DefaultRestyClient(), ServiceName: serviceName,
+	}
+}
+
+// 使用 fake cloud 认证, 返回 catalog 中服务的 ServiceClient
+func newFakeServiceClient(t *testing.T, cloud *fake.Cloud, serviceType string, serviceName string, version string) *ServiceClient {
+	authPlugin := NewPasswordAuth(cloud.AuthUrl(), cloud.User(), cloud.Project(), cloud.Region)
+	authPlugin.SetLocalTokenExpire(3600)
+	endpoint, err := authPlugin.GetServiceEndpoint(serviceType, serviceName, "public")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewServiceApi(endpoint, version, authPlugin)
+}
+
+// 使用 testdata 目录下记录的响应创建 ServiceClient
+func newReplayServiceClient(t *testing.T, cassette string, endpoint string, version string) *ServiceClient {
+	if err := session.SetReplayDir(filepath.Join("testdata", cassette)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(session.ResetRecordReplay)
+
+	authPlugin := NewPasswordAuth(
+		"http://keystone:5000/v3",
+		model.User{Name: "admin", Password: "password", Domain: model.Domain{Name: "Default"}},
+		model.Project{Name: "admin", Domain: model.Domain{Name: "Default"}},
+		"RegionOne",
+	)
+	authPlugin.SetLocalTokenExpire(3600)
+	return NewServiceApi(endpoint, version, auth_plugin.AuthPlugin(authPlugin))
+}
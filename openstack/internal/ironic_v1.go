@@ -0,0 +1,161 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/openstack/model"
+	"github.com/BytemanD/skyman/openstack/model/ironic"
+	"github.com/BytemanD/skyman/openstack/session"
+	"github.com/BytemanD/skyman/utility"
+)
+
+type IronicV1 struct {
+	*ServiceClient
+	currentVersion *model.ApiVersion
+}
+
+func (c *IronicV1) GetCurrentVersion() (*model.ApiVersion, error) {
+	if c.currentVersion == nil {
+		result := struct{ Versions model.ApiVersions }{}
+		if resp, err := c.Index(nil); err != nil {
+			return nil, err
+		} else if err := resp.UnmarshalBody(&result); err != nil {
+			return nil, err
+		}
+		c.currentVersion = result.Versions.Current()
+	}
+	if c.currentVersion != nil {
+		return c.currentVersion, nil
+	}
+	return nil, fmt.Errorf("current version not found")
+}
+func (c *IronicV1) String() string {
+	return fmt.Sprintf("<Baremetal: %s>", c.Url)
+}
+
+type BaremetalNodeApi struct{ ResourceApi }
+type BaremetalPortApi struct{ ResourceApi }
+type BaremetalDriverApi struct{ ResourceApi }
+
+func (c IronicV1) Node() BaremetalNodeApi {
+	return BaremetalNodeApi{
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl: "nodes",
+			PluralKey:   "nodes",
+		},
+	}
+}
+func (c IronicV1) Port() BaremetalPortApi {
+	return BaremetalPortApi{
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl: "ports",
+			PluralKey:   "ports",
+		},
+	}
+}
+func (c IronicV1) Driver() BaremetalDriverApi {
+	return BaremetalDriverApi{
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl: "drivers",
+			PluralKey:   "drivers",
+		},
+	}
+}
+
+// node api
+func (c BaremetalNodeApi) List(query url.Values) ([]ironic.Node, error) {
+	return ListResource[ironic.Node](c.ResourceApi, query, true)
+}
+func (c BaremetalNodeApi) Iterator(query url.Values) *ResourceIterator[ironic.Node] {
+	return NewResourceIterator[ironic.Node](c.ResourceApi, "detail", query)
+}
+
+// 查询节点, 支持 uuid 和名字
+func (c BaremetalNodeApi) Show(idOrName string) (*ironic.Node, error) {
+	node := ironic.Node{}
+	if _, err := c.R().SetResult(&node).Get(idOrName); err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+func (c BaremetalNodeApi) Find(idOrName string) (*ironic.Node, error) {
+	return c.Show(idOrName)
+}
+
+// 设置电源状态, target 例如: power on, power off, rebooting
+func (c BaremetalNodeApi) SetPowerState(id string, target string) error {
+	_, err := c.R().SetBody(map[string]string{"target": target}).Put(id, "states", "power")
+	return err
+}
+
+// 设置部署状态, target 例如: manage, provide, inspect, deploy, deleted
+func (c BaremetalNodeApi) SetProvisionState(id string, target string) error {
+	_, err := c.R().SetBody(map[string]string{"target": target}).Put(id, "states", "provision")
+	return err
+}
+func (c BaremetalNodeApi) SetMaintenance(id string, reason string) error {
+	body := map[string]string{}
+	if reason != "" {
+		body["reason"] = reason
+	}
+	_, err := c.R().SetBody(body).Put(id, "maintenance")
+	return err
+}
+func (c BaremetalNodeApi) UnsetMaintenance(id string) error {
+	_, err := c.R().Delete(id, "maintenance")
+	return err
+}
+
+// 等待节点的部署状态变为 state, 部署状态变为失败 (例如 deploy failed) 时返回错误
+func (c BaremetalNodeApi) WaitProvisionState(ctx context.Context, id string, state string, timeout time.Duration, interval int) (*ironic.Node, error) {
+	c.ResourceApi = c.WithContext(ctx)
+	var (
+		node *ironic.Node
+		err  error
+	)
+	retryErr := utility.Retry(
+		utility.RetryCondition{
+			Ctx:     c.Context(),
+			Timeout: timeout, IntervalMin: time.Second * time.Duration(interval),
+		},
+		func() bool {
+			node, err = c.Show(id)
+			if err != nil {
+				return false
+			}
+			console.Info("[node: %s] provision state: %s, target: %s", id, node.ProvisionState, node.TargetProvisionState)
+			if node.ProvisionState == state {
+				return false
+			}
+			if node.IsProvisionFailed() {
+				err = fmt.Errorf("node %s provision state is %s: %s", id, node.ProvisionState, node.LastError)
+				return false
+			}
+			return true
+		},
+	)
+	if interrupted := session.Interrupted(c.Context(), "wait node %s %s", id, state); interrupted != nil {
+		return node, interrupted
+	}
+	if err == nil && retryErr != nil {
+		err = fmt.Errorf("wait node %s %s failed: %w", id, state, retryErr)
+	}
+	return node, err
+}
+
+// port api
+func (c BaremetalPortApi) List(query url.Values) ([]ironic.Port, error) {
+	return ListResource[ironic.Port](c.ResourceApi, query, true)
+}
+func (c BaremetalPortApi) Iterator(query url.Values) *ResourceIterator[ironic.Port] {
+	return NewResourceIterator[ironic.Port](c.ResourceApi, "detail", query)
+}
+
+// driver api
+func (c BaremetalDriverApi) List(query url.Values) ([]ironic.Driver, error) {
+	return ListResource[ironic.Driver](c.ResourceApi, query)
+}
@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/BytemanD/skyman/openstack/session"
+)
+
+func TestWaitProvisionState(t *testing.T) {
+	states := []string{"cleaning", "clean wait", "available", "deploying", "deploy failed"}
+	shows := 0
+	routes := testRoutes{"GET /v1/nodes/node1": func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"uuid": "node1", "provision_state": "%s", "last_error": "boom"}`, states[shows])
+		shows++
+	}}
+	client := IronicV1{ServiceClient: newTestServiceClient(t, "ironic", "/v1", routes)}
+
+	node, err := client.Node().WaitProvisionState(context.Background(), "node1", "available", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.ProvisionState != "available" || shows != 3 {
+		t.Errorf("expect available after 3 shows, but got %s after %d", node.ProvisionState, shows)
+	}
+	if _, err := client.Node().WaitProvisionState(context.Background(), "node1", "active", 0, 0); err == nil {
+		t.Errorf("expect error when provision state is deploy failed")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Node().WaitProvisionState(ctx, "node1", "active", 0, 0); !errors.As(err, &session.InterruptedError{}) {
+		t.Errorf("expect interrupted error when context is canceled, but got %v", err)
+	}
+}
@@ -181,6 +181,7 @@ func (c KeystoneV3) Region() *RegionApi {
 	return &RegionApi{
 		ResourceApi{
 			Client:      c.rawClient,
+			Ctx:         c.Context(),
 			BaseUrl:     c.Url,
 			ResourceUrl: "regions",
 			SingularKey: "region",
@@ -193,6 +194,7 @@ func (c KeystoneV3) Service() *ServiceApi {
 	return &ServiceApi{
 		ResourceApi{
 			Client:      c.rawClient,
+			Ctx:         c.Context(),
 			BaseUrl:     c.Url,
 			ResourceUrl: "services",
 			SingularKey: "service",
@@ -205,6 +207,7 @@ func (c KeystoneV3) Endpoint() *EndpointApi {
 	return &EndpointApi{
 		ResourceApi{
 			Client:      c.rawClient,
+			Ctx:         c.Context(),
 			BaseUrl:     c.Url,
 			ResourceUrl: "endpoints",
 			SingularKey: "endpoint",
@@ -217,6 +220,7 @@ func (c KeystoneV3) User() UserApi {
 	return UserApi{
 		ResourceApi{
 			Client:      c.rawClient,
+			Ctx:         c.Context(),
 			BaseUrl:     c.Url,
 			ResourceUrl: "users",
 			SingularKey: "user",
@@ -228,6 +232,7 @@ func (c KeystoneV3) Project() ProjectApi {
 	return ProjectApi{
 		ResourceApi{
 			Client:      c.rawClient,
+			Ctx:         c.Context(),
 			BaseUrl:     c.Url,
 			ResourceUrl: "projects",
 			SingularKey: "project",
@@ -239,6 +244,7 @@ func (c KeystoneV3) RoleAssignment() RoleAssignmentApi {
 	return RoleAssignmentApi{
 		ResourceApi: ResourceApi{
 			Client:      c.rawClient,
+			Ctx:         c.Context(),
 			BaseUrl:     c.Url,
 			ResourceUrl: "role_assignments",
 			SingularKey: "role_assignment",
@@ -0,0 +1,263 @@
+package internal
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/BytemanD/skyman/openstack/model"
+	"github.com/BytemanD/skyman/openstack/model/manila"
+	"github.com/BytemanD/skyman/openstack/session"
+)
+
+type ManilaV2 struct {
+	*ServiceClient
+	currentVersion *model.ApiVersion
+	MicroVersion   *model.ApiVersion
+}
+
+func (c *ManilaV2) GetCurrentVersion() (*model.ApiVersion, error) {
+	if c.currentVersion == nil {
+		result := struct{ Versions model.ApiVersions }{}
+		if resp, err := c.Index(nil); err != nil {
+			return nil, err
+		} else if err := resp.UnmarshalBody(&result); err != nil {
+			return nil, err
+		}
+		c.currentVersion = result.Versions.Current()
+	}
+	if c.currentVersion != nil {
+		return c.currentVersion, nil
+	}
+	return nil, fmt.Errorf("current version not found")
+}
+func (c *ManilaV2) MicroVersionLargeEqual(version string) bool {
+	if c.MicroVersion == nil {
+		return false
+	}
+	return getMicroVersion(c.MicroVersion.Version).LargeEqual(version)
+}
+func (c *ManilaV2) String() string {
+	return fmt.Sprintf("<SharedFileSystem: %s>", c.Url)
+}
+
+type ShareApi struct{ ResourceApi }
+type ShareSnapshotApi struct{ ResourceApi }
+type ShareNetworkApi struct{ ResourceApi }
+type ShareTypeApi struct{ ResourceApi }
+
+func (c ManilaV2) Share() ShareApi {
+	return ShareApi{
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl:  "shares",
+			SingularKey:  "share",
+			PluralKey:    "shares",
+			MicroVersion: c.MicroVersion,
+		},
+	}
+}
+
+func (c ManilaV2) Snapshot() ShareSnapshotApi {
+	return ShareSnapshotApi{
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl: "snapshots",
+			SingularKey: "snapshot",
+			PluralKey:   "snapshots",
+		},
+	}
+}
+func (c ManilaV2) ShareNetwork() ShareNetworkApi {
+	return ShareNetworkApi{
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl: "share-networks",
+			SingularKey: "share_network",
+			PluralKey:   "share_networks",
+		},
+	}
+}
+func (c ManilaV2) ShareType() ShareTypeApi {
+	return ShareTypeApi{
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl: "types",
+			SingularKey: "share_type",
+			PluralKey:   "share_types",
+		},
+	}
+}
+
+// share api
+func (c ShareApi) Detail(query url.Values) ([]manila.Share, error) {
+	return ListResource[manila.Share](c.ResourceApi, query, true)
+}
+func (c ShareApi) Iterator(query url.Values) *ResourceIterator[manila.Share] {
+	return NewResourceIterator[manila.Share](c.ResourceApi, "detail", query)
+}
+func (c ShareApi) Show(id string) (*manila.Share, error) {
+	return ShowResource[manila.Share](c.ResourceApi, id)
+}
+func (c ShareApi) Find(idOrName string) (*manila.Share, error) {
+	return FindResource(idOrName, c.Show, c.Detail)
+}
+func (c ShareApi) Create(params map[string]interface{}) (*manila.Share, error) {
+	return createResource[manila.Share](c.ResourceApi, params)
+}
+
+// 删除共享, force 为 true 时使用 force_delete, 可以删除 error 等状态的共享
+func (c ShareApi) Delete(id string, force bool) error {
+	if force {
+		return c.doAction(id, map[string]interface{}{c.actionName("force_delete"): nil})
+	}
+	_, err := DeleteResource(c.ResourceApi, id)
+	return err
+}
+func (c ShareApi) ExportLocations(id string) ([]manila.ExportLocation, error) {
+	result := struct {
+		ExportLocations []manila.ExportLocation `json:"export_locations"`
+	}{}
+	if _, err := c.R().SetResult(&result).Get(id, "export_locations"); err != nil {
+		return nil, err
+	}
+	return result.ExportLocations, nil
+}
+
+// 2.7 之前的 action 名字带有 os- 前缀
+func (c ShareApi) actionName(action string) string {
+	if c.MicroVersion != nil && !getMicroVersion(c.MicroVersion.Version).LargeEqual("2.7") {
+		return "os-" + action
+	}
+	return action
+}
+func (c ShareApi) doAction(id string, body interface{}) error {
+	_, err := c.R().SetBody(body).Post(id, "action")
+	return err
+}
+func (c ShareApi) Extend(id string, newSize int) error {
+	return c.doAction(id, map[string]interface{}{
+		c.actionName("extend"): map[string]int{"new_size": newSize},
+	})
+}
+func (c ShareApi) ResetStatus(id string, status string) error {
+	return c.doAction(id, map[string]interface{}{
+		c.actionName("reset_status"): map[string]string{"status": status},
+	})
+}
+
+// 授权访问共享, accessType 例如 ip, user, cert, cephx; accessLevel 为 rw 或 ro
+func (c ShareApi) AllowAccess(id string, accessType, accessTo, accessLevel string) (*manila.AccessRule, error) {
+	result := struct {
+		Access manila.AccessRule `json:"access"`
+	}{}
+	body := map[string]interface{}{
+		c.actionName("allow_access"): map[string]string{
+			"access_type": accessType, "access_to": accessTo, "access_level": accessLevel,
+		},
+	}
+	if _, err := c.R().SetBody(body).SetResult(&result).Post(id, "action"); err != nil {
+		return nil, err
+	}
+	return &result.Access, nil
+}
+func (c ShareApi) DenyAccess(id string, accessId string) error {
+	return c.doAction(id, map[string]interface{}{
+		c.actionName("deny_access"): map[string]string{"access_id": accessId},
+	})
+}
+
+// 查询共享的访问规则, 2.45 及以上版本使用 share-access-rules 接口
+func (c ShareApi) AccessRules(id string) ([]manila.AccessRule, error) {
+	result := struct {
+		AccessList []manila.AccessRule `json:"access_list"`
+	}{}
+	if c.MicroVersion != nil && getMicroVersion(c.MicroVersion.Version).LargeEqual("2.45") {
+		_, err := c.R().ResetPath().SetQuery(url.Values{"share_id": []string{id}}).
+			SetResult(&result).Get("share-access-rules")
+		return result.AccessList, err
+	}
+	body := map[string]interface{}{c.actionName("access_list"): nil}
+	if _, err := c.R().SetBody(body).SetResult(&result).Post(id, "action"); err != nil {
+		return nil, err
+	}
+	return result.AccessList, nil
+}
+
+// snapshot api
+func (c ShareSnapshotApi) Detail(query url.Values) ([]manila.Snapshot, error) {
+	return ListResource[manila.Snapshot](c.ResourceApi, query, true)
+}
+func (c ShareSnapshotApi) Iterator(query url.Values) *ResourceIterator[manila.Snapshot] {
+	return NewResourceIterator[manila.Snapshot](c.ResourceApi, "detail", query)
+}
+func (c ShareSnapshotApi) Show(id string) (*manila.Snapshot, error) {
+	return ShowResource[manila.Snapshot](c.ResourceApi, id)
+}
+func (c ShareSnapshotApi) Find(idOrName string) (*manila.Snapshot, error) {
+	return FindResource(idOrName, c.Show, c.Detail)
+}
+func (c ShareSnapshotApi) Create(params map[string]interface{}) (*manila.Snapshot, error) {
+	return createResource[manila.Snapshot](c.ResourceApi, params)
+}
+func (c ShareSnapshotApi) Delete(id string) error {
+	_, err := DeleteResource(c.ResourceApi, id)
+	return err
+}
+
+// share network api
+func (c ShareNetworkApi) Detail(query url.Values) ([]manila.ShareNetwork, error) {
+	return ListResource[manila.ShareNetwork](c.ResourceApi, query, true)
+}
+func (c ShareNetworkApi) Iterator(query url.Values) *ResourceIterator[manila.ShareNetwork] {
+	return NewResourceIterator[manila.ShareNetwork](c.ResourceApi, "detail", query)
+}
+func (c ShareNetworkApi) Show(id string) (*manila.ShareNetwork, error) {
+	return ShowResource[manila.ShareNetwork](c.ResourceApi, id)
+}
+func (c ShareNetworkApi) Find(idOrName string) (*manila.ShareNetwork, error) {
+	return FindResource(idOrName, c.Show, c.Detail)
+}
+func (c ShareNetworkApi) Create(params map[string]interface{}) (*manila.ShareNetwork, error) {
+	return createResource[manila.ShareNetwork](c.ResourceApi, params)
+}
+func (c ShareNetworkApi) Delete(id string) error {
+	_, err := DeleteResource(c.ResourceApi, id)
+	return err
+}
+
+// share type api
+func (c ShareTypeApi) List(query url.Values) ([]manila.ShareType, error) {
+	return ListResource[manila.ShareType](c.ResourceApi, query)
+}
+func (c ShareTypeApi) Show(id string) (*manila.ShareType, error) {
+	return ShowResource[manila.ShareType](c.ResourceApi, id)
+}
+
+// 共享类型的列表接口不支持按名字过滤
+func (c ShareTypeApi) Find(idOrName string) (*manila.ShareType, error) {
+	shareType, err := c.Show(idOrName)
+	if err == nil || !session.IsNotFound(err) {
+		return shareType, err
+	}
+	shareTypes, err := c.List(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, shareType := range shareTypes {
+		if shareType.Name == idOrName {
+			return &shareType, nil
+		}
+	}
+	return nil, fmt.Errorf("share type %s %w", idOrName, session.ErrNotFound)
+}
+
+// 创建共享类型, dhss 为 driver_handles_share_servers 的值
+func (c ShareTypeApi) Create(name string, dhss bool, extraSpecs map[string]string, isPublic bool) (*manila.ShareType, error) {
+	specs := map[string]interface{}{"driver_handles_share_servers": dhss}
+	for k, v := range extraSpecs {
+		specs[k] = v
+	}
+	return createResource[manila.ShareType](c.ResourceApi, map[string]interface{}{
+		"name": name, "extra_specs": specs, "share_type_access:is_public": isPublic,
+	})
+}
+func (c ShareTypeApi) Delete(id string) error {
+	_, err := DeleteResource(c.ResourceApi, id)
+	return err
+}
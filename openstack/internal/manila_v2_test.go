@@ -0,0 +1,44 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/BytemanD/skyman/openstack/model"
+)
+
+func TestShareAccessRules(t *testing.T) {
+	requests := []string{}
+	accessList := func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, fmt.Sprintf("%s %s %s", r.Method, r.URL.RequestURI(), body))
+		fmt.Fprint(w, `{"access_list": [{"id": "rule1", "access_type": "ip", "access_to": "10.0.0.0/24"}]}`)
+	}
+	routes := testRoutes{
+		"POST /v2/shares/share1/action": accessList,
+		"GET /v2/share-access-rules":    accessList,
+	}
+	client := ManilaV2{ServiceClient: newTestServiceClient(t, "manila", "/v2", routes)}
+
+	expects := map[string]string{
+		"2.6":  `POST /v2/shares/share1/action {"os-access_list":null}`,
+		"2.44": `POST /v2/shares/share1/action {"access_list":null}`,
+		"2.45": `GET /v2/share-access-rules?share_id=share1 `,
+	}
+	for version, expect := range expects {
+		requests = []string{}
+		client.MicroVersion = &model.ApiVersion{Version: version}
+		rules, err := client.Share().AccessRules("share1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rules) != 1 || rules[0].Id != "rule1" {
+			t.Errorf("version %s: expect rule1, but got %v", version, rules)
+		}
+		if len(requests) != 1 || requests[0] != expect {
+			t.Errorf("version %s: expect request '%s', but got %v", version, expect, requests)
+		}
+	}
+}
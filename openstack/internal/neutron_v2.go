@@ -51,10 +51,11 @@ type sgApi struct{ ResourceApi }
 type sgRuleApi struct{ ResourceApi }
 type qosPolicyApi struct{ ResourceApi }
 type qosRuleApi struct{ ResourceApi }
+type ExtensionApi struct{ ResourceApi }
 
 func (c NeutronV2) Router() routerApi {
 	return routerApi{
-		ResourceApi{Client: c.rawClient, BaseUrl: c.Url,
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
 			ResourceUrl: "routers",
 			SingularKey: "router",
 			PluralKey:   "routers",
@@ -63,7 +64,7 @@ func (c NeutronV2) Router() routerApi {
 }
 func (c NeutronV2) Network() NetworkApi {
 	return NetworkApi{
-		ResourceApi{Client: c.rawClient, BaseUrl: c.Url,
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
 			ResourceUrl: "networks",
 			SingularKey: "network",
 			PluralKey:   "networks",
@@ -73,7 +74,7 @@ func (c NeutronV2) Network() NetworkApi {
 func (c NeutronV2) Subnet() SubnetApi {
 	return SubnetApi{
 		ResourceApi{
-			Client: c.rawClient, BaseUrl: c.Url,
+			Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
 			ResourceUrl: "subnets",
 			SingularKey: "subnet",
 			PluralKey:   "subnets",
@@ -82,7 +83,7 @@ func (c NeutronV2) Subnet() SubnetApi {
 }
 func (c NeutronV2) Port() PortApi {
 	return PortApi{
-		ResourceApi{Client: c.rawClient, BaseUrl: c.Url,
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
 			ResourceUrl: "ports",
 			SingularKey: "port",
 			PluralKey:   "ports",
@@ -91,7 +92,7 @@ func (c NeutronV2) Port() PortApi {
 }
 func (c NeutronV2) Agent() agentApi {
 	return agentApi{
-		ResourceApi{Client: c.rawClient, BaseUrl: c.Url,
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
 			ResourceUrl: "agents",
 			SingularKey: "agent",
 			PluralKey:   "agents",
@@ -101,7 +102,7 @@ func (c NeutronV2) Agent() agentApi {
 func (c NeutronV2) SecurityGroupRule() sgRuleApi {
 	return sgRuleApi{
 		ResourceApi{
-			Client: c.rawClient, BaseUrl: c.Url,
+			Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
 			ResourceUrl: "security-group-rules",
 			SingularKey: "security_group_rule",
 			PluralKey:   "security_group_rules",
@@ -111,7 +112,7 @@ func (c NeutronV2) SecurityGroupRule() sgRuleApi {
 func (c NeutronV2) SecurityGroup() sgApi {
 	return sgApi{
 		ResourceApi{
-			Client: c.rawClient, BaseUrl: c.Url,
+			Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
 			ResourceUrl: "security-groups",
 			SingularKey: "security_group",
 			PluralKey:   "security_groups"},
@@ -121,6 +122,7 @@ func (c NeutronV2) QosPolicy() qosPolicyApi {
 	return qosPolicyApi{
 		ResourceApi{
 			Client:      c.rawClient,
+			Ctx:         c.Context(),
 			BaseUrl:     c.Url,
 			ResourceUrl: "qos/policies",
 			SingularKey: "policies",
@@ -132,6 +134,7 @@ func (c NeutronV2) QosRule() qosRuleApi {
 	return qosRuleApi{
 		ResourceApi: ResourceApi{
 			Client:      c.rawClient,
+			Ctx:         c.Context(),
 			BaseUrl:     c.Url,
 			ResourceUrl: "qos/policies",
 			SingularKey: "policies",
@@ -139,12 +142,27 @@ func (c NeutronV2) QosRule() qosRuleApi {
 		},
 	}
 }
+func (c NeutronV2) Extension() ExtensionApi {
+	return ExtensionApi{
+		ResourceApi{
+			Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl: "extensions",
+			SingularKey: "extension",
+			PluralKey:   "extensions",
+		},
+	}
+}
 
 // router api
 
 func (c routerApi) List(query url.Values) ([]neutron.Router, error) {
 	return ListResource[neutron.Router](c.ResourceApi, query)
 }
+
+// 逐页获取路由
+func (c routerApi) Iterator(query url.Values) *ResourceIterator[neutron.Router] {
+	return NewResourceIterator[neutron.Router](c.ResourceApi, "", query)
+}
 func (c routerApi) ListByName(name string) ([]neutron.Router, error) {
 	return c.List(url.Values{"name": []string{name}})
 }
@@ -201,11 +219,12 @@ func (c routerApi) RemovePort(routerId, portId string) error {
 // network api
 
 func (c NetworkApi) List(query url.Values) ([]neutron.Network, error) {
-	body := struct{ Networks []neutron.Network }{}
-	if _, err := c.Get(NETWORKS, query, &body); err != nil {
-		return nil, err
-	}
-	return body.Networks, nil
+	return ListResource[neutron.Network](c.ResourceApi, query)
+}
+
+// 逐页获取网络
+func (c NetworkApi) Iterator(query url.Values) *ResourceIterator[neutron.Network] {
+	return NewResourceIterator[neutron.Network](c.ResourceApi, "", query)
 }
 func (c NetworkApi) ListByName(name string) ([]neutron.Network, error) {
 	return c.List(url.Values{"name": []string{name}})
@@ -235,6 +254,11 @@ func (c NetworkApi) Delete(id string) error {
 func (c SubnetApi) List(query url.Values) ([]neutron.Subnet, error) {
 	return ListResource[neutron.Subnet](c.ResourceApi, query)
 }
+
+// 逐页获取子网
+func (c SubnetApi) Iterator(query url.Values) *ResourceIterator[neutron.Subnet] {
+	return NewResourceIterator[neutron.Subnet](c.ResourceApi, "", query)
+}
 func (c SubnetApi) ListByName(name string) ([]neutron.Subnet, error) {
 	return c.List(url.Values{"name": []string{name}})
 }
@@ -263,6 +287,11 @@ func (c SubnetApi) Delete(id string) error {
 func (c PortApi) List(query url.Values) ([]neutron.Port, error) {
 	return ListResource[neutron.Port](c.ResourceApi, query)
 }
+
+// 逐页获取端口
+func (c PortApi) Iterator(query url.Values) *ResourceIterator[neutron.Port] {
+	return NewResourceIterator[neutron.Port](c.ResourceApi, "", query)
+}
 func (c PortApi) ListByName(name string) ([]neutron.Port, error) {
 	return c.List(url.Values{"name": []string{name}})
 }
@@ -304,12 +333,20 @@ func (c PortApi) Find(idOrName string) (*neutron.Port, error) {
 func (c agentApi) List(query url.Values) ([]neutron.Agent, error) {
 	return ListResource[neutron.Agent](c.ResourceApi, query)
 }
+func (c agentApi) Iterator(query url.Values) *ResourceIterator[neutron.Agent] {
+	return NewResourceIterator[neutron.Agent](c.ResourceApi, "", query)
+}
 
 // security group api
 
 func (c sgApi) List(query url.Values) ([]neutron.SecurityGroup, error) {
 	return ListResource[neutron.SecurityGroup](c.ResourceApi, query)
 }
+
+// 逐页获取安全组
+func (c sgApi) Iterator(query url.Values) *ResourceIterator[neutron.SecurityGroup] {
+	return NewResourceIterator[neutron.SecurityGroup](c.ResourceApi, "", query)
+}
 func (c sgApi) List2(query url.Values) result.ItemsResult[neutron.SecurityGroup] {
 	r := result.NewItemsResult[neutron.SecurityGroup](
 		c.R().SetQuery(query).Get(),
@@ -330,6 +367,9 @@ func (c sgApi) Find(idOrName string) (*neutron.SecurityGroup, error) {
 func (c sgRuleApi) List(query url.Values) ([]neutron.SecurityGroupRule, error) {
 	return ListResource[neutron.SecurityGroupRule](c.ResourceApi, query)
 }
+func (c sgRuleApi) Iterator(query url.Values) *ResourceIterator[neutron.SecurityGroupRule] {
+	return NewResourceIterator[neutron.SecurityGroupRule](c.ResourceApi, "", query)
+}
 func (c sgRuleApi) Show(id string) (*neutron.SecurityGroupRule, error) {
 	return ShowResource[neutron.SecurityGroupRule](c.ResourceApi, id)
 }
@@ -339,9 +379,21 @@ func (c sgRuleApi) Show(id string) (*neutron.SecurityGroupRule, error) {
 func (c qosPolicyApi) List(query url.Values) ([]neutron.QosPolicy, error) {
 	return ListResource[neutron.QosPolicy](c.ResourceApi, query)
 }
+func (c qosPolicyApi) Iterator(query url.Values) *ResourceIterator[neutron.QosPolicy] {
+	return NewResourceIterator[neutron.QosPolicy](c.ResourceApi, "", query)
+}
 func (c qosPolicyApi) Show(id string) (*neutron.QosPolicy, error) {
 	return ShowResource[neutron.QosPolicy](c.ResourceApi, id)
 }
 func (c qosPolicyApi) Find(idOrName string) (*neutron.QosPolicy, error) {
 	return FindResource(idOrName, c.Show, c.List)
 }
+
+// extension api
+
+func (c ExtensionApi) List(query url.Values) ([]neutron.Extension, error) {
+	return ListResource[neutron.Extension](c.ResourceApi, query)
+}
+func (c ExtensionApi) Show(alias string) (*neutron.Extension, error) {
+	return ShowResource[neutron.Extension](c.ResourceApi, alias)
+}
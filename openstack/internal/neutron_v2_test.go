@@ -0,0 +1,16 @@
+package internal
+
+import "testing"
+
+func TestNetworkListByName(t *testing.T) {
+	client := NeutronV2{
+		ServiceClient: newReplayServiceClient(t, "neutron_network_list", "http://neutron:9696", "v2.0"),
+	}
+	networks, err := client.Network().ListByName("net1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(networks) != 1 || networks[0].Name != "net1" {
+		t.Errorf("expect network net1, but got %v", networks)
+	}
+}
@@ -10,7 +10,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/BytemanD/easygo/pkg/compare"
 	"github.com/BytemanD/easygo/pkg/stringutils"
 	"github.com/BytemanD/go-console/console"
 	"github.com/BytemanD/skyman/openstack/model"
@@ -28,6 +27,9 @@ const (
 	URL_INTERFACE_DETACH    = "servers/%s/os-interface/%s"
 )
 
+// nova 每页的默认最大数量, 对应 nova 的 max_limit
+const NOVA_PAGE_LIMIT = 1000
+
 var COMPUTE_API_VERSION string
 
 type microVersion struct {
@@ -109,6 +111,7 @@ func (c NovaV2) Server() ServerApi {
 	return ServerApi{
 		ResourceApi{
 			Client:       c.rawClient,
+			Ctx:          c.Context(),
 			BaseUrl:      c.Url,
 			MicroVersion: c.MicroVersion,
 			ResourceUrl:  "servers",
@@ -121,6 +124,7 @@ func (c NovaV2) Flavor() FlavorApi {
 	return FlavorApi{
 		ResourceApi{
 			Client:       c.rawClient,
+			Ctx:          c.Context(),
 			BaseUrl:      c.Url,
 			MicroVersion: c.MicroVersion,
 			ResourceUrl:  "flavors",
@@ -133,6 +137,7 @@ func (c NovaV2) Service() ComputeServiceApi {
 	return ComputeServiceApi{
 		ResourceApi{
 			Client:       c.rawClient,
+			Ctx:          c.Context(),
 			BaseUrl:      c.Url,
 			MicroVersion: c.MicroVersion,
 			ResourceUrl:  "os-services",
@@ -145,6 +150,7 @@ func (c NovaV2) Keypair() KeypairApi {
 	return KeypairApi{
 		ResourceApi{
 			Client:       c.rawClient,
+			Ctx:          c.Context(),
 			BaseUrl:      c.Url,
 			MicroVersion: c.MicroVersion,
 			ResourceUrl:  "os-keypairs",
@@ -157,6 +163,7 @@ func (c NovaV2) Hypervisor() HypervisorApi {
 	return HypervisorApi{
 		ResourceApi{
 			Client:       c.rawClient,
+			Ctx:          c.Context(),
 			BaseUrl:      c.Url,
 			MicroVersion: c.MicroVersion,
 			ResourceUrl:  "os-hypervisors",
@@ -170,6 +177,7 @@ func (c NovaV2) Aggregate() AggregateApi {
 	return AggregateApi{
 		ResourceApi{
 			Client:       c.rawClient,
+			Ctx:          c.Context(),
 			BaseUrl:      c.Url,
 			MicroVersion: c.MicroVersion,
 			ResourceUrl:  "os-aggregates",
@@ -182,6 +190,7 @@ func (c NovaV2) AZ() AZApi {
 	return AZApi{
 		ResourceApi{
 			Client:       c.rawClient,
+			Ctx:          c.Context(),
 			BaseUrl:      c.Url,
 			MicroVersion: c.MicroVersion,
 			ResourceUrl:  "os-availability-zone",
@@ -193,6 +202,7 @@ func (c NovaV2) Migraion() MigrationApi {
 	return MigrationApi{
 		ResourceApi{
 			Client:       c.rawClient,
+			Ctx:          c.Context(),
 			BaseUrl:      c.Url,
 			MicroVersion: c.MicroVersion,
 			ResourceUrl:  "os-migrations",
@@ -206,6 +216,7 @@ func (c NovaV2) ServerGroup() ServerGroupApi {
 	return ServerGroupApi{
 		ResourceApi{
 			Client:       c.rawClient,
+			Ctx:          c.Context(),
 			BaseUrl:      c.Url,
 			MicroVersion: c.MicroVersion,
 			ResourceUrl:  "os-server-groups",
@@ -218,6 +229,7 @@ func (c NovaV2) Quota() ComputeQuotaApi {
 	return ComputeQuotaApi{
 		ResourceApi{
 			Client:       c.rawClient,
+			Ctx:          c.Context(),
 			BaseUrl:      c.Url,
 			MicroVersion: c.MicroVersion,
 			ResourceUrl:  "os-quota-sets",
@@ -230,6 +242,7 @@ func (c NovaV2) Migration() MigrationApi {
 	return MigrationApi{
 		ResourceApi{
 			Client:       c.rawClient,
+			Ctx:          c.Context(),
 			BaseUrl:      c.Url,
 			MicroVersion: c.MicroVersion,
 			ResourceUrl:  "os-migrations",
@@ -251,6 +264,11 @@ func (c ServerApi) DetailByName(name string) ([]nova.Server, error) {
 	return c.Detail(url.Values{"name": []string{name}})
 }
 
+// 逐页获取虚拟机详情
+func (c ServerApi) Iterator(query url.Values) *ResourceIterator[nova.Server] {
+	return NewResourceIterator[nova.Server](c.ResourceApi, "detail", query)
+}
+
 func (c ServerApi) Show(id string) (*nova.Server, error) {
 	return ShowResource[nova.Server](c.ResourceApi, id)
 }
@@ -275,12 +293,14 @@ func (c ServerApi) Create(options nova.ServerOpt) (*nova.Server, error) {
 	}
 	var err error
 	body := struct{ Server nova.Server }{}
+	reqBody := map[string]interface{}{"server": options}
+	if len(options.SchedulerHints) > 0 {
+		reqBody["os:scheduler_hints"] = options.SchedulerHints
+	}
 	if options.BlockDeviceMappingV2 != nil {
-		_, err = c.R().SetBody(map[string]nova.ServerOpt{"server": options}).
-			SetResult(&body).ResetPath().Post(URL_SERVER_VOLUMES_BOOT)
+		_, err = c.R().SetBody(reqBody).SetResult(&body).ResetPath().Post(URL_SERVER_VOLUMES_BOOT)
 	} else {
-		_, err = c.R().SetBody(map[string]nova.ServerOpt{"server": options}).
-			SetResult(&body).Post()
+		_, err = c.R().SetBody(reqBody).SetResult(&body).Post()
 	}
 	if err != nil {
 		return nil, err
@@ -337,7 +357,9 @@ func (c ServerApi) DeleteVolumeAndWait(id string, volumeId string, waitSeconds i
 		if time.Since(startTime) >= time.Second*time.Duration(waitSeconds) {
 			return fmt.Errorf("interface %s is not detached after %d seconds", volumeId, waitSeconds)
 		}
-		time.Sleep(time.Second * 2)
+		if err := c.sleep(time.Second*2, "wait volume %s detached from server %s", volumeId, id); err != nil {
+			return err
+		}
 	}
 }
 func (c ServerApi) ListInterfaces(id string) ([]nova.InterfaceAttachment, error) {
@@ -378,11 +400,12 @@ func (c ServerApi) DeleteInterfaceAndWait(id string, portId string, timeout time
 	reqId := resp.RequestId()
 	console.Info("[%s] detaching interface %s, request id: %s", id, portId, reqId)
 
-	return utility.RetryWithErrors(
+	err = utility.RetryWithErrors(
 		utility.RetryCondition{
+			Ctx:         c.Context(),
 			Timeout:     timeout,
 			IntervalMin: time.Second * 2},
-		[]string{"ActionNotFinishedError"},
+		[]error{utility.ErrActionNotFinished},
 		func() error {
 			action, err := c.ShowAction(id, reqId)
 			if err != nil {
@@ -399,6 +422,10 @@ func (c ServerApi) DeleteInterfaceAndWait(id string, portId string, timeout time
 			}
 		},
 	)
+	if interrupted := session.Interrupted(c.Context(), "wait interface %s detached from server %s", portId, id); interrupted != nil {
+		return interrupted
+	}
+	return err
 }
 func (c ServerApi) doAction(action string, id string, params interface{}, result ...interface{}) (*session.Response, error) {
 	req := c.R().SetBody(map[string]interface{}{action: params})
@@ -473,7 +500,7 @@ func (c ServerApi) Evacuate(id string, password string, host string, force bool)
 		data["password"] = password
 	}
 	if host != "" {
-		data["host"] = password
+		data["host"] = host
 	}
 	if force {
 		data["force"] = force
@@ -481,6 +508,116 @@ func (c ServerApi) Evacuate(id string, password string, host string, force bool)
 	_, err := c.doAction("evacuate", id, data)
 	return err
 }
+
+// 进入救援模式, 返回救援系统的管理员密码
+func (c ServerApi) Rescue(id string, password string, imageRef string) (string, error) {
+	data := map[string]interface{}{}
+	if password != "" {
+		data["adminPass"] = password
+	}
+	if imageRef != "" {
+		data["rescue_image_ref"] = imageRef
+	}
+	result := struct {
+		AdminPass string `json:"adminPass"`
+	}{}
+	if _, err := c.doAction("rescue", id, data, &result); err != nil {
+		return "", err
+	}
+	return result.AdminPass, nil
+}
+func (c ServerApi) Unrescue(id string) error {
+	_, err := c.doAction("unrescue", id, nil)
+	return err
+}
+
+// 锁定实例, 2.73 及以上版本支持指定原因
+func (c ServerApi) Lock(id string, reason string) error {
+	var data interface{}
+	if reason != "" {
+		if !c.MicroVersionLargeEqual("2.73") {
+			return fmt.Errorf("locked reason requires microversion 2.73 or later")
+		}
+		data = map[string]string{"locked_reason": reason}
+	}
+	_, err := c.doAction("lock", id, data)
+	return err
+}
+func (c ServerApi) Unlock(id string) error {
+	_, err := c.doAction("unlock", id, nil)
+	return err
+}
+
+// 触发实例的 crash dump (NMI) (2.17+)
+func (c ServerApi) TriggerCrashDump(id string) error {
+	_, err := c.doAction("trigger_crash_dump", id, nil)
+	return err
+}
+
+// 查询实例的加密密码, 密码由 metadata 服务写入, 需要使用密钥对的私钥解密
+func (c ServerApi) GetPassword(id string) (string, error) {
+	result := struct {
+		Password string `json:"password"`
+	}{}
+	if _, err := c.R().SetResult(&result).Get(id, "os-server-password"); err != nil {
+		return "", err
+	}
+	return result.Password, nil
+}
+func (c ServerApi) ClearPassword(id string) error {
+	_, err := c.R().Delete(id, "os-server-password")
+	return err
+}
+
+// server metadata
+func (c ServerApi) ListMetadata(id string) (map[string]string, error) {
+	result := struct {
+		Metadata map[string]string `json:"metadata"`
+	}{}
+	if _, err := c.R().SetResult(&result).Get(id, "metadata"); err != nil {
+		return nil, err
+	}
+	return result.Metadata, nil
+}
+
+// 添加或更新 metadata, 不影响其他的 key
+func (c ServerApi) SetMetadata(id string, metadata map[string]string) (map[string]string, error) {
+	result := struct {
+		Metadata map[string]string `json:"metadata"`
+	}{}
+	_, err := c.R().SetBody(map[string]interface{}{"metadata": metadata}).SetResult(&result).Post(id, "metadata")
+	if err != nil {
+		return nil, err
+	}
+	return result.Metadata, nil
+}
+func (c ServerApi) DeleteMetadata(id string, key string) error {
+	_, err := c.R().Delete(id, "metadata", key)
+	return err
+}
+
+// server tags (2.26+)
+func (c ServerApi) ListTags(id string) ([]string, error) {
+	result := struct {
+		Tags []string `json:"tags"`
+	}{}
+	if _, err := c.R().SetResult(&result).Get(id, "tags"); err != nil {
+		return nil, err
+	}
+	return result.Tags, nil
+}
+func (c ServerApi) AddTag(id string, tag string) error {
+	_, err := c.R().Put(id, "tags", tag)
+	return err
+}
+func (c ServerApi) DeleteTag(id string, tag string) error {
+	_, err := c.R().Delete(id, "tags", tag)
+	return err
+}
+func (c ServerApi) ClearTags(id string) error {
+	_, err := c.R().Delete(id, "tags")
+	return err
+}
 func (c ServerApi) SetPassword(id string, password, user string) error {
 	data := map[string]interface{}{
 		"adminPass": password,
@@ -547,13 +684,39 @@ func (c ServerApi) getRemoteConsole(id string, protocol string, consoleType stri
 	return &result.RemoteConsole, nil
 }
 
+// 控制台类型对应的协议
+var consoleProtocols = map[string]string{
+	"novnc": "vnc", "xvpvnc": "vnc",
+	"spice-html5": "spice", "rdp-html5": "rdp",
+	"serial": "serial", "webmks": "mks",
+}
+
 func (c ServerApi) ConsoleUrl(id string, consoleType string) (*nova.Console, error) {
+	if consoleType == "serial" {
+		return c.SerialConsoleUrl(id)
+	}
 	if c.MicroVersionLargeEqual("2.6") {
-		// TODO: do not set "vnc" directly
-		return c.getRemoteConsole(id, "vnc", consoleType)
+		protocol, ok := consoleProtocols[consoleType]
+		if !ok {
+			protocol = "vnc"
+		}
+		return c.getRemoteConsole(id, protocol, consoleType)
 	}
 	return c.getVNCConsole(id, consoleType)
 }
+
+// 串口控制台的 websocket 地址
+func (c ServerApi) SerialConsoleUrl(id string) (*nova.Console, error) {
+	if c.MicroVersionLargeEqual("2.6") {
+		return c.getRemoteConsole(id, "serial", "serial")
+	}
+	result := map[string]*nova.Console{"console": {}}
+	_, err := c.doAction("os-getSerialConsole", id, map[string]interface{}{"type": "serial"}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result["console"], nil
+}
 func (c ServerApi) Migrate(id string, host string) error {
 	data := map[string]interface{}{}
 	if host != "" {
@@ -641,6 +804,28 @@ func (c ServerApi) ListMigrations(id string, query url.Values) ([]nova.Migration
 	return result.Migrations, nil
 
 }
+
+// 查询正在进行的热迁移详情, 包括内存和磁盘的迁移进度 (2.23+)
+func (c ServerApi) ShowMigration(id string, migrationId int) (*nova.Migration, error) {
+	result := struct{ Migration nova.Migration }{}
+	if _, err := c.R().SetResult(&result).Get(id, "migrations", strconv.Itoa(migrationId)); err != nil {
+		return nil, err
+	}
+	return &result.Migration, nil
+}
+
+// 取消正在进行的热迁移 (2.24+)
+func (c ServerApi) AbortMigration(id string, migrationId int) error {
+	_, err := c.R().Delete(id, "migrations", strconv.Itoa(migrationId))
+	return err
+}
+
+// 强制完成正在进行的热迁移, 虚拟机会被暂停直到迁移完成 (2.22+)
+func (c ServerApi) ForceCompleteMigration(id string, migrationId int) error {
+	_, err := c.R().SetBody(map[string]interface{}{"force_complete": nil}).
+		Post(id, "migrations", strconv.Itoa(migrationId), "action")
+	return err
+}
 func (c ServerApi) RegionLiveMigrate(id string, destRegion string, blockMigrate bool, dryRun bool, destHost string) (*nova.RegionMigrateResp, error) {
 	data := map[string]interface{}{
 		"region":          destRegion,
@@ -680,6 +865,7 @@ func (c ServerApi) WaitStatus(serverId string, status string, interval int) (*no
 	)
 	utility.Retry(
 		utility.RetryCondition{
+			Ctx:     c.Context(),
 			Timeout: time.Second * 60 * 10, IntervalMin: time.Second * time.Duration(interval),
 		},
 		func() bool {
@@ -699,6 +885,9 @@ func (c ServerApi) WaitStatus(serverId string, status string, interval int) (*no
 
 		},
 	)
+	if interrupted := session.Interrupted(c.Context(), "wait server %s %s", serverId, status); interrupted != nil {
+		return server, interrupted
+	}
 	return server, err
 }
 func (c ServerApi) WaitBooted(id string) (*nova.Server, error) {
@@ -714,7 +903,9 @@ func (c ServerApi) WaitBooted(id string) (*nova.Server, error) {
 		if server.IsActive() && server.Host != "" {
 			return server, nil
 		}
-		time.Sleep(time.Second * 2)
+		if err := c.sleep(time.Second*2, "wait server %s booted", id); err != nil {
+			return server, err
+		}
 	}
 }
 func (c ServerApi) WaitDeleted(id string) error {
@@ -722,11 +913,9 @@ func (c ServerApi) WaitDeleted(id string) error {
 		server *nova.Server
 		err    error
 	)
-	cxt := context.TODO()
-	cxt.Done()
-
 	utility.Retry(
 		utility.RetryCondition{
+			Ctx:         c.Context(),
 			Timeout:     time.Second * 60 * 10,
 			IntervalMin: time.Second * time.Duration(2)},
 		func() bool {
@@ -735,17 +924,17 @@ func (c ServerApi) WaitDeleted(id string) error {
 				console.Info("[%s] %s", id, server.AllStatus())
 				return true
 			}
-			if compare.IsType[session.HttpError](err) {
-				httpError, _ := err.(session.HttpError)
-				if httpError.IsNotFound() {
-					console.Info("[%s] deleted", id)
-					err = nil
-					return false
-				}
+			if session.IsNotFound(err) {
+				console.Info("[%s] deleted", id)
+				err = nil
+				return false
 			}
 			return false
 		},
 	)
+	if interrupted := session.Interrupted(c.Context(), "wait server %s deleted", id); interrupted != nil {
+		return interrupted
+	}
 	return err
 }
 func (c ServerApi) WaitTask(id string, taskState string) (*nova.Server, error) {
@@ -762,7 +951,9 @@ func (c ServerApi) WaitTask(id string, taskState string) (*nova.Server, error) {
 		if strings.EqualFold(server.TaskState, strings.ToUpper(taskState)) {
 			return server, nil
 		}
-		time.Sleep(time.Second * 2)
+		if err := c.sleep(time.Second*2, "wait server %s task finished", id); err != nil {
+			return nil, err
+		}
 	}
 }
 func (c ServerApi) WaitResized(id string, newFlavorName string) (*nova.Server, error) {
@@ -780,11 +971,12 @@ func (c ServerApi) StopAndWait(id string) error {
 	if err := c.Stop(id); err != nil {
 		return err
 	}
-	return utility.RetryWithErrors(
+	err := utility.RetryWithErrors(
 		utility.RetryCondition{
+			Ctx:         c.Context(),
 			Timeout:     time.Minute * 30,
 			IntervalMin: time.Second * 2},
-		[]string{"ServerNotStopped"},
+		[]error{utility.ErrServerNotStopped},
 		func() error {
 			server, err := c.Show(id)
 			if err != nil {
@@ -796,6 +988,10 @@ func (c ServerApi) StopAndWait(id string) error {
 			return utility.NewServerNotStopped(id)
 		},
 	)
+	if interrupted := session.Interrupted(c.Context(), "wait server %s stopped", id); interrupted != nil {
+		return interrupted
+	}
+	return err
 }
 func (c ServerApi) WaitRebooted(id string, newFlavorName string) (*nova.Server, error) {
 	server, err := c.WaitTask(id, "")
@@ -827,6 +1023,11 @@ func (c FlavorApi) List(query url.Values) ([]nova.Flavor, error) {
 func (c FlavorApi) Detail(query url.Values) ([]nova.Flavor, error) {
 	return ListResource[nova.Flavor](c.ResourceApi, query, true)
 }
+
+// 逐页获取规格详情
+func (c FlavorApi) Iterator(query url.Values) *ResourceIterator[nova.Flavor] {
+	return NewResourceIterator[nova.Flavor](c.ResourceApi, "detail", query)
+}
 func (c FlavorApi) Show(id string) (*nova.Flavor, error) {
 	return ShowResource[nova.Flavor](c.ResourceApi, id)
 }
@@ -941,6 +1142,11 @@ func (c HypervisorApi) List(query url.Values) ([]nova.Hypervisor, error) {
 func (c HypervisorApi) Detail(query url.Values) ([]nova.Hypervisor, error) {
 	return ListResource[nova.Hypervisor](c.ResourceApi, query, true)
 }
+
+// 逐页获取 hypervisor 详情
+func (c HypervisorApi) Iterator(query url.Values) *ResourceIterator[nova.Hypervisor] {
+	return NewResourceIterator[nova.Hypervisor](c.ResourceApi, "detail", query)
+}
 func (c HypervisorApi) ListByName(hostname string) ([]nova.Hypervisor, error) {
 	return c.List(url.Values{"hypervisor_hostname_pattern": []string{hostname}})
 }
@@ -963,7 +1169,7 @@ func (c HypervisorApi) ShowByHostname(hostname string) (*nova.Hypervisor, error)
 		return nil, err
 	}
 	if len(hypervisors) == 0 {
-		return nil, fmt.Errorf("hypervisor %s not found", hostname)
+		return nil, fmt.Errorf("hypervisor %s %w", hostname, session.ErrNotFound)
 	}
 	return c.Show(hypervisors[0].Id)
 }
@@ -1001,6 +1207,40 @@ func (c HypervisorApi) FlavorCapacities(query url.Values) (*nova.FlavorCapacitie
 func (c KeypairApi) List(query url.Values) ([]nova.Keypair, error) {
 	return ListResource[nova.Keypair](c.ResourceApi, query)
 }
+func (c KeypairApi) Iterator(query url.Values) *ResourceIterator[nova.Keypair] {
+	return NewResourceIterator[nova.Keypair](c.ResourceApi, "", query)
+}
+
+// 密钥对的响应格式和列表中的元素一样, 都是 {"keypair": {...}}
+func (c KeypairApi) Show(name string) (*nova.Keypair, error) {
+	keypair := nova.Keypair{}
+	if _, err := c.R().SetResult(&keypair).Get(name); err != nil {
+		return nil, err
+	}
+	return &keypair, nil
+}
+
+// 导入公钥, publicKey 为空时由 nova 生成密钥对 (2.92 及以上版本不再支持), keyType 需要 2.2 及以上版本
+func (c KeypairApi) Create(name string, publicKey string, keyType string) (*nova.Keypair, error) {
+	params := map[string]interface{}{"name": name}
+	if publicKey != "" {
+		params["public_key"] = publicKey
+	} else if c.MicroVersionLargeEqual("2.92") {
+		return nil, fmt.Errorf("public key is required since microversion 2.92")
+	}
+	if keyType != "" && c.MicroVersionLargeEqual("2.2") {
+		params["type"] = keyType
+	}
+	keypair := nova.Keypair{}
+	if _, err := c.R().SetBody(ReqBody{c.SingularKey: params}).SetResult(&keypair).Post(); err != nil {
+		return nil, err
+	}
+	return &keypair, nil
+}
+func (c KeypairApi) Delete(name string) error {
+	_, err := DeleteResource(c.ResourceApi, name)
+	return err
+}
 
 // service api
 func (c ComputeServiceApi) List(query url.Values) ([]nova.Service, error) {
@@ -1016,7 +1256,7 @@ func (c ComputeServiceApi) GetByHostBinary(host string, binary string) (*nova.Se
 	}
 	switch len(services) {
 	case 0:
-		return nil, fmt.Errorf("service %s:%s not found", host, binary)
+		return nil, fmt.Errorf("service %s:%s %w", host, binary, session.ErrNotFound)
 	case 1:
 		return &services[0], nil
 	default:
@@ -1098,12 +1338,13 @@ func (c ComputeServiceApi) Disable(host string, binary string, reason string) (*
 		if reason != "" {
 			body["disabled_reason"] = reason
 		}
-		fmt.Println(body)
 		return c.update(service.Id, body)
 	}
-	err := c.doAction("disable", map[string]interface{}{
-		"host": host, "binary": binary, "status": "disabled",
-	})
+	action, body := "disable", map[string]interface{}{"host": host, "binary": binary, "status": "disabled"}
+	if reason != "" {
+		action, body["disabled_reason"] = "disable-log-reason", reason
+	}
+	err := c.doAction(action, body)
 	if err != nil {
 		return nil, err
 	} else {
@@ -1123,6 +1364,73 @@ func (c ComputeServiceApi) Delete(host string, binary string) error {
 func (c MigrationApi) List(query url.Values) ([]nova.Migration, error) {
 	return ListResource[nova.Migration](c.ResourceApi, query)
 }
+func (c MigrationApi) Iterator(query url.Values) *ResourceIterator[nova.Migration] {
+	return NewResourceIterator[nova.Migration](c.ResourceApi, "", query)
+}
+
+// 查询实例最新的迁移记录, 没有迁移记录时返回 nil
+func (c MigrationApi) Latest(serverId string, migrationType string) (*nova.Migration, error) {
+	query := url.Values{"instance_uuid": []string{serverId}}
+	if migrationType != "" {
+		query.Set("migration_type", migrationType)
+	}
+	migrations, err := c.List(query)
+	if err != nil {
+		return nil, err
+	}
+	var latest *nova.Migration
+	for i, migration := range migrations {
+		// 2.23 之前的迁移记录不包含 migration_type
+		if migration.MigrationType != "" && migrationType != "" && migration.MigrationType != migrationType {
+			continue
+		}
+		if latest == nil || migration.Id > latest.Id {
+			latest = &migrations[i]
+		}
+	}
+	return latest, nil
+}
+
+// 等待实例 id 大于 after 的迁移记录变成 statuses 中的状态, 迁移失败时返回错误
+func (c MigrationApi) Wait(ctx context.Context, serverId string, migrationType string, after int, statuses []string,
+	timeout time.Duration, interval int) (*nova.Migration, error) {
+	var (
+		migration *nova.Migration
+		err       error
+	)
+	c.ResourceApi = c.WithContext(ctx)
+	retryErr := utility.Retry(
+		utility.RetryCondition{
+			Ctx:     c.Context(),
+			Timeout: timeout, IntervalMin: time.Second * time.Duration(interval),
+		},
+		func() bool {
+			migration, err = c.Latest(serverId, migrationType)
+			if err != nil {
+				return false
+			}
+			if migration == nil || migration.Id <= after {
+				return true
+			}
+			console.Debug("[%s] %s %d status: %s", serverId, migrationType, migration.Id, migration.Status)
+			if stringutils.ContainsString(statuses, migration.Status) {
+				return false
+			}
+			if stringutils.ContainsString(nova.MIGRATION_FAILED_STATUS, migration.Status) {
+				err = fmt.Errorf("%s %d of server %s is %s", migrationType, migration.Id, serverId, migration.Status)
+				return false
+			}
+			return true
+		},
+	)
+	if interrupted := session.Interrupted(c.Context(), "wait %s of server %s", migrationType, serverId); interrupted != nil {
+		return migration, interrupted
+	}
+	if err == nil && retryErr != nil {
+		err = fmt.Errorf("wait %s of server %s failed: %w", migrationType, serverId, retryErr)
+	}
+	return migration, err
+}
 
 // avaliable zone api
 func (c AZApi) List(query url.Values) ([]nova.AvailabilityZone, error) {
@@ -1171,11 +1479,7 @@ func (c AggregateApi) Find(idOrName string) (*nova.Aggregate, error) {
 	if err == nil {
 		return agg, nil
 	}
-	if !compare.IsType[session.HttpError](err) {
-		return nil, err
-	}
-	httpError, _ := err.(session.HttpError)
-	if !httpError.IsNotFound() {
+	if !session.IsNotFound(err) {
 		return nil, err
 	}
 	aggs, err := c.List(nil)
@@ -1187,7 +1491,7 @@ func (c AggregateApi) Find(idOrName string) (*nova.Aggregate, error) {
 	})
 	switch len(aggs) {
 	case 0:
-		return nil, fmt.Errorf("aggregate %s not found", idOrName)
+		return nil, fmt.Errorf("aggregate %s %w", idOrName, session.ErrNotFound)
 	case 1:
 		return &aggs[0], nil
 	default:
@@ -1223,8 +1527,54 @@ func (c AggregateApi) RemoveHost(id int, host string) (*nova.Aggregate, error) {
 
 // server group api
 
+// 服务器组使用 offset/limit 分页, 并且不返回下一页链接
 func (c ServerGroupApi) List(query url.Values) ([]nova.ServerGroup, error) {
-	return ListResource[nova.ServerGroup](c.ResourceApi, query)
+	return listWithOffset[nova.ServerGroup](c.ResourceApi, query, NOVA_PAGE_LIMIT)
+}
+func (c ServerGroupApi) Show(id string) (*nova.ServerGroup, error) {
+	return ShowResource[nova.ServerGroup](c.ResourceApi, id)
+}
+
+// 服务器组的列表接口不支持按名字过滤
+func (c ServerGroupApi) Find(idOrName string) (*nova.ServerGroup, error) {
+	group, err := c.Show(idOrName)
+	if err == nil || !session.IsNotFound(err) {
+		return group, err
+	}
+	groups, err := c.List(nil)
+	if err != nil {
+		return nil, err
+	}
+	matched := utility.Filter(groups, func(g nova.ServerGroup) bool { return g.Name == idOrName })
+	switch len(matched) {
+	case 0:
+		return nil, fmt.Errorf("server group %s %w", idOrName, session.ErrNotFound)
+	case 1:
+		return &matched[0], nil
+	default:
+		return nil, fmt.Errorf("found multi server groups named %s", idOrName)
+	}
+}
+
+// 创建服务器组, rules (例如 max_server_per_host) 需要 2.64 及以上版本
+func (c ServerGroupApi) Create(name string, policy string, rules map[string]interface{}) (*nova.ServerGroup, error) {
+	params := map[string]interface{}{"name": name}
+	if c.MicroVersionLargeEqual("2.64") {
+		params["policy"] = policy
+		if len(rules) > 0 {
+			params["rules"] = rules
+		}
+	} else {
+		if len(rules) > 0 {
+			return nil, fmt.Errorf("rules of server group require microversion 2.64 or later")
+		}
+		params["policies"] = []string{policy}
+	}
+	return createResource[nova.ServerGroup](c.ResourceApi, params)
+}
+func (c ServerGroupApi) Delete(id string) error {
+	_, err := DeleteResource(c.ResourceApi, id)
+	return err
 }
 
 // quota api
@@ -0,0 +1,187 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/BytemanD/skyman/openstack/fake"
+	"github.com/BytemanD/skyman/openstack/model"
+	"github.com/BytemanD/skyman/openstack/model/nova"
+	"github.com/BytemanD/skyman/openstack/session"
+)
+
+func newReplayNovaClient(t *testing.T, cassette string) *NovaV2 {
+	return &NovaV2{
+		ServiceClient: newReplayServiceClient(t, cassette, "http://nova:8774/v2.1", "v2.1"),
+		MicroVersion:  &model.ApiVersion{Version: "2.1"},
+	}
+}
+
+func TestServerShow(t *testing.T) {
+	client := newReplayNovaClient(t, "nova_server_show")
+	server, err := client.Server().Show("0b3c1e62-0000-4000-8000-000000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.Name != "vm1" || server.Status != "ACTIVE" {
+		t.Errorf("expect server vm1 ACTIVE, but got %s %s", server.Name, server.Status)
+	}
+}
+
+func TestServerFindByName(t *testing.T) {
+	client := newReplayNovaClient(t, "nova_server_find")
+	server, err := client.Server().Find("vm1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.Id != "0b3c1e62-0000-4000-8000-000000000001" {
+		t.Errorf("expect server id 0b3c1e62-0000-4000-8000-000000000001, but got %s", server.Id)
+	}
+}
+
+func TestServerFindNotFound(t *testing.T) {
+	client := newReplayNovaClient(t, "nova_server_not_found")
+	if _, err := client.Server().Find("vm2"); err == nil {
+		t.Error("expect error when server not found")
+	}
+}
+
+func TestWaitStatusInterrupted(t *testing.T) {
+	client := newReplayNovaClient(t, "nova_server_show")
+	ctx, cancel := context.WithCancel(context.Background())
+	client.SetContext(ctx)
+	time.AfterFunc(time.Millisecond*100, cancel)
+
+	startTime := time.Now()
+	_, err := client.Server().WaitStatus("0b3c1e62-0000-4000-8000-000000000001", "SHUTOFF", 5)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expect context canceled, but got %v", err)
+	}
+	interrupted := session.InterruptedError{}
+	if !errors.As(err, &interrupted) || interrupted.Operation == "" {
+		t.Errorf("expect interrupted operation, but got %v", err)
+	}
+	if time.Since(startTime) >= time.Second*5 {
+		t.Errorf("wait is not interrupted in time")
+	}
+}
+
+func TestRequestInterrupted(t *testing.T) {
+	client := newReplayNovaClient(t, "nova_server_show")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client.SetContext(ctx)
+
+	_, err := client.Server().Show("0b3c1e62-0000-4000-8000-000000000001")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expect context canceled, but got %v", err)
+	}
+}
+
+func newFakeNovaClient(t *testing.T, cloud *fake.Cloud) *NovaV2 {
+	return &NovaV2{
+		ServiceClient: newFakeServiceClient(t, cloud, "compute", "nova", "v2.1"),
+		MicroVersion:  &model.ApiVersion{Version: "2.96"},
+	}
+}
+
+func TestServerTagsAndMetadata(t *testing.T) {
+	cloud := fake.NewCloud()
+	cloud.TaskDuration = 0
+	defer cloud.Close()
+	client := newFakeNovaClient(t, cloud)
+
+	tagged, err := client.Server().Create(nova.ServerOpt{
+		Name: "vm1", Flavor: "1", Image: "cirros", Tags: []string{"test"},
+		Metadata: map[string]string{"owner": "ci"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Server().Create(nova.ServerOpt{Name: "vm2", Flavor: "1", Image: "cirros"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Server().AddTag(tagged.Id, "ci"); err != nil {
+		t.Fatal(err)
+	}
+	if tags, err := client.Server().ListTags(tagged.Id); err != nil || len(tags) != 2 {
+		t.Errorf("expect 2 tags, but got %v (%v)", tags, err)
+	}
+	for query, expect := range map[string]int{"tags=test,ci": 1, "tags=test,other": 0, "not-tags=test": 1} {
+		values, _ := url.ParseQuery(query)
+		servers, err := client.Server().Detail(values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(servers) != expect {
+			t.Errorf("expect %d server(s) matched %s, but got %d", expect, query, len(servers))
+		}
+	}
+
+	metadata, err := client.Server().SetMetadata(tagged.Id, map[string]string{"env": "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metadata) != 2 || metadata["owner"] != "ci" || metadata["env"] != "test" {
+		t.Errorf("unexpected metadata %v", metadata)
+	}
+	if err := client.Server().DeleteMetadata(tagged.Id, "owner"); err != nil {
+		t.Fatal(err)
+	}
+	server, err := client.Server().Show(tagged.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !server.MatchMetadata(map[string]string{"env": "test"}) || server.MatchMetadata(map[string]string{"owner": "ci"}) {
+		t.Errorf("unexpected metadata %v", server.Metadata)
+	}
+}
+
+func TestServerLockAndRescue(t *testing.T) {
+	cloud := fake.NewCloud()
+	cloud.TaskDuration = 0
+	defer cloud.Close()
+	client := newFakeNovaClient(t, cloud)
+
+	server, err := client.Server().Create(nova.ServerOpt{Name: "vm1", Flavor: "1", Image: "cirros"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cloud.WaitTasks(0)
+	microVersion := client.MicroVersion
+	client.MicroVersion = &model.ApiVersion{Version: "2.72"}
+	if err := client.Server().Lock(server.Id, "maintenance"); err == nil {
+		t.Errorf("expect error when lock with reason before 2.73")
+	}
+	client.MicroVersion = microVersion
+	if err := client.Server().Lock(server.Id, "maintenance"); err != nil {
+		t.Fatal(err)
+	}
+	if server, err = client.Server().Show(server.Id); err != nil {
+		t.Fatal(err)
+	}
+	if !server.Locked || server.LockedReason != "maintenance" {
+		t.Errorf("expect server locked with reason, but got %v %s", server.Locked, server.LockedReason)
+	}
+	if err := client.Server().Unlock(server.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	password, err := client.Server().Rescue(server.Id, "secret", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if password != "secret" {
+		t.Errorf("expect rescue password secret, but got %s", password)
+	}
+	cloud.WaitTasks(0)
+	if server, err = client.Server().Show(server.Id); err != nil {
+		t.Fatal(err)
+	}
+	if server.Status != "RESCUE" || server.Locked {
+		t.Errorf("expect server RESCUE and unlocked, but got %s %v", server.Status, server.Locked)
+	}
+}
@@ -0,0 +1,312 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/openstack/model"
+	"github.com/BytemanD/skyman/openstack/model/octavia"
+	"github.com/BytemanD/skyman/openstack/session"
+	"github.com/BytemanD/skyman/utility"
+)
+
+type OctaviaV2 struct {
+	*ServiceClient
+	currentVersion *model.ApiVersion
+}
+
+func (c *OctaviaV2) GetCurrentVersion() (*model.ApiVersion, error) {
+	if c.currentVersion == nil {
+		result := struct{ Versions model.ApiVersions }{}
+		if resp, err := c.Index(nil); err != nil {
+			return nil, err
+		} else if err := resp.UnmarshalBody(&result); err != nil {
+			return nil, err
+		}
+		c.currentVersion = result.Versions.Current()
+	}
+	if c.currentVersion != nil {
+		return c.currentVersion, nil
+	}
+	return nil, fmt.Errorf("current version not found")
+}
+func (c *OctaviaV2) String() string {
+	return fmt.Sprintf("<LoadBalancer: %s>", c.Url)
+}
+
+type LoadBalancerApi struct{ ResourceApi }
+type ListenerApi struct{ ResourceApi }
+type PoolApi struct{ ResourceApi }
+type MemberApi struct{ ResourceApi }
+type HealthMonitorApi struct{ ResourceApi }
+type AmphoraApi struct{ ResourceApi }
+
+func (c OctaviaV2) LoadBalancer() LoadBalancerApi {
+	return LoadBalancerApi{
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl: "lbaas/loadbalancers",
+			SingularKey: "loadbalancer",
+			PluralKey:   "loadbalancers",
+		},
+	}
+}
+func (c OctaviaV2) Listener() ListenerApi {
+	return ListenerApi{
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl: "lbaas/listeners",
+			SingularKey: "listener",
+			PluralKey:   "listeners",
+		},
+	}
+}
+func (c OctaviaV2) Pool() PoolApi {
+	return PoolApi{
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl: "lbaas/pools",
+			SingularKey: "pool",
+			PluralKey:   "pools",
+		},
+	}
+}
+
+// 成员属于资源池, 接口路径中需要 pool id
+func (c OctaviaV2) Member(poolId string) MemberApi {
+	return MemberApi{
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl: fmt.Sprintf("lbaas/pools/%s/members", poolId),
+			SingularKey: "member",
+			PluralKey:   "members",
+		},
+	}
+}
+func (c OctaviaV2) HealthMonitor() HealthMonitorApi {
+	return HealthMonitorApi{
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl: "lbaas/healthmonitors",
+			SingularKey: "healthmonitor",
+			PluralKey:   "healthmonitors",
+		},
+	}
+}
+func (c OctaviaV2) Amphora() AmphoraApi {
+	return AmphoraApi{
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl: "octavia/amphorae",
+			SingularKey: "amphora",
+			PluralKey:   "amphorae",
+		},
+	}
+}
+
+func createResource[T any](r ResourceApi, params map[string]interface{}) (*T, error) {
+	respBody := map[string]*T{}
+	if _, err := r.R().SetBody(ReqBody{r.SingularKey: params}).SetResult(&respBody).Post(); err != nil {
+		return nil, err
+	}
+	return respBody[r.SingularKey], nil
+}
+
+// 等待 provisioning_status 变为 status, status 为 DELETED 时资源不存在也视为成功,
+// 此时返回的 item 为 nil
+func waitProvisioningStatus[T any](ctx context.Context, r ResourceApi, id string, status string, interval int,
+	getStatus func(item *T) (string, string)) (*T, error) {
+	var (
+		item *T
+		err  error
+	)
+	r = r.WithContext(ctx)
+	status = strings.ToUpper(status)
+	retryErr := utility.Retry(
+		utility.RetryCondition{
+			Ctx:     r.Context(),
+			Timeout: time.Second * 60 * 10, IntervalMin: time.Second * time.Duration(interval),
+		},
+		func() bool {
+			item, err = ShowResource[T](r, id)
+			if err != nil {
+				if session.IsNotFound(err) && status == octavia.PROVISIONING_DELETED {
+					err = nil
+				}
+				return false
+			}
+			provisioningStatus, operatingStatus := getStatus(item)
+			console.Info("[%s: %s] provisioning status: %s, operating status: %s",
+				r.SingularKey, id, provisioningStatus, operatingStatus)
+			switch strings.ToUpper(provisioningStatus) {
+			case status:
+				return false
+			case octavia.PROVISIONING_ERROR:
+				err = fmt.Errorf("%s %s provisioning status is %s", r.SingularKey, id, provisioningStatus)
+				return false
+			}
+			return true
+		},
+	)
+	if interrupted := session.Interrupted(r.Context(), "wait %s %s %s", r.SingularKey, id, status); interrupted != nil {
+		return item, interrupted
+	}
+	if err == nil && retryErr != nil {
+		err = fmt.Errorf("wait %s %s %s failed: %w", r.SingularKey, id, status, retryErr)
+	}
+	return item, err
+}
+
+// load balancer api
+func (c LoadBalancerApi) List(query url.Values) ([]octavia.LoadBalancer, error) {
+	return ListResource[octavia.LoadBalancer](c.ResourceApi, query)
+}
+func (c LoadBalancerApi) Iterator(query url.Values) *ResourceIterator[octavia.LoadBalancer] {
+	return NewResourceIterator[octavia.LoadBalancer](c.ResourceApi, "", query)
+}
+func (c LoadBalancerApi) Show(id string) (*octavia.LoadBalancer, error) {
+	return ShowResource[octavia.LoadBalancer](c.ResourceApi, id)
+}
+func (c LoadBalancerApi) Find(idOrName string) (*octavia.LoadBalancer, error) {
+	return FindResource(idOrName, c.Show, c.List)
+}
+func (c LoadBalancerApi) Create(params map[string]interface{}) (*octavia.LoadBalancer, error) {
+	return createResource[octavia.LoadBalancer](c.ResourceApi, params)
+}
+
+// cascade 为 true 时同时删除监听器、资源池等子资源
+func (c LoadBalancerApi) Delete(id string, cascade bool) error {
+	query := url.Values{}
+	if cascade {
+		query.Set("cascade", "true")
+	}
+	_, err := DeleteResource(c.ResourceApi, id, query)
+	return err
+}
+func (c LoadBalancerApi) Failover(id string) error {
+	_, err := c.R().Put(id, "failover")
+	return err
+}
+func (c LoadBalancerApi) Stats(id string) (*octavia.LoadBalancerStats, error) {
+	result := struct {
+		Stats octavia.LoadBalancerStats `json:"stats"`
+	}{}
+	if _, err := c.R().SetResult(&result).Get(id, "stats"); err != nil {
+		return nil, err
+	}
+	return &result.Stats, nil
+}
+
+// 等待负载均衡器的 provisioning_status 变为 status (例如 ACTIVE, DELETED)
+//
+// 负载均衡器已经不存在时, 返回 provisioning_status 为 DELETED 的负载均衡器
+func (c LoadBalancerApi) WaitProvisioningStatus(ctx context.Context, id string, status string, interval int) (*octavia.LoadBalancer, error) {
+	lb, err := waitProvisioningStatus(ctx, c.ResourceApi, id, status, interval,
+		func(lb *octavia.LoadBalancer) (string, string) {
+			return lb.ProvisioningStatus, lb.OperatingStatus
+		},
+	)
+	if lb == nil && err == nil {
+		lb = &octavia.LoadBalancer{
+			Resource: model.Resource{Id: id}, ProvisioningStatus: octavia.PROVISIONING_DELETED,
+		}
+	}
+	return lb, err
+}
+
+// listener api
+func (c ListenerApi) List(query url.Values) ([]octavia.Listener, error) {
+	return ListResource[octavia.Listener](c.ResourceApi, query)
+}
+func (c ListenerApi) Iterator(query url.Values) *ResourceIterator[octavia.Listener] {
+	return NewResourceIterator[octavia.Listener](c.ResourceApi, "", query)
+}
+func (c ListenerApi) Show(id string) (*octavia.Listener, error) {
+	return ShowResource[octavia.Listener](c.ResourceApi, id)
+}
+func (c ListenerApi) Find(idOrName string) (*octavia.Listener, error) {
+	return FindResource(idOrName, c.Show, c.List)
+}
+func (c ListenerApi) Create(params map[string]interface{}) (*octavia.Listener, error) {
+	return createResource[octavia.Listener](c.ResourceApi, params)
+}
+func (c ListenerApi) Delete(id string) error {
+	_, err := DeleteResource(c.ResourceApi, id)
+	return err
+}
+
+// pool api
+func (c PoolApi) List(query url.Values) ([]octavia.Pool, error) {
+	return ListResource[octavia.Pool](c.ResourceApi, query)
+}
+func (c PoolApi) Iterator(query url.Values) *ResourceIterator[octavia.Pool] {
+	return NewResourceIterator[octavia.Pool](c.ResourceApi, "", query)
+}
+func (c PoolApi) Show(id string) (*octavia.Pool, error) {
+	return ShowResource[octavia.Pool](c.ResourceApi, id)
+}
+func (c PoolApi) Find(idOrName string) (*octavia.Pool, error) {
+	return FindResource(idOrName, c.Show, c.List)
+}
+func (c PoolApi) Create(params map[string]interface{}) (*octavia.Pool, error) {
+	return createResource[octavia.Pool](c.ResourceApi, params)
+}
+func (c PoolApi) Delete(id string) error {
+	_, err := DeleteResource(c.ResourceApi, id)
+	return err
+}
+
+// member api
+func (c MemberApi) List(query url.Values) ([]octavia.Member, error) {
+	return ListResource[octavia.Member](c.ResourceApi, query)
+}
+func (c MemberApi) Iterator(query url.Values) *ResourceIterator[octavia.Member] {
+	return NewResourceIterator[octavia.Member](c.ResourceApi, "", query)
+}
+func (c MemberApi) Show(id string) (*octavia.Member, error) {
+	return ShowResource[octavia.Member](c.ResourceApi, id)
+}
+func (c MemberApi) Find(idOrName string) (*octavia.Member, error) {
+	return FindResource(idOrName, c.Show, c.List)
+}
+func (c MemberApi) Create(params map[string]interface{}) (*octavia.Member, error) {
+	return createResource[octavia.Member](c.ResourceApi, params)
+}
+func (c MemberApi) Delete(id string) error {
+	_, err := DeleteResource(c.ResourceApi, id)
+	return err
+}
+
+// health monitor api
+func (c HealthMonitorApi) List(query url.Values) ([]octavia.HealthMonitor, error) {
+	return ListResource[octavia.HealthMonitor](c.ResourceApi, query)
+}
+func (c HealthMonitorApi) Iterator(query url.Values) *ResourceIterator[octavia.HealthMonitor] {
+	return NewResourceIterator[octavia.HealthMonitor](c.ResourceApi, "", query)
+}
+func (c HealthMonitorApi) Show(id string) (*octavia.HealthMonitor, error) {
+	return ShowResource[octavia.HealthMonitor](c.ResourceApi, id)
+}
+func (c HealthMonitorApi) Find(idOrName string) (*octavia.HealthMonitor, error) {
+	return FindResource(idOrName, c.Show, c.List)
+}
+func (c HealthMonitorApi) Create(params map[string]interface{}) (*octavia.HealthMonitor, error) {
+	return createResource[octavia.HealthMonitor](c.ResourceApi, params)
+}
+func (c HealthMonitorApi) Delete(id string) error {
+	_, err := DeleteResource(c.ResourceApi, id)
+	return err
+}
+
+// amphora api, 需要管理员权限
+func (c AmphoraApi) List(query url.Values) ([]octavia.Amphora, error) {
+	return ListResource[octavia.Amphora](c.ResourceApi, query)
+}
+func (c AmphoraApi) Iterator(query url.Values) *ResourceIterator[octavia.Amphora] {
+	return NewResourceIterator[octavia.Amphora](c.ResourceApi, "", query)
+}
+func (c AmphoraApi) Show(id string) (*octavia.Amphora, error) {
+	return ShowResource[octavia.Amphora](c.ResourceApi, id)
+}
+func (c AmphoraApi) Failover(id string) error {
+	_, err := c.R().Put(id, "failover")
+	return err
+}
@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestWaitProvisioningStatus(t *testing.T) {
+	shows := 0
+	deleted := false
+	routes := testRoutes{
+		"DELETE /v2/lbaas/loadbalancers/lb1": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("cascade") != "true" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		},
+		"GET /v2/lbaas/loadbalancers/lb1": func(w http.ResponseWriter, r *http.Request) {
+			if deleted {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"faultcode": "Client", "faultstring": "Load Balancer lb1 not found."}`))
+				return
+			}
+			shows++
+			status := "PENDING_CREATE"
+			if shows >= 3 {
+				status = "ACTIVE"
+			}
+			fmt.Fprintf(w, `{"loadbalancer": {"id": "lb1", "provisioning_status": "%s", "operating_status": "ONLINE"}}`, status)
+		},
+	}
+	client := OctaviaV2{ServiceClient: newTestServiceClient(t, "octavia", "/v2", routes)}
+
+	lb, err := client.LoadBalancer().WaitProvisioningStatus(context.Background(), "lb1", "ACTIVE", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lb.ProvisioningStatus != "ACTIVE" || shows != 3 {
+		t.Errorf("expect ACTIVE after 3 shows, but got %s after %d", lb.ProvisioningStatus, shows)
+	}
+	if err := client.LoadBalancer().Delete("lb1", true); err != nil {
+		t.Fatal(err)
+	}
+	lb, err = client.LoadBalancer().WaitProvisioningStatus(context.Background(), "lb1", "DELETED", 0)
+	if err != nil {
+		t.Fatalf("expect deleted, but got %s", err)
+	}
+	if lb == nil || lb.ProvisioningStatus != "DELETED" {
+		t.Errorf("expect DELETED load balancer, but got %v", lb)
+	}
+}
@@ -0,0 +1,186 @@
+package internal
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/BytemanD/skyman/openstack/model"
+	"github.com/BytemanD/skyman/openstack/model/placement"
+)
+
+type PlacementV1 struct {
+	*ServiceClient
+	currentVersion *model.ApiVersion
+	MicroVersion   *model.ApiVersion
+}
+
+// 获取 placement 支持的最大版本
+//
+// placement 的版本信息在 endpoint 根路径下, 例如 http://host/placement/
+func (c *PlacementV1) GetCurrentVersion() (*model.ApiVersion, error) {
+	if c.currentVersion != nil {
+		return c.currentVersion, nil
+	}
+	result := struct {
+		Versions []placement.Version `json:"versions"`
+	}{}
+	if _, err := checkError(
+		c.rawClient.R().SetContext(c.Context()).SetResult(&result).Get(c.Url),
+	); err != nil {
+		return nil, err
+	}
+	for _, version := range result.Versions {
+		if strings.ToUpper(version.Status) == "CURRENT" {
+			c.currentVersion = &model.ApiVersion{
+				Id: version.Id, MinVersion: version.MinVersion,
+				Version: version.MaxVersion, Status: version.Status,
+			}
+			return c.currentVersion, nil
+		}
+	}
+	return nil, fmt.Errorf("current version not found")
+}
+func (c *PlacementV1) MicroVersionLargeEqual(version string) bool {
+	if c.MicroVersion == nil {
+		return false
+	}
+	return getMicroVersion(c.MicroVersion.Version).LargeEqual(version)
+}
+func (c *PlacementV1) String() string {
+	return fmt.Sprintf("<Placement: %s>", c.Url)
+}
+
+type ResourceProviderApi struct{ ResourceApi }
+type AllocationApi struct{ ResourceApi }
+type TraitApi struct{ ResourceApi }
+
+func (c PlacementV1) ResourceProvider() ResourceProviderApi {
+	return ResourceProviderApi{
+		ResourceApi{
+			Client:       c.rawClient,
+			Ctx:          c.Context(),
+			BaseUrl:      c.Url,
+			MicroVersion: c.MicroVersion,
+			ResourceUrl:  "resource_providers",
+			PluralKey:    "resource_providers",
+		},
+	}
+}
+func (c PlacementV1) Allocation() AllocationApi {
+	return AllocationApi{
+		ResourceApi{
+			Client:       c.rawClient,
+			Ctx:          c.Context(),
+			BaseUrl:      c.Url,
+			MicroVersion: c.MicroVersion,
+			ResourceUrl:  "allocations",
+		},
+	}
+}
+func (c PlacementV1) Trait() TraitApi {
+	return TraitApi{
+		ResourceApi{
+			Client:       c.rawClient,
+			Ctx:          c.Context(),
+			BaseUrl:      c.Url,
+			MicroVersion: c.MicroVersion,
+			ResourceUrl:  "traits",
+		},
+	}
+}
+
+// resource provider api
+func (c ResourceProviderApi) List(query url.Values) ([]placement.ResourceProvider, error) {
+	return ListResource[placement.ResourceProvider](c.ResourceApi, query)
+}
+func (c ResourceProviderApi) Show(uuid string) (*placement.ResourceProvider, error) {
+	provider := placement.ResourceProvider{}
+	if _, err := c.R().SetResult(&provider).Get(uuid); err != nil {
+		return nil, err
+	}
+	return &provider, nil
+}
+func (c ResourceProviderApi) Find(uuidOrName string) (*placement.ResourceProvider, error) {
+	return FindResource(uuidOrName, c.Show, c.List)
+}
+func (c ResourceProviderApi) Inventories(uuid string) ([]placement.Inventory, error) {
+	result := struct {
+		Inventories map[string]placement.Inventory `json:"inventories"`
+	}{}
+	if _, err := c.R().SetResult(&result).Get(uuid, "inventories"); err != nil {
+		return nil, err
+	}
+	inventories := []placement.Inventory{}
+	for class, inventory := range result.Inventories {
+		inventory.ResourceClass = class
+		inventories = append(inventories, inventory)
+	}
+	sort.Slice(inventories, func(i, j int) bool {
+		return inventories[i].ResourceClass < inventories[j].ResourceClass
+	})
+	return inventories, nil
+}
+func (c ResourceProviderApi) Usages(uuid string) (map[string]int, error) {
+	result := struct {
+		Usages map[string]int `json:"usages"`
+	}{}
+	if _, err := c.R().SetResult(&result).Get(uuid, "usages"); err != nil {
+		return nil, err
+	}
+	return result.Usages, nil
+}
+func (c ResourceProviderApi) Traits(uuid string) ([]string, error) {
+	result := struct {
+		Traits []string `json:"traits"`
+	}{}
+	if _, err := c.R().SetResult(&result).Get(uuid, "traits"); err != nil {
+		return nil, err
+	}
+	sort.Strings(result.Traits)
+	return result.Traits, nil
+}
+
+// 查询 resource provider 所属的聚合, 需要 1.1 及以上版本
+func (c ResourceProviderApi) Aggregates(uuid string) ([]string, error) {
+	result := struct {
+		Aggregates []string `json:"aggregates"`
+	}{}
+	if _, err := c.R().SetResult(&result).Get(uuid, "aggregates"); err != nil {
+		return nil, err
+	}
+	return result.Aggregates, nil
+}
+func (c ResourceProviderApi) Allocations(uuid string) (*placement.ProviderAllocations, error) {
+	result := placement.ProviderAllocations{}
+	if _, err := c.R().SetResult(&result).Get(uuid, "allocations"); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// allocation api
+func (c AllocationApi) Show(consumer string) (*placement.ConsumerAllocations, error) {
+	result := placement.ConsumerAllocations{}
+	if _, err := c.R().SetResult(&result).Get(consumer); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+func (c AllocationApi) Delete(consumer string) error {
+	_, err := c.R().Delete(consumer)
+	return err
+}
+
+// trait api, 需要 1.6 及以上版本
+func (c TraitApi) List(query url.Values) ([]string, error) {
+	result := struct {
+		Traits []string `json:"traits"`
+	}{}
+	if _, err := c.R().SetQuery(query).SetResult(&result).Get(); err != nil {
+		return nil, err
+	}
+	sort.Strings(result.Traits)
+	return result.Traits, nil
+}
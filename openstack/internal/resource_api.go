@@ -1,32 +1,38 @@
 package internal
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
 	"reflect"
+	"strconv"
+	"time"
 
 	"github.com/BytemanD/skyman/openstack/model"
 	"github.com/BytemanD/skyman/openstack/session"
+	"github.com/BytemanD/skyman/utility"
 	"github.com/go-resty/resty/v2"
 )
 
 func checkError(resp *resty.Response, err error) (*session.Response, error) {
+	if err != nil && resp != nil && resp.Request != nil {
+		if interrupted := session.Interrupted(resp.Request.Context(), "%s %s", resp.Request.Method, resp.Request.URL); interrupted != nil {
+			return &session.Response{Response: resp}, interrupted
+		}
+	}
 	if err != nil || resp == nil {
 		return &session.Response{Response: resp}, err
 	}
 	if resp.IsError() {
-		return &session.Response{Response: resp}, session.HttpError{
-			Status:  resp.StatusCode(),
-			Reason:  resp.Status(),
-			Message: string(resp.Body()),
-		}
+		return &session.Response{Response: resp}, session.NewHttpError(resp)
 	}
 	return &session.Response{Response: resp}, nil
 }
 
 type ResourceApi struct {
 	Client      *resty.Client
+	Ctx         context.Context
 	BaseUrl     string
 	ResourceUrl string
 
@@ -40,6 +46,30 @@ type ResourceApi struct {
 	MicroVersion *model.ApiVersion
 }
 
+func (r ResourceApi) Context() context.Context {
+	if r.Ctx == nil {
+		return context.Background()
+	}
+	return r.Ctx
+}
+
+// 返回使用 ctx 发送请求的副本, 保留原 context 中的服务名
+func (r ResourceApi) WithContext(ctx context.Context) ResourceApi {
+	if service := session.ServiceFromContext(r.Context()); service != "" && session.ServiceFromContext(ctx) == "" {
+		ctx = session.WithService(ctx, service)
+	}
+	r.Ctx = ctx
+	return r
+}
+
+// 等待 interval, 如果 context 已经结束, 返回 InterruptedError
+func (r ResourceApi) sleep(interval time.Duration, format string, args ...interface{}) error {
+	if err := utility.SleepContext(r.Context(), interval); err != nil {
+		return session.Interrupted(r.Context(), format, args...)
+	}
+	return nil
+}
+
 func (c *ResourceApi) MicroVersionLargeEqual(version string) bool {
 	clientVersion := getMicroVersion(c.MicroVersion.Version)
 	otherVersion := getMicroVersion(version)
@@ -52,7 +82,7 @@ func (c *ResourceApi) MicroVersionLargeEqual(version string) bool {
 	}
 }
 func (r ResourceApi) NewRequest(method string, u string, q url.Values, body interface{}, result interface{}) *resty.Request {
-	req := r.Client.R().SetQueryParamsFromValues(q).SetResult(result)
+	req := r.Client.R().SetContext(r.Context()).SetQueryParamsFromValues(q).SetResult(result)
 	if reqUrl, err := url.JoinPath(r.BaseUrl, u); err != nil {
 		req.Error = err
 	} else {
@@ -71,7 +101,7 @@ func (r ResourceApi) NewRequest(method string, u string, q url.Values, body inte
 }
 func (r *ResourceApi) R() *session.Request {
 	return &session.Request{
-		Request:     r.Client.R(),
+		Request:     r.Client.R().SetContext(r.Context()),
 		Baseurl:     r.BaseUrl,
 		ResourceUrl: r.ResourceUrl,
 	}
@@ -128,32 +158,207 @@ func (r ResourceApi) Patch(url string, body interface{}, result interface{}, hea
 		r.NewPatchRequest(url, body, result).SetHeaders(headers).Send(),
 	)
 }
+
+// 获取资源列表
+//
+// 如果 query 中指定了 limit, 只返回一页, 否则根据响应中的 next 链接获取所有页
 func ListResource[T any](r ResourceApi, query url.Values, detail ...bool) ([]T, error) {
+	path := ""
+	if len(detail) > 0 && detail[0] {
+		path = "detail"
+	}
+	if query.Has("limit") {
+		page, err := ListPage[T](r, path, query)
+		if err != nil {
+			return nil, err
+		}
+		return page.Items, nil
+	}
+	items := []T{}
+	iter := NewResourceIterator[T](r, path, query)
+	for iter.Next() {
+		items = append(items, iter.Page()...)
+	}
+	return items, iter.Err()
+}
+
+// 使用 offset/limit 分页获取资源列表 (例如 barbican 和 nova 的服务器组), 这些接口不返回下一页链接
+//
+// 如果 query 中指定了 limit, 只返回一页。服务端每页的数量可能小于 pageLimit (例如超过了服务端的 max_limit),
+// 所以直到返回空页时才结束
+func listWithOffset[T any](r ResourceApi, query url.Values, pageLimit int) ([]T, error) {
+	if query.Has("limit") {
+		page, err := ListPage[T](r, "", query)
+		if err != nil {
+			return nil, err
+		}
+		return page.Items, nil
+	}
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set("limit", strconv.Itoa(pageLimit))
+	items := []T{}
+	for {
+		q.Set("offset", strconv.Itoa(len(items)))
+		page, err := ListPage[T](r, "", q)
+		if err != nil {
+			return nil, err
+		}
+		if len(page.Items) == 0 {
+			return items, nil
+		}
+		items = append(items, page.Items...)
+	}
+}
+
+// 一页资源
+type Page[T any] struct {
+	Items []T
+	// 下一页的查询参数, 没有下一页时为 nil
+	Next url.Values
+}
+
+// 最后一项资源的 Id, 可用作下一页的 marker
+func (p Page[T]) LastId() string {
+	if len(p.Items) == 0 {
+		return ""
+	}
+	value := reflect.Indirect(reflect.ValueOf(p.Items[len(p.Items)-1]))
+	if value.Kind() != reflect.Struct {
+		return ""
+	}
+	if id := value.FieldByName("Id"); id.Kind() == reflect.String {
+		return id.String()
+	}
+	return ""
+}
+
+// 获取一页资源
+func ListPage[T any](r ResourceApi, path string, query url.Values) (*Page[T], error) {
 	if r.ResourceUrl == "" {
 		return nil, fmt.Errorf("ResourceUrl is empty")
 	}
 	if r.PluralKey == "" {
 		return nil, fmt.Errorf("PluralKey is empty")
 	}
-	items := []T{}
 	respBody := map[string]interface{}{}
-	if len(detail) > 0 && detail[0] {
-		if _, err := r.R().SetQuery(query).SetResult(&respBody).Get("detail"); err != nil {
-			return nil, err
-		}
+	req := r.R().SetQuery(query).SetResult(&respBody)
+	var err error
+	if path != "" {
+		_, err = req.Get(path)
 	} else {
-		if _, err := r.R().SetQuery(query).SetResult(&respBody).Get(); err != nil {
-			return nil, err
-		}
+		_, err = req.Get()
 	}
+	if err != nil {
+		return nil, err
+	}
+	page := Page[T]{Items: []T{}}
 	itemsData, err := json.Marshal(respBody[r.PluralKey])
 	if err != nil {
 		return nil, err
 	}
-	if err := json.Unmarshal(itemsData, &items); err != nil {
+	if err := json.Unmarshal(itemsData, &page.Items); err != nil {
 		return nil, err
 	}
-	return items, nil
+	if len(page.Items) > 0 {
+		page.Next = nextPageQuery(respBody, r.PluralKey, query)
+	}
+	return &page, nil
+}
+
+// 从响应中解析下一页的查询参数
+//
+//	nova/cinder/neutron: {"servers_links": [{"rel": "next", "href": "..."}]}
+//	glance:              {"next": "/v2/images?marker=..."}
+//	keystone:            {"links": {"next": "..."}}
+func nextPageQuery(respBody map[string]interface{}, pluralKey string, query url.Values) url.Values {
+	href, _ := respBody["next"].(string)
+	for _, key := range []string{pluralKey + "_links", "links"} {
+		switch links := respBody[key].(type) {
+		case []interface{}:
+			for _, link := range links {
+				if link, ok := link.(map[string]interface{}); ok && link["rel"] == "next" {
+					href, _ = link["href"].(string)
+				}
+			}
+		case map[string]interface{}:
+			if next, ok := links["next"].(string); ok {
+				href = next
+			}
+		}
+	}
+	if href == "" {
+		return nil
+	}
+	parsed, err := url.Parse(href)
+	if err != nil {
+		return nil
+	}
+	// 部分服务的 next 链接中不包含原来的过滤条件
+	next := url.Values{}
+	for k, v := range query {
+		next[k] = v
+	}
+	for k, v := range parsed.Query() {
+		next[k] = v
+	}
+	if next.Get("marker") == "" || next.Get("marker") == query.Get("marker") {
+		return nil
+	}
+	return next
+}
+
+// 分页迭代器, 逐页获取资源, 不需要一次加载所有数据
+//
+//	iter := NewResourceIterator[nova.Server](api, "detail", query)
+//	for iter.Next() {
+//		servers := iter.Page()
+//	}
+//	err := iter.Err()
+type ResourceIterator[T any] struct {
+	r     ResourceApi
+	path  string
+	query url.Values
+	page  []T
+	err   error
+	done  bool
+}
+
+func NewResourceIterator[T any](r ResourceApi, path string, query url.Values) *ResourceIterator[T] {
+	return &ResourceIterator[T]{r: r, path: path, query: query}
+}
+
+// 获取下一页, 没有更多数据或者出错时返回 false
+func (it *ResourceIterator[T]) Next() bool {
+	if it.done {
+		return false
+	}
+	page, err := ListPage[T](it.r, it.path, it.query)
+	if err != nil {
+		it.err, it.done = err, true
+		return false
+	}
+	it.page, it.query = page.Items, page.Next
+	if page.Next == nil {
+		it.done = true
+	}
+	return len(page.Items) > 0
+}
+func (it *ResourceIterator[T]) Page() []T {
+	return it.page
+}
+func (it *ResourceIterator[T]) Err() error {
+	return it.err
+}
+
+// 下一页的查询参数, 没有下一页时返回 nil
+func (it *ResourceIterator[T]) NextQuery() url.Values {
+	if it.done {
+		return nil
+	}
+	return it.query
 }
 func ShowResource[T any](r ResourceApi, id string) (*T, error) {
 	if r.ResourceUrl == "" {
@@ -186,12 +391,7 @@ func FindResource[T any](
 	if err == nil {
 		return t, nil
 	}
-	switch errType := err.(type) {
-	case session.HttpError:
-		if !errType.IsNotFound() {
-			return nil, err
-		}
-	default:
+	if !session.IsNotFound(err) {
 		return nil, err
 	}
 	ts, err := listFunc(url.Values{"name": []string{idOrName}})
@@ -200,13 +400,13 @@ func FindResource[T any](
 	}
 	switch len(ts) {
 	case 0:
-		return nil, fmt.Errorf("resource %s not found", idOrName)
+		return nil, fmt.Errorf("resource %s %w", idOrName, session.ErrNotFound)
 	case 1:
 		t := ts[0]
 		value := reflect.ValueOf(t)
 		valueName := value.FieldByName("Name")
 		if valueName.String() != idOrName {
-			return nil, fmt.Errorf("resource %s not found", idOrName)
+			return nil, fmt.Errorf("resource %s %w", idOrName, session.ErrNotFound)
 		} else {
 			return &t, nil
 		}
@@ -220,7 +420,7 @@ func FindResource[T any](
 			}
 		}
 		if len(fileted) == 0 {
-			return nil, fmt.Errorf("resource %s not found", idOrName)
+			return nil, fmt.Errorf("resource %s %w", idOrName, session.ErrNotFound)
 		}
 		if len(fileted) == 1 {
 			return &fileted[0], nil
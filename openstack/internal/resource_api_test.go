@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/BytemanD/skyman/openstack/fake"
+)
+
+func newFakeNeutronClient(t *testing.T, cloud *fake.Cloud) *NeutronV2 {
+	return &NeutronV2{ServiceClient: newFakeServiceClient(t, cloud, "network", "neutron", "v2.0")}
+}
+
+func TestListResourcePages(t *testing.T) {
+	cloud := fake.NewCloud()
+	defer cloud.Close()
+	cloud.PageSize = 2
+	for i := 1; i <= 4; i++ {
+		cloud.AddNetwork(fmt.Sprintf("net%d", i))
+	}
+	client := newFakeNeutronClient(t, cloud)
+
+	networks, err := client.Network().List(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(networks) != 5 {
+		t.Errorf("expect 5 networks, but got %d", len(networks))
+	}
+
+	networks, err = client.Network().List(url.Values{"limit": []string{"3"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(networks) != 2 {
+		t.Errorf("expect 2 networks in one page, but got %d", len(networks))
+	}
+
+	pages := []int{}
+	iter := client.Network().Iterator(url.Values{"marker": []string{networks[0].Id}})
+	for iter.Next() {
+		pages = append(pages, len(iter.Page()))
+	}
+	if err := iter.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(pages) != "[2 2]" {
+		t.Errorf("expect pages [2 2], but got %v", pages)
+	}
+	if next := iter.NextQuery(); next != nil {
+		t.Errorf("expect no next page after the last page, but got %v", next)
+	}
+
+	// 服务端每页的数量小于 limit 时, 仍然有下一页
+	iter = client.Network().Iterator(url.Values{"limit": []string{"3"}})
+	if !iter.Next() {
+		t.Fatal(iter.Err())
+	}
+	if next := iter.NextQuery(); next.Get("marker") != iter.Page()[1].Id {
+		t.Errorf("expect next page after %s, but got %v", iter.Page()[1].Id, next)
+	}
+}
+
+func TestUnauthorizedNotRetried(t *testing.T) {
+	cloud := fake.NewCloud()
+	defer cloud.Close()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	authPlugin := NewPasswordAuth(cloud.AuthUrl(), cloud.User(), cloud.Project(), cloud.Region)
+	authPlugin.SetLocalTokenExpire(3600)
+	client := NeutronV2{ServiceClient: NewServiceApi(server.URL, "v2.0", authPlugin)}
+
+	if _, err := client.Network().List(nil); err == nil {
+		t.Fatalf("expect error when token is rejected")
+	}
+	// token 不是来自本地缓存, 重试也不会成功
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expect 1 request, but got %d", n)
+	}
+}
@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -15,6 +16,30 @@ type ServiceClient struct {
 	Url        string
 	AuthPlugin auth_plugin.AuthPlugin
 	rawClient  *resty.Client
+	ctx        context.Context
+	// 服务名, 用于错误信息
+	ServiceName string
+}
+
+// 设置请求使用的 context, 取消后正在进行的请求和等待都会中止
+func (c *ServiceClient) SetContext(ctx context.Context) {
+	if c == nil {
+		return
+	}
+	c.ctx = ctx
+}
+func (c *ServiceClient) Context() context.Context {
+	if c == nil {
+		return context.Background()
+	}
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if c.ServiceName != "" {
+		return session.WithService(ctx, c.ServiceName)
+	}
+	return ctx
 }
 
 func (c *ServiceClient) AddBaseHeader(k, v string) {
@@ -45,7 +70,12 @@ func (c *ServiceClient) Index(result interface{}) (*session.Response, error) {
 		return nil, fmt.Errorf("get index url failed: %s", err)
 	}
 
-	resp, err := c.rawClient.R().SetResult(result).Get(indexUrl)
+	resp, err := c.rawClient.R().SetContext(c.Context()).SetResult(result).Get(indexUrl)
+	if err != nil {
+		if interrupted := session.Interrupted(c.Context(), "GET %s", indexUrl); interrupted != nil {
+			return nil, interrupted
+		}
+	}
 	return &session.Response{Response: resp}, err
 }
 
@@ -61,6 +91,12 @@ func NewServiceApi[T ServiceClient](endpoint string, version string, authPlugin
 		rawClient: session.DefaultRestyClient().
 			OnBeforeRequest(func(c *resty.Client, r *resty.Request) error {
 				return authPlugin.AuthRequest(r)
+			}).
+			AddRetryCondition(func(r *resty.Response, err error) bool {
+				// 缓存的 token 被拒绝 (例如已被吊销) 时重新申请 token 并重试一次,
+				// 其他的 401 (例如没有权限) 重试也不会成功
+				return r != nil && r.StatusCode() == http.StatusUnauthorized && r.Request.Attempt <= 1 &&
+					authPlugin.InvalidateCachedToken(r.Request.Header.Get(auth_plugin.X_AUTH_TOKEN))
 			}),
 	}
 }
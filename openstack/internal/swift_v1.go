@@ -0,0 +1,301 @@
+package internal
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/BytemanD/easygo/pkg/syncutils"
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/openstack/model/swift"
+	"github.com/BytemanD/skyman/openstack/session"
+	"github.com/BytemanD/skyman/utility"
+	"github.com/cheggaaa/pb/v3"
+)
+
+// 分段保存在 <container>_segments 中, 与 python-swiftclient 保持一致
+const SEGMENTS_CONTAINER_SUFFIX = "_segments"
+
+type SwiftV1 struct{ *ServiceClient }
+
+func (c *SwiftV1) String() string {
+	return fmt.Sprintf("<ObjectStore: %s>", c.Url)
+}
+
+type ContainerApi struct{ ResourceApi }
+type ObjectApi struct {
+	ResourceApi
+	container string
+}
+
+func (c SwiftV1) Container() ContainerApi {
+	return ContainerApi{
+		ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url},
+	}
+}
+func (c SwiftV1) Object(container string) ObjectApi {
+	return ObjectApi{
+		ResourceApi: ResourceApi{Client: c.rawClient, Ctx: c.Context(), BaseUrl: c.Url,
+			ResourceUrl: container,
+		},
+		container: container,
+	}
+}
+
+// 查询列表, 如果 query 中没有指定 limit, 使用 marker 查询所有页
+func listWithMarker[T any](r ResourceApi, originQuery url.Values, marker func(item T) string) ([]T, error) {
+	query := url.Values{"format": []string{"json"}}
+	for k, v := range originQuery {
+		query[k] = v
+	}
+	items := []T{}
+	for {
+		page := []T{}
+		if _, err := r.R().SetQuery(query).SetResult(&page).Get(); err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		if len(page) == 0 || query.Has("limit") {
+			return items, nil
+		}
+		query.Set("marker", marker(page[len(page)-1]))
+	}
+}
+
+// container api
+func (c ContainerApi) List(query url.Values) ([]swift.Container, error) {
+	return listWithMarker(c.ResourceApi, query, func(item swift.Container) string { return item.Name })
+}
+func (c ContainerApi) Create(name string) error {
+	_, err := c.R().Put(name)
+	return err
+}
+
+// 删除容器, 容器必须为空
+func (c ContainerApi) Delete(name string) error {
+	_, err := c.R().Delete(name)
+	return err
+}
+
+// object api
+func (c ObjectApi) List(query url.Values) ([]swift.Object, error) {
+	return listWithMarker(c.ResourceApi, query, func(item swift.Object) string {
+		return utility.OneOfString(item.Name, item.Subdir)
+	})
+}
+
+// 查询对象的元数据 (HEAD)
+func (c ObjectApi) Show(name string) (*swift.Object, error) {
+	resp, err := c.R().Head(name)
+	if err != nil {
+		return nil, err
+	}
+	header := resp.Header()
+	size, _ := strconv.ParseInt(header.Get(session.CONTENT_LENGTH), 10, 64)
+	return &swift.Object{
+		Name:              name,
+		Hash:              strings.Trim(header.Get("Etag"), `"`),
+		Bytes:             size,
+		ContentType:       header.Get(session.CONTENT_TYPE),
+		LastModified:      header.Get("Last-Modified"),
+		StaticLargeObject: strings.ToLower(header.Get("X-Static-Large-Object")) == "true",
+	}, nil
+}
+
+// 删除对象, 静态大对象同时删除它的分段
+func (c ObjectApi) Delete(name string) error {
+	object, err := c.Show(name)
+	if err != nil {
+		return err
+	}
+	query := url.Values{}
+	if object.StaticLargeObject {
+		query.Set("multipart-manifest", "delete")
+	}
+	_, err = c.R().SetQuery(query).Delete(name)
+	return err
+}
+
+// 上传对象, etag 不为空时由 swift 校验内容的 md5, 避免重试等情况下上传了不完整的内容
+func (c ObjectApi) put(ctx context.Context, name string, reader io.Reader, etag string, query url.Values) (*session.Response, error) {
+	req := c.R().SetContext(ctx).SetQuery(query).
+		SetHeader(session.CONTENT_TYPE, session.CONTENT_TYPE_STREAM)
+	if etag != "" {
+		req.SetHeader("ETag", etag)
+	}
+	return req.SetBody(reader).Put(name)
+}
+
+type segment struct {
+	index  int
+	name   string
+	offset int64
+	size   int64
+}
+
+// 计算文件中一段数据的 md5
+func md5Section(file *os.File, offset, size int64) (string, error) {
+	hash := md5.New()
+	if _, err := io.Copy(hash, io.NewSectionReader(file, offset, size)); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// 上传本地文件, 并显示进度
+//
+// 大文件以静态大对象的方式上传, 分段并行上传到 <container>_segments 中。分段的名字
+// 包含文件的修改时间、大小和分段大小, 再次上传同一个文件时, 已经上传且 md5 一致的分段会被跳过
+func (c ObjectApi) Upload(ctx context.Context, name string, filePath string, opt swift.UploadOpt) error {
+	c.ResourceApi = c.WithContext(ctx)
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if opt.SegmentSize <= 0 || stat.Size() <= opt.SegmentSize {
+		etag, err := md5Section(file, 0, stat.Size())
+		if err != nil {
+			return err
+		}
+		reader := utility.NewProcessReader(file, int(stat.Size()))
+		defer reader.Bar.Finish()
+		_, err = c.put(c.Context(), name, reader, etag, nil)
+		return err
+	}
+	return c.uploadSlo(name, file, stat, opt)
+}
+
+func (c ObjectApi) uploadSlo(name string, file *os.File, stat os.FileInfo, opt swift.UploadOpt) error {
+	segmentsContainer := c.container + SEGMENTS_CONTAINER_SUFFIX
+	segmentApi := ObjectApi{ResourceApi: c.ResourceApi, container: segmentsContainer}
+	segmentApi.ResourceUrl = segmentsContainer
+	if _, err := c.R().ResetPath().Put(segmentsContainer); err != nil {
+		return fmt.Errorf("create container %s failed: %w", segmentsContainer, err)
+	}
+
+	prefix := fmt.Sprintf("%s/slo/%d/%d/%d/", name, stat.ModTime().Unix(), stat.Size(), opt.SegmentSize)
+	uploaded := map[string]swift.Object{}
+	objects, err := segmentApi.List(url.Values{"prefix": []string{prefix}})
+	if err != nil {
+		return fmt.Errorf("list uploaded segments failed: %w", err)
+	}
+	for _, object := range objects {
+		uploaded[object.Name] = object
+	}
+
+	segments := []segment{}
+	for offset := int64(0); offset < stat.Size(); offset += opt.SegmentSize {
+		segments = append(segments, segment{
+			index: len(segments), name: fmt.Sprintf("%s%08d", prefix, len(segments)),
+			offset: offset, size: min(opt.SegmentSize, stat.Size()-offset),
+		})
+	}
+	console.Info("upload %s as %d segment(s), %d uploaded", name, len(segments), len(uploaded))
+
+	manifest := make([]swift.SloSegment, len(segments))
+	bar := pb.StartNew(int(stat.Size()))
+	defer bar.Finish()
+	ctx, cancel := context.WithCancel(c.Context())
+	defer cancel()
+	// 记录第一个错误, 并取消其他分段的上传
+	var (
+		uploadErr error
+		once      sync.Once
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			uploadErr = err
+			cancel()
+		})
+	}
+
+	syncutils.StartTasks(
+		syncutils.TaskOption{TaskName: "upload segments", MaxWorker: utility.OneOfNumber(opt.Parallel, swift.DEFAULT_UPLOAD_PARALLEL)},
+		segments,
+		func(seg segment) error {
+			if ctx.Err() != nil {
+				return nil
+			}
+			etag, err := md5Section(file, seg.offset, seg.size)
+			if err != nil {
+				fail(err)
+				return nil
+			}
+			manifest[seg.index] = swift.SloSegment{
+				Path: "/" + segmentsContainer + "/" + seg.name, Etag: etag, SizeBytes: seg.size,
+			}
+			if object, ok := uploaded[seg.name]; ok && object.Hash == etag && object.Bytes == seg.size {
+				console.Debug("segment %s is uploaded, skip", seg.name)
+				bar.Add64(seg.size)
+				return nil
+			}
+			reader := &utility.ReaderWithProcess{
+				Reader: io.NewSectionReader(file, seg.offset, seg.size), Bar: bar,
+			}
+			resp, err := segmentApi.put(ctx, seg.name, reader, etag, nil)
+			if err == nil && strings.Trim(resp.Header().Get("Etag"), `"`) != etag {
+				err = fmt.Errorf("etag of segment %s mismatch", seg.name)
+			}
+			if err != nil {
+				fail(fmt.Errorf("upload segment %d failed: %w", seg.index, err))
+			}
+			return nil
+		},
+	)
+	if interrupted := session.Interrupted(c.Context(), "upload object %s", name); interrupted != nil {
+		return interrupted
+	}
+	if uploadErr != nil {
+		return uploadErr
+	}
+	_, err = c.R().SetQuery(url.Values{"multipart-manifest": []string{"put"}}).
+		SetBody(manifest).Put(name)
+	if err != nil {
+		return fmt.Errorf("upload manifest failed: %w", err)
+	}
+	return nil
+}
+
+// 下载对象到本地文件, 并显示进度
+func (c ObjectApi) Download(ctx context.Context, name string, filePath string) error {
+	c.ResourceApi = c.WithContext(ctx)
+	object, err := c.Show(name)
+	if err != nil {
+		return err
+	}
+	if utility.IsFileExists(filePath) {
+		if err := os.Remove(filePath); err != nil {
+			return err
+		}
+	}
+	// 下载结束后停止显示进度
+	ctx, cancel := context.WithCancel(c.Context())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		req := c.R().SetContext(ctx)
+		req.SetOutput(filePath)
+		_, err := req.Get(name)
+		cancel()
+		done <- err
+	}()
+	utility.WatchFileSize(ctx, filePath, int(object.Bytes))
+	err = <-done
+	if interrupted := session.Interrupted(c.Context(), "download object %s", name); interrupted != nil {
+		os.Remove(filePath)
+		return interrupted
+	}
+	return err
+}
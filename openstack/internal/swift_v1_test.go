@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/BytemanD/skyman/openstack/model/swift"
+)
+
+// 保存在内存中的对象存储, 只实现上传需要的接口
+type fakeObjectStore struct {
+	lock      sync.Mutex
+	objects   map[string][]byte
+	manifests map[string][]swift.SloSegment
+	puts      int
+}
+
+func (s *fakeObjectStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	path := strings.TrimPrefix(r.URL.Path, "/v1/AUTH_test/")
+	switch {
+	case r.Method == http.MethodPut && !strings.Contains(path, "/"):
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPut && r.URL.Query().Get("multipart-manifest") == "put":
+		segments := []swift.SloSegment{}
+		if err := json.NewDecoder(r.Body).Decode(&segments); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.manifests[path] = segments
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		hash := md5.Sum(data)
+		// 上传对象时要求带上 ETag, 与 swift 一样校验内容
+		if r.Header.Get("Etag") != hex.EncodeToString(hash[:]) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		s.objects[path] = data
+		s.puts++
+		w.Header().Set("Etag", hex.EncodeToString(hash[:]))
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodGet:
+		container, prefix := path, r.URL.Query().Get("prefix")
+		objects := []swift.Object{}
+		if r.URL.Query().Get("marker") == "" {
+			for name, data := range s.objects {
+				if strings.HasPrefix(name, container+"/"+prefix) {
+					hash := md5.Sum(data)
+					objects = append(objects, swift.Object{
+						Name:  strings.TrimPrefix(name, container+"/"),
+						Hash:  hex.EncodeToString(hash[:]),
+						Bytes: int64(len(data)),
+					})
+				}
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(objects)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestUploadStaticLargeObject(t *testing.T) {
+	store := &fakeObjectStore{objects: map[string][]byte{}, manifests: map[string][]swift.SloSegment{}}
+	client := SwiftV1{ServiceClient: newTestServiceClient(t, "swift", "/v1/AUTH_test", store)}
+
+	filePath := filepath.Join(t.TempDir(), "data.bin")
+	content := strings.Repeat("0123456789", 25)
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	opt := swift.UploadOpt{SegmentSize: 100, Parallel: 2}
+	if err := client.Object("backup").Upload(context.Background(), "data.bin", filePath, opt); err != nil {
+		t.Fatal(err)
+	}
+	manifest := store.manifests["backup/data.bin"]
+	if len(manifest) != 3 || store.puts != 3 {
+		t.Fatalf("expect 3 segments, but got %d segments and %d puts", len(manifest), store.puts)
+	}
+	joined := ""
+	for _, segment := range manifest {
+		joined += string(store.objects[strings.TrimPrefix(segment.Path, "/")])
+	}
+	if joined != content {
+		t.Errorf("segments mismatch with file content")
+	}
+
+	// 删除一个分段后再次上传, 只需要上传缺少的分段
+	names := []string{}
+	for name := range store.objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	delete(store.objects, names[1])
+	if err := client.Object("backup").Upload(context.Background(), "data.bin", filePath, opt); err != nil {
+		t.Fatal(err)
+	}
+	if store.puts != 4 {
+		t.Errorf("expect only 1 segment to be uploaded again, but got %d puts", store.puts-3)
+	}
+	if fmt.Sprint(store.manifests["backup/data.bin"]) != fmt.Sprint(manifest) {
+		t.Errorf("manifest changed after resuming: %v", store.manifests["backup/data.bin"])
+	}
+
+	// 小文件直接上传
+	if err := client.Object("backup").Upload(context.Background(), "small.bin", filePath, swift.UploadOpt{}); err != nil {
+		t.Fatal(err)
+	}
+	if string(store.objects["backup/small.bin"]) != content {
+		t.Errorf("object mismatch with file content")
+	}
+}
@@ -0,0 +1,97 @@
+package openstack
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/BytemanD/skyman/openstack/model/nova"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	KEY_ALGORITHM_ED25519 = "ed25519"
+	KEY_ALGORITHM_RSA     = "rsa"
+	DEFAULT_RSA_BITS      = 3072
+)
+
+var KEY_ALGORITHMS = []string{KEY_ALGORITHM_ED25519, KEY_ALGORITHM_RSA}
+
+type LocalKeypair struct {
+	// PEM 格式的私钥
+	PrivateKey []byte
+	// authorized_keys 格式的公钥
+	PublicKey string
+}
+
+// 在本地生成密钥对, RSA 私钥使用 PKCS1 格式, 以便同时用于解密实例的密码, ed25519 私钥使用 OpenSSH 格式
+func GenerateKeypair(algorithm string, bits int, comment string) (*LocalKeypair, error) {
+	var (
+		publicKey interface{}
+		block     *pem.Block
+	)
+	switch algorithm {
+	case KEY_ALGORITHM_ED25519:
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		if block, err = ssh.MarshalPrivateKey(private, comment); err != nil {
+			return nil, err
+		}
+		publicKey = public
+	case KEY_ALGORITHM_RSA:
+		if bits == 0 {
+			bits = DEFAULT_RSA_BITS
+		}
+		private, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			return nil, err
+		}
+		block = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(private)}
+		publicKey = &private.PublicKey
+	default:
+		return nil, fmt.Errorf("invalid key algorithm %s, valid: %v", algorithm, KEY_ALGORITHMS)
+	}
+	sshPublicKey, err := ssh.NewPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	authorizedKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPublicKey)))
+	if comment != "" {
+		authorizedKey = fmt.Sprintf("%s %s", authorizedKey, comment)
+	}
+	return &LocalKeypair{PrivateKey: pem.EncodeToMemory(block), PublicKey: authorizedKey}, nil
+}
+
+// 在本地生成密钥对并把公钥导入到 nova, 返回的密钥对包含私钥
+func (o *Openstack) CreateKeypair(name string, algorithm string, bits int) (*nova.Keypair, error) {
+	local, err := GenerateKeypair(algorithm, bits, name)
+	if err != nil {
+		return nil, fmt.Errorf("generate keypair failed: %w", err)
+	}
+	keypair, err := o.NovaV2().Keypair().Create(name, local.PublicKey, nova.KEYPAIR_TYPE_SSH)
+	if err != nil {
+		return nil, err
+	}
+	keypair.Keypair.PrivateKey = string(local.PrivateKey)
+	return keypair, nil
+}
+
+// 保存私钥, 权限为 0600, 文件已存在时返回错误
+func SavePrivateKey(path string, privateKey string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(privateKey); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
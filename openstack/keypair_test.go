@@ -0,0 +1,91 @@
+package openstack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/openstack/fake"
+	"github.com/BytemanD/skyman/openstack/model/nova"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestCreateKeypair(t *testing.T) {
+	cloud := fake.NewCloud()
+	cloud.TaskDuration = 0
+	defer cloud.Close()
+
+	common.CONF.Identity.Api.Version = "3"
+	client := NewClient(cloud.AuthUrl(), cloud.User(), cloud.Project(), cloud.Region)
+	client.AuthPlugin.SetLocalTokenExpire(3600)
+
+	for _, algorithm := range KEY_ALGORITHMS {
+		name := "key-" + algorithm
+		keypair, err := client.CreateKeypair(name, algorithm, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signer, err := ssh.ParsePrivateKey([]byte(keypair.Keypair.PrivateKey))
+		if err != nil {
+			t.Fatalf("parse private key of %s failed: %s", algorithm, err)
+		}
+		if keypair.Keypair.Fingerprint != ssh.FingerprintLegacyMD5(signer.PublicKey()) {
+			t.Errorf("fingerprint of %s mismatch: %s", algorithm, keypair.Keypair.Fingerprint)
+		}
+		if algorithm == KEY_ALGORITHM_RSA {
+			// RSA 私钥可以用于解密实例的密码
+			if _, err := parseRSAPrivateKey([]byte(keypair.Keypair.PrivateKey)); err != nil {
+				t.Errorf("expect PKCS1 RSA private key, but got error: %s", err)
+			}
+		}
+		shown, err := client.NovaV2().Keypair().Show(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if shown.Keypair.PublicKey == "" || shown.Keypair.PrivateKey != "" {
+			t.Errorf("unexpected keypair %v", shown)
+		}
+	}
+	if _, err := client.CreateKeypair("key-dsa", "dsa", 0); err == nil {
+		t.Errorf("expect error when algorithm is invalid")
+	}
+
+	server, err := client.NovaV2().Server().Create(nova.ServerOpt{
+		Name: "vm1", Flavor: "1", Image: "cirros", KeyName: "key-ed25519",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server, err = client.NovaV2().Server().Show(server.Id); err != nil || server.KeyName != "key-ed25519" {
+		t.Errorf("expect server with keypair, but got %v (%v)", server, err)
+	}
+
+	if err := client.NovaV2().Keypair().Delete("key-rsa"); err != nil {
+		t.Fatal(err)
+	}
+	keypairs, err := client.NovaV2().Keypair().List(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keypairs) != 1 || keypairs[0].Keypair.Name != "key-ed25519" {
+		t.Errorf("expect keypair key-ed25519 left, but got %v", keypairs)
+	}
+}
+
+func TestSavePrivateKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := SavePrivateKey(path, "private key"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expect permission 0600, but got %v", info.Mode().Perm())
+	}
+	if err := SavePrivateKey(path, "other key"); err == nil {
+		t.Errorf("expect error when file exists")
+	}
+}
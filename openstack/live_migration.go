@@ -0,0 +1,117 @@
+package openstack
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/openstack/model/nova"
+	"github.com/BytemanD/skyman/openstack/session"
+	"github.com/BytemanD/skyman/utility"
+)
+
+const (
+	MIGRATION_POLICY_FORCE_COMPLETE = "force-complete"
+	MIGRATION_POLICY_ABORT          = "abort"
+)
+
+var MIGRATION_POLICIES = []string{MIGRATION_POLICY_FORCE_COMPLETE, MIGRATION_POLICY_ABORT}
+
+type MigrationWatchOpt struct {
+	Interval int
+	// 从迁移创建时算起, 处于 running 状态的迁移超过 MaxDuration 后执行 Policy, 0 表示不处理
+	MaxDuration time.Duration
+	Policy      string
+}
+
+// 查询实例正在进行的热迁移
+func (o *Openstack) RunningLiveMigration(serverId string) (*nova.Migration, error) {
+	migrations, err := o.NovaV2().Server().ListMigrations(serverId, nil)
+	if err != nil {
+		return nil, err
+	}
+	var running *nova.Migration
+	for i, migration := range migrations {
+		if running == nil || migration.Id > running.Id {
+			running = &migrations[i]
+		}
+	}
+	if running == nil {
+		return nil, fmt.Errorf("server %s has no running live migration", serverId)
+	}
+	return running, nil
+}
+
+// 解析迁移的创建时间, nova 返回的时间不带时区, 为 UTC 时间
+func migrationCreatedAt(migration nova.Migration) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+		if createdAt, err := time.Parse(layout, migration.CreatedAt); err == nil {
+			return createdAt, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid created_at %q of migration %d", migration.CreatedAt, migration.Id)
+}
+
+func (o *Openstack) applyMigrationPolicy(serverId string, migrationId int, policy string) error {
+	switch policy {
+	case MIGRATION_POLICY_FORCE_COMPLETE:
+		return o.NovaV2().Server().ForceCompleteMigration(serverId, migrationId)
+	case MIGRATION_POLICY_ABORT:
+		return o.NovaV2().Server().AbortMigration(serverId, migrationId)
+	default:
+		return fmt.Errorf("invalid migration policy %s, valid: %v", policy, MIGRATION_POLICIES)
+	}
+}
+
+// 监控实例正在进行的热迁移直到结束, 每次查询到迁移详情时调用 onProgress, 返回迁移的最终状态
+//
+// 按 abort 策略取消的迁移不视为失败
+func (o *Openstack) WatchLiveMigration(ctx context.Context, serverId string, opt MigrationWatchOpt, onProgress func(nova.Migration)) (*nova.Migration, error) {
+	migration, err := o.RunningLiveMigration(serverId)
+	if err != nil {
+		return nil, err
+	}
+	migrationId, applied := migration.Id, false
+	startTime, err := migrationCreatedAt(*migration)
+	if err != nil {
+		console.Warn("[%s] %s, use current time as start time", serverId, err)
+		startTime = time.Now()
+	}
+	err = utility.RetryError(
+		utility.RetryCondition{
+			Ctx: ctx, IntervalMin: time.Second * time.Duration(opt.Interval),
+		},
+		func() (bool, error) {
+			detail, err := o.NovaV2().Server().ShowMigration(serverId, migrationId)
+			if session.IsNotFound(err) {
+				// 迁移已经结束
+				return false, nil
+			} else if err != nil {
+				return false, err
+			}
+			if onProgress != nil {
+				onProgress(*detail)
+			}
+			if !applied && opt.Policy != "" && opt.MaxDuration > 0 && detail.Status == "running" &&
+				time.Since(startTime) >= opt.MaxDuration {
+				console.Warn("[%s] migration %d takes more than %v, %s it", serverId, migrationId, opt.MaxDuration, opt.Policy)
+				if err := o.applyMigrationPolicy(serverId, migrationId, opt.Policy); err != nil {
+					return false, fmt.Errorf("%s migration %d failed: %w", opt.Policy, migrationId, err)
+				}
+				applied = true
+			}
+			return true, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	statuses := []string{"completed"}
+	if applied && opt.Policy == MIGRATION_POLICY_ABORT {
+		// 迁移是按策略取消的, 不是迁移失败
+		statuses = append(statuses, "cancelled")
+	}
+	return o.NovaV2().Migration().Wait(ctx, serverId, nova.MIGRATION_TYPE_LIVE, migrationId-1,
+		statuses, 0, opt.Interval)
+}
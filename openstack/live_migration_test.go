@@ -0,0 +1,78 @@
+package openstack
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/openstack/fake"
+	"github.com/BytemanD/skyman/openstack/model/nova"
+)
+
+func TestWatchLiveMigration(t *testing.T) {
+	cloud := fake.NewCloud()
+	cloud.TaskDuration = 0
+	defer cloud.Close()
+
+	common.CONF.Identity.Api.Version = "3"
+	client := NewClient(cloud.AuthUrl(), cloud.User(), cloud.Project(), cloud.Region)
+	client.AuthPlugin.SetLocalTokenExpire(3600)
+
+	server, err := client.NovaV2().Server().Create(nova.ServerOpt{Name: "vm1", Flavor: "1", Image: "cirros"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cloud.WaitTasks(0)
+	if server, err = client.NovaV2().Server().Show(server.Id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.WatchLiveMigration(context.Background(), server.Id, MigrationWatchOpt{}, nil); err == nil {
+		t.Fatalf("expect error when server has no running live migration")
+	}
+	// 迁移不会自己结束, 只能强制完成或取消
+	cloud.TaskDuration = time.Hour
+
+	for _, c := range []struct{ policy, status string }{
+		{MIGRATION_POLICY_ABORT, "cancelled"},
+		{MIGRATION_POLICY_FORCE_COMPLETE, "completed"},
+	} {
+		if err := client.NovaV2().Server().LiveMigrate(server.Id, "auto", ""); err != nil {
+			t.Fatal(err)
+		}
+		progress := []nova.Migration{}
+		migration, err := client.WatchLiveMigration(context.Background(), server.Id,
+			MigrationWatchOpt{MaxDuration: time.Millisecond, Policy: c.policy},
+			func(m nova.Migration) { progress = append(progress, m) },
+		)
+		if migration == nil || migration.Status != c.status {
+			t.Fatalf("expect migration %s after %s, but got %v (%v)", c.status, c.policy, migration, err)
+		}
+		if err != nil {
+			t.Errorf("unexpected error after %s: %s", c.policy, err)
+		}
+		if len(progress) == 0 || progress[0].MemoryTotalBytes != 1024*1024*1024 {
+			t.Errorf("expect memory progress of migration, but got %v", progress)
+		}
+	}
+	moved, err := client.NovaV2().Server().Show(server.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.Status != "ACTIVE" || moved.Host == server.Host {
+		t.Errorf("expect server ACTIVE on other host, but got %s on %s", moved.Status, moved.Host)
+	}
+}
+
+func TestMigrationCreatedAt(t *testing.T) {
+	expected := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, createdAt := range []string{"2024-01-02T03:04:05.000000", "2024-01-02T03:04:05", "2024-01-02T03:04:05Z"} {
+		parsed, err := migrationCreatedAt(nova.Migration{CreatedAt: createdAt})
+		if err != nil || !parsed.Equal(expected) {
+			t.Errorf("expect %s for %s, but got %s (%v)", expected, createdAt, parsed, err)
+		}
+	}
+	if _, err := migrationCreatedAt(nova.Migration{}); err == nil {
+		t.Errorf("expect error when created_at is empty")
+	}
+}
@@ -30,9 +30,22 @@ type Password struct {
 	User User `json:"user"`
 }
 
+type ApplicationCredential struct {
+	Id     string `json:"id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Secret string `json:"secret"`
+	User   *User  `json:"user,omitempty"`
+}
+
+type TokenId struct {
+	Id string `json:"id"`
+}
+
 type Identity struct {
-	Methods  []string `json:"methods,omitempty"`
-	Password Password `json:"password,omitempty"`
+	Methods               []string               `json:"methods,omitempty"`
+	Password              *Password              `json:"password,omitempty"`
+	ApplicationCredential *ApplicationCredential `json:"application_credential,omitempty"`
+	Token                 *TokenId               `json:"token,omitempty"`
 }
 
 type Project struct {
@@ -115,7 +128,7 @@ func NewTokenCache(token Token, tokenId string, expiredAt time.Time) TokenCache
 
 type Auth struct {
 	Identity Identity `json:"identity,omitempty"`
-	Scope    Scope    `json:"scope,omitempty"`
+	Scope    *Scope   `json:"scope,omitempty"`
 }
 
 type AuthBody struct {
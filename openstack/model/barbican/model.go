@@ -0,0 +1,84 @@
+package barbican
+
+import (
+	"path"
+)
+
+const (
+	ACL_READ = "read"
+)
+
+var SECRET_TYPES = []string{"opaque", "symmetric", "public", "private", "passphrase", "certificate"}
+
+// 资源 id 为 ref 链接的最后一段, 例如 http://host:9311/v1/secrets/<id>
+func idFromRef(ref string) string {
+	if ref == "" {
+		return ""
+	}
+	return path.Base(ref)
+}
+
+type Secret struct {
+	Id           string            `json:"-"`
+	SecretRef    string            `json:"secret_ref"`
+	Name         string            `json:"name"`
+	Status       string            `json:"status,omitempty"`
+	SecretType   string            `json:"secret_type,omitempty"`
+	Algorithm    string            `json:"algorithm,omitempty"`
+	BitLength    int               `json:"bit_length,omitempty"`
+	Mode         string            `json:"mode,omitempty"`
+	ContentTypes map[string]string `json:"content_types,omitempty"`
+	Expiration   string            `json:"expiration,omitempty"`
+	CreatorId    string            `json:"creator_id,omitempty"`
+	Created      string            `json:"created,omitempty"`
+	Updated      string            `json:"updated,omitempty"`
+}
+
+func (s *Secret) SetIdFromRef() {
+	s.Id = idFromRef(s.SecretRef)
+}
+
+// 默认的 payload 类型, 没有 payload 时返回空
+func (s Secret) DefaultContentType() string {
+	return s.ContentTypes["default"]
+}
+
+type SecretOpt struct {
+	Name                   string `json:"name,omitempty"`
+	SecretType             string `json:"secret_type,omitempty"`
+	Algorithm              string `json:"algorithm,omitempty"`
+	BitLength              int    `json:"bit_length,omitempty"`
+	Mode                   string `json:"mode,omitempty"`
+	Expiration             string `json:"expiration,omitempty"`
+	Payload                string `json:"payload,omitempty"`
+	PayloadContentType     string `json:"payload_content_type,omitempty"`
+	PayloadContentEncoding string `json:"payload_content_encoding,omitempty"`
+}
+
+type SecretRef struct {
+	Name      string `json:"name"`
+	SecretRef string `json:"secret_ref"`
+}
+
+type Container struct {
+	Id           string      `json:"-"`
+	ContainerRef string      `json:"container_ref"`
+	Name         string      `json:"name"`
+	Type         string      `json:"type"`
+	Status       string      `json:"status,omitempty"`
+	SecretRefs   []SecretRef `json:"secret_refs"`
+	CreatorId    string      `json:"creator_id,omitempty"`
+	Created      string      `json:"created,omitempty"`
+	Updated      string      `json:"updated,omitempty"`
+}
+
+func (c *Container) SetIdFromRef() {
+	c.Id = idFromRef(c.ContainerRef)
+}
+
+type ACL struct {
+	ProjectAccess bool     `json:"project-access"`
+	Users         []string `json:"users,omitempty"`
+	Created       string   `json:"created,omitempty"`
+	Updated       string   `json:"updated,omitempty"`
+}
@@ -100,6 +100,16 @@ type VolumeType struct {
 	ExtraSpecs                 map[string]string `json:"extra_specs,omitempty"`
 }
 
+// 卷类型的加密参数, 例如 provider=luks, cipher=aes-xts-plain64, key_size=256
+type VolumeTypeEncryption struct {
+	EncryptionId    string `json:"encryption_id,omitempty"`
+	VolumeTypeId    string `json:"volume_type_id,omitempty"`
+	Provider        string `json:"provider"`
+	Cipher          string `json:"cipher,omitempty"`
+	KeySize         int    `json:"key_size,omitempty"`
+	ControlLocation string `json:"control_location,omitempty"`
+}
+
 func (volumeType VolumeType) GetExtraSpecsList() []string {
 	properties := []string{}
 	for key, value := range volumeType.ExtraSpecs {
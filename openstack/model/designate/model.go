@@ -0,0 +1,71 @@
+package designate
+
+import (
+	"strings"
+)
+
+const (
+	RECORD_A    = "A"
+	RECORD_AAAA = "AAAA"
+)
+
+type Zone struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Email       string `json:"email,omitempty"`
+	Ttl         int    `json:"ttl,omitempty"`
+	Serial      int    `json:"serial,omitempty"`
+	Status      string `json:"status,omitempty"`
+	Action      string `json:"action,omitempty"`
+	Type        string `json:"type,omitempty"`
+	PoolId      string `json:"pool_id,omitempty"`
+	ProjectId   string `json:"project_id,omitempty"`
+	Description string `json:"description,omitempty"`
+	CreatedAt   string `json:"created_at,omitempty"`
+	UpdatedAt   string `json:"updated_at,omitempty"`
+}
+
+type RecordSet struct {
+	Id          string   `json:"id"`
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Records     []string `json:"records"`
+	Ttl         int      `json:"ttl,omitempty"`
+	Status      string   `json:"status,omitempty"`
+	Action      string   `json:"action,omitempty"`
+	ZoneId      string   `json:"zone_id,omitempty"`
+	ZoneName    string   `json:"zone_name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	CreatedAt   string   `json:"created_at,omitempty"`
+	UpdatedAt   string   `json:"updated_at,omitempty"`
+}
+
+type FloatingIPPtr struct {
+	Id          string `json:"id"`
+	Ptrdname    string `json:"ptrdname"`
+	Address     string `json:"address,omitempty"`
+	Ttl         int    `json:"ttl,omitempty"`
+	Status      string `json:"status,omitempty"`
+	Action      string `json:"action,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// 转换为以 . 结尾的完整域名
+func Fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+// 记录名, 不是以 . 结尾的名字认为是相对于 zone 的名字, 例如 www -> www.example.com.
+func RecordName(name string, zone string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	zone = Fqdn(zone)
+	if strings.HasSuffix(name+".", "."+zone) || name+"." == zone {
+		return name + "."
+	}
+	return name + "." + zone
+}
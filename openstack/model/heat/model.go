@@ -0,0 +1,138 @@
+package heat
+
+import (
+	"net/url"
+	"strings"
+)
+
+const (
+	STATUS_COMPLETE    = "COMPLETE"
+	STATUS_FAILED      = "FAILED"
+	STATUS_IN_PROGRESS = "IN_PROGRESS"
+
+	ACTION_CREATE   = "CREATE"
+	ACTION_UPDATE   = "UPDATE"
+	ACTION_DELETE   = "DELETE"
+	ACTION_ROLLBACK = "ROLLBACK"
+
+	// heat 默认允许的最大嵌套深度 (max_nested_stack_depth)
+	MAX_NESTED_DEPTH = 5
+)
+
+type Link struct {
+	Href string `json:"href"`
+	Rel  string `json:"rel"`
+}
+
+// 从 rel 为 stack 的链接中解析资源所属的 stack 名字
+//
+//	http://heat:8004/v1/<project>/stacks/<stack name>/<stack id>
+func stackNameFromLinks(links []Link) string {
+	for _, link := range links {
+		if link.Rel != "stack" {
+			continue
+		}
+		parsed, err := url.Parse(link.Href)
+		if err != nil {
+			return ""
+		}
+		paths := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+		if len(paths) >= 2 {
+			return paths[len(paths)-2]
+		}
+	}
+	return ""
+}
+
+type Output struct {
+	OutputKey   string      `json:"output_key"`
+	OutputValue interface{} `json:"output_value"`
+	Description string      `json:"description,omitempty"`
+	OutputError string      `json:"output_error,omitempty"`
+}
+
+type Stack struct {
+	Id                string            `json:"id"`
+	StackName         string            `json:"stack_name"`
+	Description       string            `json:"description,omitempty"`
+	StackStatus       string            `json:"stack_status,omitempty"`
+	StackStatusReason string            `json:"stack_status_reason,omitempty"`
+	StackOwner        string            `json:"stack_owner,omitempty"`
+	Project           string            `json:"project,omitempty"`
+	Parent            string            `json:"parent,omitempty"`
+	CreationTime      string            `json:"creation_time,omitempty"`
+	UpdatedTime       string            `json:"updated_time,omitempty"`
+	DeletionTime      string            `json:"deletion_time,omitempty"`
+	TimeoutMins       int               `json:"timeout_mins,omitempty"`
+	DisableRollback   bool              `json:"disable_rollback"`
+	Parameters        map[string]string `json:"parameters,omitempty"`
+	Outputs           []Output          `json:"outputs,omitempty"`
+	Tags              []string          `json:"tags,omitempty"`
+	Links             []Link            `json:"links,omitempty"`
+}
+
+// 状态中的操作, 例如 CREATE_IN_PROGRESS 中的 CREATE
+func (stack Stack) Action() string {
+	action, _, _ := strings.Cut(stack.StackStatus, "_")
+	return action
+}
+func (stack Stack) IsComplete() bool {
+	return strings.HasSuffix(stack.StackStatus, "_"+STATUS_COMPLETE)
+}
+func (stack Stack) IsFailed() bool {
+	return strings.HasSuffix(stack.StackStatus, "_"+STATUS_FAILED)
+}
+func (stack Stack) IsInProgress() bool {
+	return strings.HasSuffix(stack.StackStatus, "_"+STATUS_IN_PROGRESS)
+}
+
+type Resource struct {
+	ResourceName         string   `json:"resource_name"`
+	LogicalResourceId    string   `json:"logical_resource_id"`
+	PhysicalResourceId   string   `json:"physical_resource_id"`
+	ResourceType         string   `json:"resource_type"`
+	ResourceStatus       string   `json:"resource_status"`
+	ResourceStatusReason string   `json:"resource_status_reason,omitempty"`
+	ParentResource       string   `json:"parent_resource,omitempty"`
+	RequiredBy           []string `json:"required_by,omitempty"`
+	CreationTime         string   `json:"creation_time,omitempty"`
+	UpdatedTime          string   `json:"updated_time,omitempty"`
+	Links                []Link   `json:"links,omitempty"`
+}
+
+func (resource Resource) StackName() string {
+	return stackNameFromLinks(resource.Links)
+}
+func (resource Resource) IsFailed() bool {
+	return strings.HasSuffix(resource.ResourceStatus, "_"+STATUS_FAILED)
+}
+
+type Event struct {
+	Id                   string `json:"id"`
+	EventTime            string `json:"event_time"`
+	ResourceName         string `json:"resource_name"`
+	LogicalResourceId    string `json:"logical_resource_id"`
+	PhysicalResourceId   string `json:"physical_resource_id"`
+	ResourceStatus       string `json:"resource_status"`
+	ResourceStatusReason string `json:"resource_status_reason,omitempty"`
+	Links                []Link `json:"links,omitempty"`
+}
+
+func (event Event) StackName() string {
+	return stackNameFromLinks(event.Links)
+}
+
+// 创建或更新 stack 的参数
+//
+// Template 和环境文件由 heat 解析; Files 包含模板中引用的文件 (get_file, 嵌套模板等),
+// key 为文件的 file:// 绝对路径, 模板中引用的本地路径也替换为这个 key
+type StackOpt struct {
+	StackName        string            `json:"stack_name,omitempty"`
+	Template         string            `json:"template,omitempty"`
+	Files            map[string]string `json:"files,omitempty"`
+	EnvironmentFiles []string          `json:"environment_files,omitempty"`
+	Parameters       map[string]string `json:"parameters,omitempty"`
+	TimeoutMins      int               `json:"timeout_mins,omitempty"`
+	DisableRollback  *bool             `json:"disable_rollback,omitempty"`
+	Tags             string            `json:"tags,omitempty"`
+}
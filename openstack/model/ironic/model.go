@@ -0,0 +1,74 @@
+package ironic
+
+import (
+	"strings"
+)
+
+const (
+	POWER_ON  = "power on"
+	POWER_OFF = "power off"
+	REBOOTING = "rebooting"
+
+	PROVISION_AVAILABLE  = "available"
+	PROVISION_ACTIVE     = "active"
+	PROVISION_MANAGEABLE = "manageable"
+)
+
+// 设置节点电源状态的目标, 命令行中使用 key
+var POWER_TARGETS = map[string]string{
+	"on": POWER_ON, "off": POWER_OFF, "reboot": REBOOTING,
+	"soft-off": "soft power off", "soft-reboot": "soft rebooting",
+}
+
+// 设置节点部署状态的目标, 以及操作成功后节点的部署状态
+var PROVISION_TARGETS = map[string]string{
+	"manage": PROVISION_MANAGEABLE, "inspect": PROVISION_MANAGEABLE, "clean": PROVISION_MANAGEABLE,
+	"provide": PROVISION_AVAILABLE, "deleted": PROVISION_AVAILABLE,
+	"deploy": PROVISION_ACTIVE, "rebuild": PROVISION_ACTIVE, "unrescue": PROVISION_ACTIVE, "adopt": PROVISION_ACTIVE,
+	"rescue": "rescue",
+}
+
+type Node struct {
+	Uuid                 string                 `json:"uuid"`
+	Name                 string                 `json:"name,omitempty"`
+	PowerState           string                 `json:"power_state,omitempty"`
+	TargetPowerState     string                 `json:"target_power_state,omitempty"`
+	ProvisionState       string                 `json:"provision_state,omitempty"`
+	TargetProvisionState string                 `json:"target_provision_state,omitempty"`
+	Maintenance          bool                   `json:"maintenance"`
+	MaintenanceReason    string                 `json:"maintenance_reason,omitempty"`
+	Fault                string                 `json:"fault,omitempty"`
+	LastError            string                 `json:"last_error,omitempty"`
+	InstanceUuid         string                 `json:"instance_uuid,omitempty"`
+	Driver               string                 `json:"driver,omitempty"`
+	ResourceClass        string                 `json:"resource_class,omitempty"`
+	ConductorGroup       string                 `json:"conductor_group,omitempty"`
+	Conductor            string                 `json:"conductor,omitempty"`
+	Properties           map[string]interface{} `json:"properties,omitempty"`
+	ProvisionUpdatedAt   string                 `json:"provision_updated_at,omitempty"`
+	CreatedAt            string                 `json:"created_at,omitempty"`
+	UpdatedAt            string                 `json:"updated_at,omitempty"`
+}
+
+// 部署状态是否失败, 例如 deploy failed, clean failed
+func (node Node) IsProvisionFailed() bool {
+	return strings.HasSuffix(node.ProvisionState, " failed") || node.ProvisionState == "error"
+}
+
+type Port struct {
+	Uuid                string                 `json:"uuid"`
+	Address             string                 `json:"address"`
+	NodeUuid            string                 `json:"node_uuid,omitempty"`
+	PortgroupUuid       string                 `json:"portgroup_uuid,omitempty"`
+	PxeEnabled          bool                   `json:"pxe_enabled"`
+	PhysicalNetwork     string                 `json:"physical_network,omitempty"`
+	LocalLinkConnection map[string]interface{} `json:"local_link_connection,omitempty"`
+	CreatedAt           string                 `json:"created_at,omitempty"`
+	UpdatedAt           string                 `json:"updated_at,omitempty"`
+}
+
+type Driver struct {
+	Name  string   `json:"name"`
+	Type  string   `json:"type,omitempty"`
+	Hosts []string `json:"hosts,omitempty"`
+}
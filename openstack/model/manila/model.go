@@ -0,0 +1,63 @@
+package manila
+
+import (
+	"github.com/BytemanD/skyman/openstack/model"
+)
+
+var SHARE_PROTOCOLS = []string{"NFS", "CIFS", "GLUSTERFS", "HDFS", "CEPHFS", "MAPRFS"}
+
+type Share struct {
+	model.Resource
+	Size             int               `json:"size,omitempty"`
+	ShareProto       string            `json:"share_proto,omitempty"`
+	ShareType        string            `json:"share_type,omitempty"`
+	ShareTypeName    string            `json:"share_type_name,omitempty"`
+	ShareNetworkId   string            `json:"share_network_id,omitempty"`
+	SnapshotId       string            `json:"snapshot_id,omitempty"`
+	AvailabilityZone string            `json:"availability_zone,omitempty"`
+	Host             string            `json:"host,omitempty"`
+	IsPublic         bool              `json:"is_public"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+type ExportLocation struct {
+	Id        string `json:"id"`
+	Path      string `json:"path"`
+	Preferred bool   `json:"preferred"`
+}
+
+type AccessRule struct {
+	Id          string `json:"id"`
+	ShareId     string `json:"share_id,omitempty"`
+	AccessType  string `json:"access_type"`
+	AccessTo    string `json:"access_to"`
+	AccessLevel string `json:"access_level"`
+	State       string `json:"state,omitempty"`
+	AccessKey   string `json:"access_key,omitempty"`
+	CreatedAt   string `json:"created_at,omitempty"`
+}
+
+type Snapshot struct {
+	model.Resource
+	ShareId    string `json:"share_id"`
+	Size       int    `json:"size,omitempty"`
+	ShareSize  int    `json:"share_size,omitempty"`
+	ShareProto string `json:"share_proto,omitempty"`
+}
+
+type ShareNetwork struct {
+	model.Resource
+	NeutronNetId    string `json:"neutron_net_id,omitempty"`
+	NeutronSubnetId string `json:"neutron_subnet_id,omitempty"`
+	NetworkType     string `json:"network_type,omitempty"`
+	SegmentationId  int    `json:"segmentation_id,omitempty"`
+	Cidr            string `json:"cidr,omitempty"`
+}
+
+type ShareType struct {
+	Id          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	IsPublic    bool              `json:"share_type_access:is_public"`
+	ExtraSpecs  map[string]string `json:"extra_specs,omitempty"`
+}
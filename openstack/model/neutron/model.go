@@ -88,6 +88,7 @@ type Port struct {
 	model.Resource
 	AdminStateUp    bool                   `json:"admin_state_up,omitempty"`
 	MACAddress      string                 `json:"mac_address"`
+	NetworkId       string                 `json:"network_id,omitempty"`
 	BindingHostId   string                 `json:"binding:host_id,omitempty"`
 	BindingVnicType string                 `json:"binding:vnic_type,omitempty"`
 	BindingVifType  string                 `json:"binding:vif_type,omitempty"`
@@ -211,6 +212,17 @@ type QosPolicy struct {
 	Rules   []QosRule `json:"rules"`
 }
 
+// neutron 扩展, 例如 dns-integration
+type Extension struct {
+	Alias       string `json:"alias"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Updated     string `json:"updated,omitempty"`
+}
+
+// 网络设置 dns_domain 后为端口注册 DNS 记录
+const EXTENSION_DNS_INTEGRATION = "dns-integration"
+
 type Routers []Router
 type Networks []Network
 type Ports []Port
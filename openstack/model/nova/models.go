@@ -91,6 +91,7 @@ type Server struct {
 	VmState            string                  `json:"OS-EXT-STS:vm_state,omitempty"`
 	Host               string                  `json:"OS-EXT-SRV-ATTR:host,omitempty"`
 	HypervisorHostname string                  `json:"OS-EXT-SRV-ATTR:hypervisor_hostname,omitempty"`
+	HostId             string                  `json:"hostId"`
 	AZ                 string                  `json:"OS-EXT-AZ:availability_zone,omitempty"`
 	Flavor             Flavor                  `json:"flavor,omitempty"`
 	Image              interface{}             `json:"image,omitempty"`
@@ -109,6 +110,10 @@ type Server struct {
 	KeyName            string                  `json:"key_name,omitempty"`
 	SecurityGroups     []neutron.SecurityGroup `json:"security_groups,omitempty"`
 	Progress           float32                 `json:"progress"`
+	Metadata           map[string]string       `json:"metadata,omitempty"`
+	Tags               []string                `json:"tags,omitempty"`
+	Locked             bool                    `json:"locked,omitempty"`
+	LockedReason       string                  `json:"locked_reason,omitempty"`
 }
 type Image struct {
 	Id   string `json:"id,omitempty"`
@@ -128,6 +133,16 @@ type ServersResp struct {
 	Items []Server
 }
 
+// 实例的 metadata 是否包含所有指定的 key=value
+func (s Server) MatchMetadata(metadata map[string]string) bool {
+	for k, v := range metadata {
+		if value, ok := s.Metadata[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
 func (s Server) ImageId() string {
 	if p, ok := s.Image.(map[string]interface{}); ok {
 		return p["id"].(string)
@@ -455,11 +470,20 @@ func (hypervisor *Hypervisor) SetNumaNodes(data []byte) error {
 
 type Hypervisors []Hypervisor
 
+const (
+	KEYPAIR_TYPE_SSH  = "ssh"
+	KEYPAIR_TYPE_X509 = "x509"
+)
+
 type keypair struct {
 	Name        string `json:"name"`
 	Type        string `json:"type"`
 	Fingerprint string `json:"fingerprint"`
 	PublicKey   string `json:"public_key"`
+	UserId      string `json:"user_id,omitempty"`
+	CreatedAt   string `json:"created_at,omitempty"`
+	// 只有由 nova 生成密钥对时才会返回 (2.92 之前的版本)
+	PrivateKey string `json:"private_key,omitempty"`
 }
 
 type Keypair struct {
@@ -572,8 +596,19 @@ type Console struct {
 	Protocol string `json:"protocol,omitempty"`
 }
 
+const (
+	MIGRATION_TYPE_LIVE       = "live-migration"
+	MIGRATION_TYPE_COLD       = "migration"
+	MIGRATION_TYPE_RESIZE     = "resize"
+	MIGRATION_TYPE_EVACUATION = "evacuation"
+)
+
+// 迁移失败的状态
+var MIGRATION_FAILED_STATUS = []string{"error", "failed", "cancelled", "reverted"}
+
 type Migration struct {
 	Id                int    `json:"id"`
+	Uuid              string `json:"uuid,omitempty"`
 	OldInstanceTypeId int    `json:"old_instance_type_id"`
 	NewInstanceTypeId int    `json:"new_instance_type_id"`
 	InstanceUUID      string `json:"instance_uuid"`
@@ -588,6 +623,33 @@ type Migration struct {
 	DestRegion        string `json:"dest_regoin,omitempty"`
 	CreatedAt         string `json:"created_at,omitempty"`
 	UpdatedAt         string `json:"updated_at,omitempty"`
+	// 以下字段只在查询实例正在进行的热迁移详情时返回
+	ServerUuid           string `json:"server_uuid,omitempty"`
+	MemoryTotalBytes     int64  `json:"memory_total_bytes,omitempty"`
+	MemoryProcessedBytes int64  `json:"memory_processed_bytes,omitempty"`
+	MemoryRemainingBytes int64  `json:"memory_remaining_bytes,omitempty"`
+	DiskTotalBytes       int64  `json:"disk_total_bytes,omitempty"`
+	DiskProcessedBytes   int64  `json:"disk_processed_bytes,omitempty"`
+	DiskRemainingBytes   int64  `json:"disk_remaining_bytes,omitempty"`
+}
+
+func bytesProgress(processed, total, remaining int64) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%s/%s (%d%%), remaining %s",
+		humanize.IBytes(uint64(processed)), humanize.IBytes(uint64(total)),
+		processed*100/total, humanize.IBytes(uint64(remaining)))
+}
+
+// 内存的迁移进度, 例如 512 MiB/1.0 GiB (50%), remaining 512 MiB
+func (migration Migration) MemoryProgress() string {
+	return bytesProgress(migration.MemoryProcessedBytes, migration.MemoryTotalBytes, migration.MemoryRemainingBytes)
+}
+
+// 磁盘的迁移进度, 只有块迁移时才有
+func (migration Migration) DiskProgress() string {
+	return bytesProgress(migration.DiskProcessedBytes, migration.DiskTotalBytes, migration.DiskRemainingBytes)
 }
 
 type ZoneState struct {
@@ -636,10 +698,24 @@ func (agg Aggregate) MarshalMetadata() string {
 }
 
 // {"policies": ["soft-anti-affinity"], "name": "soft-anti-affinity", "custom": false, "members": [], "id": "7357b2f7-d004-4c72-b38a-d3a156827c11", "metadata": {}}]
+const (
+	GROUP_AFFINITY           = "affinity"
+	GROUP_ANTI_AFFINITY      = "anti-affinity"
+	GROUP_SOFT_AFFINITY      = "soft-affinity"
+	GROUP_SOFT_ANTI_AFFINITY = "soft-anti-affinity"
+
+	GROUP_RULE_MAX_SERVER_PER_HOST = "max_server_per_host"
+)
+
+var GROUP_POLICIES = []string{GROUP_AFFINITY, GROUP_ANTI_AFFINITY, GROUP_SOFT_AFFINITY, GROUP_SOFT_ANTI_AFFINITY}
+
 type ServerGroup struct {
-	Id        string                 `json:"id"`
-	Name      string                 `json:"name"`
-	Policies  []string               `json:"policies"`
+	Id       string   `json:"id"`
+	Name     string   `json:"name"`
+	Policies []string `json:"policies,omitempty"`
+	// 2.64 及以上版本使用 policy 和 rules 代替 policies
+	Policy    string                 `json:"policy,omitempty"`
+	Rules     map[string]interface{} `json:"rules,omitempty"`
 	Custom    bool                   `json:"custom"`
 	Members   []string               `json:"members"`
 	Metadata  map[string]interface{} `json:"metadata"`
@@ -647,6 +723,31 @@ type ServerGroup struct {
 	UserId    string                 `json:"user_id"`
 }
 
+func (serverGroup ServerGroup) GetPolicy() string {
+	if serverGroup.Policy != "" {
+		return serverGroup.Policy
+	}
+	if len(serverGroup.Policies) > 0 {
+		return serverGroup.Policies[0]
+	}
+	return ""
+}
+
+// 每个节点上最多的成员数, 只对 anti-affinity 有效, 默认为 1
+func (serverGroup ServerGroup) MaxServerPerHost() int {
+	if value, ok := serverGroup.Rules[GROUP_RULE_MAX_SERVER_PER_HOST].(float64); ok && value > 0 {
+		return int(value)
+	}
+	return 1
+}
+func (serverGroup ServerGroup) GetRulesList() []string {
+	rules := []string{}
+	for k, v := range serverGroup.Rules {
+		rules = append(rules, fmt.Sprintf("%s=%v", k, v))
+	}
+	return rules
+}
+
 func (serverGroup ServerGroup) GetMetadataList() []string {
 	metadataList := []string{}
 	for k, v := range serverGroup.Metadata {
@@ -32,6 +32,11 @@ type ServerOpt struct {
 	KeyName              string                  `json:"key_name,omitempty"`
 	AdminPass            string                  `json:"adminPass,omitempty"`
 	SecurityGroups       []neutron.SecurityGroup `json:"security_groups,omitempty"`
+	Metadata             map[string]string       `json:"metadata,omitempty"`
+	// 2.52 及以上版本支持创建时指定标签
+	Tags []string `json:"tags,omitempty"`
+	// 调度参数, 例如 group, 不在 server 中, 而是作为 os:scheduler_hints 发送
+	SchedulerHints map[string]interface{} `json:"-"`
 }
 
 func ParseServerOptNetworks(nics []string) []ServerOptNetwork {
@@ -0,0 +1,149 @@
+package octavia
+
+import (
+	"strings"
+
+	"github.com/BytemanD/skyman/openstack/model"
+)
+
+const (
+	PROVISIONING_ACTIVE  = "ACTIVE"
+	PROVISIONING_ERROR   = "ERROR"
+	PROVISIONING_DELETED = "DELETED"
+)
+
+type IdRef struct {
+	Id string `json:"id"`
+}
+
+func joinIds(refs []IdRef) string {
+	ids := []string{}
+	for _, ref := range refs {
+		ids = append(ids, ref.Id)
+	}
+	return strings.Join(ids, "\n")
+}
+
+type LoadBalancer struct {
+	model.Resource
+	ProvisioningStatus string   `json:"provisioning_status,omitempty"`
+	OperatingStatus    string   `json:"operating_status,omitempty"`
+	AdminStateUp       bool     `json:"admin_state_up"`
+	VipAddress         string   `json:"vip_address,omitempty"`
+	VipPortId          string   `json:"vip_port_id,omitempty"`
+	VipSubnetId        string   `json:"vip_subnet_id,omitempty"`
+	VipNetworkId       string   `json:"vip_network_id,omitempty"`
+	Provider           string   `json:"provider,omitempty"`
+	FlavorId           string   `json:"flavor_id,omitempty"`
+	AvailabilityZone   string   `json:"availability_zone,omitempty"`
+	Listeners          []IdRef  `json:"listeners,omitempty"`
+	Pools              []IdRef  `json:"pools,omitempty"`
+	Tags               []string `json:"tags,omitempty"`
+}
+
+func (lb LoadBalancer) ListenerIds() string {
+	return joinIds(lb.Listeners)
+}
+func (lb LoadBalancer) PoolIds() string {
+	return joinIds(lb.Pools)
+}
+
+type LoadBalancerStats struct {
+	ActiveConnections int `json:"active_connections"`
+	BytesIn           int `json:"bytes_in"`
+	BytesOut          int `json:"bytes_out"`
+	RequestErrors     int `json:"request_errors"`
+	TotalConnections  int `json:"total_connections"`
+}
+
+type Listener struct {
+	model.Resource
+	ProvisioningStatus string   `json:"provisioning_status,omitempty"`
+	OperatingStatus    string   `json:"operating_status,omitempty"`
+	AdminStateUp       bool     `json:"admin_state_up"`
+	Protocol           string   `json:"protocol,omitempty"`
+	ProtocolPort       int      `json:"protocol_port,omitempty"`
+	ConnectionLimit    int      `json:"connection_limit,omitempty"`
+	DefaultPoolId      string   `json:"default_pool_id,omitempty"`
+	Loadbalancers      []IdRef  `json:"loadbalancers,omitempty"`
+	AllowedCidrs       []string `json:"allowed_cidrs,omitempty"`
+	Tags               []string `json:"tags,omitempty"`
+}
+
+func (listener Listener) LoadbalancerIds() string {
+	return joinIds(listener.Loadbalancers)
+}
+
+type Pool struct {
+	model.Resource
+	ProvisioningStatus string                 `json:"provisioning_status,omitempty"`
+	OperatingStatus    string                 `json:"operating_status,omitempty"`
+	AdminStateUp       bool                   `json:"admin_state_up"`
+	Protocol           string                 `json:"protocol,omitempty"`
+	LbAlgorithm        string                 `json:"lb_algorithm,omitempty"`
+	SessionPersistence map[string]interface{} `json:"session_persistence,omitempty"`
+	HealthmonitorId    string                 `json:"healthmonitor_id,omitempty"`
+	Listeners          []IdRef                `json:"listeners,omitempty"`
+	Loadbalancers      []IdRef                `json:"loadbalancers,omitempty"`
+	Members            []IdRef                `json:"members,omitempty"`
+	Tags               []string               `json:"tags,omitempty"`
+}
+
+func (pool Pool) LoadbalancerIds() string {
+	return joinIds(pool.Loadbalancers)
+}
+func (pool Pool) ListenerIds() string {
+	return joinIds(pool.Listeners)
+}
+
+type Member struct {
+	model.Resource
+	ProvisioningStatus string   `json:"provisioning_status,omitempty"`
+	OperatingStatus    string   `json:"operating_status,omitempty"`
+	AdminStateUp       bool     `json:"admin_state_up"`
+	Address            string   `json:"address,omitempty"`
+	ProtocolPort       int      `json:"protocol_port,omitempty"`
+	Weight             int      `json:"weight"`
+	SubnetId           string   `json:"subnet_id,omitempty"`
+	MonitorAddress     string   `json:"monitor_address,omitempty"`
+	MonitorPort        int      `json:"monitor_port,omitempty"`
+	Backup             bool     `json:"backup"`
+	Tags               []string `json:"tags,omitempty"`
+}
+
+type HealthMonitor struct {
+	model.Resource
+	ProvisioningStatus string   `json:"provisioning_status,omitempty"`
+	OperatingStatus    string   `json:"operating_status,omitempty"`
+	AdminStateUp       bool     `json:"admin_state_up"`
+	Type               string   `json:"type,omitempty"`
+	Delay              int      `json:"delay,omitempty"`
+	Timeout            int      `json:"timeout,omitempty"`
+	MaxRetries         int      `json:"max_retries,omitempty"`
+	MaxRetriesDown     int      `json:"max_retries_down,omitempty"`
+	HttpMethod         string   `json:"http_method,omitempty"`
+	UrlPath            string   `json:"url_path,omitempty"`
+	ExpectedCodes      string   `json:"expected_codes,omitempty"`
+	Pools              []IdRef  `json:"pools,omitempty"`
+	Tags               []string `json:"tags,omitempty"`
+}
+
+func (hm HealthMonitor) PoolIds() string {
+	return joinIds(hm.Pools)
+}
+
+type Amphora struct {
+	Id             string `json:"id"`
+	LoadbalancerId string `json:"loadbalancer_id,omitempty"`
+	ComputeId      string `json:"compute_id,omitempty"`
+	Status         string `json:"status,omitempty"`
+	Role           string `json:"role,omitempty"`
+	LbNetworkIp    string `json:"lb_network_ip,omitempty"`
+	VrrpIp         string `json:"vrrp_ip,omitempty"`
+	HaIp           string `json:"ha_ip,omitempty"`
+	ImageId        string `json:"image_id,omitempty"`
+	ComputeFlavor  string `json:"compute_flavor,omitempty"`
+	CertExpiration string `json:"cert_expiration,omitempty"`
+	CreatedAt      string `json:"created_at,omitempty"`
+	UpdatedAt      string `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,77 @@
+package placement
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type Version struct {
+	Id         string `json:"id"`
+	MinVersion string `json:"min_version"`
+	MaxVersion string `json:"max_version"`
+	Status     string `json:"status"`
+}
+
+type ResourceProvider struct {
+	Uuid               string `json:"uuid"`
+	Name               string `json:"name"`
+	Generation         int    `json:"generation"`
+	RootProviderUuid   string `json:"root_provider_uuid,omitempty"`
+	ParentProviderUuid string `json:"parent_provider_uuid,omitempty"`
+}
+
+type Inventory struct {
+	ResourceClass   string  `json:"resource_class,omitempty"`
+	Total           int     `json:"total"`
+	Reserved        int     `json:"reserved"`
+	MinUnit         int     `json:"min_unit"`
+	MaxUnit         int     `json:"max_unit"`
+	StepSize        int     `json:"step_size"`
+	AllocationRatio float64 `json:"allocation_ratio"`
+}
+
+// 可分配的资源总量: (total - reserved) * allocation_ratio
+func (inventory Inventory) Capacity() int {
+	return int(float64(inventory.Total-inventory.Reserved) * inventory.AllocationRatio)
+}
+
+type Usage struct {
+	ResourceClass string
+	Used          int
+	Capacity      int
+}
+
+type Allocation struct {
+	Generation int            `json:"generation,omitempty"`
+	Resources  map[string]int `json:"resources"`
+}
+
+// 按资源类型排序, 例如: DISK_GB=10, MEMORY_MB=1024, VCPU=1
+func (allocation Allocation) ResourcesString() string {
+	classes := []string{}
+	for class := range allocation.Resources {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	resources := []string{}
+	for _, class := range classes {
+		resources = append(resources, fmt.Sprintf("%s=%d", class, allocation.Resources[class]))
+	}
+	return strings.Join(resources, ", ")
+}
+
+// 一个 consumer (虚拟机或者迁移记录) 在各个 resource provider 上的分配
+type ConsumerAllocations struct {
+	Allocations        map[string]Allocation `json:"allocations"`
+	ConsumerGeneration *int                  `json:"consumer_generation,omitempty"`
+	ConsumerType       string                `json:"consumer_type,omitempty"`
+	ProjectId          string                `json:"project_id,omitempty"`
+	UserId             string                `json:"user_id,omitempty"`
+}
+
+// 一个 resource provider 上各个 consumer 的分配
+type ProviderAllocations struct {
+	Allocations                map[string]Allocation `json:"allocations"`
+	ResourceProviderGeneration int                   `json:"resource_provider_generation"`
+}
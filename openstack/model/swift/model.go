@@ -0,0 +1,36 @@
+package swift
+
+const DEFAULT_UPLOAD_PARALLEL = 4
+
+type Container struct {
+	Name         string `json:"name"`
+	Count        int    `json:"count"`
+	Bytes        int64  `json:"bytes"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+type Object struct {
+	Name         string `json:"name"`
+	Hash         string `json:"hash,omitempty"`
+	Bytes        int64  `json:"bytes"`
+	ContentType  string `json:"content_type,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	// 指定 delimiter 时, 返回的伪目录
+	Subdir string `json:"subdir,omitempty"`
+	// 是否为静态大对象 (SLO), 只有查询单个对象时设置
+	StaticLargeObject bool `json:"-"`
+}
+
+// 静态大对象 manifest 中的分段
+type SloSegment struct {
+	Path      string `json:"path"`
+	Etag      string `json:"etag"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+type UploadOpt struct {
+	// 分段大小, 文件大于分段大小时, 以静态大对象 (SLO) 的方式分段上传
+	SegmentSize int64
+	// 同时上传的分段数
+	Parallel int
+}
@@ -0,0 +1,167 @@
+package openstack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BytemanD/easygo/pkg/stringutils"
+	"github.com/BytemanD/skyman/openstack/model/nova"
+	"github.com/BytemanD/skyman/openstack/model/placement"
+	"github.com/BytemanD/skyman/openstack/session"
+)
+
+// 迁移结束后, 以迁移记录为 consumer 的分配应该已经被删除
+var FINISHED_MIGRATION_STATUS = []string{
+	"confirmed", "reverted", "completed", "done", "failed", "error", "cancelled",
+}
+
+// 正在迁移的虚拟机, 可能同时在源节点和目的节点上有分配
+var MIGRATING_SERVER_STATUS = []string{"RESIZE", "VERIFY_RESIZE", "MIGRATING", "REBUILD"}
+
+// 可疑的资源分配
+type AllocationAudit struct {
+	Consumer     string
+	ProviderUuid string
+	Provider     string
+	Resources    string
+	Reason       string
+	// 无法确认分配是否可疑, 例如 nova 版本低于 2.59 时无法判断 consumer 是否为迁移记录
+	Unknown bool
+}
+
+type allocationAuditor struct {
+	o          *Openstack
+	providers  map[string]string
+	servers    map[string]*nova.Server
+	migrations map[string]nova.Migration
+}
+
+func (a *allocationAuditor) providerName(uuid string) (string, error) {
+	if name, ok := a.providers[uuid]; ok {
+		return name, nil
+	}
+	provider, err := a.o.PlacementV1().ResourceProvider().Show(uuid)
+	if err != nil {
+		return "", err
+	}
+	a.providers[uuid] = provider.Name
+	return provider.Name, nil
+}
+
+// 查询虚拟机, 虚拟机不存在时返回 nil
+func (a *allocationAuditor) server(id string) (*nova.Server, error) {
+	if server, ok := a.servers[id]; ok {
+		return server, nil
+	}
+	server, err := a.o.NovaV2().Server().Show(id)
+	if err != nil && !session.IsNotFound(err) {
+		return nil, err
+	}
+	a.servers[id] = server
+	return server, nil
+}
+
+// 查询迁移记录, 只有 2.59 及以上版本的迁移记录包含 uuid
+func (a *allocationAuditor) migration(uuid string) (*nova.Migration, error) {
+	if a.migrations == nil {
+		migrations, err := a.o.NovaV2().Migration().List(nil)
+		if err != nil {
+			return nil, err
+		}
+		a.migrations = map[string]nova.Migration{}
+		for _, migration := range migrations {
+			if migration.Uuid != "" {
+				a.migrations[migration.Uuid] = migration
+			}
+		}
+	}
+	if migration, ok := a.migrations[uuid]; ok {
+		return &migration, nil
+	}
+	return nil, nil
+}
+
+// 检查 consumer 在 provider 上的分配, 返回可疑的原因, 正常时返回空字符串;
+// 无法确认时 unknown 为 true
+func (a *allocationAuditor) audit(consumer string, provider placement.ResourceProvider) (reason string, unknown bool, err error) {
+	server, err := a.server(consumer)
+	if err != nil {
+		return "", false, err
+	}
+	if server == nil {
+		migrationApi := a.o.NovaV2().Migration()
+		if !migrationApi.MicroVersionLargeEqual("2.59") {
+			return "server not found, it may be a migration (migration uuid requires nova 2.59)", true, nil
+		}
+		migration, err := a.migration(consumer)
+		if err != nil {
+			return "", false, err
+		}
+		if migration == nil {
+			return "server not found", false, nil
+		}
+		if stringutils.ContainsString(FINISHED_MIGRATION_STATUS, strings.ToLower(migration.Status)) {
+			return fmt.Sprintf("migration of server %s is %s", migration.InstanceUUID, migration.Status), false, nil
+		}
+		return "", false, nil
+	}
+	if strings.HasPrefix(strings.ToUpper(server.Status), "DELETED") ||
+		strings.ToUpper(server.Status) == "SOFT_DELETED" {
+		return fmt.Sprintf("server is %s", server.Status), false, nil
+	}
+	if server.TaskState != "" || stringutils.ContainsString(MIGRATING_SERVER_STATUS, strings.ToUpper(server.Status)) {
+		return "", false, nil
+	}
+	// 嵌套的 provider (例如 GPU) 使用根 provider 的名字比较
+	hostName := provider.Name
+	if provider.RootProviderUuid != "" && provider.RootProviderUuid != provider.Uuid {
+		if hostName, err = a.providerName(provider.RootProviderUuid); err != nil {
+			return "", false, err
+		}
+	}
+	if server.HypervisorHostname != "" && server.HypervisorHostname != hostName {
+		return fmt.Sprintf("server is on %s", server.HypervisorHostname), false, nil
+	}
+	return "", false, nil
+}
+
+// 审计 resource provider 上的分配
+//
+// 找出属于已删除的虚拟机、已结束的迁移, 或者虚拟机已经迁移到其他节点的分配。
+// nova 版本低于 2.59 时, 找不到虚拟机的分配可能属于迁移记录, 标记为 Unknown
+func (o *Openstack) AuditAllocations(providers []placement.ResourceProvider) ([]AllocationAudit, error) {
+	auditor := allocationAuditor{
+		o: o, providers: map[string]string{}, servers: map[string]*nova.Server{},
+	}
+	for _, provider := range providers {
+		auditor.providers[provider.Uuid] = provider.Name
+	}
+	audits := []AllocationAudit{}
+	for _, provider := range providers {
+		allocations, err := o.PlacementV1().ResourceProvider().Allocations(provider.Uuid)
+		if err != nil {
+			return nil, fmt.Errorf("get allocations of provider %s failed: %w", provider.Name, err)
+		}
+		consumers := []string{}
+		for consumer := range allocations.Allocations {
+			consumers = append(consumers, consumer)
+		}
+		sort.Strings(consumers)
+		for _, consumer := range consumers {
+			reason, unknown, err := auditor.audit(consumer, provider)
+			if err != nil {
+				return nil, fmt.Errorf("audit consumer %s failed: %w", consumer, err)
+			}
+			if reason == "" {
+				continue
+			}
+			audits = append(audits, AllocationAudit{
+				Consumer: consumer, ProviderUuid: provider.Uuid, Provider: provider.Name,
+				Resources: allocations.Allocations[consumer].ResourcesString(),
+				Reason:    reason, Unknown: unknown,
+			})
+		}
+	}
+	return audits, nil
+}
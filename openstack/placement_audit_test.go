@@ -0,0 +1,73 @@
+package openstack
+
+import (
+	"testing"
+
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/openstack/fake"
+	"github.com/BytemanD/skyman/openstack/model/nova"
+)
+
+func TestAuditAllocations(t *testing.T) {
+	cloud := fake.NewCloud()
+	cloud.TaskDuration = 0
+	defer cloud.Close()
+
+	common.CONF.Identity.Api.Version = "3"
+	client := NewClient(cloud.AuthUrl(), cloud.User(), cloud.Project(), cloud.Region)
+	client.AuthPlugin.SetLocalTokenExpire(3600)
+
+	server, err := client.NovaV2().Server().Create(nova.ServerOpt{Name: "vm1", Flavor: "1", Image: "cirros"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err = client.NovaV2().Server().Show(server.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherHost := cloud.Hosts[0]
+	if otherHost == server.HypervisorHostname {
+		otherHost = cloud.Hosts[1]
+	}
+	deletedServer := fake.NewId()
+	cloud.AddAllocation(deletedServer, server.HypervisorHostname, map[string]int{"VCPU": 1})
+	cloud.AddAllocation(server.Id, otherHost, map[string]int{"VCPU": 1, "MEMORY_MB": 1024})
+
+	providers, err := client.PlacementV1().ResourceProvider().List(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(providers) != len(cloud.Hosts) {
+		t.Fatalf("expect %d providers, but got %d", len(cloud.Hosts), len(providers))
+	}
+	audits, err := client.AuditAllocations(providers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reasons := map[string]AllocationAudit{}
+	for _, audit := range audits {
+		reasons[audit.Consumer] = audit
+	}
+	if len(audits) != 2 {
+		t.Fatalf("expect 2 suspicious allocations, but got %v", audits)
+	}
+	if reasons[deletedServer].Reason != "server not found" {
+		t.Errorf("unexpected audit for deleted server: %v", reasons[deletedServer])
+	}
+	if audit := reasons[server.Id]; audit.Provider != otherHost || audit.Resources != "MEMORY_MB=1024, VCPU=1" {
+		t.Errorf("unexpected audit for migrated server: %v", audit)
+	}
+
+	// 2.59 之前的迁移记录不包含 uuid, 无法确认找不到虚拟机的分配是否属于迁移记录
+	oldClient := NewClient(cloud.AuthUrl(), cloud.User(), cloud.Project(), cloud.Region)
+	oldClient.AuthPlugin.SetLocalTokenExpire(3600)
+	oldClient.ComputeApiVersion = "2.53"
+	if audits, err = oldClient.AuditAllocations(providers); err != nil {
+		t.Fatal(err)
+	}
+	for _, audit := range audits {
+		if audit.Consumer == deletedServer && !audit.Unknown {
+			t.Errorf("expect unknown audit below 2.59, but got %v", audit)
+		}
+	}
+}
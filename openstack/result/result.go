@@ -2,8 +2,6 @@ package result
 
 import (
 	"encoding/json"
-	"fmt"
-	"net/http"
 
 	"github.com/BytemanD/skyman/openstack/session"
 )
@@ -33,15 +31,13 @@ func (result HttpResult) GetError() error {
 	if !result.IsError() {
 		return nil
 	}
-	fmt.Println("xxxx", result)
-	return fmt.Errorf("result Err=%v, StatusCode: %v",
-		result.Err, result.Resp.StatusCode())
+	if result.Err != nil {
+		return result.Err
+	}
+	return session.NewHttpError(result.Resp.Response)
 }
 func (result HttpResult) NotFound() bool {
-	if !result.IsError() {
-		return false
-	}
-	return result.Resp.StatusCode() == http.StatusNotFound
+	return session.IsNotFound(result.GetError())
 }
 func (result HttpResult) StringBody() string {
 	return string(result.Resp.Body())
@@ -77,7 +73,7 @@ type ItemResult[T any] struct {
 func (result ItemResult[T]) Item() (item *T, err error) {
 	if result.IsError() {
 		item = nil
-		err = fmt.Errorf("result is error: %v, %v", result.Err, result.Resp.StatusCode())
+		err = result.GetError()
 		return
 	}
 	if result.Key == "" {
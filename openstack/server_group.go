@@ -0,0 +1,90 @@
+package openstack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BytemanD/skyman/openstack/model/nova"
+	"github.com/BytemanD/skyman/openstack/session"
+)
+
+type ServerGroupMember struct {
+	ServerId   string
+	ServerName string
+	Status     string
+	Host       string
+	HostId     string
+}
+
+// 服务器组成员当前的分布情况
+type ServerGroupCheckReport struct {
+	Group   nova.ServerGroup
+	Members []ServerGroupMember
+	// 成员数量按节点统计, 无法获取节点名时 (非管理员用户) 按 hostId 统计
+	Hosts      map[string]int
+	Violations []string
+	// 无法确定所在节点的成员
+	Unknown []string
+}
+
+func (r ServerGroupCheckReport) Satisfied() bool {
+	return len(r.Violations) == 0 && len(r.Unknown) == 0
+}
+
+// 检查服务器组成员当前所在的节点是否满足组的策略, 例如迁移后 anti-affinity 的成员可能被放到同一个节点
+func (o *Openstack) CheckServerGroup(idOrName string) (*ServerGroupCheckReport, error) {
+	group, err := o.NovaV2().ServerGroup().Find(idOrName)
+	if err != nil {
+		return nil, err
+	}
+	report := ServerGroupCheckReport{Group: *group, Hosts: map[string]int{}}
+	for _, id := range group.Members {
+		server, err := o.NovaV2().Server().Show(id)
+		if session.IsNotFound(err) {
+			report.Members = append(report.Members, ServerGroupMember{ServerId: id, Status: "NOT_FOUND"})
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		report.Members = append(report.Members, ServerGroupMember{
+			ServerId: server.Id, ServerName: server.Name, Status: server.Status,
+			Host: server.Host, HostId: server.HostId,
+		})
+		switch {
+		case server.Host != "":
+			report.Hosts[server.Host]++
+		case server.HostId != "":
+			report.Hosts[server.HostId]++
+		default:
+			report.Unknown = append(report.Unknown, server.Id)
+		}
+	}
+	hosts := []string{}
+	for host := range report.Hosts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	switch group.GetPolicy() {
+	case nova.GROUP_AFFINITY, nova.GROUP_SOFT_AFFINITY:
+		if len(hosts) > 1 {
+			report.Violations = append(report.Violations,
+				fmt.Sprintf("members are spread across %d hosts: %s", len(hosts), strings.Join(hosts, ", ")))
+		}
+	case nova.GROUP_ANTI_AFFINITY, nova.GROUP_SOFT_ANTI_AFFINITY:
+		maxServer := 1
+		if group.GetPolicy() == nova.GROUP_ANTI_AFFINITY {
+			maxServer = group.MaxServerPerHost()
+		}
+		for _, host := range hosts {
+			if report.Hosts[host] > maxServer {
+				report.Violations = append(report.Violations,
+					fmt.Sprintf("host %s has %d members, more than %d", host, report.Hosts[host], maxServer))
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown policy %s of server group %s", group.GetPolicy(), group.Id)
+	}
+	return &report, nil
+}
@@ -0,0 +1,113 @@
+package openstack
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/openstack/fake"
+	"github.com/BytemanD/skyman/openstack/model/nova"
+)
+
+func TestCheckServerGroup(t *testing.T) {
+	cloud := fake.NewCloud()
+	cloud.TaskDuration = 0
+	cloud.Hosts = []string{"fake-host-1", "fake-host-2", "fake-host-3"}
+	defer cloud.Close()
+
+	common.CONF.Identity.Api.Version = "3"
+	client := NewClient(cloud.AuthUrl(), cloud.User(), cloud.Project(), cloud.Region)
+	client.AuthPlugin.SetLocalTokenExpire(3600)
+
+	group, err := client.NovaV2().ServerGroup().Create("group1", nova.GROUP_ANTI_AFFINITY,
+		map[string]interface{}{nova.GROUP_RULE_MAX_SERVER_PER_HOST: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"vm1", "vm2"} {
+		_, err := client.NovaV2().Server().Create(nova.ServerOpt{
+			Name: name, Flavor: "1", Image: "cirros",
+			SchedulerHints: map[string]interface{}{"group": group.Id},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	cloud.WaitTasks(0)
+
+	report, err := client.CheckServerGroup("group1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Members) != 2 || !report.Satisfied() {
+		t.Fatalf("expect 2 members satisfy the policy, but got %v", report)
+	}
+	// 迁移到同一个节点后不再满足 anti-affinity
+	host := report.Members[0].Host
+	if err := client.NovaV2().Server().LiveMigrate(report.Members[1].ServerId, "auto", host); err != nil {
+		t.Fatal(err)
+	}
+	cloud.WaitTasks(0)
+	if report, err = client.CheckServerGroup(group.Id); err != nil {
+		t.Fatal(err)
+	}
+	if report.Satisfied() || report.Hosts[host] != 2 {
+		t.Errorf("expect violation on %s, but got %v", host, report)
+	}
+	// 非管理员用户看不到节点名, 按 hostId 检查
+	cloud.HideServerHost = true
+	if report, err = client.CheckServerGroup(group.Id); err != nil {
+		t.Fatal(err)
+	}
+	hostId := report.Members[0].HostId
+	if report.Satisfied() || report.Members[0].Host != "" || hostId == "" || report.Hosts[hostId] != 2 {
+		t.Errorf("expect violation on host id %s, but got %v", hostId, report)
+	}
+	// 还没有调度的成员无法确定所在节点
+	cloud.HideServerHost, cloud.TaskDuration = false, time.Hour
+	group, err = client.NovaV2().ServerGroup().Create("group2", nova.GROUP_AFFINITY, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := client.NovaV2().Server().Create(nova.ServerOpt{
+		Name: "vm3", Flavor: "1", Image: "cirros",
+		SchedulerHints: map[string]interface{}{"group": group.Id},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report, err = client.CheckServerGroup(group.Id); err != nil {
+		t.Fatal(err)
+	}
+	if report.Satisfied() || len(report.Unknown) != 1 || report.Unknown[0] != server.Id {
+		t.Errorf("expect placement of %s is unknown, but got %v", server.Id, report)
+	}
+}
+
+func TestListServerGroups(t *testing.T) {
+	cloud := fake.NewCloud()
+	cloud.PageSize = 2
+	defer cloud.Close()
+
+	common.CONF.Identity.Api.Version = "3"
+	client := NewClient(cloud.AuthUrl(), cloud.User(), cloud.Project(), cloud.Region)
+	client.AuthPlugin.SetLocalTokenExpire(3600)
+
+	for i := 0; i < 5; i++ {
+		if _, err := client.NovaV2().ServerGroup().Create(fmt.Sprintf("group%d", i), nova.GROUP_AFFINITY, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 服务器组不返回下一页链接, 需要按 offset 获取所有页
+	groups, err := client.NovaV2().ServerGroup().List(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 5 || groups[4].Name != "group4" {
+		t.Errorf("expect 5 server groups, but got %v", groups)
+	}
+	if _, err := client.NovaV2().ServerGroup().Find("group4"); err != nil {
+		t.Errorf("expect group4 found, but got %s", err)
+	}
+}
@@ -0,0 +1,42 @@
+package openstack
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+)
+
+func TestDecryptServerPassword(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := rsa.EncryptPKCS1v15(rand.Reader, &key.PublicKey, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted := base64.StdEncoding.EncodeToString(data)
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, block := range []*pem.Block{
+		{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)},
+		{Type: "PRIVATE KEY", Bytes: pkcs8},
+	} {
+		password, err := DecryptServerPassword(encrypted, pem.EncodeToMemory(block))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if password != "secret" {
+			t.Errorf("expect password secret, but got %s", password)
+		}
+	}
+	if _, err := DecryptServerPassword(encrypted, []byte("invalid")); err == nil {
+		t.Errorf("expect error when private key is invalid")
+	}
+}
@@ -1,21 +1,160 @@
 package session
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
 
 const (
 	CODE_404 = 404
+
+	HEADER_COMPUTE_REQUEST_ID = "X-Compute-Request-Id"
 )
 
+// 用于 errors.Is 判断 API 错误的类型, 例如 errors.Is(err, session.ErrNotFound)
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrOverLimit    = errors.New("over limit")
+)
+
+// API 返回的错误
+//
+// Code 和 Message 从各服务的 fault 格式中解析, 例如 nova 的 itemNotFound,
+// neutron 的 NeutronError; 解析失败时 Message 为响应体
 type HttpError struct {
-	Status  int
-	Reason  string
-	Message string
+	Status    int
+	Reason    string
+	Code      string
+	Message   string
+	RequestId string
+	Service   string
+	Method    string
+	Url       string
+	Body      string
 }
 
 func (err HttpError) Error() string {
-	return fmt.Sprintf("[%d] %s: %s", err.Status, err.Reason, err.Message)
+	msg := fmt.Sprintf("[%d]", err.Status)
+	if err.Service != "" {
+		msg += " " + err.Service
+	}
+	if err.Method != "" {
+		msg += fmt.Sprintf(" %s %s", err.Method, err.Url)
+	}
+	if err.Code != "" {
+		msg += fmt.Sprintf(": %s: %s", err.Code, err.Message)
+	} else {
+		msg += ": " + err.Message
+	}
+	if err.RequestId != "" {
+		msg += fmt.Sprintf(" (request id: %s)", err.RequestId)
+	}
+	return msg
+}
+
+func (err HttpError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return err.Status == http.StatusBadRequest
+	case ErrUnauthorized:
+		return err.Status == http.StatusUnauthorized
+	case ErrForbidden:
+		return err.Status == http.StatusForbidden
+	case ErrNotFound:
+		return err.Status == http.StatusNotFound
+	case ErrConflict:
+		return err.Status == http.StatusConflict
+	case ErrOverLimit:
+		return err.Status == http.StatusRequestEntityTooLarge || err.Status == http.StatusTooManyRequests
+	}
+	return false
 }
 
 func (err HttpError) IsNotFound() bool {
 	return err.Status == CODE_404
 }
+
+func IsBadRequest(err error) bool   { return errors.Is(err, ErrBadRequest) }
+func IsUnauthorized(err error) bool { return errors.Is(err, ErrUnauthorized) }
+func IsForbidden(err error) bool    { return errors.Is(err, ErrForbidden) }
+func IsNotFound(err error) bool     { return errors.Is(err, ErrNotFound) }
+func IsConflict(err error) bool     { return errors.Is(err, ErrConflict) }
+func IsOverLimit(err error) bool    { return errors.Is(err, ErrOverLimit) }
+
+// 返回错误链中的 HttpError
+func AsHttpError(err error) (*HttpError, bool) {
+	httpError := HttpError{}
+	if errors.As(err, &httpError) {
+		return &httpError, true
+	}
+	return nil, false
+}
+
+// 根据响应生成 HttpError
+func NewHttpError(resp *resty.Response) HttpError {
+	body := resp.Body()
+	code, message := ParseFault(body)
+	httpError := HttpError{
+		Status:    resp.StatusCode(),
+		Reason:    resp.Status(),
+		Code:      code,
+		Message:   message,
+		RequestId: resp.Header().Get(HEADER_REQUEST_ID),
+		Body:      string(body),
+	}
+	if httpError.RequestId == "" {
+		httpError.RequestId = resp.Header().Get(HEADER_COMPUTE_REQUEST_ID)
+	}
+	if resp.Request != nil {
+		httpError.Method = resp.Request.Method
+		httpError.Url = resp.Request.URL
+		httpError.Service = ServiceFromContext(resp.Request.Context())
+	}
+	if httpError.Message == "" {
+		httpError.Message = strings.TrimPrefix(httpError.Reason, fmt.Sprintf("%d ", httpError.Status))
+	}
+	return httpError
+}
+
+type serviceKey struct{}
+
+// 在 context 中记录服务名, 用于错误信息、限流和请求统计
+func WithService(ctx context.Context, service string) context.Context {
+	return context.WithValue(ctx, serviceKey{}, service)
+}
+func ServiceFromContext(ctx context.Context) string {
+	if service, ok := ctx.Value(serviceKey{}).(string); ok {
+		return service
+	}
+	return ""
+}
+
+// 操作被取消 (例如收到 SIGINT/SIGTERM) 或超过截止时间
+type InterruptedError struct {
+	Operation string
+	Err       error
+}
+
+func (err InterruptedError) Error() string {
+	return fmt.Sprintf("%s interrupted: %s", err.Operation, err.Err)
+}
+func (err InterruptedError) Unwrap() error {
+	return err.Err
+}
+
+// 如果 ctx 已经结束, 返回 InterruptedError, 否则返回 nil
+func Interrupted(ctx context.Context, format string, args ...interface{}) error {
+	if ctx == nil || ctx.Err() == nil {
+		return nil
+	}
+	return InterruptedError{Operation: fmt.Sprintf(format, args...), Err: ctx.Err()}
+}
@@ -0,0 +1,89 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	htmlTagRegex = regexp.MustCompile(`<[^>]*>`)
+	spaceRegex   = regexp.MustCompile(`\s+`)
+)
+
+// 解析 OpenStack 各服务的错误响应, 返回错误类型和错误信息
+//
+// 支持的格式:
+//
+//	nova/cinder: {"itemNotFound": {"code": 404, "message": "..."}}
+//	keystone:    {"error": {"code": 401, "title": "Unauthorized", "message": "..."}}
+//	neutron:     {"NeutronError": {"type": "NetworkNotFound", "message": "...", "detail": ""}}
+//	placement:   {"errors": [{"status": 404, "title": "Not Found", "detail": "...", "code": "..."}]}
+//	ironic:      {"error_message": "{\"faultstring\": \"...\", ...}"}
+//	glance:      纯文本或 HTML
+func ParseFault(body []byte) (string, string) {
+	text := strings.TrimSpace(string(body))
+	if text == "" {
+		return "", ""
+	}
+	fault := map[string]interface{}{}
+	if err := json.Unmarshal(body, &fault); err != nil {
+		// glance 等服务返回的 HTML/纯文本
+		text = htmlTagRegex.ReplaceAllString(text, " ")
+		return "", strings.TrimSpace(spaceRegex.ReplaceAllString(text, " "))
+	}
+	if errorMessage, ok := fault["error_message"].(string); ok {
+		ironicFault := struct {
+			Faultstring string `json:"faultstring"`
+		}{}
+		if err := json.Unmarshal([]byte(errorMessage), &ironicFault); err == nil && ironicFault.Faultstring != "" {
+			return "", ironicFault.Faultstring
+		}
+		return "", errorMessage
+	}
+	if errs, ok := fault["errors"].([]interface{}); ok && len(errs) > 0 {
+		if item, ok := errs[0].(map[string]interface{}); ok {
+			return faultString(item, "code", "title"), faultString(item, "detail", "message")
+		}
+	}
+	if message, ok := fault["message"].(string); ok {
+		return faultString(fault, "title", "code"), message
+	}
+	if len(fault) == 1 {
+		for key, value := range fault {
+			item, ok := value.(map[string]interface{})
+			if !ok {
+				break
+			}
+			code := key
+			switch key {
+			case "NeutronError":
+				code = faultString(item, "type")
+			case "error":
+				code = faultString(item, "title")
+			}
+			message := faultString(item, "message", "detail")
+			if detail := faultString(item, "detail"); detail != "" && detail != message {
+				message = fmt.Sprintf("%s %s", message, detail)
+			}
+			return code, message
+		}
+	}
+	return "", text
+}
+
+// 返回第一个非空的字段值
+func faultString(item map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		switch value := item[key].(type) {
+		case string:
+			if value != "" {
+				return value
+			}
+		case float64:
+			return fmt.Sprintf("%v", value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseFault(t *testing.T) {
+	cases := []struct {
+		body    string
+		code    string
+		message string
+	}{
+		{`{"itemNotFound": {"code": 404, "message": "Instance 1111 could not be found."}}`,
+			"itemNotFound", "Instance 1111 could not be found."},
+		{`{"error": {"code": 401, "title": "Unauthorized", "message": "The request you have made requires authentication."}}`,
+			"Unauthorized", "The request you have made requires authentication."},
+		{`{"NeutronError": {"type": "NetworkNotFound", "message": "Network 2222 could not be found.", "detail": ""}}`,
+			"NetworkNotFound", "Network 2222 could not be found."},
+		{`{"errors": [{"status": 404, "title": "Not Found", "detail": "No resource provider with uuid 3333 found", "code": "placement.undefined_code"}]}`,
+			"placement.undefined_code", "No resource provider with uuid 3333 found"},
+		{`{"error_message": "{\"faultstring\": \"Node 4444 could not be found.\", \"debuginfo\": null}"}`,
+			"", "Node 4444 could not be found."},
+		{"<html><body><h1>404 Not Found</h1>\n No image found with ID 5555\n</body></html>",
+			"", "404 Not Found No image found with ID 5555"},
+	}
+	for _, c := range cases {
+		code, message := ParseFault([]byte(c.body))
+		if code != c.code || message != c.message {
+			t.Errorf("parse %s: expect (%q, %q), but got (%q, %q)", c.body, c.code, c.message, code, message)
+		}
+	}
+}
+
+func TestHttpError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(HEADER_COMPUTE_REQUEST_ID, "req-1111")
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"conflictingRequest": {"code": 409, "message": "Cannot 'start' instance while it is in vm_state active"}}`))
+	}))
+	defer server.Close()
+
+	resp, err := DefaultRestyClient().R().
+		SetContext(WithService(context.Background(), "nova")).
+		Post(server.URL + "/servers/1111/action")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = fmt.Errorf("start server failed: %w", NewHttpError(resp))
+	if !IsConflict(err) || errors.Is(err, ErrNotFound) {
+		t.Errorf("expect conflict error, but got %s", err)
+	}
+	httpError, ok := AsHttpError(err)
+	if !ok {
+		t.Fatalf("expect HttpError, but got %T", err)
+	}
+	if httpError.RequestId != "req-1111" || httpError.Service != "nova" || httpError.Code != "conflictingRequest" {
+		t.Errorf("unexpected error %#v", httpError)
+	}
+	if !strings.Contains(err.Error(), "(request id: req-1111)") {
+		t.Errorf("request id not in error message: %s", err)
+	}
+}
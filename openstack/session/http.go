@@ -59,10 +59,37 @@ func LogRespAfterResponse(c *resty.Client, r *resty.Response) error {
 // 默认的 Client
 //
 // 记录请求日志，设置content-type=application/json
+//
+// 如果设置了 TLS 选项, 同时设置 TLS 配置
+//
+// 如果开启了记录/回放模式, 使用对应的 Transport
+//
+// 请求按服务限流, 服务端限流(429/503, 以及带 Retry-After 的 413)时按 Retry-After 或指数退避重试
 func DefaultRestyClient() *resty.Client {
-	return resty.New().
+	client := resty.New().
 		SetHeader(CONTENT_TYPE, CONTENT_TYPE_JSON).
-		SetRetryCount(DEFAULT_RETRY_COUNT).
+		SetRetryCount(retryCount).
+		SetRetryMaxWaitTime(DEFAULT_RETRY_MAX_WAIT_TIME).
+		SetRetryAfter(RetryAfter).
+		AddRetryCondition(func(r *resty.Response, err error) bool {
+			return IsThrottled(r)
+		}).
 		OnBeforeRequest(LogBeforeRequest).
-		OnAfterResponse(LogRespAfterResponse)
+		OnBeforeRequest(limitBeforeRequest).
+		OnAfterResponse(LogRespAfterResponse).
+		OnAfterResponse(limitAfterResponse)
+	if retryWaitTime > 0 {
+		client.SetRetryWaitTime(retryWaitTime)
+	}
+	if config := GetTLSConfig(); config != nil {
+		client.SetTLSClientConfig(config)
+	}
+	if transport := client.GetClient().Transport; transport != nil {
+		if wrapped := wrapTransport(transport); wrapped != transport {
+			client.SetTransport(wrapped)
+		}
+	} else if wrapped := wrapTransport(http.DefaultTransport); wrapped != http.DefaultTransport {
+		client.SetTransport(wrapped)
+	}
+	return client
 }
@@ -0,0 +1,211 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	HEADER_RETRY_AFTER = "Retry-After"
+
+	DEFAULT_RETRY_MAX_WAIT_TIME = time.Second * 60
+)
+
+// 令牌桶限流器, 每秒生成 rate 个令牌, 最多积累 burst 个
+type RateLimiter struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	lock   sync.Mutex
+}
+
+func NewRateLimiter(rate float64) *RateLimiter {
+	burst := rate
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{rate: rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// 获取一个令牌, 没有令牌时等待, ctx 取消时返回错误
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.lock.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.lock.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// 归还令牌
+		l.lock.Lock()
+		l.tokens++
+		l.lock.Unlock()
+		return ctx.Err()
+	}
+}
+
+// 每个服务的请求统计
+type RequestStats struct {
+	Service   string
+	Requests  int64
+	Throttled int64
+	Retried   int64
+}
+
+type requestCounter struct {
+	requests, throttled, retried atomic.Int64
+}
+
+var (
+	retryCount    = DEFAULT_RETRY_COUNT
+	retryWaitTime time.Duration
+
+	rateLimiters = map[string]*RateLimiter{}
+	counters     = map[string]*requestCounter{}
+	limitLock    = &sync.Mutex{}
+)
+
+// 设置重试次数和初始等待时间, 对之后通过 DefaultRestyClient 创建的 client 生效, count 为 0 表示不重试
+//
+// 重试间隔按指数退避并加入随机抖动, 服务端返回 Retry-After 时优先使用
+func SetRetryOptions(count int, waitTime time.Duration) error {
+	if count < 0 {
+		return fmt.Errorf("invalid retry count %d, it must not be negative", count)
+	}
+	retryCount, retryWaitTime = count, waitTime
+	return nil
+}
+
+// 设置服务每秒最多发送的请求数, rate <= 0 表示不限制
+func SetRateLimit(service string, rate float64) {
+	limitLock.Lock()
+	defer limitLock.Unlock()
+	if rate <= 0 {
+		delete(rateLimiters, service)
+		return
+	}
+	console.Debug("set rate limit of %s to %v/s", service, rate)
+	rateLimiters[service] = NewRateLimiter(rate)
+}
+func getRateLimiter(service string) *RateLimiter {
+	limitLock.Lock()
+	defer limitLock.Unlock()
+	return rateLimiters[service]
+}
+func getCounter(service string) *requestCounter {
+	limitLock.Lock()
+	defer limitLock.Unlock()
+	if _, ok := counters[service]; !ok {
+		counters[service] = &requestCounter{}
+	}
+	return counters[service]
+}
+
+// 返回各服务的请求统计, 按服务名排序
+func GetRequestStats() []RequestStats {
+	limitLock.Lock()
+	defer limitLock.Unlock()
+	stats := []RequestStats{}
+	for service, counter := range counters {
+		stats = append(stats, RequestStats{
+			Service:   service,
+			Requests:  counter.requests.Load(),
+			Throttled: counter.throttled.Load(),
+			Retried:   counter.retried.Load(),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Service < stats[j].Service })
+	return stats
+}
+func ResetRequestStats() {
+	limitLock.Lock()
+	defer limitLock.Unlock()
+	counters = map[string]*requestCounter{}
+}
+
+// 服务端限流或暂时不可用
+//
+// 413 也可能是请求体过大或者超出配额, 只有返回了 Retry-After 时才认为是限流 (例如 nova 的旧版限流)
+func IsThrottled(resp *resty.Response) bool {
+	if resp == nil {
+		return false
+	}
+	switch resp.StatusCode() {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusRequestEntityTooLarge:
+		return resp.Header().Get(HEADER_RETRY_AFTER) != ""
+	}
+	return false
+}
+
+// 解析 Retry-After 头, 支持秒数和 HTTP 日期两种格式
+//
+// 返回 0 时 resty 使用默认的指数退避
+func RetryAfter(c *resty.Client, resp *resty.Response) (time.Duration, error) {
+	value := resp.Header().Get(HEADER_RETRY_AFTER)
+	if value == "" {
+		return 0, nil
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Second * time.Duration(seconds), nil
+	}
+	if date, err := http.ParseTime(value); err == nil && time.Until(date) > 0 {
+		return time.Until(date), nil
+	}
+	return 0, nil
+}
+
+func limitBeforeRequest(c *resty.Client, r *resty.Request) error {
+	service := ServiceFromContext(r.Context())
+	if service == "" {
+		return nil
+	}
+	counter := getCounter(service)
+	counter.requests.Add(1)
+	if r.Attempt > 1 {
+		counter.retried.Add(1)
+	}
+	if limiter := getRateLimiter(service); limiter != nil {
+		return limiter.Wait(r.Context())
+	}
+	return nil
+}
+func limitAfterResponse(c *resty.Client, r *resty.Response) error {
+	if !IsThrottled(r) {
+		return nil
+	}
+	service := ServiceFromContext(r.Request.Context())
+	if service == "" {
+		return nil
+	}
+	getCounter(service).throttled.Add(1)
+	console.Debug("%s throttled: [%d] %s %s, retry after '%s'", service, r.StatusCode(),
+		r.Request.Method, r.Request.URL, r.Header().Get(HEADER_RETRY_AFTER))
+	return nil
+}
@@ -0,0 +1,107 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(10)
+	start := time.Now()
+	for i := 0; i < 15; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if spend := time.Since(start); spend < time.Millisecond*400 {
+		t.Errorf("expect waiting about 500ms, but spend %s", spend)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := limiter.Wait(ctx); err == nil {
+		t.Errorf("expect error when context is canceled")
+	}
+}
+
+func TestRetryThrottled(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set(HEADER_RETRY_AFTER, "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	ResetRequestStats()
+
+	resp, err := DefaultRestyClient().R().
+		SetContext(WithService(context.Background(), "nova")).
+		Get(server.URL + "/servers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		t.Errorf("expect status 200, but got %d", resp.StatusCode())
+	}
+	stats := GetRequestStats()
+	if len(stats) != 1 || stats[0].Requests != 2 || stats[0].Throttled != 1 || stats[0].Retried != 1 {
+		t.Errorf("unexpected stats %v", stats)
+	}
+}
+
+func TestSetRetryOptions(t *testing.T) {
+	defer SetRetryOptions(DEFAULT_RETRY_COUNT, 0)
+	if err := SetRetryOptions(-1, 0); err == nil {
+		t.Errorf("expect error when retry count is negative")
+	}
+	// 0 表示不重试
+	if err := SetRetryOptions(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set(HEADER_RETRY_AFTER, "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	resp, err := DefaultRestyClient().R().Get(server.URL + "/servers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode() != http.StatusTooManyRequests || requests.Load() != 1 {
+		t.Errorf("expect 1 request without retry, but got %d (status %d)", requests.Load(), resp.StatusCode())
+	}
+}
+
+func TestIsThrottled(t *testing.T) {
+	for _, c := range []struct {
+		status     int
+		retryAfter string
+		throttled  bool
+	}{
+		{http.StatusTooManyRequests, "", true},
+		{http.StatusServiceUnavailable, "", true},
+		{http.StatusRequestEntityTooLarge, "", false},
+		{http.StatusRequestEntityTooLarge, "1", true},
+		{http.StatusBadRequest, "1", false},
+	} {
+		resp := &resty.Response{RawResponse: &http.Response{StatusCode: c.status, Header: http.Header{}}}
+		if c.retryAfter != "" {
+			resp.RawResponse.Header.Set(HEADER_RETRY_AFTER, c.retryAfter)
+		}
+		if IsThrottled(resp) != c.throttled {
+			t.Errorf("expect throttled=%v for %d (Retry-After: %q)", c.throttled, c.status, c.retryAfter)
+		}
+	}
+}
@@ -0,0 +1,401 @@
+package session
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/BytemanD/go-console/console"
+)
+
+const (
+	SCRUBBED        = "<SCRUBBED>"
+	CASSETTE_EXT    = ".json"
+	X_AUTH_TOKEN    = "X-Auth-Token"
+	X_SUBJECT_TOKEN = "X-Subject-Token"
+)
+
+// 记录时需要替换的请求体/响应体字段
+var SCRUB_BODY_KEYS = []string{
+	"password", "adminPass", "secret", "private_key", "payload", "get-payload",
+}
+
+// 响应体不是 JSON 格式的敏感接口, 记录时整个响应体都会被替换
+var scrubBodyPaths = []*regexp.Regexp{
+	// barbican 获取 secret 的内容
+	regexp.MustCompile(`/secrets/[^/]+/payload/?$`),
+}
+
+type RecordedBody struct {
+	Body   string `json:"body,omitempty"`
+	Base64 bool   `json:"base64,omitempty"`
+}
+
+func newRecordedBody(data []byte) RecordedBody {
+	if utf8.Valid(data) {
+		return RecordedBody{Body: string(scrubBody(data))}
+	}
+	return RecordedBody{Body: base64.StdEncoding.EncodeToString(data), Base64: true}
+}
+func (b RecordedBody) Bytes() []byte {
+	if b.Base64 {
+		data, _ := base64.StdEncoding.DecodeString(b.Body)
+		return data
+	}
+	return []byte(b.Body)
+}
+
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Url    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	RecordedBody
+}
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Status     string      `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	RecordedBody
+}
+
+// 一次请求和响应
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+func (i Interaction) key() string {
+	parsed, err := url.Parse(i.Request.Url)
+	if err != nil {
+		return i.Request.Method + " " + i.Request.Url
+	}
+	return interactionKey(i.Request.Method, parsed)
+}
+
+func interactionKey(method string, u *url.URL) string {
+	key := method + " " + strings.TrimSuffix(u.Path, "/")
+	if query := u.Query().Encode(); query != "" {
+		key += "?" + query
+	}
+	return key
+}
+
+func scrubValue(parentKey string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			switch {
+			case contains(SCRUB_BODY_KEYS, k):
+				v[k] = SCRUBBED
+			// token 认证请求体中的 token
+			case parentKey == "token" && k == "id":
+				v[k] = SCRUBBED
+			default:
+				v[k] = scrubValue(k, item)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = scrubValue(parentKey, item)
+		}
+		return v
+	default:
+		return value
+	}
+}
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
+
+func scrubBody(data []byte) []byte {
+	var body interface{}
+	if len(data) == 0 || json.Unmarshal(data, &body) != nil {
+		return data
+	}
+	scrubbed, err := json.Marshal(scrubValue("", body))
+	if err != nil {
+		return data
+	}
+	return scrubbed
+}
+
+// 默认的 header 过滤方法, 替换 token
+func DefaultSafeHeader(header http.Header) http.Header {
+	safeHeaders := http.Header{}
+	for k, v := range header {
+		if k == X_AUTH_TOKEN || k == X_SUBJECT_TOKEN {
+			safeHeaders[k] = []string{"<TOKEN>"}
+		} else {
+			safeHeaders[k] = v
+		}
+	}
+	return safeHeaders
+}
+
+// 记录请求和响应, 每个请求保存为一个 JSON 文件 (cassette)
+type Recorder struct {
+	Dir        string
+	Transport  http.RoundTripper
+	SafeHeader func(header http.Header) http.Header
+
+	counter *recordCounter
+}
+
+type recordCounter struct {
+	mu    sync.Mutex
+	count int
+}
+
+// 从目录中已有的最大序号开始计数, 避免覆盖之前记录的文件
+func newRecordCounter(dir string) *recordCounter {
+	counter := &recordCounter{}
+	files, _ := filepath.Glob(filepath.Join(dir, "*"+CASSETTE_EXT))
+	for _, file := range files {
+		index, _, found := strings.Cut(filepath.Base(file), "-")
+		if !found {
+			continue
+		}
+		if n, err := strconv.Atoi(index); err == nil && n > counter.count {
+			counter.count = n
+		}
+	}
+	return counter
+}
+
+func (c *recordCounter) next() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.count++
+	return c.count
+}
+
+var invalidFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+func (r *Recorder) nextFile(req *http.Request) string {
+	if r.counter == nil {
+		r.counter = newRecordCounter(r.Dir)
+	}
+	name := invalidFileChars.ReplaceAllString(strings.Trim(req.URL.Path, "/"), "_")
+	if len(name) > 64 {
+		name = name[len(name)-64:]
+	}
+	return filepath.Join(r.Dir, fmt.Sprintf("%05d-%s-%s%s", r.counter.next(), req.Method, name, CASSETTE_EXT))
+}
+
+func readAndRestore(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, err
+}
+
+func scrubBodyOf(u *url.URL, data []byte) RecordedBody {
+	for _, pattern := range scrubBodyPaths {
+		if len(data) > 0 && pattern.MatchString(u.Path) {
+			return RecordedBody{Body: SCRUBBED}
+		}
+	}
+	return newRecordedBody(data)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil && body != nil {
+			reqBody, _ = io.ReadAll(body)
+			body.Close()
+		}
+	}
+	resp, err := r.Transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	respBody, err := readAndRestore(&resp.Body)
+	if err != nil {
+		return resp, err
+	}
+	safeHeader := r.SafeHeader
+	if safeHeader == nil {
+		safeHeader = DefaultSafeHeader
+	}
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method:       req.Method,
+			Url:          req.URL.String(),
+			Header:       DefaultSafeHeader(safeHeader(req.Header)),
+			RecordedBody: newRecordedBody(reqBody),
+		},
+		Response: RecordedResponse{
+			StatusCode:   resp.StatusCode,
+			Status:       resp.Status,
+			Header:       DefaultSafeHeader(safeHeader(resp.Header)),
+			RecordedBody: scrubBodyOf(req.URL, respBody),
+		},
+	}
+	data, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		console.Warn("marshal interaction failed: %s", err)
+		return resp, nil
+	}
+	file := r.nextFile(req)
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		console.Warn("save interaction to %s failed: %s", file, err)
+	}
+	return resp, nil
+}
+
+// 使用记录的响应替代真实的请求
+//
+// 相同的请求 (方法、路径和参数) 按记录的顺序返回, 最后一个响应会被重复使用,
+// 以便支持轮询的场景
+type Replayer struct {
+	Dir          string
+	mu           sync.Mutex
+	interactions map[string][]Interaction
+}
+
+func NewReplayer(dir string) (*Replayer, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+CASSETTE_EXT))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no cassettes found in %s", dir)
+	}
+	sort.Strings(files)
+	replayer := &Replayer{Dir: dir, interactions: map[string][]Interaction{}}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		interaction := Interaction{}
+		if err := json.Unmarshal(data, &interaction); err != nil {
+			return nil, fmt.Errorf("invalid cassette %s: %s", file, err)
+		}
+		key := interaction.key()
+		replayer.interactions[key] = append(replayer.interactions[key], interaction)
+	}
+	return replayer, nil
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	// 与 http.Transport 一致, 请求已经取消时直接返回
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := interactionKey(req.Method, req.URL)
+	interactions := r.interactions[key]
+	if len(interactions) == 0 {
+		return nil, fmt.Errorf("no recorded response for %s", key)
+	}
+	interaction := interactions[0]
+	if len(interactions) > 1 {
+		r.interactions[key] = interactions[1:]
+	}
+	console.Debug("replay response for %s", key)
+	header := interaction.Response.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	body := interaction.Response.Bytes()
+	return &http.Response{
+		StatusCode:    interaction.Response.StatusCode,
+		Status:        interaction.Response.Status,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+	}, nil
+}
+
+var (
+	recordDir  string
+	replayer   *Replayer
+	safeHeader func(header http.Header) http.Header
+)
+
+// 开启记录模式, 之后通过 DefaultRestyClient 创建的 client 会记录所有的请求
+func SetRecordDir(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	recordDir, replayer = dir, nil
+	return nil
+}
+
+// 开启回放模式, 之后通过 DefaultRestyClient 创建的 client 不再访问网络
+func SetReplayDir(dir string) error {
+	r, err := NewReplayer(dir)
+	if err != nil {
+		return err
+	}
+	recordDir, replayer = "", r
+	return nil
+}
+
+// 关闭记录和回放模式
+func ResetRecordReplay() {
+	recordDir, replayer = "", nil
+}
+
+// 设置记录时过滤 header 的方法, 例如 AuthPlugin.GetSafeHeader
+func SetSafeHeaderFunc(f func(header http.Header) http.Header) {
+	safeHeader = f
+}
+
+// 同一目录共享计数, 保证文件的顺序与请求的顺序一致
+var recordCounters = map[string]*recordCounter{}
+var recordCountersLock sync.Mutex
+
+func wrapTransport(transport http.RoundTripper) http.RoundTripper {
+	if replayer != nil {
+		return replayer
+	}
+	if recordDir == "" {
+		return transport
+	}
+	recordCountersLock.Lock()
+	defer recordCountersLock.Unlock()
+	counter, ok := recordCounters[recordDir]
+	if !ok {
+		counter = newRecordCounter(recordDir)
+		recordCounters[recordDir] = counter
+	}
+	return &Recorder{
+		Dir: recordDir, Transport: transport, counter: counter,
+		SafeHeader: func(header http.Header) http.Header {
+			if safeHeader == nil {
+				return header
+			}
+			return safeHeader(header)
+		},
+	}
+}
@@ -0,0 +1,118 @@
+package session
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(X_SUBJECT_TOKEN, "secret-token")
+		w.Header().Set(CONTENT_TYPE, CONTENT_TYPE_JSON)
+		w.Write([]byte(`{"server": {"id": "1111", "name": "foo"}}`))
+	}))
+	defer server.Close()
+	defer ResetRecordReplay()
+
+	dir := t.TempDir()
+	if err := SetRecordDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	_, err := DefaultRestyClient().R().
+		SetHeader(X_AUTH_TOKEN, "secret-token").
+		SetBody(map[string]interface{}{"auth": map[string]string{"password": "secret-password"}}).
+		Post(server.URL + "/servers/1111")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*"+CASSETTE_EXT))
+	if len(files) != 1 {
+		t.Fatalf("expect 1 cassette, but got %d", len(files))
+	}
+	data, _ := os.ReadFile(files[0])
+	if strings.Contains(string(data), "secret-") {
+		t.Errorf("cassette is not scrubbed: %s", data)
+	}
+
+	server.Close()
+	if err := SetReplayDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	body := struct{ Server struct{ Id, Name string } }{}
+	resp, err := DefaultRestyClient().R().Post(server.URL + "/servers/1111")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(resp.Body(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Server.Name != "foo" {
+		t.Errorf("expect server foo, but got %s", body.Server.Name)
+	}
+}
+
+func TestRecordScrubSecretPayload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/payload") {
+			w.Header().Set(CONTENT_TYPE, "text/plain")
+			w.Write([]byte("secret-payload"))
+			return
+		}
+		w.Header().Set(CONTENT_TYPE, CONTENT_TYPE_JSON)
+		w.Write([]byte(`{"private_key": "secret-key", "payload": "secret-payload"}`))
+	}))
+	defer server.Close()
+	defer ResetRecordReplay()
+
+	dir := t.TempDir()
+	if err := SetRecordDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/v1/secrets/1111/payload", "/v1/secrets/1111"} {
+		if _, err := DefaultRestyClient().R().Get(server.URL + path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*"+CASSETTE_EXT))
+	if len(files) != 2 {
+		t.Fatalf("expect 2 cassettes, but got %d", len(files))
+	}
+	for _, file := range files {
+		data, _ := os.ReadFile(file)
+		if strings.Contains(string(data), "secret-") {
+			t.Errorf("cassette %s is not scrubbed: %s", file, data)
+		}
+	}
+}
+
+func TestRecordContinueIndex(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	defer ResetRecordReplay()
+
+	dir := t.TempDir()
+	existed := filepath.Join(dir, "00007-GET-servers"+CASSETTE_EXT)
+	if err := os.WriteFile(existed, []byte(`{}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetRecordDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DefaultRestyClient().R().Get(server.URL + "/servers"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(existed)
+	if err != nil || string(data) != `{}` {
+		t.Errorf("expect %s not overwritten, but got %s, %v", existed, data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "00008-GET-servers"+CASSETTE_EXT)); err != nil {
+		t.Errorf("expect index continue from 8, but got %s", err)
+	}
+}
@@ -1,6 +1,7 @@
 package session
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strings"
@@ -14,6 +15,10 @@ type Request struct {
 	ResourceUrl string
 }
 
+func (r *Request) SetContext(ctx context.Context) *Request {
+	r.Request.SetContext(ctx)
+	return r
+}
 func (r *Request) SetBody(body interface{}) *Request {
 	r.Request.SetBody(body)
 	return r
@@ -51,59 +56,36 @@ func (r Request) buildResponse(rawResp *resty.Response) (*Response, error) {
 	}
 }
 
-func (r Request) Get(path ...string) (*Response, error) {
+func (r Request) send(method string, path ...string) (*Response, error) {
 	url, err := r.buildUrl(path...)
 	if err != nil {
 		return nil, err
 	}
-	rawResp, err := r.Request.Get(url)
+	rawResp, err := r.Request.Execute(method, url)
 	if err != nil {
+		if interrupted := Interrupted(r.Request.Context(), "%s %s", method, url); interrupted != nil {
+			return nil, interrupted
+		}
 		return nil, err
 	}
 	return r.buildResponse(rawResp)
 }
+
+func (r Request) Get(path ...string) (*Response, error) {
+	return r.send(resty.MethodGet, path...)
+}
 func (r Request) Post(path ...string) (*Response, error) {
-	url, err := r.buildUrl(path...)
-	if err != nil {
-		return nil, err
-	}
-	rawResp, err := r.Request.Post(url)
-	if err != nil {
-		return nil, err
-	}
-	return r.buildResponse(rawResp)
+	return r.send(resty.MethodPost, path...)
 }
 func (r Request) Put(path ...string) (*Response, error) {
-	url, err := r.buildUrl(path...)
-	if err != nil {
-		return nil, err
-	}
-	rawResp, err := r.Request.Put(url)
-	if err != nil {
-		return nil, err
-	}
-	return r.buildResponse(rawResp)
+	return r.send(resty.MethodPut, path...)
 }
 func (r Request) Patch(path ...string) (*Response, error) {
-	url, err := r.buildUrl(path...)
-	if err != nil {
-		return nil, err
-	}
-	rawResp, err := r.Request.Patch(url)
-	if err != nil {
-		return nil, err
-	}
-	return r.buildResponse(rawResp)
+	return r.send(resty.MethodPatch, path...)
 }
-
 func (r Request) Delete(path ...string) (*Response, error) {
-	url, err := r.buildUrl(path...)
-	if err != nil {
-		return nil, err
-	}
-	rawResp, err := r.Request.Delete(url)
-	if err != nil {
-		return nil, err
-	}
-	return r.buildResponse(rawResp)
+	return r.send(resty.MethodDelete, path...)
+}
+func (r Request) Head(path ...string) (*Response, error) {
+	return r.send(resty.MethodHead, path...)
 }
@@ -13,10 +13,7 @@ func (r Response) RequestId() string {
 }
 
 func (r Response) Error() error {
-	return HttpError{
-		Status:  r.StatusCode(),
-		Reason:  r.Status(),
-		Message: string(r.Body())}
+	return NewHttpError(r.Response)
 }
 func (r Response) IsNotFound() bool {
 	return r.StatusCode() == CODE_404
@@ -0,0 +1,71 @@
+package session
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/BytemanD/go-console/console"
+)
+
+type TLSOptions struct {
+	// CA 证书文件
+	CACert string
+	// 客户端证书和私钥文件, 用于双向认证
+	Cert string
+	Key  string
+	// 不校验服务端证书
+	Insecure bool
+}
+
+var tlsConfig *tls.Config
+
+func (opts TLSOptions) TLSConfig() (*tls.Config, error) {
+	config := &tls.Config{InsecureSkipVerify: opts.Insecure}
+	if opts.CACert != "" {
+		caData, err := os.ReadFile(opts.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("read cacert failed: %s", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("no certificates found in %s", opts.CACert)
+		}
+		config.RootCAs = pool
+	}
+	if opts.Cert != "" || opts.Key != "" {
+		if opts.Cert == "" || opts.Key == "" {
+			return nil, fmt.Errorf("both cert and key are required")
+		}
+		cert, err := tls.LoadX509KeyPair(opts.Cert, opts.Key)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate failed: %s", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// 设置 TLS 选项, 对之后通过 DefaultRestyClient 创建的 client 生效
+func SetTLSOptions(opts TLSOptions) error {
+	config, err := opts.TLSConfig()
+	if err != nil {
+		return err
+	}
+	if opts.Insecure {
+		console.Warn("certificate verification is disabled")
+	}
+	tlsConfig = config
+	return nil
+}
+
+func GetTLSConfig() *tls.Config {
+	if tlsConfig == nil {
+		return nil
+	}
+	return tlsConfig.Clone()
+}
@@ -0,0 +1,34 @@
+package session
+
+import (
+	"fmt"
+	"net/url"
+
+	"golang.org/x/net/websocket"
+)
+
+// 连接 websocket, 例如 nova 的串口控制台 ws://host:6083/?token=xxx
+//
+// novnc/nova 的代理会校验 Origin, 所以使用与 websocket 地址相同的主机作为 Origin
+func DialWebsocket(rawUrl string, protocols ...string) (*websocket.Conn, error) {
+	parsed, err := url.Parse(rawUrl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid websocket url %s: %w", rawUrl, err)
+	}
+	origin := url.URL{Scheme: "http", Host: parsed.Host}
+	if parsed.Scheme == "wss" {
+		origin.Scheme = "https"
+	}
+	config, err := websocket.NewConfig(rawUrl, origin.String())
+	if err != nil {
+		return nil, err
+	}
+	config.Protocol = protocols
+	config.TlsConfig = GetTLSConfig()
+	conn, err := websocket.DialConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("connect to %s failed: %w", parsed.Host, err)
+	}
+	conn.PayloadType = websocket.BinaryFrame
+	return conn, nil
+}
@@ -0,0 +1,196 @@
+/*
+Token 本地缓存
+
+缓存文件保存在用户家目录的 .skyman_token_cache 目录下, 每个认证信息对应一个文件,
+文件名为认证地址、用户、项目和 region 的摘要, 以便多个 skyman 进程共享同一个 token。
+*/
+package token_cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BytemanD/go-console/console"
+	"github.com/BytemanD/skyman/openstack/model"
+)
+
+const (
+	DEFAULT_TOKEN_CACHE_DIR = ".skyman_token_cache"
+	CACHE_FILE_EXT          = ".json"
+)
+
+// token 过期前提前失效的时间, 避免请求过程中 token 过期
+var SAFETY_MARGIN = time.Minute * 5
+
+type Key struct {
+	AuthUrl string `json:"authUrl"`
+	User    string `json:"user"`
+	Project string `json:"project"`
+	Region  string `json:"region"`
+}
+
+func (k Key) Digest() string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{k.AuthUrl, k.User, k.Project, k.Region}, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+type Entry struct {
+	Key
+	TokenId   string      `json:"tokenId"`
+	Token     model.Token `json:"token"`
+	ExpiredAt time.Time   `json:"expiredAt"`
+}
+
+func (e Entry) IsValid() bool {
+	if e.TokenId == "" {
+		return false
+	}
+	return time.Now().Add(SAFETY_MARGIN).Before(e.ExpiredAt)
+}
+
+// 缓存目录, 优先使用环境变量 HOME 指定的家目录
+func CacheDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		u, err := user.Current()
+		if err != nil {
+			return "", err
+		}
+		homeDir = u.HomeDir
+	}
+	return filepath.Join(homeDir, DEFAULT_TOKEN_CACHE_DIR), nil
+}
+
+func cacheFile(key Key) (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, key.Digest()+CACHE_FILE_EXT), nil
+}
+
+func readEntry(file string) (*Entry, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	entry := Entry{}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("invalid token cache %s: %s", file, err)
+	}
+	return &entry, nil
+}
+
+// 读取有效的缓存, 缓存不存在或者已过期时返回 nil
+func Load(key Key) *Entry {
+	file, err := cacheFile(key)
+	if err != nil {
+		console.Warn("get token cache file failed: %s", err)
+		return nil
+	}
+	entry, err := readEntry(file)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			console.Warn("load token cache failed: %s", err)
+		}
+		return nil
+	}
+	if !entry.IsValid() {
+		console.Debug("token cache %s expired at %s", file, entry.ExpiredAt)
+		return nil
+	}
+	console.Debug("use token cache %s", file)
+	return entry
+}
+
+func Save(entry Entry) error {
+	file, err := cacheFile(entry.Key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	// 先写入临时文件再重命名, 避免并发的进程读到不完整的文件
+	tmpFile, err := os.CreateTemp(filepath.Dir(file), "."+entry.Digest()+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	if err := tmpFile.Chmod(0600); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	console.Debug("save token cache to %s", file)
+	return os.Rename(tmpFile.Name(), file)
+}
+
+func Remove(key Key) error {
+	file, err := cacheFile(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	console.Debug("removed token cache %s", file)
+	return nil
+}
+
+func List() ([]Entry, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*"+CACHE_FILE_EXT))
+	if err != nil {
+		return nil, err
+	}
+	entries := []Entry{}
+	for _, file := range files {
+		entry, err := readEntry(file)
+		if err != nil {
+			console.Warn("%s", err)
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, nil
+}
+
+// 清理缓存, 如果 expiredOnly 为 true, 只清理已失效的缓存
+func Purge(expiredOnly bool) (int, error) {
+	entries, err := List()
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, entry := range entries {
+		if expiredOnly && entry.IsValid() {
+			continue
+		}
+		if err := Remove(entry.Key); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
@@ -0,0 +1,120 @@
+package token_cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newEntry(user string, expiredAt time.Time) Entry {
+	return Entry{
+		Key:     Key{AuthUrl: "http://keystone:5000/v3", User: user, Project: "admin", Region: "RegionOne"},
+		TokenId: "token-" + user, ExpiredAt: expiredAt,
+	}
+}
+
+func TestEntryIsValid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		entry  Entry
+		expect bool
+	}{
+		{"valid", newEntry("admin", time.Now().Add(time.Hour)), true},
+		{"expired", newEntry("admin", time.Now().Add(-time.Minute)), false},
+		{"within safety margin", newEntry("admin", time.Now().Add(SAFETY_MARGIN-time.Second)), false},
+		{"after safety margin", newEntry("admin", time.Now().Add(SAFETY_MARGIN+time.Minute)), true},
+		{"empty token", Entry{ExpiredAt: time.Now().Add(time.Hour)}, false},
+	}
+	for _, testCase := range testCases {
+		if valid := testCase.entry.IsValid(); valid != testCase.expect {
+			t.Errorf("%s: expect valid %v, but got %v", testCase.name, testCase.expect, valid)
+		}
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	testCases := []struct {
+		name     string
+		entry    Entry
+		loadable bool
+	}{
+		{"valid", newEntry("admin", time.Now().Add(time.Hour)), true},
+		{"expired", newEntry("demo", time.Now().Add(time.Minute)), false},
+	}
+	for _, testCase := range testCases {
+		if err := Save(testCase.entry); err != nil {
+			t.Fatalf("%s: %s", testCase.name, err)
+		}
+		file, _ := cacheFile(testCase.entry.Key)
+		info, err := os.Stat(file)
+		if err != nil {
+			t.Fatalf("%s: %s", testCase.name, err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("%s: expect mode 0600, but got %o", testCase.name, info.Mode().Perm())
+		}
+		entry := Load(testCase.entry.Key)
+		if loaded := entry != nil; loaded != testCase.loadable {
+			t.Errorf("%s: expect loaded %v, but got %v", testCase.name, testCase.loadable, loaded)
+		}
+		if entry != nil && entry.TokenId != testCase.entry.TokenId {
+			t.Errorf("%s: expect token %s, but got %s", testCase.name, testCase.entry.TokenId, entry.TokenId)
+		}
+	}
+	// 临时文件重命名后不应该残留
+	dir, _ := CacheDir()
+	files, _ := os.ReadDir(dir)
+	if len(files) != len(testCases) {
+		t.Errorf("expect %d cache files, but got %d", len(testCases), len(files))
+	}
+	if dirInfo, _ := os.Stat(dir); dirInfo.Mode().Perm() != 0700 {
+		t.Errorf("expect dir mode 0700, but got %o", dirInfo.Mode().Perm())
+	}
+}
+
+func TestListAndPurge(t *testing.T) {
+	testCases := []struct {
+		name        string
+		expiredOnly bool
+		purged      int
+		left        int
+	}{
+		{"expired only", true, 2, 1},
+		{"all", false, 3, 0},
+	}
+	for _, testCase := range testCases {
+		t.Setenv("HOME", t.TempDir())
+		for _, entry := range []Entry{
+			newEntry("admin", time.Now().Add(time.Hour)),
+			newEntry("demo", time.Now().Add(-time.Hour)),
+			newEntry("guest", time.Now().Add(time.Minute)),
+		} {
+			if err := Save(entry); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// 无效的缓存文件会被忽略
+		dir, _ := CacheDir()
+		if err := os.WriteFile(filepath.Join(dir, "invalid"+CACHE_FILE_EXT), []byte("{"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		entries, err := List()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 3 {
+			t.Errorf("%s: expect 3 entries, but got %d", testCase.name, len(entries))
+		}
+		purged, err := Purge(testCase.expiredOnly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries, _ = List()
+		if purged != testCase.purged || len(entries) != testCase.left {
+			t.Errorf("%s: expect purged %d and left %d, but got %d and %d",
+				testCase.name, testCase.purged, testCase.left, purged, len(entries))
+		}
+	}
+}
@@ -96,7 +96,7 @@ func (c QGAChecker) MakesureInterfaceExist(attachment *nova.InterfaceAttachment)
 			IntervalMax:  time.Second * 10,
 			IntervalStep: time.Second,
 		},
-		[]string{"GuestHasNoIpaddressError"},
+		[]error{utility.ErrGuestHasNoIpaddress},
 		func() error {
 			ipaddrs := serverGuest.GetIpaddrs()
 			console.Debug("[%s] found ip address on guest: %v", c.ServerId, ipaddrs)
@@ -203,6 +203,7 @@ func (t ServerActionTest) getServerBootOption(name string) nova.ServerOpt {
 		Flavor:           TEST_FLAVORS[0].Id,
 		Image:            t.Config.Images[0],
 		AvailabilityZone: t.Config.AvailabilityZone,
+		KeyName:          t.Config.KeyName,
 	}
 	if len(t.Config.Networks) >= 1 {
 		opt.Networks = []nova.ServerOptNetwork{
@@ -253,7 +254,7 @@ func (t *ServerActionTest) WaitSnapshotCreated(snapshotId string) error {
 		utility.RetryCondition{
 			Timeout:     time.Minute * 5,
 			IntervalMin: time.Second * 2},
-		[]string{"SnapshotIsNotAvailable"},
+		[]error{utility.ErrSnapshotNotAvailable},
 		func() error {
 			snapshot, err := t.Client.CinderV2().Snapshot().Show(snapshotId)
 			if err != nil {
@@ -278,7 +279,7 @@ func (t *ServerActionTest) WaitVolumeTaskDone(volumeId string) error {
 			IntervalMin:  time.Second,
 			IntervalStep: time.Second,
 			IntervalMax:  time.Second * 10},
-		[]string{"VolumeHasTaskError"},
+		[]error{utility.ErrVolumeHasTask},
 		func() error {
 			vol, err := t.Client.CinderV2().Volume().Show(volumeId)
 			if err != nil {
@@ -35,7 +35,7 @@ func (t ServerExtendVolume) Start() error {
 			IntervalMin:  time.Second,
 			IntervalStep: time.Second,
 			IntervalMax:  time.Second * 5},
-		[]string{"VolumeHasTaskError"},
+		[]error{utility.ErrVolumeHasTask},
 		func() error {
 			vol, err := t.Client.CinderV2().Volume().Show(attachment.VolumeId)
 			if err != nil {
@@ -26,7 +26,7 @@ func (t *ServerSnapshot) Start() error {
 		utility.RetryCondition{
 			Timeout:     time.Minute * 10,
 			IntervalMin: time.Second * 2},
-		[]string{"ImageNotActive"},
+		[]error{utility.ErrImageNotActive},
 		func() error {
 			image, err := t.Client.GlanceV2().Images().Show(imageId)
 			if err != nil {
@@ -46,7 +46,7 @@ func (t *ServerLiveMigrate) waitServerBooted(serverId string) error {
 			IntervalStep: time.Second,
 			IntervalMax:  time.Second * 10,
 		},
-		[]string{"ServerNotBooted"},
+		[]error{utility.ErrServerNotBooted},
 		func() error {
 			consoleLog, err := t.Client.NovaV2().Server().ConsoleLog(serverId, 50)
 			if err != nil {
@@ -93,7 +93,7 @@ func (t *ServerLiveMigrate) startPing(targetIp string) error {
 		utility.RetryCondition{
 			Timeout:     time.Second * 60,
 			IntervalMin: time.Second * 2},
-		[]string{"GuestNoIpaddressError"},
+		[]error{utility.ErrGuestNoIpaddress},
 		func() error {
 			ipaddrs = clientGuest.GetIpaddrs()
 			if len(ipaddrs) == 0 {
@@ -156,7 +156,7 @@ func (t *ServerLiveMigrate) checkPingBeforeMigrate(targetIp string) error {
 			Timeout:     time.Minute * 5,
 			IntervalMin: time.Second * 4,
 		},
-		[]string{"PingLossPackage"},
+		[]error{utility.ErrPingLossPackage},
 		func() error {
 			if err := t.startPing(targetIp); err != nil {
 				return err
@@ -204,7 +204,7 @@ func (t ServerLiveMigrate) confirmServerHasIpAddress() error {
 	serverGuest.ConnectToQGA(t.Config.QGAChecker.QgaConnectTimeout)
 	err = utility.RetryWithErrors(
 		utility.RetryCondition{Timeout: time.Second * 60, IntervalMin: time.Second * 2},
-		[]string{"GuestNoIpaddressError"},
+		[]error{utility.ErrGuestNoIpaddress},
 		func() error {
 			ipaddrs := serverGuest.GetIpaddrs()
 			if len(ipaddrs) == 0 {
@@ -47,6 +47,7 @@ func (t Case) getServerBootOption(testId int) nova.ServerOpt {
 		Flavor:           t.firstFlavor(),
 		Image:            t.firstImage(),
 		AvailabilityZone: t.Config.AvailabilityZone,
+		KeyName:          t.Config.KeyName,
 	}
 	if len(t.Config.Networks) >= 1 {
 		opt.Networks = []nova.ServerOptNetwork{
@@ -0,0 +1,61 @@
+package server_actions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BytemanD/skyman/common"
+	"github.com/BytemanD/skyman/openstack"
+	"github.com/BytemanD/skyman/openstack/fake"
+)
+
+func newFakeCase(t *testing.T, actions string) *Case {
+	cloud := fake.NewCloud()
+	cloud.TaskDuration = time.Millisecond * 20
+	cloud.ResizeConfirmWindow = time.Millisecond * 100
+	t.Cleanup(cloud.Close)
+
+	network := cloud.AddNetwork("test-net")
+	cloud.AddSubnet(network.Id, "test-subnet", "192.168.100.0/24")
+
+	common.CONF.Identity.Api.Version = "3"
+	client := openstack.NewClient(cloud.AuthUrl(), cloud.User(), cloud.Project(), cloud.Region)
+	client.ComputeApiVersion = "2.1"
+	client.AuthPlugin.SetLocalTokenExpire(3600)
+
+	actionList, err := NewActionCountList(actions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Case{
+		Actions: *actionList,
+		Client:  client,
+		Config: common.CaseConfig{
+			Flavors: []string{"1"}, Images: []string{"cirros"}, Networks: []string{network.Id},
+			Workers: 1, DeleteIfSuccess: true, VolumeSize: 1,
+		},
+	}
+}
+
+func TestServerActions(t *testing.T) {
+	c := newFakeCase(t, "reboot,hard_reboot,stop,start,pause,unpause,suspend,resume,"+
+		"live_migrate,shelve,unshelve,rebuild,rename,"+
+		"net_attach,port_detach,volume_attach,volume_detach,migrate")
+	c.Start()
+
+	reports := c.Report().WorkerReports
+	if len(reports) != 1 {
+		t.Fatalf("expect 1 worker report, got %d", len(reports))
+	}
+	for _, result := range reports[0].Results {
+		if result.Error != nil {
+			t.Errorf("action %s failed: %s", result.Action, result.Error)
+		}
+	}
+	if reports[0].Error != nil {
+		t.Fatalf("worker failed: %s", reports[0].Error)
+	}
+	if len(reports[0].Results) != c.Actions.Total() {
+		t.Errorf("expect %d results, got %d", c.Actions.Total(), len(reports[0].Results))
+	}
+}
@@ -19,6 +19,56 @@ type SnapshotIsNotAvailable ErrArgs
 type ServerNotBooted ErrArgs
 type ImageNotActive ErrArgs
 
+// 用于 errors.Is 判断错误类型, 例如 errors.Is(err, utility.ErrServerNotStopped)
+var (
+	ErrActionNotFinished    = ActionNotFinishedError{}
+	ErrGuestNoIpaddress     = GuestNoIpaddressError{}
+	ErrVolumeHasTask        = VolumeHasTaskError{}
+	ErrGuestHasNoIpaddress  = GuestHasNoIpaddressError{}
+	ErrPingLossPackage      = PingLossPackage{}
+	ErrServerNotStopped     = ServerNotStopped{}
+	ErrSnapshotNotAvailable = SnapshotIsNotAvailable{}
+	ErrServerNotBooted      = ServerNotBooted{}
+	ErrImageNotActive       = ImageNotActive{}
+)
+
+func (e ActionNotFinishedError) Is(target error) bool {
+	_, ok := target.(ActionNotFinishedError)
+	return ok
+}
+func (e GuestNoIpaddressError) Is(target error) bool {
+	_, ok := target.(GuestNoIpaddressError)
+	return ok
+}
+func (e VolumeHasTaskError) Is(target error) bool {
+	_, ok := target.(VolumeHasTaskError)
+	return ok
+}
+func (e GuestHasNoIpaddressError) Is(target error) bool {
+	_, ok := target.(GuestHasNoIpaddressError)
+	return ok
+}
+func (e PingLossPackage) Is(target error) bool {
+	_, ok := target.(PingLossPackage)
+	return ok
+}
+func (e ServerNotStopped) Is(target error) bool {
+	_, ok := target.(ServerNotStopped)
+	return ok
+}
+func (e SnapshotIsNotAvailable) Is(target error) bool {
+	_, ok := target.(SnapshotIsNotAvailable)
+	return ok
+}
+func (e ServerNotBooted) Is(target error) bool {
+	_, ok := target.(ServerNotBooted)
+	return ok
+}
+func (e ImageNotActive) Is(target error) bool {
+	_, ok := target.(ImageNotActive)
+	return ok
+}
+
 func (e ActionNotFinishedError) Error() string {
 	return fmt.Sprintf("action %s is not finished", e.Args...)
 }
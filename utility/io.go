@@ -2,6 +2,7 @@ package utility
 
 import (
 	"bufio"
+	"context"
 	"encoding/base64"
 	"io"
 	"net"
@@ -85,12 +86,15 @@ func GetAllIpaddress() ([]string, error) {
 	return ips, nil
 }
 
-func WatchFileSize(filePath string, size int) {
+// 显示文件大小的进度, ctx 结束时停止
+func WatchFileSize(ctx context.Context, filePath string, size int) {
 	bar := pb.StartNew(size)
 	currentSize := int64(0)
 	for {
 		if !IsFileExists(filePath) {
-			time.Sleep(time.Second * 2)
+			if SleepContext(ctx, time.Second*2) != nil {
+				break
+			}
 			continue
 		}
 		stat, err := os.Stat(filePath)
@@ -102,7 +106,9 @@ func WatchFileSize(filePath string, size int) {
 			break
 		}
 		currentSize = stat.Size()
-		time.Sleep(time.Second * 2)
+		if SleepContext(ctx, time.Second*2) != nil {
+			break
+		}
 	}
 	bar.Finish()
 }
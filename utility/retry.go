@@ -2,10 +2,10 @@ package utility
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
-	"github.com/BytemanD/easygo/pkg/compare"
 	"github.com/BytemanD/go-console/console"
 )
 
@@ -37,6 +37,8 @@ func (i *StepInterval) Next() time.Duration {
 }
 
 type RetryCondition struct {
+	// 为空时使用 context.Background()
+	Ctx          context.Context
 	Timeout      time.Duration
 	IntervalMin  time.Duration
 	IntervalMax  time.Duration
@@ -59,6 +61,27 @@ func (c *RetryCondition) NextInterval() time.Duration {
 	return c.interval
 
 }
+
+// 等待下一次重试, 如果 Ctx 已经结束, 返回 Ctx.Err()
+func (c *RetryCondition) wait() error {
+	ctx := c.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return SleepContext(ctx, c.NextInterval())
+}
+
+// 等待 d, 如果 ctx 提前结束, 返回 ctx.Err()
+func SleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
 func Retry(condition RetryCondition, function func() bool) error {
 	startTime := time.Now()
 	for {
@@ -69,7 +92,9 @@ func Retry(condition RetryCondition, function func() bool) error {
 		if condition.Timeout > 0 && time.Since(startTime) >= condition.Timeout {
 			return fmt.Errorf("retry timeout(%v)", condition.Timeout)
 		}
-		time.Sleep(condition.NextInterval())
+		if err := condition.wait(); err != nil {
+			return err
+		}
 	}
 }
 func RetryError(condition RetryCondition, function func() (bool, error)) error {
@@ -82,10 +107,13 @@ func RetryError(condition RetryCondition, function func() (bool, error)) error {
 		if condition.Timeout > 0 && time.Since(startTime) >= condition.Timeout {
 			return fmt.Errorf("retry timeout(%v)", condition.Timeout)
 		}
-		time.Sleep(condition.NextInterval())
+		if err := condition.wait(); err != nil {
+			return err
+		}
 	}
 }
 func RetryWithContext(ctx context.Context, condition RetryCondition, function func() error) error {
+	condition.Ctx = ctx
 	startTime := time.Now()
 	for {
 		if ctx.Err() != nil {
@@ -99,11 +127,14 @@ func RetryWithContext(ctx context.Context, condition RetryCondition, function fu
 		if condition.Timeout > 0 && time.Since(startTime) >= condition.Timeout {
 			return fmt.Errorf("retry timeout(%v), last error: %s", condition.Timeout, err)
 		}
-		time.Sleep(condition.NextInterval())
+		if err := condition.wait(); err != nil {
+			return err
+		}
 	}
 }
 
-func RetryWithErrors(condition RetryCondition, matchErrors []string, function func() error) error {
+// 当 function 返回的错误匹配 (errors.Is) matchErrors 中的任意一个时重试
+func RetryWithErrors(condition RetryCondition, matchErrors []error, function func() error) error {
 	startTime := time.Now()
 	var err error
 	for {
@@ -115,7 +146,7 @@ func RetryWithErrors(condition RetryCondition, matchErrors []string, function fu
 			return fmt.Errorf("retry timeout(%v), last error: %v", condition.Timeout, err)
 		}
 		for _, e := range matchErrors {
-			if compare.IsStructOf(e, err) {
+			if errors.Is(err, e) {
 				err = nil
 				break
 			}
@@ -123,6 +154,8 @@ func RetryWithErrors(condition RetryCondition, matchErrors []string, function fu
 		if err != nil {
 			return err
 		}
-		time.Sleep(condition.NextInterval())
+		if err := condition.wait(); err != nil {
+			return err
+		}
 	}
 }